@@ -0,0 +1,136 @@
+// Package backoff computes how long to wait between attempts.
+//
+// Strategies are pure: given the attempt number and the previous delay they
+// return the next delay, and randomised strategies take their randomness from
+// an injectable source. Waiting goes through a Clock so callers can swap in a
+// FakeClock and test retry or reconnect loops without sleeping.
+package backoff
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// longest is the largest delay a strategy returns when it has no Max.
+const longest = time.Duration(math.MaxInt64)
+
+// Strategy returns the delay before retry number attempt (starting at 1).
+// prev is the delay returned for the previous attempt, or 0 before the first.
+type Strategy interface {
+	Next(attempt int, prev time.Duration) time.Duration
+}
+
+// Func adapts a plain function to Strategy.
+type Func func(attempt int, prev time.Duration) time.Duration
+
+// Next implements Strategy.
+func (f Func) Next(attempt int, prev time.Duration) time.Duration { return f(attempt, prev) }
+
+// Constant waits the same Delay every time.
+type Constant struct {
+	Delay time.Duration
+}
+
+// Next implements Strategy.
+func (c Constant) Next(int, time.Duration) time.Duration { return c.Delay }
+
+// Exponential waits Base, then Base*Multiplier, Base*Multiplier², … capped at
+// Max. Multiplier defaults to 2; without Max the delay stops growing at the
+// longest Duration.
+type Exponential struct {
+	Base       time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// Next implements Strategy.
+func (e Exponential) Next(attempt int, _ time.Duration) time.Duration {
+	m := e.Multiplier
+	if m <= 1 {
+		m = 2
+	}
+	limit := longest
+	if e.Max > 0 {
+		limit = e.Max
+	}
+	d := float64(e.Base)
+	for i := 1; i < attempt; i++ {
+		d *= m
+		if d >= float64(limit) {
+			return limit
+		}
+	}
+	return capAt(time.Duration(d), e.Max)
+}
+
+// DecorrelatedJitter implements the "decorrelated jitter" algorithm: each
+// delay is drawn uniformly from [Base, prev*3], capped at Max or, without
+// one, at the longest Duration. It spreads
+// retries from many clients better than plain exponential backoff.
+type DecorrelatedJitter struct {
+	Base time.Duration
+	Max  time.Duration
+	// Rand returns a float in [0, 1). Defaults to math/rand/v2.Float64;
+	// tests can pin it for deterministic delays.
+	Rand func() float64
+}
+
+// Next implements Strategy.
+func (j DecorrelatedJitter) Next(_ int, prev time.Duration) time.Duration {
+	r := j.Rand
+	if r == nil {
+		r = rand.Float64
+	}
+	upper := longest
+	if prev <= longest/3 {
+		upper = max(prev*3, j.Base)
+	}
+	d := j.Base + min(time.Duration(r()*float64(upper-j.Base)), upper-j.Base)
+	return capAt(d, j.Max)
+}
+
+// Fibonacci waits Base×fib(attempt): Base, Base, 2×Base, 3×Base, 5×Base, …
+// capped at Max or the longest Duration. It grows more gently than
+// Exponential.
+type Fibonacci struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Next implements Strategy.
+func (f Fibonacci) Next(attempt int, _ time.Duration) time.Duration {
+	limit := longest
+	if f.Max > 0 {
+		limit = f.Max
+	}
+	a, b := 1.0, 1.0
+	for i := 1; i < attempt; i++ {
+		a, b = b, a+b
+		if a*float64(f.Base) >= float64(limit) {
+			return limit
+		}
+	}
+	return capAt(time.Duration(a*float64(f.Base)), f.Max)
+}
+
+func capAt(d, max time.Duration) time.Duration {
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
+
+// Sleep waits for d on clock, returning early with ctx.Err() if ctx is done.
+func Sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
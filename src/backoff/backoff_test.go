@@ -0,0 +1,138 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStrategies(t *testing.T) {
+	const ms = time.Millisecond
+	tests := []struct {
+		name string
+		s    Strategy
+		want []time.Duration // for attempts 1, 2, ...
+	}{
+		{"constant", Constant{Delay: 5 * ms}, []time.Duration{5 * ms, 5 * ms, 5 * ms}},
+		{"exponential", Exponential{Base: 10 * ms}, []time.Duration{10 * ms, 20 * ms, 40 * ms, 80 * ms}},
+		{"exponential x3", Exponential{Base: 10 * ms, Multiplier: 3}, []time.Duration{10 * ms, 30 * ms, 90 * ms}},
+		{"exponential capped", Exponential{Base: 10 * ms, Max: 35 * ms}, []time.Duration{10 * ms, 20 * ms, 35 * ms, 35 * ms}},
+		{"fibonacci", Fibonacci{Base: ms}, []time.Duration{ms, ms, 2 * ms, 3 * ms, 5 * ms, 8 * ms}},
+		{"fibonacci capped", Fibonacci{Base: ms, Max: 4 * ms}, []time.Duration{ms, ms, 2 * ms, 3 * ms, 4 * ms, 4 * ms}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prev time.Duration
+			for i, want := range tt.want {
+				got := tt.s.Next(i+1, prev)
+				if got != want {
+					t.Fatalf("attempt %d: %v, want %v", i+1, got, want)
+				}
+				prev = got
+			}
+		})
+	}
+}
+
+func TestExponentialCapDoesNotOverflow(t *testing.T) {
+	e := Exponential{Base: time.Second, Max: time.Minute}
+	if d := e.Next(1000, 0); d != time.Minute {
+		t.Fatalf("attempt 1000 = %v, want the cap", d)
+	}
+}
+
+func TestUncappedDelaysDoNotOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Strategy
+	}{
+		{"exponential", Exponential{Base: time.Second}},
+		{"exponential x10", Exponential{Base: time.Hour, Multiplier: 10}},
+		{"fibonacci", Fibonacci{Base: time.Second}},
+		{"decorrelated jitter", DecorrelatedJitter{Base: time.Second, Rand: func() float64 { return 0.999999 }}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prev time.Duration
+			for i := 1; i <= 200; i++ {
+				d := tt.s.Next(i, prev)
+				if d < prev {
+					t.Fatalf("attempt %d: %v after %v", i, d, prev)
+				}
+				prev = d
+			}
+			if prev < longest/2 {
+				t.Fatalf("attempt 200: %v, want close to the longest Duration", prev)
+			}
+		})
+	}
+}
+
+func TestDecorrelatedJitterBounds(t *testing.T) {
+	const base, maxDelay = 10 * time.Millisecond, time.Second
+	tests := []struct {
+		name string
+		rand float64
+		prev time.Duration
+		want time.Duration
+	}{
+		{"first retry is Base", 0.99, 0, base},
+		{"low draw is Base", 0, 100 * time.Millisecond, base},
+		{"middle draw", 0.5, 100 * time.Millisecond, base + 145*time.Millisecond},
+		{"capped", 0.99, 900 * time.Millisecond, maxDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := DecorrelatedJitter{Base: base, Max: maxDelay, Rand: func() float64 { return tt.rand }}
+			if d := j.Next(2, tt.prev); d != tt.want {
+				t.Fatalf("Next = %v, want %v", d, tt.want)
+			}
+		})
+	}
+
+	// With the real source every delay stays in [Base, min(prev*3, Max)].
+	j := DecorrelatedJitter{Base: base, Max: maxDelay}
+	var prev time.Duration
+	for i := 1; i <= 1000; i++ {
+		d := j.Next(i, prev)
+		upper := min(max(prev*3, base), maxDelay)
+		if d < base || d > upper {
+			t.Fatalf("attempt %d: %v outside [%v, %v]", i, d, base, upper)
+		}
+		prev = d
+	}
+}
+
+func TestSleepOnFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	done := make(chan error)
+	go func() { done <- Sleep(context.Background(), clock, time.Minute) }()
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(59 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("Sleep returned before the clock reached its deadline")
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := clock.Now(); !got.Equal(time.Unix(60, 0)) {
+		t.Fatalf("Now = %v", got)
+	}
+}
+
+func TestSleepStopsWithContext(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, clock, time.Hour); err != context.Canceled {
+		t.Fatalf("Sleep = %v, want context.Canceled", err)
+	}
+	if err := Sleep(context.Background(), clock, 0); err != nil {
+		t.Fatalf("Sleep(0) = %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+// Package breaker stops calling a dependency that keeps failing. A circuit
+// starts closed, passing every call; enough consecutive failures open it,
+// and calls fail at once with ErrOpen; after a cooldown it is half-open and
+// lets a trial call through, whose outcome closes or reopens it.
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopatterns/backoff"
+)
+
+// ErrOpen is returned instead of calling through an open circuit.
+var ErrOpen = errors.New("breaker: circuit open")
+
+// State is where a circuit is in its cycle.
+type State int
+
+// The states of a circuit.
+const (
+	Closed State = iota
+	Open
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Settings tune a Breaker. The zero value is usable.
+type Settings struct {
+	// Failures is how many failures in a row open the circuit; 5 by
+	// default.
+	Failures int
+	// Cooldown is how long the circuit stays open before a trial call;
+	// 30s by default.
+	Cooldown time.Duration
+	// Trials is how many calls may be in flight while half-open; 1 by
+	// default.
+	Trials int
+	// Clock tells the time, for tests; backoff.System by default.
+	Clock backoff.Clock
+	// OnStateChange, if set, is called on every transition, with the
+	// Group key of the circuit ("" outside a Group). It must not call
+	// back into the breaker.
+	OnStateChange func(key string, from, to State)
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.Failures <= 0 {
+		s.Failures = 5
+	}
+	if s.Cooldown <= 0 {
+		s.Cooldown = 30 * time.Second
+	}
+	if s.Trials <= 0 {
+		s.Trials = 1
+	}
+	if s.Clock == nil {
+		s.Clock = backoff.System
+	}
+	return s
+}
+
+// Breaker is one circuit. It is safe for concurrent use.
+type Breaker struct {
+	key string
+	s   Settings
+
+	mu       sync.Mutex
+	state    State
+	gen      int       // bumped on every transition
+	failures int       // in a row, while closed
+	opened   time.Time // while open
+	trials   int       // in flight, while half-open
+}
+
+// New returns a closed circuit.
+func New(s Settings) *Breaker {
+	return &Breaker{s: s.withDefaults()}
+}
+
+// State returns the circuit's state, moving it from open to half-open if
+// the cooldown is over.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cool()
+	return b.state
+}
+
+// Allow asks to make a call. It returns ErrOpen if the circuit is open, or
+// half-open with its trial calls in flight; otherwise the caller makes the
+// call and reports its outcome with done, exactly once.
+func (b *Breaker) Allow() (done func(ok bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cool()
+	switch b.state {
+	case Open:
+		return nil, ErrOpen
+	case HalfOpen:
+		if b.trials >= b.s.Trials {
+			return nil, ErrOpen
+		}
+		b.trials++
+	}
+	var once sync.Once
+	gen := b.gen
+	return func(ok bool) { once.Do(func() { b.record(gen, ok) }) }, nil
+}
+
+// Do calls fn through the circuit, counting any error as a failure.
+func (b *Breaker) Do(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err == nil)
+	return err
+}
+
+// record counts the outcome of a call allowed in generation gen. Calls
+// allowed before the last transition change nothing: they say nothing
+// about the dependency since the circuit opened, or closed again.
+func (b *Breaker) record(gen int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.gen {
+		return
+	}
+	switch b.state {
+	case Closed:
+		if ok {
+			b.failures = 0
+		} else if b.failures++; b.failures >= b.s.Failures {
+			b.open()
+		}
+	case HalfOpen:
+		b.trials--
+		if ok {
+			b.failures = 0
+			b.set(Closed)
+		} else {
+			b.open()
+		}
+	}
+}
+
+// cool moves an open circuit whose cooldown is over to half-open.
+func (b *Breaker) cool() {
+	if b.state == Open && !b.s.Clock.Now().Before(b.opened.Add(b.s.Cooldown)) {
+		b.trials = 0
+		b.set(HalfOpen)
+	}
+}
+
+func (b *Breaker) open() {
+	b.opened = b.s.Clock.Now()
+	b.set(Open)
+}
+
+func (b *Breaker) set(to State) {
+	from := b.state
+	b.state = to
+	b.gen++
+	if from != to && b.s.OnStateChange != nil {
+		b.s.OnStateChange(b.key, from, to)
+	}
+}
+
+// Group keeps one circuit per key, such as a job type or a downstream
+// host, so one failing dependency does not stop calls to the others.
+type Group struct {
+	s Settings
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewGroup returns a group whose circuits all use s.
+func NewGroup(s Settings) *Group {
+	return &Group{s: s.withDefaults(), breakers: map[string]*Breaker{}}
+}
+
+// Get returns the circuit for key, creating it closed.
+func (g *Group) Get(key string) *Breaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.breakers[key]
+	if !ok {
+		b = &Breaker{key: key, s: g.s}
+		g.breakers[key] = b
+	}
+	return b
+}
+
+// States returns the state of every circuit by key.
+func (g *Group) States() map[string]State {
+	g.mu.Lock()
+	list := make(map[string]*Breaker, len(g.breakers))
+	for k, b := range g.breakers {
+		list[k] = b
+	}
+	g.mu.Unlock()
+	states := make(map[string]State, len(list))
+	for k, b := range list {
+		states[k] = b.State()
+	}
+	return states
+}
@@ -0,0 +1,247 @@
+// Package cache provides a generic in-memory LRU cache with per-entry TTLs.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"gopatterns/singleflight"
+)
+
+// EvictReason tells an OnEvict hook why an entry left the cache.
+type EvictReason int
+
+const (
+	Expired  EvictReason = iota // the entry's TTL elapsed
+	Capacity                    // the cache was full and the entry was least recently used
+	Removed                     // Delete or Purge was called
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case Expired:
+		return "expired"
+	case Capacity:
+		return "capacity"
+	case Removed:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Options configures a Cache.
+type Options[K comparable, V any] struct {
+	// MaxEntries bounds the cache size; 0 means unbounded.
+	MaxEntries int
+	// TTL is the default lifetime of an entry; 0 means entries never expire.
+	TTL time.Duration
+	// OnEvict, if set, is called after an entry is evicted. It runs without
+	// the cache lock held, so it may call back into the cache.
+	OnEvict func(key K, value V, reason EvictReason)
+	// Now overrides the clock, mainly for tests.
+	Now func() time.Time
+}
+
+// Stats are cumulative counters since the cache was created.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// Cache is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	opts Options[K, V]
+
+	mu    sync.Mutex
+	ll    *list.List // front = most recently used
+	items map[K]*list.Element
+	stats Stats
+
+	loads singleflight.Group[K, V]
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // zero = never
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// New returns an empty cache.
+func New[K comparable, V any](opts Options[K, V]) *Cache[K, V] {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Cache[K, V]{
+		opts:  opts,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+	}
+}
+
+// Get returns the live value for key and marks it recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	var evicted []eviction[K, V]
+	defer func() { c.notify(evicted) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if c.expired(e) {
+		evicted = append(evicted, c.remove(el, Expired))
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// GetOrLoad returns the cached value for key, calling load on a miss and
+// caching its result. Concurrent misses for the same key share one load.
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	v, err, _ := c.loads.Do(key, func() (V, error) {
+		v, err := load()
+		if err == nil {
+			c.Set(key, v)
+		}
+		return v, err
+	})
+	return v, err
+}
+
+// Set stores value under key with the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL stores value under key for ttl; 0 means no expiry.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var evicted []eviction[K, V]
+	defer func() { c.notify(evicted) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.opts.Now().Add(ttl)
+	}
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expires = value, expires
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+
+	for c.opts.MaxEntries > 0 && c.ll.Len() > c.opts.MaxEntries {
+		evicted = append(evicted, c.remove(c.ll.Back(), Capacity))
+	}
+}
+
+// Delete removes key, reporting whether it was present.
+func (c *Cache[K, V]) Delete(key K) bool {
+	var evicted []eviction[K, V]
+	defer func() { c.notify(evicted) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if ok {
+		evicted = append(evicted, c.remove(el, Removed))
+	}
+	return ok
+}
+
+// DeleteExpired drops every expired entry and returns how many were removed.
+func (c *Cache[K, V]) DeleteExpired() int {
+	var evicted []eviction[K, V]
+	defer func() { c.notify(evicted) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if c.expired(el.Value.(*entry[K, V])) {
+			evicted = append(evicted, c.remove(el, Expired))
+		}
+		el = prev
+	}
+	return len(evicted)
+}
+
+// Purge removes every entry.
+func (c *Cache[K, V]) Purge() {
+	var evicted []eviction[K, V]
+	defer func() { c.notify(evicted) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.ll.Back(); el != nil; el = c.ll.Back() {
+		evicted = append(evicted, c.remove(el, Removed))
+	}
+}
+
+// Len returns the number of entries, including ones that expired but have
+// not been collected yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Entries = c.ll.Len()
+	return s
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expires.IsZero() && !c.opts.Now().Before(e.expires)
+}
+
+// remove unlinks el; the caller must hold c.mu.
+func (c *Cache[K, V]) remove(el *list.Element, reason EvictReason) eviction[K, V] {
+	e := c.ll.Remove(el).(*entry[K, V])
+	delete(c.items, e.key)
+	if reason != Removed {
+		c.stats.Evictions++
+	}
+	return eviction[K, V]{key: e.key, value: e.value, reason: reason}
+}
+
+func (c *Cache[K, V]) notify(evicted []eviction[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, ev := range evicted {
+		c.opts.OnEvict(ev.key, ev.value, ev.reason)
+	}
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNow is a clock the tests move by hand.
+type fakeNow struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (f *fakeNow) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.t
+}
+
+func (f *fakeNow) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.t = f.t.Add(d)
+	f.mu.Unlock()
+}
+
+func TestEntriesExpire(t *testing.T) {
+	clock := &fakeNow{t: time.Unix(0, 0)}
+	var reasons []EvictReason
+	c := New(Options[string, int]{
+		TTL:     time.Minute,
+		Now:     clock.Now,
+		OnEvict: func(_ string, _ int, r EvictReason) { reasons = append(reasons, r) },
+	})
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, 0) // never expires
+	c.SetWithTTL("c", 3, 2*time.Minute)
+
+	clock.Advance(59 * time.Second)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) before TTL = %d, %v", v, ok)
+	}
+	clock.Advance(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get(a) at TTL still hit")
+	}
+	if n := c.DeleteExpired(); n != 0 {
+		t.Fatalf("DeleteExpired = %d, want 0", n)
+	}
+	clock.Advance(time.Minute)
+	if n := c.DeleteExpired(); n != 1 {
+		t.Fatalf("DeleteExpired = %d, want 1", n)
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) without TTL = %d, %v", v, ok)
+	}
+	if len(reasons) != 2 || reasons[0] != Expired || reasons[1] != Expired {
+		t.Fatalf("evictions = %v, want two expired", reasons)
+	}
+	if s := c.Stats(); s.Entries != 1 || s.Evictions != 2 || s.Hits != 2 || s.Misses != 1 {
+		t.Fatalf("Stats = %+v", s)
+	}
+}
+
+func TestLeastRecentlyUsedIsEvicted(t *testing.T) {
+	type eviction struct {
+		key    string
+		reason EvictReason
+	}
+	var evicted []eviction
+	c := New(Options[string, int]{
+		MaxEntries: 2,
+		OnEvict:    func(k string, _ int, r EvictReason) { evicted = append(evicted, eviction{k, r}) },
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b was kept over more recently used entries")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Fatalf("%s was evicted", k)
+		}
+	}
+	c.Delete("a")
+	c.Purge()
+	want := []eviction{{"b", Capacity}, {"a", Removed}, {"c", Removed}}
+	if len(evicted) != len(want) {
+		t.Fatalf("evicted %v, want %v", evicted, want)
+	}
+	for i := range want {
+		if evicted[i] != want[i] {
+			t.Fatalf("evicted %v, want %v", evicted, want)
+		}
+	}
+	if s := c.Stats(); s.Evictions != 1 || s.Entries != 0 {
+		t.Fatalf("Stats = %+v, want 1 eviction and no entries", s)
+	}
+}
+
+func TestConcurrentGetSet(t *testing.T) {
+	c := New(Options[int, string]{MaxEntries: 64})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				k := (g*1000 + i) % 100
+				c.Set(k, strconv.Itoa(k))
+				if v, ok := c.Get(k); ok && v != strconv.Itoa(k) {
+					t.Errorf("Get(%d) = %q", k, v)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := c.Len(); n > 64 {
+		t.Fatalf("Len = %d, above MaxEntries 64", n)
+	}
+}
+
+func TestGetOrLoadSharesOneLoad(t *testing.T) {
+	c := New(Options[string, int]{})
+	var (
+		mu    sync.Mutex
+		loads int
+		wg    sync.WaitGroup
+	)
+	release := make(chan struct{})
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad("k", func() (int, error) {
+				mu.Lock()
+				loads++
+				mu.Unlock()
+				<-release
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("GetOrLoad = %d, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if loads != 1 {
+		t.Fatalf("loads = %d, want 1", loads)
+	}
+	if v, ok := c.Get("k"); !ok || v != 42 {
+		t.Fatalf("Get after load = %d, %v", v, ok)
+	}
+}
@@ -0,0 +1,30 @@
+// Command gopatterns bundles every example in this repository into a single
+// binary with one subcommand per example.
+package main
+
+import (
+	"os"
+
+	"gopatterns/internal/cli"
+	"gopatterns/internal/demos/channels"
+	"gopatterns/internal/demos/dbcalls"
+	"gopatterns/internal/demos/interfaces"
+	"gopatterns/internal/demos/workers"
+	"gopatterns/task-manager/taskcli"
+)
+
+func main() {
+	root := &cli.Command{
+		Name:    "gopatterns",
+		Summary: "Go concurrency and design patterns, one subcommand per example",
+		Subcommands: []*cli.Command{
+			taskcli.Root(),
+			workers.Command(),
+			channels.Command(),
+			interfaces.Command(),
+			dbcalls.Command(),
+		},
+	}
+	root.Subcommands = append(root.Subcommands, cli.CompletionCommands(root)...)
+	os.Exit(cli.Main(root))
+}
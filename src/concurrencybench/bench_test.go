@@ -0,0 +1,18 @@
+package concurrencybench
+
+import "testing"
+
+// BenchmarkStrategies runs every strategy under every mix, e.g.
+//
+//	go test -bench . -cpu 1,4,8 ./concurrencybench
+func BenchmarkStrategies(b *testing.B) {
+	for _, mix := range Mixes {
+		for _, st := range Strategies {
+			b.Run(mix.Name+"/"+st.Name, func(b *testing.B) {
+				s := st.New()
+				defer s.Close()
+				Run(b, s, mix)
+			})
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package concurrencybench
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"text/tabwriter"
+)
+
+// Keys is the size of the key space every workload touches.
+const Keys = 1024
+
+// Mix is a workload: WritePercent of operations are Sets, the rest Gets.
+type Mix struct {
+	Name         string
+	WritePercent int
+}
+
+// Mixes are the read/write ratios compared by Report and the benchmarks.
+var Mixes = []Mix{
+	{"read-only", 0},
+	{"read-heavy", 10},
+	{"balanced", 50},
+	{"write-heavy", 90},
+}
+
+// Run drives s with the mix from b.RunParallel goroutines.
+func Run(b *testing.B, s Store, mix Mix) {
+	for k := 0; k < Keys; k++ {
+		s.Set(k, k)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			k := (i * 7919) % Keys
+			if i%100 < mix.WritePercent {
+				s.Set(k, i)
+			} else {
+				s.Get(k)
+			}
+			i++
+		}
+	})
+}
+
+// Report benchmarks every strategy under every mix and writes a ns/op table
+// to w, one row per strategy and one column per mix.
+func Report(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "strategy\t")
+	for _, mix := range Mixes {
+		fmt.Fprintf(tw, "%s (%d%% w)\t", mix.Name, mix.WritePercent)
+	}
+	fmt.Fprintln(tw)
+
+	for _, st := range Strategies {
+		fmt.Fprintf(tw, "%s\t", st.Name)
+		for _, mix := range Mixes {
+			s := st.New()
+			res := testing.Benchmark(func(b *testing.B) { Run(b, s, mix) })
+			s.Close()
+			fmt.Fprintf(tw, "%d ns/op\t", res.NsPerOp())
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,156 @@
+// Package concurrencybench measures the usual ways of sharing a map between
+// goroutines, so the trade-offs described in the parallel demo are backed by
+// numbers from the machine you are actually running on.
+package concurrencybench
+
+import (
+	"sync"
+
+	"gopatterns/syncx"
+)
+
+// Store is the minimal key/value surface every strategy implements.
+type Store interface {
+	Get(key int) (int, bool)
+	Set(key, value int)
+	Close()
+}
+
+// Strategy names a Store implementation.
+type Strategy struct {
+	Name string
+	New  func() Store
+}
+
+// Strategies lists every implementation under test.
+var Strategies = []Strategy{
+	{"mutex", func() Store { return &mutexStore{m: make(map[int]int)} }},
+	{"rwmutex", func() Store { return &rwMutexStore{m: make(map[int]int)} }},
+	{"channel", newChannelStore},
+	{"sync.Map", func() Store { return &syncMapStore{} }},
+	{"sharded", func() Store { return &shardedStore{m: syncx.NewMap[int, int](0)} }},
+}
+
+// mutexStore guards a plain map with sync.Mutex: every access is exclusive.
+type mutexStore struct {
+	mu sync.Mutex
+	m  map[int]int
+}
+
+func (s *mutexStore) Get(k int) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[k]
+	return v, ok
+}
+
+func (s *mutexStore) Set(k, v int) {
+	s.mu.Lock()
+	s.m[k] = v
+	s.mu.Unlock()
+}
+
+func (s *mutexStore) Close() {}
+
+// rwMutexStore lets readers proceed in parallel with RLock.
+type rwMutexStore struct {
+	mu sync.RWMutex
+	m  map[int]int
+}
+
+func (s *rwMutexStore) Get(k int) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[k]
+	return v, ok
+}
+
+func (s *rwMutexStore) Set(k, v int) {
+	s.mu.Lock()
+	s.m[k] = v
+	s.mu.Unlock()
+}
+
+func (s *rwMutexStore) Close() {}
+
+// channelStore owns the map in a single goroutine and serves requests sent
+// over a channel ("share memory by communicating").
+type channelStore struct {
+	reqs chan channelReq
+	done chan struct{}
+}
+
+type channelReq struct {
+	key, value int
+	write      bool
+	reply      chan channelReply
+}
+
+type channelReply struct {
+	value int
+	ok    bool
+}
+
+func newChannelStore() Store {
+	s := &channelStore{reqs: make(chan channelReq), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		m := make(map[int]int)
+		for req := range s.reqs {
+			if req.write {
+				m[req.key] = req.value
+				req.reply <- channelReply{}
+				continue
+			}
+			v, ok := m[req.key]
+			req.reply <- channelReply{v, ok}
+		}
+	}()
+	return s
+}
+
+var replyPool = sync.Pool{New: func() any { return make(chan channelReply, 1) }}
+
+func (s *channelStore) do(req channelReq) channelReply {
+	req.reply = replyPool.Get().(chan channelReply)
+	s.reqs <- req
+	r := <-req.reply
+	replyPool.Put(req.reply)
+	return r
+}
+
+func (s *channelStore) Get(k int) (int, bool) {
+	r := s.do(channelReq{key: k})
+	return r.value, r.ok
+}
+
+func (s *channelStore) Set(k, v int) { s.do(channelReq{key: k, value: v, write: true}) }
+
+func (s *channelStore) Close() {
+	close(s.reqs)
+	<-s.done
+}
+
+// syncMapStore uses sync.Map, tuned for stable keys read far more than written.
+type syncMapStore struct{ m sync.Map }
+
+func (s *syncMapStore) Get(k int) (int, bool) {
+	v, ok := s.m.Load(k)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (s *syncMapStore) Set(k, v int) { s.m.Store(k, v) }
+
+func (s *syncMapStore) Close() {}
+
+// shardedStore spreads keys over independently locked shards.
+type shardedStore struct{ m *syncx.Map[int, int] }
+
+func (s *shardedStore) Get(k int) (int, bool) { return s.m.Load(k) }
+
+func (s *shardedStore) Set(k, v int) { s.m.Store(k, v) }
+
+func (s *shardedStore) Close() {}
@@ -0,0 +1,436 @@
+// Package cli is the small command framework shared by every binary in this
+// repository: nested subcommands, flags that may follow positional
+// arguments, per-command help, environment-backed flag defaults and a
+// structured logger configured from the command line.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Env carries the process I/O and logger into commands, so commands never
+// touch os.Stdout directly and can be driven from tests or other programs.
+type Env struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Log    *slog.Logger
+	Getenv func(string) string
+
+	// Translate, if set, translates the text this package prints: help,
+	// its headings and the package's own usage errors. Execute sets it
+	// from Command.Translate.
+	Translate func(string) string
+}
+
+// NewEnv returns an Env wired to the real process.
+func NewEnv() *Env {
+	return &Env{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Log:    slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		Getenv: os.Getenv,
+	}
+}
+
+// Command is a node in the command tree. A command either has Subcommands
+// or a Run function.
+type Command struct {
+	Name    string
+	Aliases []string // alternative names accepted on the command line
+	Summary string   // one line, shown in the parent's command list
+	Usage   string   // argument synopsis, e.g. "<id> [--force]"
+	Help    string   // optional longer description
+
+	// Flags registers the command's flags. For commands with Subcommands
+	// these are parsed before the subcommand name; for leaf commands they
+	// may appear anywhere among the arguments.
+	Flags func(fs *flag.FlagSet)
+
+	// Raw passes the arguments to Run untouched, for commands that do
+	// their own parsing.
+	Raw bool
+
+	// DashArgs makes arguments that start with "-" but name none of the
+	// command's flags positional instead of an error, for commands whose
+	// arguments use that syntax themselves ("task modify 3 -waiting").
+	DashArgs bool
+
+	// Hidden leaves the command out of its parent's command list.
+	Hidden bool
+
+	// Translate, if set, translates the help of the command and of its
+	// subcommands. It is called only when help is printed, after the
+	// flags before the help request have been parsed. Optional.
+	Translate func(env *Env, msg string) string
+
+	// Complete returns shell completion candidates: values for the named
+	// flag, or for the next positional argument when flag is "" (args are
+	// the positional arguments typed so far). Candidates may append a tab
+	// and a description. Optional.
+	Complete func(ctx context.Context, env *Env, flag string, args []string) []string
+
+	Subcommands []*Command
+	Run         func(ctx context.Context, env *Env, args []string) error
+
+	// Expand is consulted, once the command's flags are parsed, for a
+	// subcommand name that matches none of Subcommands. It returns the
+	// command line to run instead, such as a user-defined alias's
+	// expansion followed by args, or ok false if name means nothing.
+	// Optional; the expansion is not expanded again.
+	Expand func(ctx context.Context, env *Env, name string, args []string) (expanded []string, ok bool, err error)
+}
+
+// UsageError reports a malformed command line. Main prints the command's
+// usage after it and exits with status 2.
+type UsageError struct {
+	Msg   string
+	Usage string // synopsis of the failing command, filled in by Execute
+	Err   error  // the error Msg describes, if any
+}
+
+func (e *UsageError) Error() string { return e.Msg }
+
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// Usagef returns a UsageError.
+func Usagef(format string, args ...any) error {
+	return &UsageError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// ExitError makes Main exit with Code. Err is printed like any other
+// error; a nil Err means the command has already reported the failure in
+// its own way.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// Main runs root with the process arguments and returns the exit status.
+func Main(root *Command) int {
+	env := NewEnv()
+	err := Execute(context.Background(), env, root, os.Args[1:])
+	return Report(env, root.Name, err)
+}
+
+// Report prints err the way every binary in the repo does and maps it to an
+// exit status: 0 for success or -h, 2 for usage errors, an ExitError's
+// Code, 1 otherwise.
+func Report(env *Env, prog string, err error) int {
+	var (
+		usage *UsageError
+		exit  *ExitError
+	)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &exit):
+		if exit.Err != nil {
+			fmt.Fprintf(env.Stderr, "%s: %v\n", prog, exit.Err)
+		}
+		return exit.Code
+	case errors.As(err, &usage):
+		fmt.Fprintf(env.Stderr, "%s: %v\n", prog, err)
+		if usage.Usage != "" {
+			fmt.Fprintf(env.Stderr, env.T("usage: %s\n"), usage.Usage)
+		}
+		return 2
+	default:
+		fmt.Fprintf(env.Stderr, "%s: %v\n", prog, err)
+		return 1
+	}
+}
+
+// Execute parses args for cmd and runs it, descending into subcommands.
+// The root command also accepts the shared logging flags.
+func Execute(ctx context.Context, env *Env, cmd *Command, args []string) error {
+	return execute(ctx, env, cmd, cmd.Name, args, true)
+}
+
+func execute(ctx context.Context, env *Env, cmd *Command, path string, args []string, root bool) error {
+	if cmd.Translate != nil {
+		env.Translate = func(msg string) string { return cmd.Translate(env, msg) }
+	}
+	if cmd.Raw {
+		return cmd.Run(ctx, env, args)
+	}
+
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() { PrintUsage(env, env.Stderr, cmd, path, fs) }
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	var logOpts logOptions
+	if root {
+		logOpts.register(fs, env)
+	}
+
+	if len(cmd.Subcommands) == 0 {
+		parse := ParseInterspersed
+		if cmd.DashArgs {
+			parse = parseDashArgs
+		}
+		rest, err := parse(fs, args)
+		if err != nil {
+			return err
+		}
+		if root {
+			if err := logOpts.apply(env); err != nil {
+				return err
+			}
+		}
+		return withUsage(cmd.Run(ctx, env, rest), cmd, path)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if root {
+		if err := logOpts.apply(env); err != nil {
+			return err
+		}
+	}
+	rest := fs.Args()
+	if len(rest) == 0 {
+		PrintUsage(env, env.Stderr, cmd, path, fs)
+		return env.usagef("missing command")
+	}
+	name, rest := rest[0], rest[1:]
+	if name == "help" {
+		return help(env, cmd, path, fs, rest)
+	}
+	sub := Lookup(cmd, name)
+	if sub == nil && cmd.Expand != nil {
+		expanded, ok, err := cmd.Expand(ctx, env, name, rest)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if len(expanded) == 0 {
+				return env.usagef("%q expands to nothing", name)
+			}
+			if sub = Lookup(cmd, expanded[0]); sub == nil {
+				return env.usagef("%q expands to unknown command %q", name, expanded[0])
+			}
+			rest = expanded[1:]
+		}
+	}
+	if sub == nil {
+		return env.usagef("unknown command %q (run '%s help')", name, path)
+	}
+	return execute(ctx, env, sub, path+" "+sub.Name, rest, false)
+}
+
+// withUsage attaches the command synopsis to usage errors from Run.
+func withUsage(err error, cmd *Command, path string) error {
+	var usage *UsageError
+	if errors.As(err, &usage) && usage.Usage == "" {
+		usage.Usage = strings.TrimSpace(path + " " + cmd.Usage)
+	}
+	return err
+}
+
+// Lookup finds the direct subcommand of cmd called name.
+func Lookup(cmd *Command, name string) *Command {
+	for _, sub := range cmd.Subcommands {
+		if sub.Name == name || slices.Contains(sub.Aliases, name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+func help(env *Env, cmd *Command, path string, fs *flag.FlagSet, args []string) error {
+	for _, name := range args {
+		sub := Lookup(cmd, name)
+		if sub == nil {
+			return env.usagef("unknown command %q", name)
+		}
+		cmd, path = sub, path+" "+sub.Name
+		fs = flag.NewFlagSet(path, flag.ContinueOnError)
+		if cmd.Flags != nil {
+			cmd.Flags(fs)
+		}
+		if cmd.Raw {
+			// Raw commands print their own help.
+			return cmd.Run(context.Background(), env, []string{"-h"})
+		}
+	}
+	PrintUsage(env, env.Stdout, cmd, path, fs)
+	return nil
+}
+
+// PrintUsage writes the help text for cmd, translated by env.Translate.
+func PrintUsage(env *Env, w io.Writer, cmd *Command, path string, fs *flag.FlagSet) {
+	synopsis := cmd.Usage
+	if synopsis == "" && len(cmd.Subcommands) > 0 {
+		synopsis = env.T("[flags] <command> [args]")
+	}
+	fmt.Fprintf(w, env.T("Usage: %s %s\n"), path, synopsis)
+	if len(cmd.Aliases) > 0 {
+		fmt.Fprintf(w, env.T("Aliases: %s\n"), strings.Join(cmd.Aliases, ", "))
+	}
+	if cmd.Summary != "" {
+		fmt.Fprintf(w, "\n%s\n", env.T(cmd.Summary))
+	}
+	if cmd.Help != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(env.T(cmd.Help)))
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		fmt.Fprintln(w, env.T("\nCommands:"))
+		subs := append([]*Command(nil), cmd.Subcommands...)
+		sort.Slice(subs, func(i, j int) bool { return subs[i].Name < subs[j].Name })
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range subs {
+			if !sub.Hidden {
+				fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, env.T(sub.Summary))
+			}
+		}
+		tw.Flush()
+	}
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(w, env.T("\nFlags:"))
+		fs.VisitAll(func(f *flag.Flag) { f.Usage = env.T(f.Usage) })
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+	if len(cmd.Subcommands) > 0 {
+		fmt.Fprintf(w, env.T("\nRun '%s help <command>' for details on a command.\n"), path)
+	}
+}
+
+// ParseInterspersed parses flags that may appear before, between or after
+// positional arguments, which the flag package alone does not allow.
+// Everything after a literal "--" is treated as positional.
+func ParseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// parseDashArgs is ParseInterspersed for commands with DashArgs set: the
+// arguments between unknown dash words are parsed as usual and the words
+// themselves kept, in order, among the positional arguments.
+func parseDashArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for len(args) > 0 {
+		i := 0
+		for i < len(args) && args[i] != "--" && !unknownFlag(fs, args[i]) {
+			i++
+		}
+		rest, err := ParseInterspersed(fs, args[:i])
+		if err != nil {
+			return nil, err
+		}
+		positional = append(positional, rest...)
+		switch {
+		case i == len(args):
+			return positional, nil
+		case args[i] == "--":
+			return append(positional, args[i+1:]...), nil
+		}
+		positional = append(positional, args[i])
+		args = args[i+1:]
+	}
+	return positional, nil
+}
+
+// unknownFlag reports whether arg looks like a flag that fs does not define.
+// -h and -help are never unknown, so help keeps working.
+func unknownFlag(fs *flag.FlagSet, arg string) bool {
+	name, ok := strings.CutPrefix(arg, "-")
+	if !ok {
+		return false
+	}
+	name, _, _ = strings.Cut(strings.TrimPrefix(name, "-"), "=")
+	return name != "" && name != "h" && name != "help" && fs.Lookup(name) == nil
+}
+
+// StringList is a flag.Value collecting every occurrence of a repeated flag.
+type StringList []string
+
+func (s *StringList) String() string { return strings.Join(*s, ",") }
+
+// Set implements flag.Value.
+func (s *StringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// OptionalString is a string flag that remembers whether it was given, so
+// "not set" and "set to empty" can be told apart.
+type OptionalString struct {
+	Value string
+	IsSet bool
+}
+
+func (o *OptionalString) String() string { return o.Value }
+
+// Set implements flag.Value.
+func (o *OptionalString) Set(v string) error {
+	o.Value, o.IsSet = v, true
+	return nil
+}
+
+// T returns the translation of msg by e.Translate, or msg itself.
+func (e *Env) T(msg string) string {
+	if e.Translate == nil {
+		return msg
+	}
+	return e.Translate(msg)
+}
+
+// usagef is Usagef with format translated by e.Translate.
+func (e *Env) usagef(format string, args ...any) error {
+	return Usagef(e.T(format), args...)
+}
+
+// EnvString returns the value of the environment variable key, or def when
+// it is unset. Use it as a flag default so that flags override the
+// environment and the environment overrides built-in defaults.
+func (e *Env) EnvString(key, def string) string {
+	if e.Getenv != nil {
+		if v := e.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return def
+}
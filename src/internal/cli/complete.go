@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Shell completion works like this: the generated script calls the hidden
+// "__complete" command with the words typed so far (the last one possibly
+// empty), and prints the candidates it returns, one per line. A candidate
+// may carry a description after a tab, which zsh and fish display. Command
+// and flag names come from the command tree; argument and flag values come
+// from each command's Complete function.
+
+// CompletionCommands returns the "completion" command, which prints a
+// completion script for root, and the hidden "__complete" command the
+// script calls. Append them to root's Subcommands.
+func CompletionCommands(root *Command) []*Command {
+	return []*Command{
+		{
+			Name:    "completion",
+			Summary: "Print a shell completion script",
+			Usage:   "bash|zsh|fish",
+			Help: fmt.Sprintf(`Load the script in your shell's startup file, e.g.
+
+  bash: source <(%[1]s completion bash)
+  zsh:  source <(%[1]s completion zsh)
+  fish: %[1]s completion fish | source`, root.Name),
+			Complete: func(ctx context.Context, env *Env, flag string, args []string) []string {
+				if flag == "" && len(args) == 0 {
+					return []string{"bash", "zsh", "fish"}
+				}
+				return nil
+			},
+			Run: func(ctx context.Context, env *Env, args []string) error {
+				if len(args) != 1 {
+					return Usagef("completion takes exactly one shell name")
+				}
+				return WriteCompletion(env.Stdout, root.Name, args[0])
+			},
+		},
+		{
+			Name:   "__complete",
+			Hidden: true,
+			Raw:    true,
+			Run: func(ctx context.Context, env *Env, args []string) error {
+				for _, c := range Complete(ctx, env, root, args) {
+					fmt.Fprintln(env.Stdout, c)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Complete returns the candidates for the last of words, which are the
+// command-line words after the program name.
+func Complete(ctx context.Context, env *Env, root *Command, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	args, cur := words[:len(words)-1], words[len(words)-1]
+	// Commands may log while opening stores; keep that off the terminal.
+	quiet := *env
+	quiet.Stderr = io.Discard
+
+	cmd, isRoot := root, true
+	for len(cmd.Subcommands) > 0 && !cmd.Raw {
+		fs := completionFlags(&quiet, cmd, isRoot)
+		i, pending := scanFlags(fs, args, false)
+		// Apply the parent's flags (a -data-dir, say) so Complete functions
+		// further down see them.
+		fs.Parse(args[:i])
+		switch {
+		case pending != "":
+			return filter(callComplete(ctx, &quiet, cmd, pending, nil), cur)
+		case i == len(args):
+			if strings.HasPrefix(cur, "-") {
+				return filter(flagNames(fs), cur)
+			}
+			var names []string
+			for _, sub := range cmd.Subcommands {
+				if !sub.Hidden {
+					names = append(names, sub.Name+"\t"+sub.Summary)
+				}
+			}
+			return filter(names, cur)
+		}
+		sub := Lookup(cmd, args[i])
+		if sub == nil {
+			return nil
+		}
+		cmd, args, isRoot = sub, args[i+1:], false
+	}
+	if cmd.Raw {
+		return nil
+	}
+
+	fs := completionFlags(&quiet, cmd, isRoot)
+	_, pending := scanFlags(fs, args, true)
+	if pending != "" {
+		return filter(callComplete(ctx, &quiet, cmd, pending, nil), cur)
+	}
+	if strings.HasPrefix(cur, "-") {
+		return filter(flagNames(fs), cur)
+	}
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if isFlag(a) {
+			if f := fs.Lookup(flagName(a)); f != nil && !isBoolFlag(f) && !strings.Contains(a, "=") {
+				i++
+			}
+			continue
+		}
+		positional = append(positional, a)
+	}
+	return filter(callComplete(ctx, &quiet, cmd, "", positional), cur)
+}
+
+func callComplete(ctx context.Context, env *Env, cmd *Command, flag string, args []string) []string {
+	if cmd.Complete == nil {
+		return nil
+	}
+	return cmd.Complete(ctx, env, flag, args)
+}
+
+func completionFlags(env *Env, cmd *Command, root bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	if root {
+		var o logOptions
+		o.register(fs, env)
+	}
+	return fs
+}
+
+// scanFlags walks args the way the flag package would. It returns the index
+// of the first positional argument (len(args) if none) and, if the last
+// word is a flag still waiting for its value, that flag's name. With
+// interspersed set it keeps going past positional arguments.
+func scanFlags(fs *flag.FlagSet, args []string, interspersed bool) (int, string) {
+	first := -1
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			if first < 0 {
+				first = i + 1
+			}
+			break
+		}
+		if !isFlag(a) {
+			if !interspersed {
+				return i, ""
+			}
+			if first < 0 {
+				first = i
+			}
+			continue
+		}
+		f := fs.Lookup(flagName(a))
+		if f == nil || isBoolFlag(f) || strings.Contains(a, "=") {
+			continue
+		}
+		if i == len(args)-1 {
+			return len(args), f.Name
+		}
+		i++
+	}
+	if first < 0 || first > len(args) {
+		first = len(args)
+	}
+	return first, ""
+}
+
+func isFlag(arg string) bool { return len(arg) > 1 && arg[0] == '-' }
+
+func flagName(arg string) string {
+	name := strings.TrimLeft(arg, "-")
+	name, _, _ = strings.Cut(name, "=")
+	return name
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func flagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, "-"+f.Name+"\t"+f.Usage) })
+	return names
+}
+
+// filter keeps the candidates whose value (the part before any tab) starts
+// with prefix. Flags match with one or two leading dashes.
+func filter(candidates []string, prefix string) []string {
+	if strings.HasPrefix(prefix, "--") {
+		prefix = prefix[1:]
+	}
+	var out []string
+	for _, c := range candidates {
+		value, _, _ := strings.Cut(c, "\t")
+		if strings.HasPrefix(value, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// WriteCompletion writes the completion script for program to w.
+func WriteCompletion(w io.Writer, program, shell string) error {
+	fn := "_" + nonIdent.ReplaceAllString(program, "_") + "_complete"
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return Usagef("unsupported shell %q (want bash, zsh or fish)", shell)
+	}
+	r := strings.NewReplacer("PROG", program, "FUNC", fn)
+	_, err := io.WriteString(w, r.Replace(script))
+	return err
+}
+
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+const bashCompletion = `# bash completion for PROG
+FUNC() {
+    local IFS=$'\n' line
+    local -a out
+    out=($(PROG __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+    COMPREPLY=()
+    for line in "${out[@]}"; do
+        COMPREPLY+=("${line%%$'\t'*}")
+    done
+}
+complete -o default -F FUNC PROG
+`
+
+const zshCompletion = `#compdef PROG
+# zsh completion for PROG
+FUNC() {
+    local line
+    local -a candidates
+    for line in "${(@f)$(PROG __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        if [[ $line == *$'\t'* ]]; then
+            candidates+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            candidates+=("${line//:/\\:}")
+        fi
+    done
+    if (( ${#candidates} )); then
+        _describe -t values 'PROG' candidates
+    else
+        _files
+    fi
+}
+if [[ $funcstack[1] == FUNC ]]; then
+    FUNC "$@"
+else
+    compdef FUNC PROG
+fi
+`
+
+const fishCompletion = `# fish completion for PROG
+function FUNC
+    set -l tokens (commandline -opc)
+    set -l cur (commandline -ct)
+    PROG __complete $tokens[2..-1] "$cur" 2>/dev/null
+end
+complete -c PROG -f -a '(FUNC)'
+`
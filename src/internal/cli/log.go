@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"flag"
+	"log/slog"
+	"strings"
+)
+
+// logOptions are the logging flags every root command accepts.
+type logOptions struct {
+	level  string
+	format string
+}
+
+func (o *logOptions) register(fs *flag.FlagSet, env *Env) {
+	fs.StringVar(&o.level, "log-level", env.EnvString("GOPATTERNS_LOG_LEVEL", "warn"), "log level: debug, info, warn or error (env GOPATTERNS_LOG_LEVEL)")
+	fs.StringVar(&o.format, "log-format", env.EnvString("GOPATTERNS_LOG_FORMAT", "text"), "log format: text or json (env GOPATTERNS_LOG_FORMAT)")
+}
+
+func (o *logOptions) apply(env *Env) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(o.level)); err != nil {
+		return Usagef("invalid -log-level %q", o.level)
+	}
+	hopts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(o.format) {
+	case "text":
+		env.Log = slog.New(slog.NewTextHandler(env.Stderr, hopts))
+	case "json":
+		env.Log = slog.New(slog.NewJSONHandler(env.Stderr, hopts))
+	default:
+		return Usagef("invalid -log-format %q", o.format)
+	}
+	return nil
+}
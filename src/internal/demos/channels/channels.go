@@ -1,13 +1,18 @@
-package main
+package channels
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"time"
+
+	"gopatterns/internal/cli"
 )
 
 // Demo 1: Basic Channel Communication
 func basicChannels() {
-	fmt.Println("=== Basic Channels ===")
+	fmt.Fprintln(out, "=== Basic Channels ===")
 
 	// Create a channel that can send/receive strings
 	messages := make(chan string)
@@ -19,12 +24,12 @@ func basicChannels() {
 
 	// Receive from channel (this blocks until we get a message)
 	msg := <-messages
-	fmt.Println("Received:", msg)
+	fmt.Fprintln(out, "Received:", msg)
 }
 
 // Demo 2: Buffered Channels
 func bufferedChannels() {
-	fmt.Println("\n=== Buffered Channels ===")
+	fmt.Fprintln(out, "\n=== Buffered Channels ===")
 
 	// Buffered channel can hold 2 values without blocking
 	numbers := make(chan int, 2)
@@ -32,16 +37,16 @@ func bufferedChannels() {
 	// We can send 2 values without a receiver
 	numbers <- 1
 	numbers <- 2
-	fmt.Println("Sent 2 numbers without blocking!")
+	fmt.Fprintln(out, "Sent 2 numbers without blocking!")
 
 	// Now receive them
-	fmt.Println("Received:", <-numbers)
-	fmt.Println("Received:", <-numbers)
+	fmt.Fprintln(out, "Received:", <-numbers)
+	fmt.Fprintln(out, "Received:", <-numbers)
 }
 
 // Demo 3: Worker Pattern
 func workerPattern() {
-	fmt.Println("\n=== Worker Pattern ===")
+	fmt.Fprintln(out, "\n=== Worker Pattern ===")
 
 	jobs := make(chan int, 5)
 	results := make(chan int, 5)
@@ -60,14 +65,14 @@ func workerPattern() {
 	// Collect results
 	for r := 1; r <= 5; r++ {
 		result := <-results
-		fmt.Printf("Result: %d\n", result)
+		fmt.Fprintf(out, "Result: %d\n", result)
 	}
 }
 
 // Worker function that processes jobs
 func worker(id int, jobs <-chan int, results chan<- int) {
 	for job := range jobs { // Range over channel until it's closed
-		fmt.Printf("Worker %d processing job %d\n", id, job)
+		fmt.Fprintf(out, "Worker %d processing job %d\n", id, job)
 		time.Sleep(time.Second) // Simulate work
 		results <- job * 2      // Send result
 	}
@@ -75,7 +80,7 @@ func worker(id int, jobs <-chan int, results chan<- int) {
 
 // Demo 4: Select Statement
 func selectDemo() {
-	fmt.Println("\n=== Select Statement ===")
+	fmt.Fprintln(out, "\n=== Select Statement ===")
 
 	c1 := make(chan string)
 	c2 := make(chan string)
@@ -95,18 +100,18 @@ func selectDemo() {
 	for i := 0; i < 2; i++ {
 		select {
 		case msg1 := <-c1:
-			fmt.Println("Got:", msg1)
+			fmt.Fprintln(out, "Got:", msg1)
 		case msg2 := <-c2:
-			fmt.Println("Got:", msg2)
+			fmt.Fprintln(out, "Got:", msg2)
 		case <-time.After(3 * time.Second):
-			fmt.Println("Timeout!")
+			fmt.Fprintln(out, "Timeout!")
 		}
 	}
 }
 
 // Demo 5: Pipeline Pattern
 func pipelineDemo() {
-	fmt.Println("\n=== Pipeline Pattern ===")
+	fmt.Fprintln(out, "\n=== Pipeline Pattern ===")
 
 	// Stage 1: Generate numbers
 	numbers := make(chan int)
@@ -128,13 +133,32 @@ func pipelineDemo() {
 
 	// Stage 3: Print results
 	for square := range squares {
-		fmt.Printf("Square: %d\n", square)
+		fmt.Fprintf(out, "Square: %d\n", square)
+	}
+}
+
+// out is where the demo prints; Command points it at the command's stdout.
+var out io.Writer = os.Stdout
+
+// Command returns the `channels` subcommand.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:    "channels",
+		Summary: "Walk through channels, buffering, select and pipelines",
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return cli.Usagef("channels takes no arguments")
+			}
+			out = env.Stdout
+			run()
+			return nil
+		},
 	}
 }
 
-func main() {
-	fmt.Println("🚀 Go Channels Demo - Concurrent Communication Made Easy!")
-	fmt.Println("========================================================")
+func run() {
+	fmt.Fprintln(out, "🚀 Go Channels Demo - Concurrent Communication Made Easy!")
+	fmt.Fprintln(out, "========================================================")
 
 	basicChannels()
 	bufferedChannels()
@@ -142,11 +166,11 @@ func main() {
 	selectDemo()
 	pipelineDemo()
 
-	fmt.Println("\n🎯 Key Points:")
-	fmt.Println("• Channels are Go's way for goroutines to communicate")
-	fmt.Println("• <- operator sends/receives data")
-	fmt.Println("• Channels block until both sender and receiver are ready")
-	fmt.Println("• Buffered channels can hold values without blocking")
-	fmt.Println("• close() tells receivers no more data is coming")
-	fmt.Println("• select {} lets you handle multiple channels at once")
+	fmt.Fprintln(out, "\n🎯 Key Points:")
+	fmt.Fprintln(out, "• Channels are Go's way for goroutines to communicate")
+	fmt.Fprintln(out, "• <- operator sends/receives data")
+	fmt.Fprintln(out, "• Channels block until both sender and receiver are ready")
+	fmt.Fprintln(out, "• Buffered channels can hold values without blocking")
+	fmt.Fprintln(out, "• close() tells receivers no more data is coming")
+	fmt.Fprintln(out, "• select {} lets you handle multiple channels at once")
 }
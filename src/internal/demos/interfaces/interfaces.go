@@ -1,6 +1,13 @@
-package main
+package interfaces
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"gopatterns/internal/cli"
+)
 
 // Step 1: Define an interface
 // An interface is just a list of methods that something can do
@@ -39,17 +46,36 @@ func (c Cow) Speak() string {
 // Step 3: Use the interface
 // This function works with ANY type that has a Speak() method
 func MakeSound(a Animal) {
-	fmt.Printf("The animal says: %s\n", a.Speak())
+	fmt.Fprintf(out, "The animal says: %s\n", a.Speak())
+}
+
+// out is where the demo prints; Command points it at the command's stdout.
+var out io.Writer = os.Stdout
+
+// Command returns the `interfaces` subcommand.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:    "interfaces",
+		Summary: "Show how Go interfaces are satisfied implicitly",
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return cli.Usagef("interfaces takes no arguments")
+			}
+			out = env.Stdout
+			run()
+			return nil
+		},
+	}
 }
 
-func main() {
+func run() {
 	// Create different animals
 	dog := Dog{Name: "Buddy"}
 	cat := Cat{Name: "Whiskers"}
 	cow := Cow{Name: "Bessie"}
 
 	// They all satisfy the Animal interface because they have Speak() method
-	fmt.Println("=== Interface Demo ===")
+	fmt.Fprintln(out, "=== Interface Demo ===")
 
 	MakeSound(dog) // Works!
 	MakeSound(cat) // Works!
@@ -58,11 +84,11 @@ func main() {
 	// You can also store them in a slice of Animal interface
 	animals := []Animal{dog, cat, cow}
 
-	fmt.Println("\n=== All animals speaking ===")
+	fmt.Fprintln(out, "\n=== All animals speaking ===")
 	for _, animal := range animals {
 		MakeSound(animal)
 	}
 
-	fmt.Println("\n🎯 Key Point: MakeSound() doesn't care what type of animal it is!")
-	fmt.Println("It just knows each animal can Speak() - that's the power of interfaces!")
+	fmt.Fprintln(out, "\n🎯 Key Point: MakeSound() doesn't care what type of animal it is!")
+	fmt.Fprintln(out, "It just knows each animal can Speak() - that's the power of interfaces!")
 }
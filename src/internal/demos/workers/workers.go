@@ -0,0 +1,304 @@
+package workers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"gopatterns/backoff"
+	"gopatterns/internal/cli"
+	"gopatterns/retry"
+	"gopatterns/workerpool"
+)
+
+// Job represents work to be done
+type Job struct {
+	ID   int
+	Data string
+}
+
+// Result represents the outcome of processing a job in the hand-rolled
+// rate-limited pool; workerpool reports outcomes as workerpool.Result.
+type Result struct {
+	Job    Job
+	Output string
+	Error  error
+}
+
+// Example 1: Basic Worker Pool
+func basicWorkerPool() {
+	fmt.Fprintln(out, "=== Basic Worker Pool ===")
+
+	// 3 workers, room for 10 queued jobs
+	pool := workerpool.New(3, func(ctx context.Context, job Job) (string, error) {
+		fmt.Fprintf(out, "Processing job %d\n", job.ID)
+
+		// Simulate work with random duration
+		time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
+		return fmt.Sprintf("Processed %s", job.Data), nil
+	}, workerpool.WithQueueSize[Job, string](10))
+
+	// Send 10 jobs, then let the pool drain
+	for j := 1; j <= 10; j++ {
+		pool.Submit(context.Background(), Job{ID: j, Data: fmt.Sprintf("task-%d", j)})
+	}
+	go pool.Shutdown(context.Background())
+
+	// Collect results until the pool closes the channel
+	for result := range pool.Results() {
+		if result.Err != nil {
+			fmt.Fprintf(out, "❌ Job %d failed: %v\n", result.Job.ID, result.Err)
+		} else {
+			fmt.Fprintf(out, "✅ Job %d completed: %s\n", result.Job.ID, result.Value)
+		}
+	}
+}
+
+// Example 2: Worker Pool with Error Handling
+func workerPoolWithErrors() {
+	fmt.Fprintln(out, "\n=== Worker Pool with Error Handling ===")
+
+	pool := workerpool.New(2, func(ctx context.Context, job Job) (string, error) {
+		time.Sleep(500 * time.Millisecond)
+
+		// Simulate random failures (30% chance)
+		if rand.Intn(10) < 3 {
+			return "", fmt.Errorf("%s: random failure", job.Data)
+		}
+		return "Successfully processed", nil
+	},
+		workerpool.WithQueueSize[Job, string](8),
+		// Try each job twice, and keep the ones that fail both times
+		workerpool.WithRetry[Job, string](retry.Attempts(2), retry.WithBackoff(backoff.Constant{Delay: 100 * time.Millisecond})),
+		workerpool.WithDeadLetters[Job, string](8))
+
+	// Send jobs (some will fail)
+	for j := 1; j <= 8; j++ {
+		pool.Submit(context.Background(), Job{ID: j, Data: fmt.Sprintf("data-%d", j)})
+	}
+	go pool.Shutdown(context.Background())
+
+	// Collect results and handle errors
+	successCount := 0
+	errorCount := 0
+
+	for result := range pool.Results() {
+		if result.Err != nil {
+			fmt.Fprintf(out, "❌ Job %d failed: %v\n", result.Job.ID, result.Err)
+			errorCount++
+		} else {
+			fmt.Fprintf(out, "✅ Job %d: %s\n", result.Job.ID, result.Value)
+			successCount++
+		}
+	}
+
+	fmt.Fprintf(out, "Summary: %d successful, %d failed\n", successCount, errorCount)
+
+	// Failed jobs wait in the dead letters for inspection or Redrive
+	for _, d := range pool.DeadLetters() {
+		fmt.Fprintf(out, "🪦 Job %d failed %d times, last: %v\n", d.Job.ID, len(d.Errors), d.Err())
+	}
+}
+
+// Example 3: Worker Pool with Context and Graceful Shutdown
+func workerPoolWithContext() {
+	fmt.Fprintln(out, "\n=== Worker Pool with Graceful Shutdown ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	pool := workerpool.New(2, func(ctx context.Context, job Job) (string, error) {
+		// Simulate work that stops early if the pool gives up on it
+		select {
+		case <-time.After(800 * time.Millisecond):
+			return "Completed", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, workerpool.WithQueueSize[Job, string](2))
+
+	// Send jobs continuously until the context is cancelled
+	go func() {
+		for j := 1; ; j++ {
+			if err := pool.Submit(ctx, Job{ID: j, Data: fmt.Sprintf("urgent-task-%d", j)}); err != nil {
+				fmt.Fprintln(out, "📤 Stopping job sender...")
+				return
+			}
+			fmt.Fprintf(out, "📤 Sent job %d\n", j)
+			time.Sleep(200 * time.Millisecond)
+		}
+	}()
+
+	// Stop when the context is done, giving running jobs a moment to finish
+	go func() {
+		<-ctx.Done()
+		fmt.Fprintln(out, "🛑 Context cancelled, waiting for workers to finish...")
+		grace, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := pool.Shutdown(grace); err != nil {
+			fmt.Fprintf(out, "🛑 Gave up waiting: %v\n", err)
+		}
+	}()
+
+	// Collect results until the pool has shut down
+	for result := range pool.Results() {
+		if result.Err != nil {
+			fmt.Fprintf(out, "❌ Job %d error: %v\n", result.Job.ID, result.Err)
+		} else {
+			fmt.Fprintf(out, "✅ Job %d: %s\n", result.Job.ID, result.Value)
+		}
+	}
+	fmt.Fprintln(out, "✅ All workers stopped gracefully")
+}
+
+// Example 4: Rate-Limited Worker Pool
+func rateLimitedWorkerPool() {
+	fmt.Fprintln(out, "\n=== Rate-Limited Worker Pool ===")
+
+	jobs := make(chan Job, 10)
+	results := make(chan Result, 10)
+
+	// Rate limiter: 2 operations per second
+	rateLimiter := make(chan struct{}, 2)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// Refill rate limiter tokens
+				select {
+				case rateLimiter <- struct{}{}:
+				default:
+				}
+				select {
+				case rateLimiter <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	// Start workers with rate limiting
+	for w := 1; w <= 3; w++ {
+		go rateLimitedWorker(w, jobs, results, rateLimiter)
+	}
+
+	// Send jobs
+	start := time.Now()
+	for j := 1; j <= 6; j++ {
+		jobs <- Job{ID: j, Data: fmt.Sprintf("rate-limited-task-%d", j)}
+	}
+	close(jobs)
+
+	// Collect results
+	for r := 1; r <= 6; r++ {
+		result := <-results
+		elapsed := time.Since(start)
+		fmt.Fprintf(out, "✅ Job %d completed after %v: %s\n",
+			result.Job.ID, elapsed.Round(100*time.Millisecond), result.Output)
+	}
+}
+
+func rateLimitedWorker(id int, jobs <-chan Job, results chan<- Result, rateLimiter <-chan struct{}) {
+	for job := range jobs {
+		// Wait for rate limit token
+		<-rateLimiter
+		fmt.Fprintf(out, "🚀 Worker %d got rate limit token for job %d\n", id, job.ID)
+
+		// Process job
+		time.Sleep(100 * time.Millisecond)
+
+		results <- Result{
+			Job:    job,
+			Output: fmt.Sprintf("Rate-limited processing by worker %d", id),
+		}
+	}
+}
+
+// Example 5: Autoscaling Worker Pool
+func autoscalingWorkerPool() {
+	fmt.Fprintln(out, "\n=== Autoscaling Worker Pool ===")
+
+	// Between 1 and 5 workers: more while a burst backs up, fewer once idle
+	pool := workerpool.New(1, func(ctx context.Context, job Job) (string, error) {
+		time.Sleep(300 * time.Millisecond)
+		return "done", nil
+	}, workerpool.WithAutoscale[Job, string](workerpool.Autoscale{Min: 1, Max: 5, IdleTimeout: 500 * time.Millisecond}),
+		workerpool.WithQueueSize[Job, string](20))
+	go func() {
+		for range pool.Results() {
+		}
+	}()
+
+	// A burst of 20 jobs, then quiet
+	for j := 1; j <= 20; j++ {
+		pool.Submit(context.Background(), Job{ID: j, Data: fmt.Sprintf("burst-%d", j)})
+	}
+	for i := 0; i < 8; i++ {
+		fmt.Fprintf(out, "⚖️  %v: %d workers\n", time.Duration(i)*250*time.Millisecond, pool.Workers())
+		time.Sleep(250 * time.Millisecond)
+	}
+	pool.Shutdown(context.Background())
+}
+
+// out is where the demo prints; Command points it at the command's stdout.
+var out io.Writer = os.Stdout
+
+// Command returns the `workers` subcommand.
+func Command() *cli.Command {
+	var example string
+	return &cli.Command{
+		Name:    "workers",
+		Summary: "Run the worker pool examples",
+		Usage:   "[-example basic|errors|context|ratelimit|autoscale]",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&example, "example", "", "run only this example (default: all)")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return cli.Usagef("workers takes no arguments")
+			}
+			examples := map[string]func(){
+				"basic":     basicWorkerPool,
+				"errors":    workerPoolWithErrors,
+				"context":   workerPoolWithContext,
+				"ratelimit": rateLimitedWorkerPool,
+				"autoscale": autoscalingWorkerPool,
+			}
+			out = env.Stdout
+			if example == "" {
+				run()
+				return nil
+			}
+			fn, ok := examples[example]
+			if !ok {
+				return cli.Usagef("unknown example %q", example)
+			}
+			fn()
+			return nil
+		},
+	}
+}
+
+func run() {
+	fmt.Fprintln(out, "🏭 Advanced Worker Patterns in Go")
+	fmt.Fprintln(out, "==================================")
+
+	basicWorkerPool()
+	workerPoolWithErrors()
+	workerPoolWithContext()
+	rateLimitedWorkerPool()
+	autoscalingWorkerPool()
+
+	fmt.Fprintln(out, "\n🎯 Key Worker Pattern Benefits:")
+	fmt.Fprintln(out, "• Concurrency: Multiple workers process jobs simultaneously")
+	fmt.Fprintln(out, "• Scalability: The worker count can follow the load")
+	fmt.Fprintln(out, "• Error handling: Isolated failures don't crash the system")
+	fmt.Fprintln(out, "• Graceful shutdown: Context-aware workers can stop cleanly")
+	fmt.Fprintln(out, "• Rate limiting: Control resource usage and external API calls")
+}
@@ -0,0 +1,140 @@
+// Package lifecycle gives long-running commands one consistent way to stop:
+// wait for SIGINT/SIGTERM (or a programmatic Stop), then run the registered
+// shutdown hooks in registration order within a deadline.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout bounds how long all shutdown hooks may take together.
+const DefaultTimeout = 10 * time.Second
+
+// ErrForced is returned by Wait when a second signal arrives while the hooks
+// are still running.
+var ErrForced = errors.New("lifecycle: forced shutdown")
+
+// Hook releases one resource. It should return promptly once ctx is done.
+type Hook func(ctx context.Context) error
+
+// Option configures a Lifecycle.
+type Option func(*Lifecycle)
+
+// WithTimeout sets the overall shutdown deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(l *Lifecycle) { l.timeout = d }
+}
+
+// WithSignals replaces the signals that trigger shutdown.
+func WithSignals(sigs ...os.Signal) Option {
+	return func(l *Lifecycle) { l.signals = sigs }
+}
+
+// Lifecycle coordinates the shutdown of a process.
+type Lifecycle struct {
+	timeout time.Duration
+	signals []os.Signal
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	sigCh  chan os.Signal
+
+	mu    sync.Mutex
+	hooks []namedHook
+	once  sync.Once
+}
+
+type namedHook struct {
+	name string
+	fn   Hook
+}
+
+// New starts listening for shutdown signals. The returned Lifecycle's
+// Context is derived from parent.
+func New(parent context.Context, opts ...Option) *Lifecycle {
+	l := &Lifecycle{
+		timeout: DefaultTimeout,
+		signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.ctx, l.cancel = context.WithCancel(parent)
+	l.sigCh = make(chan os.Signal, 2)
+	signal.Notify(l.sigCh, l.signals...)
+	go func() {
+		select {
+		case <-l.sigCh:
+			l.cancel()
+		case <-l.ctx.Done():
+		}
+	}()
+	return l
+}
+
+// Context is cancelled as soon as shutdown begins. Pass it to servers and
+// loops so they stop accepting new work.
+func (l *Lifecycle) Context() context.Context { return l.ctx }
+
+// Register adds a hook. Hooks run in the order they were registered.
+func (l *Lifecycle) Register(name string, fn Hook) {
+	l.mu.Lock()
+	l.hooks = append(l.hooks, namedHook{name, fn})
+	l.mu.Unlock()
+}
+
+// Stop begins shutdown without a signal.
+func (l *Lifecycle) Stop() { l.cancel() }
+
+// Wait blocks until shutdown begins, then runs every hook and returns their
+// combined errors. A second signal while hooks run abandons the remaining
+// hooks and returns ErrForced.
+func (l *Lifecycle) Wait() error {
+	<-l.ctx.Done()
+
+	var err error
+	l.once.Do(func() { err = l.shutdown() })
+	return err
+}
+
+func (l *Lifecycle) shutdown() error {
+	defer signal.Stop(l.sigCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	l.mu.Lock()
+	hooks := append([]namedHook(nil), l.hooks...)
+	l.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		var errs []error
+		for _, h := range hooks {
+			if err := h.fn(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			}
+			if ctx.Err() != nil {
+				errs = append(errs, fmt.Errorf("lifecycle: deadline exceeded before hook %q finished", h.name))
+				break
+			}
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-l.sigCh:
+		return ErrForced
+	case <-ctx.Done():
+		return fmt.Errorf("lifecycle: shutdown timed out after %v", l.timeout)
+	}
+}
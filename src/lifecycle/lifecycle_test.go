@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHooksRunInRegistrationOrder(t *testing.T) {
+	l := New(context.Background())
+	var order []string
+	boom := errors.New("boom")
+	for _, name := range []string{"http", "pool", "db"} {
+		l.Register(name, func(context.Context) error {
+			order = append(order, name)
+			if name == "pool" {
+				return boom
+			}
+			return nil
+		})
+	}
+	l.Stop()
+	err := l.Wait()
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "pool: boom") {
+		t.Fatalf("Wait = %v, want the pool hook's error", err)
+	}
+	if got := strings.Join(order, ","); got != "http,pool,db" {
+		t.Fatalf("hooks ran as %s, want http,pool,db", got)
+	}
+	if l.Context().Err() == nil {
+		t.Fatal("Context not cancelled by Stop")
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		hook Hook
+		want string
+	}{
+		{"hook honours ctx", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, ""},
+		{"hook ignores ctx", func(context.Context) error {
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		}, "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(context.Background(), WithTimeout(20*time.Millisecond))
+			var mu sync.Mutex
+			ranNext := false
+			l.Register("slow", tt.hook)
+			l.Register("next", func(context.Context) error {
+				mu.Lock()
+				ranNext = true
+				mu.Unlock()
+				return nil
+			})
+			l.Stop()
+			start := time.Now()
+			err := l.Wait()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Wait = %v, want an error containing %q", err, tt.want)
+			}
+			if took := time.Since(start); took > 150*time.Millisecond {
+				t.Fatalf("Wait took %v past a 20ms deadline", took)
+			}
+			time.Sleep(250 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			if ranNext {
+				t.Fatal("hook after the deadline ran")
+			}
+		})
+	}
+}
+
+func TestSignalStartsShutdown(t *testing.T) {
+	l := New(context.Background(), WithSignals(syscall.SIGUSR1))
+	stopped := make(chan struct{})
+	l.Register("hook", func(context.Context) error {
+		close(stopped)
+		return nil
+	})
+	go syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	if err := l.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("hook did not run")
+	}
+}
+
+func TestSecondSignalForcesShutdown(t *testing.T) {
+	l := New(context.Background(), WithSignals(syscall.SIGUSR2))
+	l.Register("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	l.Stop()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	}()
+	if err := l.Wait(); !errors.Is(err, ErrForced) {
+		t.Fatalf("Wait = %v, want ErrForced", err)
+	}
+}
@@ -0,0 +1,64 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+// Fetcher loads the value for a single key.
+type Fetcher[K, V any] func(ctx context.Context, key K) (V, error)
+
+// Result is the outcome of fetching one key.
+type Result[K, V any] struct {
+	Key   K
+	Value V
+	Err   error
+}
+
+// FetchAll fetches every key with at most the configured limit in flight and
+// returns one Result per key, in key order. Unlike Collect, a failing key does
+// not stop the others; each Result carries its own error.
+//
+// Cancelling ctx short-circuits the call: no further keys are started, keys
+// that were never fetched get ctx.Err() as their error, and FetchAll returns
+// ctx.Err() alongside the partial results.
+func FetchAll[K, V any](ctx context.Context, keys []K, fetch Fetcher[K, V], opts ...Option) ([]Result[K, V], error) {
+	cfg := newConfig(opts)
+
+	results := make([]Result[K, V], len(keys))
+	for i, k := range keys {
+		results[i].Key = k
+	}
+
+	sem := make(chan struct{}, cfg.limit)
+	var wg sync.WaitGroup
+
+	next := 0
+schedule:
+	for ; next < len(keys); next++ {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break schedule
+		}
+
+		wg.Add(1)
+		go func(r *Result[K, V]) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.Value, r.Err = fetch(ctx, r.Key)
+		}(&results[next])
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := next; i < len(results); i++ {
+			results[i].Err = err
+		}
+		return results, err
+	}
+	return results, nil
+}
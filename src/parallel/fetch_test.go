@@ -0,0 +1,65 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchAllOrderAndPartialErrors(t *testing.T) {
+	keys := []string{"a", "bad", "c", "d"}
+	results, err := FetchAll(context.Background(), keys, func(_ context.Context, k string) (string, error) {
+		if k == "bad" {
+			return "", fmt.Errorf("no row for %q", k)
+		}
+		return k + "!", nil
+	}, WithLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if r.Key != keys[i] {
+			t.Fatalf("results[%d].Key = %q, want %q", i, r.Key, keys[i])
+		}
+		if (r.Err != nil) != (r.Key == "bad") {
+			t.Fatalf("results[%d] = %+v", i, r)
+		}
+		if r.Err == nil && r.Value != r.Key+"!" {
+			t.Fatalf("results[%d].Value = %q", i, r.Value)
+		}
+	}
+}
+
+func TestFetchAllShortCircuitsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	results, err := FetchAll(ctx, make([]int, 100), func(ctx context.Context, _ int) (int, error) {
+		if calls.Add(1) == 3 {
+			cancel()
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return 1, nil
+		}
+	}, WithLimit(4))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(results) != 100 {
+		t.Fatalf("len(results) = %d, want 100", len(results))
+	}
+	if n := calls.Load(); n >= 100 {
+		t.Fatalf("fetcher called %d times after cancellation", n)
+	}
+	if last := results[99]; !errors.Is(last.Err, context.Canceled) {
+		t.Fatalf("unfetched key error = %v", last.Err)
+	}
+}
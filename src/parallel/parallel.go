@@ -0,0 +1,95 @@
+// Package parallel runs independent calls concurrently while keeping the
+// results in the same order as the inputs.
+package parallel
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Option configures a parallel call.
+type Option func(*config)
+
+type config struct {
+	limit int
+}
+
+// WithLimit caps the number of calls in flight. Values below 1 are ignored.
+func WithLimit(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.limit = n
+		}
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{limit: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Collect calls fn once per input, with at most the configured limit running
+// at the same time, and returns the outputs in input order.
+//
+// The first error cancels the context passed to the remaining calls and is
+// returned once every started call has finished. If ctx is cancelled before
+// all inputs are scheduled, Collect stops scheduling and returns ctx.Err().
+func Collect[In, Out any](ctx context.Context, inputs []In, fn func(context.Context, In) (Out, error), opts ...Option) ([]Out, error) {
+	cfg := newConfig(opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := make([]Out, len(inputs))
+	sem := make(chan struct{}, cfg.limit)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+schedule:
+	for i, in := range inputs {
+		if ctx.Err() != nil {
+			fail(ctx.Err())
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(ctx.Err())
+			break schedule
+		}
+
+		wg.Add(1)
+		go func(i int, in In) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Each goroutine owns exactly one slot, so no lock is needed.
+			v, err := fn(ctx, in)
+			if err != nil {
+				fail(err)
+				return
+			}
+			out[i] = v
+		}(i, in)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCollectPreservesOrder(t *testing.T) {
+	inputs := []int{5, 4, 3, 2, 1}
+	got, err := Collect(context.Background(), inputs, func(_ context.Context, n int) (int, error) {
+		// Later inputs finish first.
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{50, 40, 30, 20, 10}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCollectRespectsLimit(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	inputs := make([]int, 20)
+	_, err := Collect(context.Background(), inputs, func(context.Context, int) (int, error) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		return 0, nil
+	}, WithLimit(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Fatalf("peak concurrency %d exceeds limit 3", p)
+	}
+}
+
+func TestCollectFirstErrorCancelsRest(t *testing.T) {
+	boom := errors.New("boom")
+	var started atomic.Int32
+	_, err := Collect(context.Background(), make([]int, 50), func(ctx context.Context, _ int) (int, error) {
+		if started.Add(1) == 1 {
+			return 0, boom
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return 0, nil
+		}
+	}, WithLimit(2))
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+	if n := started.Load(); n == 50 {
+		t.Fatalf("all %d calls started despite early failure", n)
+	}
+}
+
+func TestCollectEmpty(t *testing.T) {
+	got, err := Collect(context.Background(), []string(nil), func(context.Context, string) (string, error) {
+		t.Fatal("fn called for empty input")
+		return "", nil
+	})
+	if err != nil || len(got) != 0 {
+		t.Fatalf("got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package retry re-runs a failing operation with a delay between attempts.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopatterns/backoff"
+)
+
+// Option configures Do.
+type Option func(*config)
+
+type config struct {
+	attempts int
+	backoff  backoff.Strategy
+	clock    backoff.Clock
+	retryIf  func(error) bool
+	onRetry  []func(attempt int, err error, delay time.Duration)
+}
+
+// Attempts sets the maximum number of calls, including the first one.
+// Values below 1 are ignored. The default is 3.
+func Attempts(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.attempts = n
+		}
+	}
+}
+
+// WithBackoff sets the strategy that picks the delay between attempts.
+func WithBackoff(s backoff.Strategy) Option {
+	return func(c *config) { c.backoff = s }
+}
+
+// WithClock replaces the clock used for waiting, e.g. with a
+// backoff.FakeClock in tests.
+func WithClock(clock backoff.Clock) Option {
+	return func(c *config) { c.clock = clock }
+}
+
+// RetryIf restricts retries to errors for which pred returns true.
+// Errors wrapped with Permanent are never retried regardless.
+func RetryIf(pred func(error) bool) Option {
+	return func(c *config) { c.retryIf = pred }
+}
+
+// OnRetry registers a hook called before each wait, e.g. for logging.
+func OnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *config) { c.onRetry = append(c.onRetry, fn) }
+}
+
+// Error is returned once every attempt has failed.
+type Error struct {
+	Attempts int
+	Errors   []error // one per attempt, oldest first
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("retry: giving up after %d attempts: %v", e.Attempts, e.Last())
+}
+
+// Last returns the error from the final attempt.
+func (e *Error) Last() error { return e.Errors[len(e.Errors)-1] }
+
+// Unwrap exposes the final error to errors.Is and errors.As.
+func (e *Error) Unwrap() error { return e.Last() }
+
+type permanentError struct{ err error }
+
+func (p permanentError) Error() string { return p.err.Error() }
+func (p permanentError) Unwrap() error { return p.err }
+
+// Permanent wraps err so Do returns it immediately without retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error, the attempts
+// are exhausted, or ctx is done.
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// DoValue is Do for operations that return a value.
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	cfg := config{
+		attempts: 3,
+		backoff:  backoff.Exponential{Base: 100 * time.Millisecond, Max: 5 * time.Second},
+		clock:    backoff.System,
+		retryIf:  func(error) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		zero  T
+		errs  []error
+		delay time.Duration
+	)
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+
+		var perm permanentError
+		if errors.As(err, &perm) {
+			return zero, perm.err
+		}
+		errs = append(errs, err)
+		if attempt >= cfg.attempts || !cfg.retryIf(err) {
+			return zero, &Error{Attempts: attempt, Errors: errs}
+		}
+
+		delay = cfg.backoff.Next(attempt, delay)
+		for _, hook := range cfg.onRetry {
+			hook(attempt, err, delay)
+		}
+
+		if err := backoff.Sleep(ctx, cfg.clock, delay); err != nil {
+			return zero, errors.Join(err, &Error{Attempts: attempt, Errors: errs})
+		}
+	}
+}
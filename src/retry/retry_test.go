@@ -0,0 +1,181 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"gopatterns/backoff"
+)
+
+// noWait keeps the tests from sleeping between attempts.
+var noWait = WithBackoff(backoff.Constant{})
+
+func TestAttempts(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name      string
+		opts      []Option
+		failFirst int // calls that fail before one succeeds
+		wantCalls int
+		wantErr   bool
+	}{
+		{"first try", nil, 0, 1, false},
+		{"succeeds on the last", nil, 2, 3, false},
+		{"default gives up after 3", nil, 5, 3, true},
+		{"Attempts(5)", []Option{Attempts(5)}, 4, 5, false},
+		{"Attempts(1)", []Option{Attempts(1)}, 1, 1, true},
+		{"Attempts(0) ignored", []Option{Attempts(0)}, 5, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			v, err := DoValue(context.Background(), func(context.Context) (int, error) {
+				calls++
+				if calls <= tt.failFirst {
+					return 0, boom
+				}
+				return calls, nil
+			}, append([]Option{noWait}, tt.opts...)...)
+			if calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !tt.wantErr {
+				if err != nil || v != calls {
+					t.Fatalf("DoValue = %d, %v", v, err)
+				}
+				return
+			}
+			var re *Error
+			if !errors.As(err, &re) || re.Attempts != calls || len(re.Errors) != calls || !errors.Is(err, boom) {
+				t.Fatalf("err = %#v, want a retry.Error over %d attempts wrapping boom", err, calls)
+			}
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	retryable := errors.New("retryable")
+	fatal := errors.New("fatal")
+	tests := []struct {
+		name      string
+		err       error
+		opts      []Option
+		wantCalls int
+		wantRetry bool // err is a *Error
+	}{
+		{"retried by default", retryable, nil, 3, true},
+		{"Permanent stops at once", Permanent(fatal), nil, 1, false},
+		{"RetryIf false", fatal, []Option{RetryIf(func(err error) bool { return errors.Is(err, retryable) })}, 1, true},
+		{"RetryIf true", retryable, []Option{RetryIf(func(err error) bool { return errors.Is(err, retryable) })}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), func(context.Context) error {
+				calls++
+				return tt.err
+			}, append([]Option{noWait}, tt.opts...)...)
+			if calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			var re *Error
+			if errors.As(err, &re) != tt.wantRetry {
+				t.Fatalf("err = %#v, retry.Error %v", err, tt.wantRetry)
+			}
+			if !errors.Is(err, errors.Unwrap(tt.err)) && !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v does not wrap %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestOnRetryHooks(t *testing.T) {
+	type call struct {
+		attempt int
+		delay   time.Duration
+	}
+	var first, second []call
+	boom := errors.New("boom")
+	Do(context.Background(), func(context.Context) error { return boom },
+		Attempts(4),
+		WithBackoff(backoff.Func(func(attempt int, _ time.Duration) time.Duration {
+			return time.Duration(attempt) * time.Nanosecond
+		})),
+		OnRetry(func(attempt int, err error, d time.Duration) {
+			if err != boom {
+				t.Errorf("hook got %v", err)
+			}
+			first = append(first, call{attempt, d})
+		}),
+		OnRetry(func(attempt int, _ error, d time.Duration) { second = append(second, call{attempt, d}) }),
+	)
+	want := []call{{1, 1}, {2, 2}, {3, 3}}
+	for _, got := range [][]call{first, second} {
+		if len(got) != len(want) {
+			t.Fatalf("hook calls = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("hook calls = %v, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestWaitsOnClockAndStopsWithContext(t *testing.T) {
+	clock := backoff.NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan error)
+	go func() {
+		done <- Do(ctx, func(context.Context) error {
+			calls++
+			return errors.New("boom")
+		}, Attempts(10), WithClock(clock), WithBackoff(backoff.Constant{Delay: time.Minute}))
+	}()
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Minute) // second attempt
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	err := <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2", calls)
+	}
+}
+
+func TestTransient(t *testing.T) {
+	dial := &url.Error{Op: "Post", URL: "http://x", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{Code: 503, Status: "503 Service Unavailable"}, true},
+		{fmt.Errorf("webhook: %w", &StatusError{Code: 500}), true},
+		{&StatusError{Code: 404, Status: "404 Not Found"}, false},
+		{&StatusError{Code: 429}, false},
+		{dial, true},
+		{&url.Error{Op: "Post", URL: "http://x", Err: io.ErrUnexpectedEOF}, true},
+		{&url.Error{Op: "Post", URL: "http://x", Err: context.DeadlineExceeded}, true},
+		{&url.Error{Op: "Post", URL: "ftp://x", Err: errors.New("unsupported protocol scheme")}, false},
+		{errors.New("invalid character '<'"), false},
+	}
+	for _, tt := range tests {
+		if got := Transient(tt.err); got != tt.want {
+			t.Errorf("Transient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
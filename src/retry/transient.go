@@ -0,0 +1,41 @@
+package retry
+
+import (
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// StatusError is an HTTP response whose status the caller treats as a
+// failure. Only 5xx statuses are Transient.
+type StatusError struct {
+	Code   int
+	Status string // e.g. "503 Service Unavailable"
+	Body   string // the start of the response body, if the caller kept it
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return "status " + e.Status
+	}
+	return "status " + e.Status + ": " + e.Body
+}
+
+// Transient reports whether err is worth retrying: a network failure or
+// timeout, a connection dropped before the response, or a StatusError with a
+// 5xx status. Use it with RetryIf for HTTP calls.
+func Transient(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
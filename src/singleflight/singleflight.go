@@ -0,0 +1,112 @@
+// Package singleflight collapses concurrent calls for the same key into a
+// single execution whose result is shared by every caller.
+package singleflight
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Group deduplicates calls by key. The zero value is ready to use.
+type Group[K comparable, V any] struct {
+	// TTL keeps a successful result around after the call returns, so calls
+	// arriving within TTL reuse it instead of running fn again; it is dropped
+	// once TTL has passed. Zero means results are only shared with callers
+	// that overlap the execution.
+	TTL time.Duration
+	// Now overrides the clock, mainly for tests.
+	Now func() time.Time
+
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+type call[V any] struct {
+	done    chan struct{}
+	val     V
+	err     error
+	expires time.Time // set once done, when TTL > 0
+	dups    int
+}
+
+// Do runs fn for key unless a call for key is already in flight (or a
+// cached result is still fresh), in which case it waits for and returns that
+// result. shared reports whether the result was given to more than one
+// caller. A panic in fn is reported to every waiter as an error.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		select {
+		case <-c.done:
+			if g.now().Before(c.expires) {
+				c.dups++
+				g.mu.Unlock()
+				return c.val, c.err, true
+			}
+			delete(g.calls, key)
+		default:
+			c.dups++
+			g.mu.Unlock()
+			<-c.done
+			return c.val, c.err, true
+		}
+	}
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	g.run(key, c, fn)
+
+	g.mu.Lock()
+	shared = c.dups > 0
+	g.mu.Unlock()
+	return c.val, c.err, shared
+}
+
+func (g *Group[K, V]) run(key K, c *call[V], fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("singleflight: panic: %v", r)
+		}
+
+		g.mu.Lock()
+		if g.TTL > 0 && c.err == nil {
+			c.expires = g.now().Add(g.TTL)
+			time.AfterFunc(g.TTL, func() { g.expire(key, c) })
+		} else if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		close(c.done)
+		g.mu.Unlock()
+	}()
+	c.val, c.err = fn()
+}
+
+// expire drops the cached result c for key, unless it has been replaced
+// or, by g.Now, is still fresh; Do replaces it then.
+func (g *Group[K, V]) expire(key K, c *call[V]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == c && !g.now().Before(c.expires) {
+		delete(g.calls, key)
+	}
+}
+
+// Forget drops any in-flight or cached call for key, so the next Do runs fn
+// again. Callers already waiting still receive the original result.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
+
+func (g *Group[K, V]) now() time.Time {
+	if g.Now != nil {
+		return g.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,120 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoCollapsesConcurrentCalls(t *testing.T) {
+	var (
+		g       Group[string, int]
+		calls   atomic.Int32
+		wg      sync.WaitGroup
+		shared  atomic.Int32
+		release = make(chan struct{})
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, s := g.Do("k", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 7, nil
+			})
+			if err != nil || v != 7 {
+				t.Errorf("Do = %d, %v", v, err)
+			}
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("fn ran %d times, want 1", n)
+	}
+	if n := shared.Load(); n != 10 {
+		t.Fatalf("%d callers saw shared, want 10", n)
+	}
+}
+
+func TestTTLReusesResult(t *testing.T) {
+	now := time.Unix(0, 0)
+	g := Group[string, int]{TTL: time.Minute, Now: func() time.Time { return now }}
+	n := 0
+	fn := func() (int, error) { n++; return n, nil }
+
+	tests := []struct {
+		advance time.Duration
+		want    int
+		shared  bool
+	}{
+		{0, 1, false},
+		{30 * time.Second, 1, true},
+		{30 * time.Second, 2, false}, // the first result expired
+		{0, 2, true},
+	}
+	for i, tt := range tests {
+		now = now.Add(tt.advance)
+		v, err, shared := g.Do("k", fn)
+		if err != nil || v != tt.want || shared != tt.shared {
+			t.Fatalf("call %d: Do = %d, %v, shared %v; want %d, shared %v", i, v, err, shared, tt.want, tt.shared)
+		}
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	g := Group[string, int]{TTL: time.Minute}
+	boom := errors.New("boom")
+	if _, err, _ := g.Do("k", func() (int, error) { return 0, boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+	if v, err, _ := g.Do("k", func() (int, error) { return 1, nil }); err != nil || v != 1 {
+		t.Fatalf("Do after error = %d, %v; want a fresh call", v, err)
+	}
+}
+
+func TestPanicBecomesError(t *testing.T) {
+	var g Group[string, int]
+	_, err, _ := g.Do("k", func() (int, error) { panic("bad") })
+	if err == nil {
+		t.Fatal("panic was not reported")
+	}
+}
+
+func TestExpiredResultsAreSwept(t *testing.T) {
+	g := Group[int, int]{TTL: 10 * time.Millisecond}
+	for i := range 100 {
+		g.Do(i, func() (int, error) { return i, nil })
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		g.mu.Lock()
+		n := len(g.calls)
+		g.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d expired results still held", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestForgetRunsAgain(t *testing.T) {
+	g := Group[string, int]{TTL: time.Hour}
+	n := 0
+	fn := func() (int, error) { n++; return n, nil }
+	g.Do("k", fn)
+	g.Forget("k")
+	if v, _, _ := g.Do("k", fn); v != 2 {
+		t.Fatalf("Do after Forget = %d, want 2", v)
+	}
+}
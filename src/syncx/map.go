@@ -0,0 +1,184 @@
+// Package syncx holds concurrency primitives that the standard library does
+// not provide out of the box.
+package syncx
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"sync"
+)
+
+// DefaultShards is the shard count used when NewMap is given zero.
+const DefaultShards = 32
+
+// Map is a concurrent map split into independently locked shards, so
+// writers to different keys rarely contend. The zero value is not usable;
+// create maps with NewMap.
+type Map[K comparable, V any] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []shard[K, V]
+}
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	_  [32]byte // keep neighbouring shard locks on separate cache lines
+}
+
+// NewMap returns an empty map with n shards, rounded up to a power of two.
+func NewMap[K comparable, V any](n int) *Map[K, V] {
+	if n <= 0 {
+		n = DefaultShards
+	}
+	n = 1 << bits.Len(uint(n-1))
+	m := &Map[K, V]{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(n - 1),
+		shards: make([]shard[K, V], n),
+	}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+func (m *Map[K, V]) shard(key K) *shard[K, V] {
+	return &m.shards[hashKey(m.seed, key)&m.mask]
+}
+
+// Load returns the value stored for key.
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	v, ok := s.m[key]
+	s.mu.RUnlock()
+	return v, ok
+}
+
+// Store sets the value for key.
+func (m *Map[K, V]) Store(key K, value V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	s.m[key] = value
+	s.mu.Unlock()
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it
+// stores and returns value. loaded reports whether the value was present.
+func (m *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// LoadAndDelete removes key and returns its previous value, if any.
+func (m *Map[K, V]) LoadAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	v, ok := s.m[key]
+	delete(s.m, key)
+	s.mu.Unlock()
+	return v, ok
+}
+
+// Delete removes key.
+func (m *Map[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.mu.Lock()
+	delete(s.m, key)
+	s.mu.Unlock()
+}
+
+// Compute atomically replaces the value for key with the result of fn.
+// fn receives the current value and whether it exists; returning keep=false
+// deletes the key. fn runs with the shard locked and must not call back
+// into the map.
+func (m *Map[K, V]) Compute(key K, fn func(old V, loaded bool) (value V, keep bool)) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, loaded := s.m[key]
+	v, keep := fn(old, loaded)
+	if keep {
+		s.m[key] = v
+	} else {
+		delete(s.m, key)
+	}
+	return v, keep
+}
+
+// Len returns the number of entries. Shards are counted one after another,
+// so the result is only a snapshot while writers are active.
+func (m *Map[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range calls fn for each entry until fn returns false. Each shard is read
+// locked while it is visited, so fn must not write to the map.
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		for k, v := range s.m {
+			if !fn(k, v) {
+				s.mu.RUnlock()
+				return
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// Clear removes every entry.
+func (m *Map[K, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		clear(s.m)
+		s.mu.Unlock()
+	}
+}
+
+// hashKey hashes the common key kinds directly and everything else with
+// maphash.Comparable, which hashes equal keys alike, +0.0 and -0.0 included.
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return mix(seed, uint64(k))
+	case int64:
+		return mix(seed, uint64(k))
+	case int32:
+		return mix(seed, uint64(k))
+	case uint:
+		return mix(seed, uint64(k))
+	case uint64:
+		return mix(seed, k)
+	case uint32:
+		return mix(seed, uint64(k))
+	default:
+		return maphash.Comparable(seed, key)
+	}
+}
+
+func mix(seed maphash.Seed, v uint64) uint64 {
+	var b [8]byte
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	return maphash.Bytes(seed, b[:])
+}
@@ -0,0 +1,137 @@
+package syncx
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestMapBasics(t *testing.T) {
+	m := NewMap[string, int](4)
+	m.Store("a", 1)
+	if v, loaded := m.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Fatalf("LoadOrStore existing = %d, %v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("b", 2); loaded || v != 2 {
+		t.Fatalf("LoadOrStore new = %d, %v", v, loaded)
+	}
+	m.Compute("a", func(old int, _ bool) (int, bool) { return old + 10, true })
+	if v, _ := m.Load("a"); v != 11 {
+		t.Fatalf("Compute: a = %d, want 11", v)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", m.Len())
+	}
+	sum := 0
+	m.Range(func(_ string, v int) bool { sum += v; return true })
+	if sum != 13 {
+		t.Fatalf("Range sum = %d, want 13", sum)
+	}
+	if v, ok := m.LoadAndDelete("b"); !ok || v != 2 {
+		t.Fatalf("LoadAndDelete = %d, %v", v, ok)
+	}
+	m.Clear()
+	if m.Len() != 0 {
+		t.Fatalf("Len after Clear = %d", m.Len())
+	}
+}
+
+func TestMapEqualKeysShareAShard(t *testing.T) {
+	m := NewMap[float64, string](64)
+	m.Store(0, "zero")
+	negZero := math.Copysign(0, -1)
+	if v, ok := m.Load(negZero); !ok || v != "zero" {
+		t.Fatalf("Load(-0) = %q, %v; want the value stored for +0", v, ok)
+	}
+	m.Store(negZero, "negative zero")
+	if m.Len() != 1 {
+		t.Fatalf("Len = %d after storing +0 and -0, want 1", m.Len())
+	}
+
+	type point struct{ x, y float64 }
+	p := NewMap[point, int](64)
+	p.Store(point{0, 1}, 1)
+	if _, ok := p.Load(point{negZero, 1}); !ok {
+		t.Fatal("struct key with -0 not found under +0")
+	}
+}
+
+func TestMapConcurrentWriters(t *testing.T) {
+	m := NewMap[int, int](0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				m.Store(g*1000+i, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if m.Len() != 8000 {
+		t.Fatalf("Len = %d, want 8000", m.Len())
+	}
+}
+
+// The benchmarks below compare the sharded map against sync.Map and a
+// single-mutex map under a read-heavy (90% loads) workload.
+
+const benchKeys = 1024
+
+var keys = func() []string {
+	k := make([]string, benchKeys)
+	for i := range k {
+		k[i] = "key-" + strconv.Itoa(i)
+	}
+	return k
+}()
+
+type mutexMap struct {
+	mu sync.RWMutex
+	m  map[string]int
+}
+
+func (m *mutexMap) Load(k string) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *mutexMap) Store(k string, v int) {
+	m.mu.Lock()
+	m.m[k] = v
+	m.mu.Unlock()
+}
+
+func runMixed(b *testing.B, load func(string), store func(string, int)) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			k := keys[i%benchKeys]
+			if i%10 == 0 {
+				store(k, i)
+			} else {
+				load(k)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkShardedMap(b *testing.B) {
+	m := NewMap[string, int](0)
+	runMixed(b, func(k string) { m.Load(k) }, m.Store)
+}
+
+func BenchmarkSyncMap(b *testing.B) {
+	var m sync.Map
+	runMixed(b, func(k string) { m.Load(k) }, func(k string, v int) { m.Store(k, v) })
+}
+
+func BenchmarkMutexMap(b *testing.B) {
+	m := &mutexMap{m: make(map[string]int)}
+	runMixed(b, func(k string) { m.Load(k) }, m.Store)
+}
@@ -0,0 +1,142 @@
+// Package auth abstracts how `task serve` authenticates incoming requests.
+//
+// A Provider turns an HTTP request into an Identity. Three providers are
+// built in — static bearer tokens, an Apache htpasswd file, and OpenID
+// Connect ID tokens — and New picks one from a Config so self-hosters can
+// switch mechanism without touching code.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrUnauthenticated is returned when a request carries no usable credentials.
+var ErrUnauthenticated = errors.New("auth: unauthenticated")
+
+// Identity describes who made a request.
+type Identity struct {
+	Subject string   // user name, token owner or OIDC "sub" claim
+	Scopes  []string // optional permissions granted to the caller
+}
+
+// HasScope reports whether the identity was granted scope.
+func (id Identity) HasScope(scope string) bool {
+	return slices.Contains(id.Scopes, scope)
+}
+
+// Scopes the task server checks. ScopeWrite implies ScopeRead.
+const (
+	ScopeRead  = "tasks:read"  // list and fetch tasks
+	ScopeWrite = "tasks:write" // change them too
+)
+
+// Can reports whether the identity may act with scope, one of ScopeRead
+// and ScopeWrite. An identity granted neither, such as any htpasswd user
+// or a token without scopes, may do anything.
+func (id Identity) Can(scope string) bool {
+	switch {
+	case id.HasScope(ScopeWrite):
+		return true
+	case id.HasScope(ScopeRead):
+		return scope == ScopeRead
+	default:
+		return true
+	}
+}
+
+// Provider authenticates a single request.
+type Provider interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// Config selects and configures a built-in provider.
+type Config struct {
+	Type string // "none", "static", "htpasswd" or "oidc"
+
+	Tokens    map[string]string // static: token -> subject, with full access
+	TokenFile string            // static: file read with LoadTokens when Tokens is nil
+
+	HtpasswdFile string // htpasswd: path to the password file
+
+	Issuer   string // oidc: issuer URL used for discovery
+	ClientID string // oidc: expected audience
+}
+
+// New builds the provider described by cfg.
+func New(cfg Config) (Provider, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "none":
+		return Anonymous{}, nil
+	case "static":
+		if cfg.Tokens == nil && cfg.TokenFile != "" {
+			tokens, err := LoadTokens(cfg.TokenFile)
+			if err != nil {
+				return nil, err
+			}
+			return NewStaticTokens(tokens), nil
+		}
+		return NewStatic(cfg.Tokens), nil
+	case "htpasswd":
+		return LoadHtpasswd(cfg.HtpasswdFile)
+	case "oidc":
+		return NewOIDC(context.Background(), cfg.Issuer, cfg.ClientID)
+	default:
+		return nil, fmt.Errorf("auth: unknown provider %q", cfg.Type)
+	}
+}
+
+// Anonymous accepts every request as the "anonymous" subject.
+type Anonymous struct{}
+
+// Authenticate implements Provider.
+func (Anonymous) Authenticate(*http.Request) (Identity, error) {
+	return Identity{Subject: "anonymous"}, nil
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying id.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity stored by Middleware, if any.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// Middleware rejects requests the provider cannot authenticate and stores
+// the resulting Identity in the request context for downstream handlers.
+func Middleware(p Provider, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := p.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="task"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+	})
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer ..." header,
+// or from the access_token query parameter of a WebSocket handshake, since
+// browsers cannot set headers on those.
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if h == "" && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		tok := r.URL.Query().Get("access_token")
+		return tok, tok != ""
+	}
+	if len(h) <= len(prefix) || !strings.EqualFold(h[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(h[len(prefix):]), true
+}
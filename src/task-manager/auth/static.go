@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Token is one entry of a token file.
+type Token struct {
+	Secret  string   // the token itself, or HashPrefix and its SHA-256
+	Subject string   // who the token belongs to; also its name
+	Scopes  []string // nil grants everything
+}
+
+// HashPrefix marks a Secret stored as a SHA-256 hash, as `task token`
+// writes them, rather than in the clear.
+const HashPrefix = "sha256:"
+
+// Static authenticates bearer tokens against a fixed table.
+type Static struct {
+	tokens []Token
+}
+
+// NewStatic returns a provider accepting the given token -> subject pairs,
+// each with full access.
+func NewStatic(tokens map[string]string) *Static {
+	list := make([]Token, 0, len(tokens))
+	for tok, sub := range tokens {
+		list = append(list, Token{Secret: tok, Subject: sub})
+	}
+	return NewStaticTokens(list)
+}
+
+// NewStaticTokens returns a provider accepting the given tokens, granting
+// each its scopes.
+func NewStaticTokens(tokens []Token) *Static {
+	return &Static{tokens: append([]Token(nil), tokens...)}
+}
+
+// Authenticate implements Provider.
+func (s *Static) Authenticate(r *http.Request) (Identity, error) {
+	tok, ok := bearerToken(r)
+	if !ok {
+		return Identity{}, ErrUnauthenticated
+	}
+	hashed := HashToken(tok)
+	// Compare against every token so timing does not leak which prefix matched.
+	var id Identity
+	found := false
+	for _, known := range s.tokens {
+		presented := tok
+		if strings.HasPrefix(known.Secret, HashPrefix) {
+			presented = hashed
+		}
+		if subtle.ConstantTimeCompare([]byte(known.Secret), []byte(presented)) == 1 {
+			id, found = Identity{Subject: known.Subject, Scopes: known.Scopes}, true
+		}
+	}
+	if !found {
+		return Identity{}, ErrUnauthenticated
+	}
+	return id, nil
+}
+
+// GenerateToken returns a new random bearer token.
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "task_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken is the Secret under which tok is stored hashed.
+func HashToken(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return HashPrefix + hex.EncodeToString(sum[:])
+}
+
+// LoadTokens reads a token file for NewStaticTokens: one "token subject"
+// line per token, optionally followed by comma-separated scopes, with blank
+// lines and '#' comments ignored.
+func LoadTokens(path string) ([]Token, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	defer f.Close()
+
+	var tokens []Token
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("auth: %s:%d: want \"token subject [scopes]\"", path, line)
+		}
+		t := Token{Secret: fields[0], Subject: fields[1]}
+		if len(fields) == 3 {
+			t.Scopes = strings.Split(fields[2], ",")
+			for _, sc := range t.Scopes {
+				if sc != ScopeRead && sc != ScopeWrite {
+					return nil, fmt.Errorf("auth: %s:%d: unknown scope %q (want %s or %s)", path, line, sc, ScopeRead, ScopeWrite)
+				}
+			}
+		}
+		tokens = append(tokens, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	return tokens, nil
+}
+
+// SaveTokens replaces the token file at path with tokens, readable only by
+// its owner.
+func SaveTokens(path string, tokens []Token) error {
+	var buf bytes.Buffer
+	buf.WriteString("# task serve tokens: secret subject [scopes]\n")
+	for _, t := range tokens {
+		fmt.Fprintf(&buf, "%s %s", t.Secret, t.Subject)
+		if len(t.Scopes) > 0 {
+			fmt.Fprintf(&buf, " %s", strings.Join(t.Scopes, ","))
+		}
+		buf.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package caldav syncs a task list with a CalDAV collection of VTODOs, such
+// as a Nextcloud, Fastmail or Radicale task list, in both directions.
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to one CalDAV calendar collection.
+type Client struct {
+	URL      string // collection URL, e.g. https://cloud.example.com/remote.php/dav/calendars/alice/tasks/
+	User     string // Basic auth credentials; app passwords work
+	Password string
+	HTTP     *http.Client // nil means a client with a 30s timeout
+}
+
+// resource is a calendar object on the server.
+type resource struct {
+	Href string // absolute URL
+	ETag string
+	Data []byte
+}
+
+// calendarQuery asks for every VTODO with its ETag and data (RFC 4791
+// section 7.8).
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag/>
+    <c:calendar-data/>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VTODO"/>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`
+
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ETag string `xml:"DAV: getetag"`
+				Data string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// list fetches every VTODO in the collection.
+func (c *Client) list(ctx context.Context) ([]resource, error) {
+	resp, err := c.do(ctx, "REPORT", c.URL, strings.NewReader(calendarQuery), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("caldav REPORT %s: %s", c.URL, resp.Status)
+	}
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("caldav REPORT %s: %w", c.URL, err)
+	}
+	var out []resource
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200") || ps.Prop.Data == "" {
+				continue
+			}
+			href, err := c.resolve(r.Href)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, resource{Href: href, ETag: ps.Prop.ETag, Data: []byte(ps.Prop.Data)})
+		}
+	}
+	return out, nil
+}
+
+// put stores data at href. With an etag the write only succeeds if the
+// object is unchanged; without one only if it does not exist yet. The new
+// ETag is returned when the server reports it.
+func (c *Client) put(ctx context.Context, href string, data []byte, etag string) (string, error) {
+	headers := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	if etag != "" {
+		headers["If-Match"] = etag
+	} else {
+		headers["If-None-Match"] = "*"
+	}
+	resp, err := c.do(ctx, http.MethodPut, href, bytes.NewReader(data), headers)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("caldav PUT %s: %s", href, resp.Status)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// remove deletes href if it still has etag.
+func (c *Client) remove(ctx context.Context, href, etag string) error {
+	resp, err := c.do(ctx, http.MethodDelete, href, nil, map[string]string{"If-Match": etag})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("caldav DELETE %s: %s", href, resp.Status)
+	}
+	return nil
+}
+
+// objectURL is where a new task with the given UID is created.
+func (c *Client) objectURL(uid string) (string, error) {
+	base := c.URL
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return c.resolveAgainst(base, url.PathEscape(uid)+".ics")
+}
+
+func (c *Client) resolve(href string) (string, error) {
+	return c.resolveAgainst(c.URL, href)
+}
+
+func (c *Client) resolveAgainst(base, href string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	h, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(h).String(), nil
+}
+
+func (c *Client) do(ctx context.Context, method, target string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if c.User != "" {
+		req.SetBasicAuth(c.User, c.Password)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caldav %s %s: %w", method, target, err)
+	}
+	return resp, nil
+}
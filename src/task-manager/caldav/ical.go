@@ -0,0 +1,319 @@
+package caldav
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// object is a calendar object resource holding one VTODO. It keeps every
+// content line it was parsed from, so properties this package does not
+// map (alarms, descriptions, client extensions) survive a round trip.
+type object struct {
+	lines      []string // unfolded content lines
+	begin, end int      // indexes of BEGIN:VTODO and END:VTODO
+}
+
+// parseObject unfolds data and locates its first VTODO.
+func parseObject(data []byte) (*object, error) {
+	o := &object{begin: -1, end: -1}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(o.lines) > 0 {
+			o.lines[len(o.lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			o.lines = append(o.lines, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for i, line := range o.lines {
+		switch {
+		case o.begin < 0 && strings.EqualFold(line, "BEGIN:VTODO"):
+			o.begin = i
+		case o.begin >= 0 && strings.EqualFold(line, "END:VTODO"):
+			o.end = i
+			return o, nil
+		}
+	}
+	return nil, fmt.Errorf("no VTODO in calendar object")
+}
+
+// newObject starts an empty VTODO with the given UID and creation time.
+func newObject(uid string, created time.Time) *object {
+	return &object{
+		lines: []string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//gopatterns//task//EN",
+			"BEGIN:VTODO",
+			"UID:" + uid,
+			"CREATED:" + created.UTC().Format(dateTime),
+			"END:VTODO",
+			"END:VCALENDAR",
+		},
+		begin: 3,
+		end:   6,
+	}
+}
+
+// props calls fn with the index, name, parameters and value of every
+// property directly inside the VTODO, skipping nested components such as
+// VALARM.
+func (o *object) props(fn func(i int, name, params, value string)) {
+	depth := 0
+	for i := o.begin + 1; i < o.end; i++ {
+		line := o.lines[i]
+		name, params, value := splitLine(line)
+		switch {
+		case name == "BEGIN":
+			depth++
+		case name == "END":
+			depth--
+		case depth == 0:
+			fn(i, name, params, value)
+		}
+	}
+}
+
+// get returns the parameters and value of a VTODO property.
+func (o *object) get(name string) (params, value string, ok bool) {
+	o.props(func(_ int, n, p, v string) {
+		if !ok && n == name {
+			params, value, ok = p, v, true
+		}
+	})
+	return params, value, ok
+}
+
+// set replaces a VTODO property, adding it if missing; an empty value
+// removes it.
+func (o *object) set(name, params, value string) {
+	var at []int
+	o.props(func(i int, n, _, _ string) {
+		if n == name {
+			at = append(at, i)
+		}
+	})
+	for j := len(at) - 1; j >= 0; j-- {
+		o.remove(at[j])
+	}
+	if value == "" {
+		return
+	}
+	line := name + params + ":" + value
+	o.lines = append(o.lines[:o.end], append([]string{line}, o.lines[o.end:]...)...)
+	o.end++
+}
+
+func (o *object) remove(i int) {
+	o.lines = append(o.lines[:i], o.lines[i+1:]...)
+	o.end--
+}
+
+// bytes folds the content lines at 75 octets with CRLF endings.
+func (o *object) bytes() []byte {
+	var b bytes.Buffer
+	for _, s := range o.lines {
+		limit := 75
+		for len(s) > limit {
+			cut := limit
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			b.WriteString(s[:cut] + "\r\n ")
+			s = s[cut:]
+			limit = 74
+		}
+		b.WriteString(s + "\r\n")
+	}
+	return b.Bytes()
+}
+
+// splitLine splits "NAME;PARAM=x:value" into upper-cased name, parameters
+// (with their leading ";") and value. Colons inside quoted parameter values
+// do not end the parameters.
+func splitLine(line string) (name, params, value string) {
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			head := line[:i]
+			value = line[i+1:]
+			name, params, _ = strings.Cut(head, ";")
+			if params != "" {
+				params = ";" + params
+			}
+			return strings.ToUpper(name), params, value
+		}
+	}
+	return strings.ToUpper(line), "", ""
+}
+
+const (
+	date     = "20060102"
+	dateTime = "20060102T150405Z"
+)
+
+// parseTime reads a DATE or DATE-TIME value. A date-only value becomes the
+// end of that day in local time, matching how due dates without a time are
+// stored; a floating time is taken as local.
+func parseTime(params, value string) (time.Time, error) {
+	loc := time.Local
+	for _, p := range strings.Split(strings.TrimPrefix(params, ";"), ";") {
+		if k, v, ok := strings.Cut(p, "="); ok && strings.EqualFold(k, "TZID") {
+			if l, err := time.LoadLocation(strings.Trim(v, `"`)); err == nil {
+				loc = l
+			}
+		}
+	}
+	switch {
+	case len(value) == len(date):
+		t, err := time.ParseInLocation(date, value, time.Local)
+		if err != nil {
+			return t, err
+		}
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 23, 59, 59, 0, time.Local), nil
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(dateTime, value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+func isEndOfDay(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h == 23 && m == 59 && s == 59
+}
+
+var (
+	escaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	unescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+// priorityOf maps the iCalendar scale (1 highest, 9 lowest, 0 undefined)
+// to task priorities: 1-4 high, 5 medium, 6-9 low.
+func priorityOf(value string) tasks.Priority {
+	n, _ := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case n >= 1 && n <= 4:
+		return tasks.PriorityHigh
+	case n == 5:
+		return tasks.PriorityMedium
+	case n >= 6 && n <= 9:
+		return tasks.PriorityLow
+	}
+	return tasks.PriorityNone
+}
+
+// icalPriority is the inverse of priorityOf.
+func icalPriority(p tasks.Priority) string {
+	switch {
+	case p >= tasks.PriorityHigh:
+		return "1"
+	case p == tasks.PriorityMedium:
+		return "5"
+	case p == tasks.PriorityLow:
+		return "9"
+	}
+	return ""
+}
+
+// apply copies the mapped VTODO properties onto t: summary, status,
+// completion time, due date and priority. Other task fields are left alone.
+func (o *object) apply(t *tasks.Task, now time.Time) {
+	if _, v, ok := o.get("SUMMARY"); ok && strings.TrimSpace(v) != "" {
+		t.Description = strings.TrimSpace(unescaper.Replace(v))
+	}
+	_, status, _ := o.get("STATUS")
+	switch strings.ToUpper(status) {
+	case "COMPLETED":
+		t.Move(tasks.Done, now)
+		if p, v, ok := o.get("COMPLETED"); ok {
+			if at, err := parseTime(p, v); err == nil {
+				t.CompletedAt = &at
+			}
+		}
+	case "CANCELLED":
+		t.Move(tasks.Cancelled, now)
+	case "IN-PROCESS":
+		t.Move(tasks.InProgress, now)
+	default:
+		// VTODO has no waiting status; NEEDS-ACTION leaves one alone.
+		if t.Status != tasks.Waiting {
+			t.Move(tasks.Pending, now)
+		}
+	}
+	t.Due = nil
+	if p, v, ok := o.get("DUE"); ok {
+		if due, err := parseTime(p, v); err == nil {
+			t.Due = &due
+		}
+	}
+	_, prio, _ := o.get("PRIORITY")
+	t.Priority = priorityOf(prio)
+}
+
+// update writes t's mapped fields into the VTODO and stamps it.
+func (o *object) update(t tasks.Task, now time.Time) {
+	stamp := now.UTC().Format(dateTime)
+	o.set("DTSTAMP", "", stamp)
+	o.set("LAST-MODIFIED", "", stamp)
+	o.set("SUMMARY", "", escaper.Replace(t.Description))
+	switch t.Status {
+	case tasks.Done:
+		o.set("STATUS", "", "COMPLETED")
+		if t.CompletedAt != nil {
+			o.set("COMPLETED", "", t.CompletedAt.UTC().Format(dateTime))
+		}
+		o.set("PERCENT-COMPLETE", "", "100")
+	case tasks.Cancelled:
+		o.set("STATUS", "", "CANCELLED")
+		o.set("COMPLETED", "", "")
+		o.set("PERCENT-COMPLETE", "", "")
+	case tasks.InProgress:
+		o.set("STATUS", "", "IN-PROCESS")
+		o.set("COMPLETED", "", "")
+		o.set("PERCENT-COMPLETE", "", "")
+	default:
+		o.set("STATUS", "", "NEEDS-ACTION")
+		o.set("COMPLETED", "", "")
+		o.set("PERCENT-COMPLETE", "", "")
+	}
+	switch {
+	case t.Due == nil:
+		o.set("DUE", "", "")
+	case isEndOfDay(t.Due.Local()):
+		o.set("DUE", ";VALUE=DATE", t.Due.Local().Format(date))
+	default:
+		o.set("DUE", "", t.Due.UTC().Format(dateTime))
+	}
+	// Keep a client's finer-grained value (say 3) while it still maps to
+	// the task's priority.
+	if _, v, _ := o.get("PRIORITY"); priorityOf(v) != t.Priority {
+		o.set("PRIORITY", "", icalPriority(t.Priority))
+	}
+}
+
+// modified is the VTODO's LAST-MODIFIED time, or the zero time.
+func (o *object) modified() time.Time {
+	p, v, ok := o.get("LAST-MODIFIED")
+	if !ok {
+		return time.Time{}
+	}
+	t, _ := parseTime(p, v)
+	return t
+}
@@ -0,0 +1,219 @@
+package caldav
+
+import (
+	"context"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// State is what a sync remembers about every task that exists on both
+// sides, keyed by UUID (the VTODO's UID).
+type State map[string]Entry
+
+// Entry records where a task lives on the server and the versions of both
+// copies after the last sync, so the next one can tell which side changed.
+type Entry struct {
+	Href string `json:"href"`
+	ETag string `json:"etag"`
+	Rev  int    `json:"rev"` // local revision
+}
+
+// Stats summarises one sync from the client's point of view.
+type Stats struct {
+	Sent      int // objects created, updated or deleted on the server
+	Received  int // tasks created or updated locally
+	Removed   int // tasks deleted locally
+	Conflicts int // tasks changed on both sides; the later change won
+}
+
+// remote is a parsed server object.
+type remote struct {
+	resource
+	obj *object
+}
+
+// Sync makes the list in tx and the collection agree. A task changed on
+// one side since the last sync is copied to the other; one changed on both
+// keeps the later change; one deleted on one side and unchanged on the
+// other is deleted there too, otherwise it is kept. state is the result of
+// the previous sync (nil the first time); the new state is returned and
+// should be saved once tx commits. Local changes are journalled under op,
+// so `task undo` reverts a sync.
+func (c *Client) Sync(ctx context.Context, tx tasks.Tx, state State, op tasks.Operation) (State, Stats, error) {
+	var stats Stats
+	local, err := withUUIDs(tx)
+	if err != nil {
+		return nil, stats, err
+	}
+	list, err := c.list(ctx)
+	if err != nil {
+		return nil, stats, err
+	}
+	byUID := map[string]remote{}
+	for _, r := range list {
+		obj, err := parseObject(r.Data)
+		if err != nil {
+			continue // not a task; leave it alone
+		}
+		_, uid, _ := obj.get("UID")
+		if _, dup := byUID[uid]; uid == "" || dup {
+			continue
+		}
+		byUID[uid] = remote{r, obj}
+	}
+
+	now := op.Time
+	next := State{}
+	pull := func(tx tasks.Tx, t tasks.Task, r remote, created bool) error {
+		before := t
+		r.obj.apply(&t, now)
+		if t.Description == "" {
+			t.Description = "(no summary)"
+		}
+		if created || len(tasks.Diff(&before, &t)) > 0 {
+			if err := tx.Put(t); err != nil {
+				return err
+			}
+			stats.Received++
+			t, _ = tx.Get(t.ID)
+		}
+		next[t.UUID] = Entry{Href: r.Href, ETag: r.ETag, Rev: t.Rev}
+		return nil
+	}
+	push := func(t tasks.Task, href, etag string, obj *object) error {
+		obj.update(t, now)
+		tag, err := c.put(ctx, href, obj.bytes(), etag)
+		if err != nil {
+			return err
+		}
+		stats.Sent++
+		next[t.UUID] = Entry{Href: href, ETag: tag, Rev: t.Rev}
+		return nil
+	}
+
+	err = tasks.Record(tx, op, func(tx tasks.Tx) error {
+		for _, t := range local {
+			e, synced := state[t.UUID]
+			r, onServer := byUID[t.UUID]
+			delete(byUID, t.UUID)
+			localChanged := !synced || e.Rev != t.Rev
+			switch {
+			case onServer:
+				remoteChanged := !synced || e.ETag == "" || r.ETag != e.ETag
+				switch {
+				case localChanged && remoteChanged:
+					stats.Conflicts++
+					if r.obj.modified().After(updated(t)) {
+						err = pull(tx, t, r, false)
+					} else {
+						err = push(t, r.Href, r.ETag, r.obj)
+					}
+				case remoteChanged:
+					err = pull(tx, t, r, false)
+				case localChanged:
+					err = push(t, r.Href, r.ETag, r.obj)
+				default:
+					next[t.UUID] = e
+				}
+			case synced && !localChanged:
+				// Deleted on the server since the last sync.
+				if err = tx.Delete(t.ID); err == nil {
+					stats.Removed++
+				}
+			default:
+				var href string
+				if href, err = c.objectURL(t.UUID); err == nil {
+					err = push(t, href, "", newObject(t.UUID, t.CreatedAt))
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for uid, r := range byUID {
+			if e, synced := state[uid]; synced && e.ETag == r.ETag {
+				// Deleted here since the last sync.
+				if err := c.remove(ctx, r.Href, r.ETag); err != nil {
+					return err
+				}
+				stats.Sent++
+				continue
+			}
+			id, err := tx.NextID()
+			if err != nil {
+				return err
+			}
+			t := tasks.Task{ID: id, UUID: uid, CreatedAt: now}
+			if p, v, ok := r.obj.get("CREATED"); ok {
+				if at, err := parseTime(p, v); err == nil {
+					t.CreatedAt = at
+				}
+			}
+			if err := pull(tx, t, r, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	return next, stats, c.fillETags(ctx, next)
+}
+
+// fillETags looks up the ETags a server did not return from PUT, so the
+// next sync does not mistake the objects for remote changes.
+func (c *Client) fillETags(ctx context.Context, st State) error {
+	missing := false
+	for _, e := range st {
+		missing = missing || e.ETag == ""
+	}
+	if !missing {
+		return nil
+	}
+	list, err := c.list(ctx)
+	if err != nil {
+		return err
+	}
+	etags := make(map[string]string, len(list))
+	for _, r := range list {
+		etags[r.Href] = r.ETag
+	}
+	for uid, e := range st {
+		if e.ETag == "" {
+			e.ETag = etags[e.Href]
+			st[uid] = e
+		}
+	}
+	return nil
+}
+
+// updated is when t last changed locally.
+func updated(t tasks.Task) time.Time {
+	if t.UpdatedAt != nil {
+		return *t.UpdatedAt
+	}
+	return t.CreatedAt
+}
+
+// withUUIDs gives every task a UUID, the UID of its VTODO, without
+// journalling it.
+func withUUIDs(tx tasks.Tx) ([]tasks.Task, error) {
+	list, err := tx.List()
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range list {
+		if t.UUID != "" {
+			continue
+		}
+		t.UUID = tasks.NewUUID()
+		if err := tx.Put(t); err != nil {
+			return nil, err
+		}
+		list[i] = t
+	}
+	return list, nil
+}
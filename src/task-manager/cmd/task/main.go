@@ -0,0 +1,14 @@
+// Command task is the standalone task manager binary. The same commands are
+// available as `gopatterns task`.
+package main
+
+import (
+	"os"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/taskcli"
+)
+
+func main() {
+	os.Exit(cli.Main(taskcli.Root()))
+}
@@ -0,0 +1,117 @@
+// Package github mirrors tasks tagged gh:owner/repo to issues of that
+// GitHub repository and brings changes made to the issues back.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPI is the REST API root of github.com.
+const DefaultAPI = "https://api.github.com"
+
+// ErrGone is returned for an issue that was deleted or moved elsewhere.
+var ErrGone = errors.New("issue no longer exists")
+
+// Client talks to the GitHub REST API.
+type Client struct {
+	API   string       // API root; "" means DefaultAPI. GitHub Enterprise: https://host/api/v3
+	Token string       // personal access token with access to the issues
+	HTTP  *http.Client // nil means a client with a 30s timeout
+}
+
+// Issue is the part of a GitHub issue a sync looks at.
+type Issue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // "open" or "closed"
+	HTMLURL   string     `json:"html_url"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+// issueEdit is the body of a request creating or changing an issue.
+type issueEdit struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	State string `json:"state,omitempty"`
+}
+
+func (c *Client) issue(ctx context.Context, repo string, number int) (Issue, error) {
+	var iss Issue
+	err := c.call(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", repo, number), nil, &iss)
+	return iss, err
+}
+
+func (c *Client) create(ctx context.Context, repo string, in issueEdit) (Issue, error) {
+	var iss Issue
+	err := c.call(ctx, http.MethodPost, "/repos/"+repo+"/issues", in, &iss)
+	return iss, err
+}
+
+func (c *Client) edit(ctx context.Context, repo string, number int, in issueEdit) (Issue, error) {
+	var iss Issue
+	err := c.call(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repo, number), in, &iss)
+	return iss, err
+}
+
+// call sends in as JSON to path and decodes the response into out.
+func (c *Client) call(ctx context.Context, method, path string, in, out any) error {
+	api := c.API
+	if api == "" {
+		api = DefaultAPI
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(api, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound && method == http.MethodGet,
+		resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("github %s %s: %w", method, path, ErrGone)
+	case resp.StatusCode/100 != 2:
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&e) == nil && e.Message != "" {
+			return fmt.Errorf("github %s %s: %s: %s", method, path, resp.Status, e.Message)
+		}
+		return fmt.Errorf("github %s %s: %s", method, path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("github %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// TagPrefix marks the tag naming the repository a task is mirrored to, as
+// in gh:owner/repo.
+const TagPrefix = "gh:"
+
+// State is what a sync remembers about every mirrored task, keyed by the
+// task's UUID.
+type State map[string]Entry
+
+// Entry links a task to its issue and records the versions of both after
+// the last sync, so the next one can tell which side changed.
+type Entry struct {
+	Repo    string    `json:"repo"`
+	Number  int       `json:"number"`
+	Updated time.Time `json:"updated"` // the issue's updated_at
+	Rev     int       `json:"rev"`     // local revision
+}
+
+// Stats summarises one sync from the client's point of view.
+type Stats struct {
+	Created   int // issues opened for newly tagged tasks
+	Sent      int // issues changed to match their task
+	Received  int // tasks changed to match their issue
+	Conflicts int // both changed; the later change won
+	Gone      int // issues deleted or transferred; their tasks are no longer mirrored
+	Mirrored  int // tagged tasks that have an issue after the sync
+}
+
+// Repo returns the owner/repo a task's gh: tag names, or "" if it has
+// none. With several such tags the first one counts.
+func Repo(t tasks.Task) string {
+	for _, tag := range t.Tags {
+		if repo, ok := strings.CutPrefix(tag, TagPrefix); ok && validRepo(repo) {
+			return repo
+		}
+	}
+	return ""
+}
+
+func validRepo(repo string) bool {
+	owner, name, ok := strings.Cut(repo, "/")
+	return ok && owner != "" && name != "" && !strings.ContainsAny(name, "/ ")
+}
+
+// Sync mirrors the tasks in tx tagged gh:owner/repo to issues: a task
+// without an issue gets one, with its description as the title, and after
+// that title and open/closed state follow whichever side changed since the
+// last sync, the later change winning when both did. Issues are never
+// deleted; a task that loses its tag or is deleted simply stops being
+// mirrored, and picks its issue up again if the tag returns. state is the
+// result of the previous sync (nil the first time); the new state is
+// returned and should be saved once tx commits. Local changes are
+// journalled under op, so `task undo` reverts them.
+func (c *Client) Sync(ctx context.Context, tx tasks.Tx, state State, op tasks.Operation) (State, Stats, error) {
+	var stats Stats
+	next := State{}
+	now := op.Time
+	err := tasks.Record(tx, op, func(tx tasks.Tx) error {
+		list, err := tx.List()
+		if err != nil {
+			return err
+		}
+		for _, t := range list {
+			repo := Repo(t)
+			e, synced := state[t.UUID]
+			if repo == "" || t.UUID == "" {
+				if synced {
+					// Remember the issue in case the tag comes back.
+					next[t.UUID] = e
+				}
+				continue
+			}
+			if !synced || e.Repo != repo {
+				iss, err := c.open(ctx, repo, t)
+				if err != nil {
+					return err
+				}
+				stats.Created++
+				stats.Mirrored++
+				next[t.UUID] = Entry{Repo: repo, Number: iss.Number, Updated: iss.UpdatedAt, Rev: t.Rev}
+				continue
+			}
+
+			iss, err := c.issue(ctx, repo, e.Number)
+			if errors.Is(err, ErrGone) {
+				stats.Gone++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			localChanged := e.Rev != t.Rev
+			remoteChanged := !iss.UpdatedAt.Equal(e.Updated)
+			if localChanged && remoteChanged {
+				stats.Conflicts++
+				if iss.UpdatedAt.After(updated(t)) {
+					localChanged = false
+				} else {
+					remoteChanged = false
+				}
+			}
+			switch {
+			case remoteChanged:
+				before := t
+				apply(&t, iss, now)
+				if len(tasks.Diff(&before, &t)) > 0 {
+					if err := tx.Put(t); err != nil {
+						return err
+					}
+					stats.Received++
+					if t, err = tx.Get(t.ID); err != nil {
+						return err
+					}
+				}
+			case localChanged:
+				if edit := changes(t, iss); edit != (issueEdit{}) {
+					if iss, err = c.edit(ctx, repo, e.Number, edit); err != nil {
+						return err
+					}
+					stats.Sent++
+				}
+			}
+			next[t.UUID] = Entry{Repo: repo, Number: e.Number, Updated: iss.UpdatedAt, Rev: t.Rev}
+			stats.Mirrored++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	return next, stats, nil
+}
+
+// open creates the issue for t, closed if t is done or cancelled.
+func (c *Client) open(ctx context.Context, repo string, t tasks.Task) (Issue, error) {
+	var body []string
+	for _, n := range t.Notes {
+		body = append(body, "- "+n.Text)
+	}
+	iss, err := c.create(ctx, repo, issueEdit{Title: t.Description, Body: strings.Join(body, "\n")})
+	if err != nil || !t.Closed() {
+		return iss, err
+	}
+	return c.edit(ctx, repo, iss.Number, issueEdit{State: "closed"})
+}
+
+// apply copies the issue's title and state to t.
+func apply(t *tasks.Task, iss Issue, now time.Time) {
+	if iss.Title != "" {
+		t.Description = iss.Title
+	}
+	switch {
+	case iss.State == "closed" && !t.Closed():
+		at := now
+		if iss.ClosedAt != nil {
+			at = *iss.ClosedAt
+		}
+		t.Complete(at)
+	case iss.State == "open" && t.Closed():
+		t.Reopen()
+	}
+}
+
+// changes is the edit that brings iss in line with t.
+func changes(t tasks.Task, iss Issue) issueEdit {
+	var edit issueEdit
+	if t.Description != iss.Title {
+		edit.Title = t.Description
+	}
+	state := "open"
+	if t.Closed() {
+		state = "closed"
+	}
+	if state != iss.State {
+		edit.State = state
+	}
+	return edit
+}
+
+// updated is when t last changed locally.
+func updated(t tasks.Task) time.Time {
+	if t.UpdatedAt != nil {
+		return *t.UpdatedAt
+	}
+	return t.CreatedAt
+}
@@ -1,3 +0,0 @@
-module task-manager
-
-go 1.23.4
@@ -0,0 +1,161 @@
+// Package hooks runs user scripts around task changes, in the manner of
+// git hooks: each script sees the task as JSON and can reject the change
+// or rewrite the task before it is saved.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Hook names, which are also the script file names.
+const (
+	Add      = "on-add"      // a task is created
+	Modify   = "on-modify"   // a task changes without being completed
+	Complete = "on-complete" // a task is completed
+	Delete   = "on-delete"   // a task is deleted; the script can only reject it
+)
+
+// Names lists every hook.
+var Names = []string{Add, Modify, Complete, Delete}
+
+// RejectedError reports a hook that exited with a non-zero status.
+type RejectedError struct {
+	Hook string
+	Err  error
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s hook rejected the change (%v)", e.Hook, e.Err)
+}
+
+func (e *RejectedError) Unwrap() error { return e.Err }
+
+// Runner runs the scripts in one directory.
+type Runner struct {
+	Dir    string
+	Env    []string  // added to the scripts' environment, e.g. TASK_COMMAND=add
+	Stderr io.Writer // receives the scripts' standard error
+}
+
+// With returns a copy of r whose scripts also get env, which takes
+// precedence over r.Env. It returns nil for a nil r.
+func (r *Runner) With(env ...string) *Runner {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Env = append(slices.Clone(r.Env), env...)
+	return &c
+}
+
+// path returns the script for hook, or "" if there is no executable one.
+func (r *Runner) path(hook string) string {
+	if r == nil || r.Dir == "" {
+		return ""
+	}
+	p := filepath.Join(r.Dir, hook)
+	fi, err := os.Stat(p)
+	if err != nil || fi.IsDir() || fi.Mode()&0o111 == 0 {
+		return ""
+	}
+	return p
+}
+
+// Run runs hook with t as JSON on its standard input. A script that exits
+// non-zero rejects the change. One that prints a task as JSON replaces t;
+// its ID and sync metadata are kept from t, but with the next revision, so
+// a rewrite made on a `task serve` reaches the machine that sent the task.
+func (r *Runner) Run(ctx context.Context, hook string, t tasks.Task) (tasks.Task, error) {
+	path := r.path(hook)
+	if path == "" {
+		return t, nil
+	}
+	in, err := json.Marshal(t)
+	if err != nil {
+		return t, err
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(append(in, '\n'))
+	cmd.Stdout = &out
+	cmd.Stderr = r.Stderr
+	cmd.Env = append(os.Environ(), "TASK_HOOK="+hook)
+	cmd.Env = append(cmd.Env, r.Env...)
+	if err := cmd.Run(); err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return t, &RejectedError{Hook: hook, Err: err}
+		}
+		return t, fmt.Errorf("%s hook: %w", hook, err)
+	}
+	if hook == Delete || strings.TrimSpace(out.String()) == "" {
+		return t, nil
+	}
+
+	var changed tasks.Task
+	if err := json.Unmarshal(out.Bytes(), &changed); err != nil {
+		return t, fmt.Errorf("%s hook: output is not a task: %w", hook, err)
+	}
+	changed.ID, changed.Parent, changed.CreatedAt = t.ID, t.Parent, t.CreatedAt
+	changed.UUID, changed.Rev, changed.UpdatedAt = t.UUID, t.Rev+1, t.UpdatedAt
+	changed.Stamps, changed.Conflicts = t.Stamps, t.Conflicts
+	if strings.TrimSpace(changed.Description) == "" {
+		return t, fmt.Errorf("%s hook: %w: empty description", hook, tasks.ErrInvalid)
+	}
+	return changed, nil
+}
+
+// Tx runs the hooks for every task written or deleted through tx. Wrap the
+// transaction a command's or a server's changes go through, not the one
+// `task sync` applies the server's list with: the server ran its hooks. A
+// nil r returns tx as is.
+func (r *Runner) Tx(ctx context.Context, tx tasks.Tx) tasks.Tx {
+	if r == nil {
+		return tx
+	}
+	return &hookTx{Tx: tx, ctx: ctx, r: r}
+}
+
+type hookTx struct {
+	tasks.Tx
+	ctx context.Context
+	r   *Runner
+}
+
+func (h *hookTx) Put(t tasks.Task) error {
+	cur, err := h.Tx.Get(t.ID)
+	hook := Modify
+	switch {
+	case errors.Is(err, tasks.ErrNotFound):
+		hook = Add
+	case err != nil:
+		return err
+	case t.Done() && !cur.Done():
+		hook = Complete
+	}
+	if t, err = h.r.Run(h.ctx, hook, t); err != nil {
+		return err
+	}
+	return h.Tx.Put(t)
+}
+
+func (h *hookTx) Delete(id int) error {
+	if t, err := h.Tx.Get(id); err == nil {
+		if _, err := h.r.Run(h.ctx, Delete, t); err != nil {
+			return err
+		}
+	}
+	return h.Tx.Delete(id)
+}
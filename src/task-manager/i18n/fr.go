@@ -0,0 +1,475 @@
+package i18n
+
+// french is the French catalog. Keys are the English formats exactly as
+// the code passes them, trailing newlines and tabs included.
+var french = map[string]string{
+	// Words used on their own: statuses, priorities, history actions,
+	// board lanes, digest sections and weekdays.
+	"pending":             "à faire",
+	"started":             "en cours",
+	"completed":           "terminée",
+	"in-progress":         "en cours",
+	"waiting":             "en attente",
+	"done":                "terminée",
+	"cancelled":           "annulée",
+	"created":             "créée",
+	"updated":             "modifiée",
+	"deleted":             "supprimée",
+	"low":                 "basse",
+	"medium":              "moyenne",
+	"high":                "haute",
+	"OVERDUE":             "EN RETARD",
+	"today":               "aujourd'hui",
+	"tomorrow":            "demain",
+	"yesterday":           "hier",
+	"today %s":            "aujourd'hui %s",
+	"tomorrow %s":         "demain %s",
+	"in %s":               "dans %s",
+	"%s overdue":          "en retard de %s",
+	"%s ago":              "il y a %s",
+	"Backlog":             "À faire",
+	"In Progress":         "En cours",
+	"Done":                "Terminé",
+	"Waiting":             "En attente",
+	"Overdue":             "En retard",
+	"Due today":           "À rendre aujourd'hui",
+	"Completed yesterday": "Terminées hier",
+	"Monday":              "lundi",
+	"Tuesday":             "mardi",
+	"Wednesday":           "mercredi",
+	"Thursday":            "jeudi",
+	"Friday":              "vendredi",
+	"Saturday":            "samedi",
+	"Sunday":              "dimanche",
+	"Complete":            "Terminer",
+	"read-only":           "lecture seule",
+	"read-write":          "lecture-écriture",
+	"hashed":              "hachée",
+	"plain text":          "en clair",
+	" (active)":           " (actif)",
+	"Total":               "Total",
+
+	// Counts.
+	"%d task":           "%d tâche",
+	"%d tasks":          "%d tâches",
+	"%d day":            "%d jour",
+	"%d matching task":  "%d tâche correspondante",
+	"%d matching tasks": "%d tâches correspondantes",
+	"%d days":           "%d jours",
+	"%d week":           "%d semaine",
+	"%d weeks":          "%d semaines",
+	"%d month":          "%d mois",
+	"%d months":         "%d mois",
+	"%d year":           "%d an",
+	"%d years":          "%d ans",
+	"%d conflict":       "%d conflit",
+	"%d conflicts":      "%d conflits",
+	"%d token":          "%d jeton",
+	"%d tokens":         "%d jetons",
+
+	// Table headings.
+	"ID\tStatus\tPri\tDue\tProject\tDescription\tTags":           "ID\tStatut\tPrio\tÉchéance\tProjet\tDescription\tÉtiquettes",
+	"ID\tStatus\tPri\tDue\tProject\tAssignee\tDescription\tTags": "ID\tStatut\tPrio\tÉchéance\tProjet\tResponsable\tDescription\tÉtiquettes",
+	"ID\tUrg\tPri\tDue\tProject\tDescription\tTags":              "ID\tUrg\tPrio\tÉchéance\tProjet\tDescription\tÉtiquettes",
+	"ID\tDeleted\tBy\tPurged in\tDescription":                    "ID\tSupprimée\tPar\tPurgée dans\tDescription",
+	"Tag\tOpen\tTotal":       "Étiquette\tOuvertes\tTotal",
+	"Project\tPending\tDone": "Projet\tÀ faire\tFaites",
+	"\tList\tOpen\tTotal":    "\tListe\tOuvertes\tTotal",
+	"Name\tFilter":           "Nom\tFiltre",
+	"Project\tTasks\tEstimated\tTracked\tRatio\tAccuracy": "Projet\tTâches\tEstimé\tPassé\tRapport\tPrécision",
+	"Name\tAccess\tStored":                                "Nom\tAccès\tStockage",
+	"Time\tUser\tCommand\tChange":                         "Heure\tUtilisateur\tCommande\tModification",
+	"Time\tUser\tCommand\tTask\tChange":                   "Heure\tUtilisateur\tCommande\tTâche\tModification",
+
+	// task show.
+	"Task %d: %s\n\n":      "Tâche %d : %s\n\n",
+	"Status:\t%s\n":        "Statut :\t%s\n",
+	"Created:\t%s\n":       "Créée :\t%s\n",
+	"Due:\t%s\n":           "Échéance :\t%s\n",
+	"Snoozed:\tuntil %s\n": "Reportée :\tjusqu'au %s\n",
+	"Priority:\t%s\n":      "Priorité :\t%s\n",
+	"Estimate:\t%s\n":      "Estimation :\t%s\n",
+	"Tracked:\t%s\n":       "Temps passé :\t%s\n",
+	"Project:\t%s\n":       "Projet :\t%s\n",
+	"Context:\t@%s\n":      "Contexte :\t@%s\n",
+	"Context:\t-\n":        "Contexte :\t-\n",
+	"Assignee:\t%s\n":      "Responsable :\t%s\n",
+	"Tags:\t%s\n":          "Étiquettes :\t%s\n",
+	"Parent:\t%d %s\n":     "Parente :\t%d %s\n",
+	"Subtasks:\t%s\n":      "Sous-tâches :\t%s\n",
+	"UUID:\t%s\n":          "UUID :\t%s\n",
+	"Conflicts:\t%d (review with 'task conflicts %d')\n": "Conflits :\t%d (à examiner avec 'task conflicts %d')\n",
+	"\nNotes:":       "\nNotes :",
+	"\nAttachments:": "\nPièces jointes :",
+
+	// Changes to tasks.
+	"Added task %d: %s\n":                        "Tâche %d ajoutée : %s\n",
+	"Completed task %d: %s\n":                    "Tâche %d terminée : %s\n",
+	"Skipped task %d: already completed\n":       "Tâche %d ignorée : déjà terminée\n",
+	"Completed %s, skipped %d\n":                 "Terminé : %s, ignorées : %d\n",
+	"Deleted task %d: %s\n":                      "Tâche %d supprimée : %s\n",
+	"Deleted %s\n":                               "Supprimé : %s\n",
+	"Updated task %d: %s\n":                      "Tâche %d modifiée : %s\n",
+	"Started task %d: %s\n":                      "Tâche %d commencée : %s\n",
+	"Stopped task %d: %s\n":                      "Tâche %d remise à faire : %s\n",
+	"(none of %d matching task on this page)\n":  "(%d tâche correspondante, aucune sur cette page)\n",
+	"(none of %d matching tasks on this page)\n": "(%d tâches correspondantes, aucune sur cette page)\n",
+	"(%d-%d of %s)\n":                            "(%d-%d sur %s)\n",
+	"Task %d is waiting: %s\n":                   "Tâche %d en attente : %s\n",
+	"Cancelled task %d: %s\n":                    "Tâche %d annulée : %s\n",
+	"Reopened task %d: %s\n":                     "Tâche %d rouverte : %s\n",
+	"Assigned task %d to %s: %s\n":               "Tâche %d attribuée à %s : %s\n",
+	"Unassigned task %d: %s\n":                   "Tâche %d sans responsable : %s\n",
+	"Added note %d to task %d: %s\n":             "Note %d ajoutée à la tâche %d : %s\n",
+	"Snoozed task %d until %s: %s\n":             "Tâche %d reportée au %s : %s\n",
+	"Woke task %d: %s\n":                         "Tâche %d réveillée : %s\n",
+	"Restored task %d: %s\n":                     "Tâche %d restaurée : %s\n",
+	"Restored task %d as %d: %s\n":               "Tâche %d restaurée sous le numéro %d : %s\n",
+	"Purged %s\n":                                "Purgé : %s\n",
+	"Undid %s from %s\n":                         "Annulé : %s du %s\n",
+	"  removed task %d\n":                        "  tâche %d retirée\n",
+	"  restored task %d: %s\n":                   "  tâche %d restaurée : %s\n",
+	"  put task %d back in the trash: %s\n":      "  tâche %d remise à la corbeille : %s\n",
+	"Imported %s from %s\n":                      "Importé : %s depuis %s\n",
+	"Attached %d to task %d: %s\n":               "Pièce jointe %d ajoutée à la tâche %d : %s\n",
+	"Skipped %s: already attached to task %d\n":  "%s ignoré : déjà joint à la tâche %d\n",
+	"Detached %s from task %d: %s\n":             "%s retiré de la tâche %d : %s\n",
+	"Skipped %s already in the list\n":           "Ignoré : %s déjà dans la liste\n",
+
+	// Listings.
+	"No tasks.":     "Aucune tâche.",
+	"No tags.":      "Aucune étiquette.",
+	"No projects.":  "Aucun projet.",
+	"No changes.":   "Aucune modification.",
+	"No conflicts.": "Aucun conflit.",
+	"No tokens.":    "Aucun jeton.",
+	"No completed tasks with both an estimate and tracked time.": "Aucune tâche terminée avec à la fois une estimation et un temps passé.",
+	"The trash is empty.":                                                                      "La corbeille est vide.",
+	"No history for task %d.\n":                                                                "Aucun historique pour la tâche %d.\n",
+	"(%d snoozed task hidden until later; -all shows it)\n":                                    "(%d tâche reportée masquée pour l'instant ; -all l'affiche)\n",
+	"(%d snoozed tasks hidden until later; -all shows them)\n":                                 "(%d tâches reportées masquées pour l'instant ; -all les affiche)\n",
+	"(context %s; 'task context none' clears it)\n":                                            "(contexte %s ; 'task context none' le désactive)\n",
+	"\nWatching for changes (updated %s); Ctrl-C stops.\n":                                     "\nEn attente de modifications (mis à jour à %s) ; Ctrl-C pour arrêter.\n",
+	"Open tasks over the last %s: %d -> %d (%d added, %d reopened, %d closed, %d deleted)\n\n": "Tâches ouvertes sur %s : %d -> %d (%d ajoutées, %d rouvertes, %d fermées, %d supprimées)\n\n",
+	"Digest for %s, %s":                                                                        "Résumé du %s %s",
+	"  nothing":                                                                                "  rien",
+	"due %s":                                                                                   "échéance %s",
+	"Nothing due within %s\n":                                                                  "Rien à rendre d'ici %s\n",
+
+	// Contexts.
+	"Defined context %s: %s\n":   "Contexte %s défini : %s\n",
+	"Redefined context %s: %s\n": "Contexte %s redéfini : %s\n",
+	"Deleted context %s\n":       "Contexte %s supprimé\n",
+	"Context cleared":            "Contexte désactivé",
+	"Context %s is active: %s\n": "Le contexte %s est actif : %s\n",
+	"No contexts. Define one with 'task context define <name> <filter>'.": "Aucun contexte. Définissez-en un avec 'task context define <nom> <filtre>'.",
+
+	// Conflicts.
+	"Task %d: %s\n":                          "Tâche %d : %s\n",
+	"  no conflicts":                         "  aucun conflit",
+	"  %s: kept %s, discarded %s (set %s)\n": "  %s : %s conservé, %s écarté (saisi le %s)\n",
+	"kept the current values":                "valeurs actuelles conservées",
+	"took the discarded values":              "valeurs écartées reprises",
+	"Resolved %s on task %d (%s): %s\n":      "Résolu : %s sur la tâche %d (%s) : %s\n",
+
+	// Choosing a task by description.
+	"Several tasks match %q:\n": "Plusieurs tâches correspondent à %q :\n",
+	"  %d) task %d: %s\n":       "  %d) tâche %d : %s\n",
+	"%s which? [1-%d]: ":        "%s laquelle ? [1-%d] : ",
+
+	// Confirming destructive changes.
+	"Delete %s?":                        "Supprimer %s ?",
+	"Complete %s?":                      "Terminer %s ?",
+	"Purge %s from the trash for good?": "Purger définitivement %s de la corbeille ?",
+	"[y/N]":                             "[o/N]",
+	"y":                                 "o",
+	"yes":                               "oui",
+
+	// Duplicates.
+	"Task %d looks the same: %s\n":                                        "La tâche %d semble identique : %s\n",
+	"Add a [n]ote to task %d instead, [a]dd anyway or [c]ancel? [n/a/C] ": "Ajouter une [n]ote à la tâche %d à la place, l'[a]jouter quand même ou abandonner ? [n/a/C] ",
+	"note":                                 "note",
+	"add":                                  "ajouter",
+	"Skipped %q: task %d looks the same\n": "%q ignorée : la tâche %d semble identique\n",
+
+	"Shared %s in %s\n": "Partagé : %s dans %s\n",
+
+	// Backups.
+	"Backed up %s (%s) to %s\n":              "Sauvegardé : %s (%s) dans %s\n",
+	"%d list":                                "%d liste",
+	"%d lists":                               "%d listes",
+	"%d old backup":                          "%d ancienne sauvegarde",
+	"%d old backups":                         "%d anciennes sauvegardes",
+	"Removed %s\n":                           "Supprimé : %s\n",
+	"No backups in %s\n":                     "Aucune sauvegarde dans %s\n",
+	"Snapshot\tTaken\tSize":                  "Sauvegarde\tPrise le\tTaille",
+	"Replace %s with the snapshot taken %s?": "Remplacer %s par la sauvegarde du %s ?",
+	"Rolled list %s back to %s\n":            "Liste %s ramenée à %s\n",
+
+	// Sync and integrations.
+	"Synced with %s: %d sent, %d received, %d removed, %d conflicts\n":       "Synchronisé avec %s : %d envoyées, %d reçues, %d retirées, %d conflits\n",
+	"Review them with 'task conflicts'.":                                     "Examinez-les avec 'task conflicts'.",
+	"Synced %s with GitHub: %d opened, %d sent, %d received, %d conflicts\n": "Synchronisé avec GitHub : %s, %d ouverts, %d envoyés, %d reçus, %d conflits\n",
+	"%d issue no longer exists; its tasks are no longer mirrored\n":          "%d ticket n'existe plus ; ses tâches ne sont plus synchronisées\n",
+	"%d issues no longer exist; their tasks are no longer mirrored\n":        "%d tickets n'existent plus ; leurs tâches ne sont plus synchronisées\n",
+	"Pulled from %s: %d added, %d updated\n":                                 "Importé depuis %s : %d ajoutées, %d modifiées\n",
+	"Moved %s to %s\n":                                                       "%s passé à %s\n",
+	"No completed tasks to report":                                           "Aucune tâche terminée à signaler",
+	"Serving tasks over gRPC on %s\n":                                        "Tâches servies en gRPC sur %s\n",
+	"Serving sync on http://%s%s\n":                                          "Synchronisation servie sur http://%s%s\n",
+	"Serving changes on ws://%s%s\n":                                         "Modifications servies sur ws://%s%s\n",
+	"Serving GraphQL on http://%s%s\n":                                       "GraphQL servi sur http://%s%s\n",
+
+	// Passwords and tokens.
+	"CalDAV password for %s: ":                          "Mot de passe CalDAV de %s : ",
+	"Passphrase: ":                                      "Phrase secrète : ",
+	"New passphrase for the task file: ":                "Nouvelle phrase secrète du fichier de tâches : ",
+	"Repeat passphrase: ":                               "Répétez la phrase secrète : ",
+	"Forgot cached keys":                                "Clés en cache oubliées",
+	"Created %s token %s. It is not shown again:\n%s\n": "Jeton %[2]s créé en %[1]s. Il ne sera plus affiché :\n%[3]s\n",
+	"Revoked %s\n":                                      "Révoqué : %s\n",
+
+	// Help and usage errors of package cli.
+	"usage: %s\n":              "usage : %s\n",
+	"Usage: %s %s\n":           "Usage : %s %s\n",
+	"Aliases: %s\n":            "Alias : %s\n",
+	"[flags] <command> [args]": "[options] <commande> [arguments]",
+	"\nCommands:":              "\nCommandes :",
+	"\nFlags:":                 "\nOptions :",
+	"\nRun '%s help <command>' for details on a command.\n": "\nLancez '%s help <commande>' pour le détail d'une commande.\n",
+	"missing command":                    "commande manquante",
+	"unknown command %q":                 "commande inconnue %q",
+	"unknown command %q (run '%s help')": "commande inconnue %q (lancez '%s help')",
+	"%q expands to nothing":              "%q ne se développe en rien",
+	"%q expands to unknown command %q":   "%q se développe en la commande inconnue %q",
+
+	// The edit form.
+	"# Editing task %d. Lines starting with '#' are ignored.\n":   "# Modification de la tâche %d. Les lignes commençant par '#' sont ignorées.\n",
+	"# Save and quit to apply; clear the description to abort.\n": "# Enregistrez et quittez pour appliquer ; videz la description pour abandonner.\n",
+
+	// Errors of package tasks.
+	"task not found":           "tâche introuvable",
+	"task %d: %w":              "tâche %d : %w",
+	"no task with UUID %s: %w": "aucune tâche avec l'UUID %s : %w",
+	"UUID prefix %s is ambiguous: it matches tasks %s": "le préfixe d'UUID %s est ambigu : il correspond aux tâches %s",
+	"invalid task":                             "tâche invalide",
+	"already completed":                        "déjà terminée",
+	"invalid status change":                    "changement de statut invalide",
+	"task data is corrupt":                     "les données des tâches sont corrompues",
+	"%s: %w: %v":                               "%s : %w : %v",
+	"no lists":                                 "aucune liste",
+	"nothing to undo":                          "rien à annuler",
+	"wrong passphrase":                         "phrase secrète incorrecte",
+	"task file is encrypted":                   "le fichier de tâches est chiffré",
+	"change saved but not committed to git":    "modification enregistrée mais pas commitée dans git",
+	"the git backend needs git on the PATH":    "le moteur git a besoin de git dans le PATH",
+	"%w: priority %d":                          "%w : priorité %d",
+	"%w: empty description":                    "%w : description vide",
+	"%w: empty note":                           "%w : note vide",
+	"%w: parent ID %d":                         "%w : ID de parente %d",
+	"task %d is %w":                            "la tâche %d est %w",
+	"task %d has %d open subtask":              "la tâche %d a %d sous-tâche ouverte",
+	"task %d has %d open subtasks":             "la tâche %d a %d sous-tâches ouvertes",
+	"%w: task %d is %s; reopen it instead":     "%w : la tâche %d est %s ; rouvrez-la plutôt",
+	"%w: task %d is %s, not done or cancelled": "%w : la tâche %d est %s, ni terminée ni annulée",
+	"%w: task %d is already %s":                "%w : la tâche %d est déjà %s",
+	"%w: task %d is %s and cannot become %s":   "%w : la tâche %d est %s et ne peut pas devenir %s",
+	"task %d cannot be a subtask of itself or of its own subtasks": "la tâche %d ne peut pas être une sous-tâche d'elle-même ou de ses propres sous-tâches",
+	"parent: %w":                          "parente : %w",
+	"task %d has no conflicts to resolve": "la tâche %d n'a aucun conflit à résoudre",
+	"invalid priority %q (want low, medium, high or 0-%d)":          "priorité invalide %q (attendu : low, medium, high ou 0-%d)",
+	"unknown status %q (want %s)":                                   "statut inconnu %q (attendu : %s)",
+	"invalid date %q (want YYYY-MM-DD)":                             "date invalide %q (attendu : AAAA-MM-JJ)",
+	"unknown sort field %q (want %s)":                               "champ de tri inconnu %q (attendu : %s)",
+	"empty sort order %q":                                           "ordre de tri vide %q",
+	"invalid list name %q (use letters, digits, - and _)":           "nom de liste invalide %q (utilisez des lettres, des chiffres, - et _)",
+	"filter: empty expression":                                      "filtre : expression vide",
+	"filter: unexpected %q":                                         "filtre : %q inattendu",
+	"filter: unterminated quote":                                    "filtre : guillemet non refermé",
+	"filter: missing )":                                             "filtre : ) manquante",
+	"filter: incomplete term at %q (want field=value)":              "filtre : terme incomplet à %q (attendu : champ=valeur)",
+	"filter: unexpected end (want field=value)":                     "filtre : fin inattendue (attendu : champ=valeur)",
+	"filter: expected field=value at %q":                            "filtre : champ=valeur attendu à %q",
+	"filter: %s cannot be compared with %s":                         "filtre : %s ne peut pas être comparé avec %s",
+	"filter: %s cannot be compared with %s none":                    "filtre : %s ne peut pas être comparé avec %s none",
+	"filter: empty tag":                                             "filtre : étiquette vide",
+	"filter: %w":                                                    "filtre : %w",
+	"filter: %s: %w":                                                "filtre : %s : %w",
+	"filter: unknown field %q (want one of %s)":                     "filtre : champ inconnu %q (attendu : l'un de %s)",
+	"list %q: %w":                                                   "liste %q : %w",
+	"backup of %s: %w":                                              "sauvegarde de %s : %w",
+	"backup format version %d is newer than this binary supports":   "la version %d du format de sauvegarde est plus récente que ce que ce programme prend en charge",
+	"database schema version %d is newer than this binary supports": "la version %d du schéma de la base est plus récente que ce que ce programme prend en charge",
+	"%s: format version %d is newer than this binary supports":      "%s : la version %d du format est plus récente que ce que ce programme prend en charge",
+	"migrate schema to version %d: %w":                              "migration du schéma vers la version %d : %w",
+	"open %s: database is locked by another task process":           "ouverture de %s : la base est verrouillée par un autre processus task",
+	"open %s: %w":                                                   "ouverture de %s : %w",
+	"unsupported key derivation %q":                                 "dérivation de clé non prise en charge %q",
+	"create data dir: %w":                                           "création du répertoire de données : %w",
+	"lock tasks: %w":                                                "verrouillage des tâches : %w",
+	"lock %s: %w":                                                   "verrouillage de %s : %w",
+	"read tasks: %w":                                                "lecture des tâches : %w",
+	"write tasks: %w":                                               "écriture des tâches : %w",
+
+	// Errors of the task command.
+	"missing task ID":                       "ID de tâche manquant",
+	"missing task description":              "description de tâche manquante",
+	"missing search query":                  "recherche manquante",
+	"missing note text":                     "texte de note manquant",
+	"invalid task ID %q":                    "ID de tâche invalide %q",
+	"invalid task ID range %q":              "plage d'ID de tâches invalide %q",
+	"%w: nothing matches %q":                "%w : rien ne correspond à %q",
+	"%q is ambiguous; give a task ID":       "%q est ambigu ; donnez un ID de tâche",
+	"invalid choice %q":                     "choix invalide %q",
+	"%w; complete them first or use -force": "%w ; terminez-les d'abord ou utilisez -force",
+	"not confirmed, nothing was changed (-yes skips the question)":         "non confirmé, rien n'a été modifié (-yes évite la question)",
+	"not added, nothing was changed (-allow-duplicate adds it anyway)":     "non ajoutée, rien n'a été modifié (-allow-duplicate l'ajoute quand même)",
+	"restore: %w (see 'task trash')":                                       "restauration : %w (voir 'task trash')",
+	"assign takes task IDs and a user":                                     "assign prend des ID de tâches et un utilisateur",
+	"attach takes a task ID and at least one file or URL":                  "attach prend un ID de tâche et au moins un fichier ou une URL",
+	"detach takes a task ID and an attachment number":                      "detach prend un ID de tâche et un numéro de pièce jointe",
+	"open takes a task ID and an attachment number":                        "open prend un ID de tâche et un numéro de pièce jointe",
+	"edit takes a task ID":                                                 "edit prend un ID de tâche",
+	"history takes exactly one task ID":                                    "history prend exactement un ID de tâche",
+	"show takes exactly one task ID":                                       "show prend exactement un ID de tâche",
+	"note takes a task ID and the note text":                               "note prend un ID de tâche et le texte de la note",
+	"snooze takes task IDs and a date":                                     "snooze prend des ID de tâches et une date",
+	"import takes exactly one file":                                        "import prend exactement un fichier",
+	"backup takes no arguments":                                            "backup ne prend pas d'argument",
+	"board takes no arguments":                                             "board ne prend pas d'argument",
+	"burndown takes no arguments":                                          "burndown ne prend pas d'argument",
+	"caldav takes no arguments":                                            "caldav ne prend pas d'argument",
+	"digest takes no arguments":                                            "digest ne prend pas d'argument",
+	"export takes no arguments":                                            "export ne prend pas d'argument",
+	"issue no longer exists":                                               "le ticket n'existe plus",
+	"github takes no arguments":                                            "github ne prend pas d'argument",
+	"jira pull takes no arguments":                                         "jira pull ne prend pas d'argument",
+	"jira push takes no arguments":                                         "jira push ne prend pas d'argument",
+	"lists takes no arguments":                                             "lists ne prend pas d'argument",
+	"lock takes no arguments":                                              "lock ne prend pas d'argument",
+	"log takes no arguments":                                               "log ne prend pas d'argument",
+	"next takes no arguments":                                              "next ne prend pas d'argument",
+	"projects takes no arguments":                                          "projects ne prend pas d'argument",
+	"remind takes no arguments":                                            "remind ne prend pas d'argument",
+	"serve takes no arguments":                                             "serve ne prend pas d'argument",
+	"share takes no arguments":                                             "share ne prend pas d'argument",
+	"stats takes no arguments":                                             "stats ne prend pas d'argument",
+	"sync takes no arguments":                                              "sync ne prend pas d'argument",
+	"tags takes no arguments":                                              "tags ne prend pas d'argument",
+	"trash takes no arguments":                                             "trash ne prend pas d'argument",
+	"undo takes no arguments":                                              "undo ne prend pas d'argument",
+	"context list takes no arguments":                                      "context list ne prend pas d'argument",
+	"context none takes no arguments":                                      "context none ne prend pas d'argument",
+	"token list takes no arguments":                                        "token list ne prend pas d'argument",
+	"context define takes a name and a filter":                             "context define prend un nom et un filtre",
+	"context delete takes one name":                                        "context delete prend un seul nom",
+	"context takes one name (define a context with 'task context define')": "context prend un seul nom (définissez un contexte avec 'task context define')",
+	"token create takes one name":                                          "token create prend un seul nom",
+	"token revoke takes token names":                                       "token revoke prend des noms de jetons",
+	"git needs arguments, e.g. task git log":                               "git a besoin d'arguments, p. ex. task git log",
+	"-stdin takes no description arguments":                                "-stdin ne prend pas d'argument de description",
+	"no tasks on standard input":                                           "aucune tâche sur l'entrée standard",
+	"-due: %v":                                                             "-due : %v",
+	"-priority: %v":                                                        "-priority : %v",
+	"-estimate: %v":                                                        "-estimate : %v",
+	"-due-before: %v":                                                      "-due-before : %v",
+	"-due-after: %v":                                                       "-due-after : %v",
+	"-sort: %v":                                                            "-sort : %v",
+	"due: %v":                                                              "due : %v",
+	"priority: %v":                                                         "priority : %v",
+	"estimate: %v":                                                         "estimate : %v",
+	"status: %v":                                                           "status : %v",
+	"-limit, -offset and -page must not be negative":                       "-limit, -offset et -page ne doivent pas être négatifs",
+	"-page and -offset cannot be combined":                                 "-page et -offset ne peuvent pas être combinés",
+	"-days must be at least 1":                                             "-days doit valoir au moins 1",
+	"-interval must be positive":                                           "-interval doit être positif",
+	"-json cannot be combined with -daemon":                                "-json ne peut pas être combiné avec -daemon",
+	"-keep and -take are mutually exclusive":                               "-keep et -take s'excluent mutuellement",
+	"-keep and -take need a task ID":                                       "-keep et -take ont besoin d'un ID de tâche",
+	"fields need -keep or -take":                                           "les champs ont besoin de -keep ou -take",
+	"-map applies only to the csv format":                                  "-map ne s'applique qu'au format csv",
+	"-map wants field=column, got %q":                                      "-map attend champ=colonne, reçu %q",
+	"-map: unknown field %q (want one of %s)":                              "-map : champ inconnu %q (attendu : l'un de %s)",
+	"-post: no [[webhooks]] entry in the config file lists the %q event":   "-post : aucune entrée [[webhooks]] du fichier de configuration ne liste l'événement %q",
+	"cannot tell the format of %q; use -format":                            "impossible de deviner le format de %q ; utilisez -format",
+	"unknown format %q (want %s)":                                          "format inconnu %q (attendu : %s)",
+	"unknown backend %q (want bolt, json or git)":                          "moteur inconnu %q (attendu : bolt, json ou git)",
+	"import %s: %w":                                                        "import de %s : %w",
+	"export %s: %w":                                                        "export en %s : %w",
+	"context given twice: %s":                                              "contexte donné deux fois : %s",
+	"%s given twice: %s":                                                   "%s donné deux fois : %s",
+	"%s given twice":                                                       "%s donné deux fois",
+	"description given twice (%q)":                                         "description donnée deux fois (%q)",
+	"description cannot be empty":                                          "la description ne peut pas être vide",
+	"invalid date %q (want YYYY-MM-DD, YYYY-MM-DD HH:MM or e.g. \"tomorrow\", \"next friday 5pm\", \"in 3 days\")": "date invalide %q (attendu : AAAA-MM-JJ, AAAA-MM-JJ HH:MM ou p. ex. \"tomorrow\", \"next friday 5pm\", \"in 3 days\")",
+	"invalid estimate %q (want e.g. 45m, 2h or 1h30m)":                                                             "estimation invalide %q (attendu p. ex. : 45m, 2h ou 1h30m)",
+	"task %d has no attachments":                              "la tâche %d n'a pas de pièce jointe",
+	"task %d has %d attachments; say which to open":           "la tâche %d a %d pièces jointes ; dites laquelle ouvrir",
+	"task %d has no attachment %d":                            "la tâche %d n'a pas de pièce jointe %d",
+	"attachment %d of task %d: %w":                            "pièce jointe %d de la tâche %d : %w",
+	"invalid attachment number %q":                            "numéro de pièce jointe invalide %q",
+	"opening files is not supported on %s":                    "l'ouverture de fichiers n'est pas prise en charge sous %s",
+	"attach: %w":                                              "pièce jointe : %w",
+	"editor %q: %w":                                           "éditeur %q : %w",
+	"line %d: expected \"field: value\"":                      "ligne %d : \"champ: valeur\" attendu",
+	"line %d: %v":                                             "ligne %d : %v",
+	"line %d: completed must be true or false":                "ligne %d : completed doit valoir true ou false",
+	"line %d: unknown field %q":                               "ligne %d : champ inconnu %q",
+	"empty description, edit aborted":                         "description vide, modification abandonnée",
+	"alias %s: %w":                                            "alias %s : %w",
+	"trailing backslash in %q":                                "barre oblique inverse finale dans %q",
+	"unterminated %c quote in %q":                             "guillemet %c non refermé dans %q",
+	"invalid context name %q":                                 "nom de contexte invalide %q",
+	"no context named %s":                                     "aucun contexte nommé %s",
+	"no context named %s (see 'task context')":                "aucun contexte nommé %s (voir 'task context')",
+	"context %s: %w":                                          "contexte %s : %w",
+	"invalid token name %q":                                   "nom de jeton invalide %q",
+	"a token named %s already exists; revoke it first":        "un jeton nommé %s existe déjà ; révoquez-le d'abord",
+	"no token named %s":                                       "aucun jeton nommé %s",
+	"create backup dir: %w":                                   "création du répertoire de sauvegarde : %w",
+	"write backup: %w":                                        "écriture de la sauvegarde : %w",
+	"write %s: %w":                                            "écriture de %s : %w",
+	"list %s: %w":                                             "liste %s : %w",
+	"%w: no backups in %s":                                    "%w : aucune sauvegarde dans %s",
+	"no collection configured; pass -url collection-url":      "aucune collection configurée ; passez -url url-de-collection",
+	"no remote configured; pass -remote url":                  "aucun serveur configuré ; passez -remote url",
+	"caldav: set TASK_CALDAV_PASSWORD or run from a terminal": "caldav : définissez TASK_CALDAV_PASSWORD ou lancez depuis un terminal",
+	"github: set TASK_GITHUB_TOKEN or GITHUB_TOKEN to a token with access to the issues": "github : définissez TASK_GITHUB_TOKEN ou GITHUB_TOKEN avec un jeton qui a accès aux tickets",
+	"jira: set url in the [jira] table of the config file":                               "jira : définissez url dans la table [jira] du fichier de configuration",
+	"jira: set TASK_JIRA_TOKEN to an API token":                                          "jira : définissez TASK_JIRA_TOKEN avec un jeton d'API",
+	"task git needs the git backend (-backend git or backend = \"git\")":                 "task git a besoin du moteur git (-backend git ou backend = \"git\")",
+	"encryption needs the json backend (-backend json or backend = \"json\")":            "le chiffrement a besoin du moteur json (-backend json ou backend = \"json\")",
+	"no task history in %s yet":                                                          "pas encore d'historique des tâches dans %s",
+	"git: %w":                                                                            "git : %w",
+	"task file is encrypted: set TASK_PASSPHRASE or run from a terminal":                 "le fichier de tâches est chiffré : définissez TASK_PASSPHRASE ou lancez depuis un terminal",
+	"TASK_ENCRYPT: invalid boolean %q":                                                   "TASK_ENCRYPT : booléen invalide %q",
+	"empty passphrase":                                                                   "phrase secrète vide",
+	"passphrases do not match":                                                           "les phrases secrètes ne correspondent pas",
+	"key cache %s is not a private directory":                                            "le cache de clés %s n'est pas un répertoire privé",
+	"unknown color %q":                                                                   "couleur inconnue %q",
+	"theme: unknown theme %q (want %s)":                                                  "theme : thème inconnu %q (attendu : %s)",
+	"colors.%s: %w":                                                                      "colors.%s : %w",
+	"colors: unknown entry %q (want header, overdue, high or completed)":                 "colors : entrée inconnue %q (attendu : header, overdue, high ou completed)",
+	"config: %w":        "configuration : %w",
+	"config %s: %w":     "configuration %s : %w",
+	"config %s: %s: %w": "configuration %s : %s : %w",
+	"unknown profile %q: define [profiles.%s] in the config file":       "profil inconnu %q : définissez [profiles.%s] dans le fichier de configuration",
+	"config %s: invalid profile name %q (use letters, digits, - and _)": "configuration %s : nom de profil invalide %q (utilisez des lettres, des chiffres, - et _)",
+	"config %s: profiles.%s: %w":                                        "configuration %s : profiles.%s : %w",
+	"config %s: profiles.%s: profiles cannot be nested":                 "configuration %s : profiles.%s : les profils ne peuvent pas être imbriqués",
+	"config %s: unknown profile %q":                                     "configuration %s : profil inconnu %q",
+	"config %s: unknown setting %q":                                     "configuration %s : réglage inconnu %q",
+	"config %s: default_priority: %w":                                   "configuration %s : default_priority : %w",
+	"config %s: remind_within: %w":                                      "configuration %s : remind_within : %w",
+	"config %s: key_cache: %w":                                          "configuration %s : key_cache : %w",
+	"config %s: sort: %w":                                               "configuration %s : sort : %w",
+	"config %s: invalid alias name %q":                                  "configuration %s : nom d'alias invalide %q",
+	"config %s: alias.%s: want a command line, got %q":                  "configuration %s : alias.%s : ligne de commande attendue, reçu %q",
+	"config %s: csv_columns: unknown field %q (want one of %s)":         "configuration %s : csv_columns : champ inconnu %q (attendu : l'un de %s)",
+	"config %s: jira.fields: unknown task field %q (want one of %s)":    "configuration %s : jira.fields : champ de tâche inconnu %q (attendu : l'un de %s)",
+	"config %s: locale: unsupported language %q (want one of %s)":       "configuration %s : locale : langue non prise en charge %q (attendu : l'une de %s)",
+	"config %s: trash_days must not be negative":                        "configuration %s : trash_days ne doit pas être négatif",
+	"config %s: backup_keep must not be negative":                       "configuration %s : backup_keep ne doit pas être négatif",
+	"config %s: webhooks[%d]: missing url":                              "configuration %s : webhooks[%d] : url manquante",
+	"config %s: webhooks[%d]: unknown event %q (want %s)":               "configuration %s : webhooks[%d] : événement inconnu %q (attendu : %s)",
+}
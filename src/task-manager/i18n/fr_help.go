@@ -0,0 +1,948 @@
+package i18n
+
+import "maps"
+
+// frenchHelp is the French help of the task commands: their summaries,
+// descriptions and flags. It is kept apart from french for its size and
+// merged into it.
+var frenchHelp = map[string]string{
+	"Manage tasks from the command line":                                   "Gérer des tâches en ligne de commande",
+	"storage backend: bolt, json or git (env TASK_BACKEND, default bolt)":  "moteur de stockage : bolt, json ou git (env TASK_BACKEND, bolt par défaut)",
+	"config file (env TASK_CONFIG, default ~/.config/task/config.toml)":    "fichier de configuration (env TASK_CONFIG, ~/.config/task/config.toml par défaut)",
+	"directory holding the task database (env TASK_DATA_DIR)":              "répertoire de la base de tâches (env TASK_DATA_DIR)",
+	"print errors to stderr as JSON objects (env TASK_JSON_ERRORS)":        "afficher les erreurs sur stderr en objets JSON (env TASK_JSON_ERRORS)",
+	"task list to use (env TASK_LIST, default from config or \"default\")": "liste de tâches à utiliser (env TASK_LIST, par défaut celle de la configuration ou \"default\")",
+	"config profile to use, e.g. work (env TASK_PROFILE)":                  "profil de configuration à utiliser, p. ex. work (env TASK_PROFILE)",
+	"Add a new task": "Ajouter une tâche",
+
+	`Fields can be written into the description, so quick capture needs a
+single argument:
+
+  task add "Buy milk +groceries @errands due:fri p:high"
+
+  +name          attach a tag
+  @name          set the context
+  due:friday     any date -due takes, with - for spaces: due:next-week
+  p:high         or pri:, priority:, any level -priority takes
+  est:30m        or estimate:, any effort -estimate takes
+  project:home   or proj:; also assignee:
+
+They override the flags. Other words, such as URLs, stay in the
+description; -literal keeps it exactly as typed.
+
+A description much like that of an open task is taken for a repeat: you
+are asked whether to add it as a note to that task instead, add it anyway
+or cancel. With -stdin such lines are skipped with a warning.
+-allow-duplicate adds them without asking.
+
+With -stdin, every non-blank line of standard input becomes a task, all
+added in one transaction and with the same flags:
+
+  cat todo.txt | task add -stdin -project house`: `Les champs peuvent s'écrire dans la description, si bien qu'une saisie
+rapide tient en un seul argument :
+
+  task add "Buy milk +groceries @errands due:fri p:high"
+
+  +nom           ajoute une étiquette
+  @nom           fixe le contexte
+  due:friday     toute date acceptée par -due, avec - pour les espaces :
+                 due:next-week
+  p:high         ou pri:, priority:, tout niveau accepté par -priority
+  est:30m        ou estimate:, tout effort accepté par -estimate
+  project:home   ou proj: ; de même assignee:
+
+Ils l'emportent sur les options. Les autres mots, comme les URL, restent
+dans la description ; -literal la garde telle qu'elle a été tapée.
+
+Une description très proche de celle d'une tâche ouverte est prise pour
+un doublon : on vous demande s'il faut plutôt l'ajouter en note à cette
+tâche, l'ajouter quand même ou abandonner. Avec -stdin, ces lignes sont
+ignorées avec un avertissement. -allow-duplicate les ajoute sans
+demander.
+
+Avec -stdin, chaque ligne non vide de l'entrée standard devient une
+tâche, toutes ajoutées en une seule transaction et avec les mêmes
+options :
+
+  cat todo.txt | task add -stdin -project house`,
+
+	"add tasks even if they repeat an open task":                     "ajouter les tâches même si elles répètent une tâche ouverte",
+	"user responsible for the task":                                  "personne responsable de la tâche",
+	"GTD context the task is done in, e.g. @home":                    "contexte GTD dans lequel la tâche se fait, p. ex. @home",
+	"due date (YYYY-MM-DD or YYYY-MM-DD HH:MM)":                      "échéance (AAAA-MM-JJ ou AAAA-MM-JJ HH:MM)",
+	"expected effort, e.g. 45m or 2h":                                "effort prévu, p. ex. 45m ou 2h",
+	"keep +tag, @context and field:value words in the description":   "garder les mots +étiquette, @contexte et champ:valeur dans la description",
+	"make the new task a subtask of this task ID or UUID":            "faire de la tâche une sous-tâche de cet ID ou UUID",
+	"priority: low, medium, high, none or 0-9 (default from config)": "priorité : low, medium, high, none ou 0-9 (par défaut celle de la configuration)",
+	"project the task belongs to (dots nest: website.blog)":          "projet de la tâche (les points imbriquent : website.blog)",
+	"add one task per line of standard input":                        "ajouter une tâche par ligne de l'entrée standard",
+	"attach a tag (repeatable)":                                      "ajouter une étiquette (répétable)",
+	"List all tasks, most important first":                           "Lister toutes les tâches, les plus importantes d'abord",
+
+	`A filter expression narrows the list where the flags fall short:
+
+  task list 'status=pending and (tag=home or priority=high) and due<2025-01-01'
+
+Terms compare project, tag, context, assignee, status, priority, search,
+due, created or completed with a value using = (or :) and !=, and
+priority and the dates also with <, <=, > and >=. They combine with and,
+or and not, and parentheses; dates are read like -due-before's, in double
+quotes if they hold spaces or colons ("next friday"), and none matches
+tasks without one. The flags and the expression must all match.
+
+Due dates are shown relative to now ("today 17:00", "in 3 days",
+"2 weeks overdue"); -absolute, or absolute_dates in the config file, shows
+them as dates instead.
+
+Tasks snoozed with 'task snooze' are left out until their date passes,
+unless -all is given. The filter of the active 'task context', if any,
+applies on top of the flags.
+
+-limit shows at most that many tasks, after skipping -offset of them;
+-page 3 shows the third page of -limit tasks (20 if -limit is not given).
+A footer then says which of how many matching tasks are shown. With -tree
+the rows are counted, subtasks included.
+
+-watch keeps the list on screen and redraws it whenever the task data
+changes, from this or any other process, and once a minute so due dates
+stay current. Ctrl-C stops it.
+
+Without -sort the list is ordered by the config file's sort setting, or
+by descending priority. -sort takes comma-separated fields, each ascending
+unless prefixed with "-"; ties fall through to the next field and finally
+to the ID. Subtasks without a due date or priority of their own sort by
+their parent's; other tasks without a due date, project, context or
+assignee sort last on that field. Fields: assignee, completed, context, created, description, due, id, priority, project, status.`: `Une expression de filtre affine la liste là où les options ne suffisent
+pas :
+
+  task list 'status=pending and (tag=home or priority=high) and due<2025-01-01'
+
+Les termes comparent project, tag, context, assignee, status, priority,
+search, due, created ou completed à une valeur avec = (ou :) et !=, et la
+priorité et les dates aussi avec <, <=, > et >=. Ils se combinent avec
+and, or, not et des parenthèses ; les dates se lisent comme celles de
+-due-before, entre guillemets si elles contiennent des espaces ou des
+deux-points ("next friday"), et none désigne les tâches qui n'en ont pas.
+Les options et l'expression doivent toutes correspondre.
+
+Les échéances sont affichées par rapport à maintenant ("aujourd'hui
+17:00", "dans 3 jours", "en retard de 2 semaines") ; -absolute, ou
+absolute_dates dans le fichier de configuration, les affiche en dates.
+
+Les tâches reportées avec 'task snooze' sont omises jusqu'à leur date,
+sauf avec -all. Le filtre du 'task context' actif, s'il y en a un,
+s'ajoute aux options.
+
+-limit affiche au plus ce nombre de tâches, après en avoir sauté
+-offset ; -page 3 affiche la troisième page de -limit tâches (20 si
+-limit n'est pas donné). Un pied de liste indique alors lesquelles des
+tâches correspondantes sont affichées. Avec -tree, ce sont les lignes qui
+sont comptées, sous-tâches comprises.
+
+-watch garde la liste à l'écran et la redessine dès que les données
+changent, par ce processus ou un autre, et une fois par minute pour que
+les échéances restent à jour. Ctrl-C l'arrête.
+
+Sans -sort, la liste suit le réglage sort du fichier de configuration,
+ou la priorité décroissante. -sort prend des champs séparés par des
+virgules, chacun croissant sauf s'il est précédé de "-" ; les égalités
+passent au champ suivant puis à l'ID. Les sous-tâches sans échéance ou
+priorité propre sont triées selon celles de leur parente ; les autres
+tâches sans échéance, projet, contexte ou responsable passent en dernier
+sur ce champ. Champs : assignee, completed, context, created, description, due, id, priority, project, status.`,
+
+	"show due dates as dates rather than relative to now":      "afficher les échéances en dates plutôt que par rapport à maintenant",
+	"include snoozed tasks":                                    "inclure les tâches reportées",
+	"only tasks assigned to this user (\"none\": unassigned)":  "seulement les tâches attribuées à cet utilisateur (\"none\" : sans responsable)",
+	"only tasks in this context":                               "seulement les tâches de ce contexte",
+	"only tasks due after this date":                           "seulement les tâches à rendre après cette date",
+	"only tasks due before this date":                          "seulement les tâches à rendre avant cette date",
+	"print the tasks as a JSON array":                          "afficher les tâches en tableau JSON",
+	"show at most this many tasks (0 for all)":                 "afficher au plus ce nombre de tâches (0 pour toutes)",
+	"skip this many matching tasks":                            "sauter ce nombre de tâches correspondantes",
+	"show this page of -limit tasks, counting from 1":          "afficher cette page de -limit tâches, en comptant à partir de 1",
+	"only tasks in this project or its sub-projects":           "seulement les tâches de ce projet ou de ses sous-projets",
+	"sort by these comma-separated fields, e.g. due,-priority": "trier selon ces champs séparés par des virgules, p. ex. due,-priority",
+	"only tasks with this tag (repeatable, all must match)":    "seulement les tâches ayant cette étiquette (répétable, toutes doivent correspondre)",
+	"show subtasks indented under their parents":               "afficher les sous-tâches en retrait sous leur parente",
+	"redraw the list whenever the tasks change":                "redessiner la liste dès que les tâches changent",
+	"Show tasks as a kanban board":                             "Afficher les tâches en tableau kanban",
+
+	`Lays tasks out in four columns by status: Backlog (pending), In Progress
+(started with 'task start'), Waiting ('task hold') and Done; snoozed and
+cancelled tasks are left out. Open tasks are ordered by priority and the
+most recently completed come first under Done. The board fills the
+terminal width, or $COLUMNS when output is not a terminal.`: `Répartit les tâches en quatre colonnes selon leur statut : À faire
+(pending), En cours (commencées avec 'task start'), En attente ('task
+hold') et Terminé ; les tâches reportées et annulées sont omises. Les
+tâches ouvertes sont triées par priorité, et les plus récemment terminées
+viennent en premier sous Terminé. Le tableau occupe la largeur du
+terminal, ou $COLUMNS quand la sortie n'est pas un terminal.`,
+
+	"show at most this many completed tasks (0 for all)": "afficher au plus ce nombre de tâches terminées (0 pour toutes)",
+	"List the most urgent open tasks":                    "Lister les tâches ouvertes les plus urgentes",
+
+	`Ranks open, unsnoozed tasks by an urgency score in the style of
+Taskwarrior: the sum of weighted terms for due date proximity, priority,
+age, tags, notes, project, being in progress, blocking an open parent task
+and being blocked by open subtasks, plus a bonus for tags such as "next".
+Subtasks without a due date or priority of their own count their parent's.
+The weights can be changed in the [urgency] table of the config file.
+Only tasks matching the active 'task context' are ranked.`: `Classe les tâches ouvertes et non reportées selon un score d'urgence à la
+manière de Taskwarrior : la somme de termes pondérés pour la proximité de
+l'échéance, la priorité, l'âge, les étiquettes, les notes, le projet, le
+fait d'être en cours, de bloquer une tâche parente ouverte ou d'être
+bloquée par des sous-tâches ouvertes, plus un bonus pour des étiquettes
+comme "next". Les sous-tâches sans échéance ou priorité propre comptent
+celles de leur parente. Les poids se changent dans la table [urgency] du
+fichier de configuration. Seules les tâches du 'task context' actif sont
+classées.`,
+
+	"print the tasks, with their urgency, as a JSON array": "afficher les tâches, avec leur urgence, en tableau JSON",
+	"Summarise overdue, due today and completed yesterday": "Résumer les tâches en retard, à rendre aujourd'hui et terminées hier",
+
+	`Prints a daily summary of the list: open tasks that are overdue, open
+tasks due today, and tasks completed yesterday. Snoozed tasks are left out.
+
+-cron prints plain text without color, and nothing at all when every
+section is empty, so cron only mails a digest worth reading:
+
+  0 7 * * *  task digest -cron
+
+-post also sends the digest to every [[webhooks]] entry in the config file
+that lists the "digest" event, with the plain text as its message.`: `Affiche un résumé quotidien de la liste : les tâches ouvertes en retard,
+celles à rendre aujourd'hui et les tâches terminées hier. Les tâches
+reportées sont omises.
+
+-cron affiche du texte brut sans couleur, et rien du tout quand toutes
+les sections sont vides, si bien que cron n'envoie que les résumés qui
+valent la peine d'être lus :
+
+  0 7 * * *  task digest -cron
+
+-post envoie aussi le résumé à chaque entrée [[webhooks]] du fichier de
+configuration qui liste l'événement "digest", avec le texte brut comme
+message.`,
+
+	"plain output, and none when there is nothing to report":     "sortie brute, et rien quand il n'y a rien à signaler",
+	"print the digest as JSON":                                   "afficher le résumé en JSON",
+	"send the digest to webhooks subscribed to the digest event": "envoyer le résumé aux webhooks abonnés à l'événement digest",
+	"Mark tasks as completed":                                    "Marquer des tâches comme terminées",
+
+	`Completes every listed task in one step, which undo reverts as a whole.
+Ranges such as 10-20 cover the tasks that exist in them. Subtasks given
+together with their parent are completed first; tasks that are already done
+are skipped.
+
+Instead of IDs, an open task can be named by (part of) its description:
+"grocer" finds "Buy groceries". When several tasks match equally well, you
+are asked which one to complete.
+
+Completing several tasks lists them and asks for confirmation first,
+unless -yes is given.`: `Termine toutes les tâches données en une seule étape, que undo annule
+d'un bloc. Les plages comme 10-20 couvrent les tâches qui y existent. Les
+sous-tâches données avec leur parente sont terminées d'abord ; les tâches
+déjà terminées sont ignorées.
+
+Au lieu d'un ID, une tâche ouverte peut être désignée par (une partie de)
+sa description : "grocer" trouve "Buy groceries". Quand plusieurs tâches
+correspondent aussi bien, on vous demande laquelle terminer.
+
+Terminer plusieurs tâches les liste et demande d'abord confirmation,
+sauf avec -yes.`,
+
+	"complete tasks even if they have open subtasks": "terminer les tâches même si elles ont des sous-tâches ouvertes",
+	"short for -yes": "raccourci de -yes",
+	"do not ask for confirmation, e.g. in scripts": "ne pas demander de confirmation, p. ex. dans des scripts",
+	"Mark tasks as in progress":                    "Marquer des tâches comme en cours",
+
+	`Moves the given tasks from the backlog to "In Progress" on 'task board'.
+'task stop' moves them back; completing a task ends its progress too. The
+time in between is added to the task's tracked time, which 'task show'
+displays and 'task stats' compares with its estimate.`: `Fait passer les tâches données de la colonne À faire à "En cours" dans
+'task board'. 'task stop' les remet à faire ; terminer une tâche met
+aussi fin à son avancement. Le temps écoulé entre-temps s'ajoute au temps
+passé sur la tâche, que 'task show' affiche et que 'task stats' compare à
+son estimation.`,
+
+	"Move started or waiting tasks back to the backlog":                   "Remettre à faire des tâches commencées ou en attente",
+	"The time the tasks were in progress is added to their tracked time.": "Le temps pendant lequel les tâches étaient en cours s'ajoute à leur temps passé.",
+	"Mark tasks as waiting on someone or something else":                  "Marquer des tâches comme en attente de quelqu'un ou de quelque chose",
+
+	`Waiting tasks stay in the list, marked [~], and get their own lane on
+'task board'; 'task start' or 'task stop' picks them up again. To hide a
+task until a date instead, use 'task snooze'.`: `Les tâches en attente restent dans la liste, marquées [~], et ont leur
+propre colonne dans 'task board' ; 'task start' ou 'task stop' les
+reprend. Pour masquer plutôt une tâche jusqu'à une date, utilisez 'task
+snooze'.`,
+
+	"Close tasks without completing them": "Clore des tâches sans les terminer",
+
+	`Cancelled tasks are closed like completed ones, marked [-] in the list,
+but do not count as done in statistics. 'task reopen' undoes this.`: `Les tâches annulées sont closes comme les tâches terminées, marquées [-]
+dans la liste, mais ne comptent pas comme faites dans les statistiques.
+'task reopen' annule cela.`,
+
+	"Move done or cancelled tasks back to the backlog": "Remettre à faire des tâches terminées ou annulées",
+	"Hide tasks from the list until a date":            "Masquer des tâches de la liste jusqu'à une date",
+
+	`Keeps the given tasks out of 'task list' and 'task board' until the date
+passes, so the list only shows what can be acted on now. A bare date wakes
+the tasks at the start of that day; "none" wakes them at once. 'task list
+-all' shows snoozed tasks too.`: `Garde les tâches données hors de 'task list' et de 'task board' jusqu'à
+la date, pour que la liste ne montre que ce qui peut être fait
+maintenant. Une date seule réveille les tâches au début de ce jour ;
+"none" les réveille tout de suite. 'task list -all' montre aussi les
+tâches reportées.`,
+
+	"Delete tasks": "Supprimer des tâches",
+
+	`Deletes every listed task in one step, which undo reverts as a whole.
+Ranges such as 10-20 cover the tasks that exist in them.
+
+Deleted tasks go to the trash, where 'task trash' lists them and
+'task restore' brings them back until they are purged after trash_days
+days (30 by default).
+
+The tasks are listed and you are asked to confirm, unless -yes is given.`: `Supprime toutes les tâches données en une seule étape, que undo annule
+d'un bloc. Les plages comme 10-20 couvrent les tâches qui y existent.
+
+Les tâches supprimées vont à la corbeille, où 'task trash' les liste et
+d'où 'task restore' les ramène, jusqu'à ce qu'elles soient purgées après
+trash_days jours (30 par défaut).
+
+Les tâches sont listées et on vous demande de confirmer, sauf avec -yes.`,
+
+	"List deleted tasks that can still be restored": "Lister les tâches supprimées qui peuvent encore être restaurées",
+
+	`Deleted tasks stay in the trash for trash_days days (30 unless the config
+file says otherwise) and are purged for good the next time something is
+deleted or restored after that. 'task restore <id>' brings one back.
+
+-empty purges the whole trash at once, after listing it and asking for
+confirmation unless -yes is given; 'task undo' reverts that too.`: `Les tâches supprimées restent dans la corbeille pendant trash_days jours
+(30 sauf indication contraire du fichier de configuration) et sont
+purgées définitivement la fois suivante où quelque chose est supprimé ou
+restauré. 'task restore <id>' en ramène une.
+
+-empty purge toute la corbeille d'un coup, après l'avoir listée et avoir
+demandé confirmation, sauf avec -yes ; 'task undo' annule cela aussi.`,
+
+	"purge every trashed task now":                                      "purger dès maintenant toutes les tâches de la corbeille",
+	"print the trashed tasks as JSON":                                   "afficher les tâches de la corbeille en JSON",
+	"Bring deleted tasks back from the trash, or roll back to a backup": "Ramener des tâches supprimées de la corbeille, ou revenir à une sauvegarde",
+
+	`Moves the listed tasks from the trash back into the list, in one step that
+undo reverts as a whole. A task keeps its ID unless another task has taken
+it since, and becomes a top-level task if its parent is gone.
+
+Given a snapshot from 'task backup' instead (its file name, a path, or
+"latest"), rolls every list it holds, or only the one chosen with -list,
+back to its tasks and trash in the snapshot, after asking for
+confirmation unless -yes is given. The history is kept, with the rollback
+logged in it, and 'task undo' reverts the rollback of a list.`: `Ramène les tâches données de la corbeille dans la liste, en une seule
+étape que undo annule d'un bloc. Une tâche garde son ID sauf si une autre
+tâche l'a pris depuis, et devient une tâche de premier niveau si sa
+parente a disparu.
+
+Avec une sauvegarde de 'task backup' à la place (son nom de fichier, un
+chemin ou "latest"), ramène chaque liste qu'elle contient, ou seulement
+celle choisie avec -list, à ses tâches et à sa corbeille dans la
+sauvegarde, après avoir demandé confirmation, sauf avec -yes.
+L'historique est conservé, le retour arrière y étant consigné, et 'task
+undo' annule le retour arrière d'une liste.`,
+
+	"Save a compressed snapshot of every task list": "Enregistrer une sauvegarde compressée de toutes les listes de tâches",
+
+	`Writes every list's tasks, trash and history to a timestamped file such
+as task-20260501-093000.json.gz in the backups directory next to the task
+data, or in backup_dir from the config file. Snapshots are read through the
+storage backend rather than copied from its files, so one taken with the
+bolt backend restores into the json backend and back; lists kept encrypted
+stay encrypted with their own passphrase.
+
+Only the newest snapshots are kept: -keep, or backup_keep in the config
+file, says how many (10 by default, 0 for all). Run it from cron for
+regular backups, and roll back with 'task restore <snapshot>'.
+
+-list shows the snapshots, newest first.`: `Écrit les tâches, la corbeille et l'historique de chaque liste dans un
+fichier horodaté comme task-20260501-093000.json.gz, dans le répertoire
+backups à côté des données, ou dans le backup_dir du fichier de
+configuration. Les sauvegardes sont lues à travers le moteur de stockage
+plutôt que copiées depuis ses fichiers, si bien qu'une sauvegarde prise
+avec le moteur bolt se restaure dans le moteur json et inversement ; les
+listes chiffrées restent chiffrées avec leur propre phrase secrète.
+
+Seules les sauvegardes les plus récentes sont conservées : -keep, ou
+backup_keep dans le fichier de configuration, dit combien (10 par défaut,
+0 pour toutes). Lancez-la depuis cron pour des sauvegardes régulières, et
+revenez en arrière avec 'task restore <sauvegarde>'.
+
+-list affiche les sauvegardes, les plus récentes d'abord.`,
+
+	"directory for the snapshots (default from config or <data dir>/backups)": "répertoire des sauvegardes (par défaut celui de la configuration ou <data dir>/backups)",
+	"number of snapshots to keep, 0 for all (default from config or 10)":      "nombre de sauvegardes à garder, 0 pour toutes (par défaut celui de la configuration ou 10)",
+	"list the snapshots instead of taking one":                                "lister les sauvegardes au lieu d'en prendre une",
+	"Change a task's fields, or open it in $EDITOR":                           "Modifier les champs d'une tâche, ou l'ouvrir dans $EDITOR",
+
+	`With field flags or modifications the task is updated directly. Without
+any, the task is written to a temporary file and opened in $VISUAL or
+$EDITOR (default vi); the saved file is applied when the editor exits.
+
+Modifications are Taskwarrior's, and may be mixed with the flags:
+
+  due:+2d        move the due date 2 days later (from today if it has
+                 none); also -3d, and min, h, w, mo (months) and y
+  due:friday     any date -due takes; due: alone clears it
+  priority:H     or pri:, any level -priority takes
+  estimate:2h    or est:, any effort -estimate takes
+  status:waiting any status -status takes
+  project:home   or proj:; also description: (desc:), context:,
+                 assignee: and parent:
+  +urgent        attach a tag
+  -waiting       detach a tag (not one named like a flag, e.g. -project)
+
+Other words together become the new description:
+
+  task modify 12 due:+2d priority:H +urgent -waiting`: `Avec des options de champ ou des modifications, la tâche est mise à jour
+directement. Sans elles, la tâche est écrite dans un fichier temporaire
+ouvert dans $VISUAL ou $EDITOR (vi par défaut) ; le fichier enregistré
+est appliqué à la fermeture de l'éditeur.
+
+Les modifications sont celles de Taskwarrior, et peuvent se mêler aux
+options :
+
+  due:+2d        repousse l'échéance de 2 jours (à partir d'aujourd'hui
+                 s'il n'y en a pas) ; aussi -3d, et min, h, w, mo (mois)
+                 et y
+  due:friday     toute date acceptée par -due ; due: seul l'efface
+  priority:H     ou pri:, tout niveau accepté par -priority
+  estimate:2h    ou est:, tout effort accepté par -estimate
+  status:waiting tout statut accepté par -status
+  project:home   ou proj: ; de même description: (desc:), context:,
+                 assignee: et parent:
+  +urgent        ajoute une étiquette
+  -waiting       retire une étiquette (pas une qui porte le nom d'une
+                 option, p. ex. -project)
+
+Les autres mots forment ensemble la nouvelle description :
+
+  task modify 12 due:+2d priority:H +urgent -waiting`,
+
+	"assign the task to this user, or \"none\" to unassign it": "attribuer la tâche à cet utilisateur, ou \"none\" pour ne l'attribuer à personne",
+	"set the task's context (empty to clear)":                  "fixer le contexte de la tâche (vide pour l'effacer)",
+	"new description":                                                           "nouvelle description",
+	"new due date, or \"none\" to clear it":                                     "nouvelle échéance, ou \"none\" pour l'effacer",
+	"expected effort, e.g. 45m or 2h, or \"none\" to clear it":                  "effort prévu, p. ex. 45m ou 2h, ou \"none\" pour l'effacer",
+	"make the task a subtask of this task ID or UUID, or \"none\" to detach it": "faire de la tâche une sous-tâche de cet ID ou UUID, ou \"none\" pour l'en détacher",
+	"new priority: low, medium, high, 0-9 or none":                              "nouvelle priorité : low, medium, high, 0-9 ou none",
+	"move the task to this project (empty to clear)":                            "déplacer la tâche dans ce projet (vide pour l'effacer)",
+	"new status: pending, in-progress, waiting, done or cancelled":              "nouveau statut : pending, in-progress, waiting, done ou cancelled",
+	"detach a tag (repeatable)":                                                 "retirer une étiquette (répétable)",
+	"Assign tasks to a user":                                                    "Attribuer des tâches à un utilisateur",
+
+	`Sets who owns the given tasks, e.g. "task assign 4 alice", so a list shared
+through 'task serve' shows who does what; "none" unassigns them. Filter with
+'task list -assignee alice'.`: `Indique qui s'occupe des tâches données, p. ex. "task assign 4 alice",
+pour qu'une liste partagée par 'task serve' montre qui fait quoi ; "none"
+les retire à leur responsable. Filtrez avec 'task list -assignee alice'.`,
+
+	"Add a timestamped note to a task":                                      "Ajouter une note horodatée à une tâche",
+	"Notes are kept in the order they were added and shown by 'task show'.": "Les notes sont conservées dans l'ordre où elles ont été ajoutées et affichées par 'task show'.",
+	"Attach files or URLs to a task":                                        "Joindre des fichiers ou des URL à une tâche",
+
+	`Only the reference is stored: files by absolute path, so they must exist
+and stay where they are, and URLs as given. Attachments are numbered in the
+order they were added; 'task show' lists them and 'task open' opens them.`: `Seule la référence est enregistrée : les fichiers par leur chemin absolu,
+si bien qu'ils doivent exister et rester où ils sont, et les URL telles
+quelles. Les pièces jointes sont numérotées dans l'ordre où elles ont été
+ajoutées ; 'task show' les liste et 'task open' les ouvre.`,
+
+	"Remove an attachment from a task":                              "Retirer une pièce jointe d'une tâche",
+	"The file itself is left alone; only the reference is removed.": "Le fichier lui-même n'est pas touché ; seule la référence est retirée.",
+	"Open a task's attachment":                                      "Ouvrir une pièce jointe d'une tâche",
+
+	`Opens attachment n (1 when the task has only one) with the desktop's
+default application: xdg-open on Linux and the BSDs, open on macOS.`: `Ouvre la pièce jointe n (1 quand la tâche n'en a qu'une) avec
+l'application par défaut du bureau : xdg-open sous Linux et les BSD, open
+sous macOS.`,
+
+	"Show every field of a task, with its notes and attachments": "Afficher tous les champs d'une tâche, avec ses notes et pièces jointes",
+	"print the task as a JSON object":                            "afficher la tâche en objet JSON",
+	"Revert the last add, complete, delete or edit":              "Annuler le dernier ajout, la dernière clôture, suppression ou modification",
+
+	`Every mutating command is journalled together with the previous state of
+the tasks it touched. undo pops the newest entry and restores that state;
+run it again to step further back (up to 100 operations).`: `Chaque commande qui modifie des tâches est journalisée avec l'état
+précédent des tâches touchées. undo retire l'entrée la plus récente et
+restaure cet état ; relancez-le pour remonter plus loin (jusqu'à 100
+opérations).`,
+
+	"Show every change made to a task": "Afficher toutes les modifications d'une tâche",
+
+	`Lists, oldest first, every change to the task: when, by whom, through
+which command, and each field's old and new value. The history is kept for
+the life of the list, even after the task is deleted.`: `Liste, de la plus ancienne à la plus récente, chaque modification de la
+tâche : quand, par qui, par quelle commande, et l'ancienne et la nouvelle
+valeur de chaque champ. L'historique est conservé pendant toute la vie de
+la liste, même après la suppression de la tâche.`,
+
+	"print the events as a JSON array":                  "afficher les événements en tableau JSON",
+	"Show recent changes to every task, newest first":   "Afficher les modifications récentes de toutes les tâches, les plus récentes d'abord",
+	"show at most this many changes (0 for all)":        "afficher au plus ce nombre de modifications (0 pour toutes)",
+	"only changes made by this user":                    "seulement les modifications faites par cet utilisateur",
+	"Chart the number of open tasks over the last days": "Tracer le nombre de tâches ouvertes sur les derniers jours",
+
+	`Draws one bar per day with the number of tasks open at the end of it,
+worked out from the change history, and sums up how many tasks were added,
+reopened, closed and deleted in that time. A falling chart means the list
+is shrinking. When there are more days than fit the terminal, each bar
+stands for several days and shows the last of them.`: `Dessine une barre par jour avec le nombre de tâches ouvertes à la fin de
+ce jour, calculé depuis l'historique des modifications, et récapitule
+combien de tâches ont été ajoutées, rouvertes, fermées et supprimées dans
+cette période. Un graphique descendant signifie que la liste diminue.
+Quand il y a plus de jours que le terminal n'en peut afficher, chaque
+barre représente plusieurs jours et montre le dernier d'entre eux.`,
+
+	"chart this many days, ending today":               "tracer ce nombre de jours, jusqu'à aujourd'hui",
+	"print the daily counts as a JSON array":           "afficher les comptes quotidiens en tableau JSON",
+	"Compare estimates with tracked time, per project": "Comparer estimations et temps passé, par projet",
+
+	`Counts the completed tasks that have both an estimate (-estimate on add
+or edit) and tracked time (from 'task start' to 'task stop' or completion).
+Ratio is tracked over estimated time, so 1.25x means the work took a
+quarter longer than planned. Accuracy is how close each estimate came, on
+average: 100% for exact estimates, 50% for ones off by a factor of two.`: `Compte les tâches terminées qui ont à la fois une estimation (-estimate
+à l'ajout ou à la modification) et du temps passé (de 'task start' à
+'task stop' ou à la clôture). Le rapport est le temps passé divisé par
+le temps estimé : 1.25x signifie que le travail a pris un quart de plus
+que prévu. La précision dit à quel point chaque estimation était juste,
+en moyenne : 100 % pour une estimation exacte, 50 % pour une estimation
+fausse d'un facteur deux.`,
+
+	"print the statistics as a JSON object":                      "afficher les statistiques en objet JSON",
+	"only this project and its sub-projects":                     "seulement ce projet et ses sous-projets",
+	"List tags with their open and total task counts":            "Lister les étiquettes avec leur nombre de tâches ouvertes et au total",
+	"print the counts as a JSON array":                           "afficher les comptes en tableau JSON",
+	"List projects with their pending and completed task counts": "Lister les projets avec leur nombre de tâches à faire et terminées",
+	"Save filters and apply one to every list until cleared":     "Enregistrer des filtres et en appliquer un à toutes les listes jusqu'à nouvel ordre",
+
+	`A context is a saved filter expression that, once switched on, applies to
+every 'task list' and 'task next' until switched off:
+
+  task context define work "project=acme or tag=work"
+  task context work
+  task context none
+
+Without arguments this shows the defined contexts and the active one.
+Filters test project, tag, context (a task's @context), assignee, status,
+priority and search with = and !=, combined with and, or, not and
+parentheses. Contexts are kept in the data directory and shared by all
+lists.`: `Un contexte est une expression de filtre enregistrée qui, une fois
+activée, s'applique à chaque 'task list' et 'task next' jusqu'à sa
+désactivation :
+
+  task context define work "project=acme or tag=work"
+  task context work
+  task context none
+
+Sans argument, la commande affiche les contextes définis et celui qui est
+actif. Les filtres testent project, tag, context (le @contexte d'une
+tâche), assignee, status, priority et search avec = et !=, combinés avec
+and, or, not et des parenthèses. Les contextes sont conservés dans le
+répertoire de données et partagés par toutes les listes.`,
+
+	"List the task lists with their open and total task counts":                                "Lister les listes de tâches avec leur nombre de tâches ouvertes et au total",
+	"print the lists as a JSON array":                                                          "afficher les listes en tableau JSON",
+	"Find tasks whose description, notes, tags, project or context contain every query term":   "Trouver les tâches dont la description, les notes, les étiquettes, le projet ou le contexte contiennent tous les termes cherchés",
+	"Matching is case-insensitive and matches substrings, so \"doc\" finds \"Documentation\".": "La recherche ignore la casse et trouve les sous-chaînes : \"doc\" trouve \"Documentation\".",
+	"print the matches as a JSON array":                                                        "afficher les résultats en tableau JSON",
+	"Add tasks from a file written by another tool":                                            "Ajouter des tâches depuis un fichier écrit par un autre outil",
+
+	`Imported tasks get new IDs. The format is guessed from the file name when
+-format is not given (*.txt is todo.txt, *.json Taskwarrior's 'task export',
+*.csv and *.tsv CSV); "-" reads standard input. Tasks whose UUID is already
+in the list are skipped, so importing the same export twice adds nothing.
+Formats: csv, taskwarrior, todotxt.
+
+CSV files need a header row. Columns named after a task field, or a common
+synonym such as "Title" or "Due Date", are picked up on their own; others
+are mapped with -map, or with [csv_columns] in the config file:
+
+  task import -map description=Summary -map due=Deadline tasks.csv
+
+Fields: id, description, status, priority, due, project, context, tags, parent, created_at, completed_at, assignee.`: `Les tâches importées reçoivent de nouveaux ID. Le format est deviné
+d'après le nom du fichier quand -format n'est pas donné (*.txt est
+todo.txt, *.json le 'task export' de Taskwarrior, *.csv et *.tsv du CSV) ;
+"-" lit l'entrée standard. Les tâches dont l'UUID est déjà dans la liste
+sont ignorées, si bien qu'importer deux fois le même export n'ajoute rien.
+Formats : csv, taskwarrior, todotxt.
+
+Les fichiers CSV ont besoin d'une ligne d'en-tête. Les colonnes qui
+portent le nom d'un champ, ou un synonyme courant comme "Title" ou "Due
+Date", sont reconnues d'elles-mêmes ; les autres s'associent avec -map,
+ou avec [csv_columns] dans le fichier de configuration :
+
+  task import -map description=Summary -map due=Deadline tasks.csv
+
+Champs : id, description, status, priority, due, project, context, tags, parent, created_at, completed_at, assignee.`,
+
+	"input format: csv, taskwarrior, todotxt":                        "format d'entrée : csv, taskwarrior, todotxt",
+	"read a CSV `field=column` from that column (repeatable)":        "lire le champ CSV de `field=column` dans cette colonne (répétable)",
+	"Write every task in another format":                             "Écrire toutes les tâches dans un autre format",
+	"Formats: csv, html, ics, ics-todo, markdown, todotxt.":          "Formats : csv, html, ics, ics-todo, markdown, todotxt.",
+	"output format: csv, html, ics, ics-todo, markdown, todotxt":     "format de sortie : csv, html, ics, ics-todo, markdown, todotxt",
+	"write to this file instead of stdout":                           "écrire dans ce fichier plutôt que sur la sortie standard",
+	"Publish a read-only view of some tasks as static HTML and JSON": "Publier une vue en lecture seule de certaines tâches en HTML et JSON statiques",
+
+	`Writes index.html, a page summing up the selected tasks with a sortable
+table per project, and tasks.json, the same tasks as JSON, to the -o
+directory (share by default). Neither needs the task command or a server,
+so the directory can be dropped onto any static host for people who just
+want to see where things stand:
+
+  task share -project website -o /var/www/status
+
+Completed tasks are included, so the page shows progress, unless -open is
+given. Notes are left out unless -notes is given, and attachments, sync
+metadata and GTD contexts always are.`: `Écrit index.html, une page qui récapitule les tâches choisies avec un
+tableau triable par projet, et tasks.json, les mêmes tâches en JSON, dans
+le répertoire -o (share par défaut). Ni l'un ni l'autre n'a besoin de la
+commande task ou d'un serveur, si bien que le répertoire peut être déposé
+sur n'importe quel hébergement statique pour ceux qui veulent seulement
+voir où en sont les choses :
+
+  task share -project website -o /var/www/status
+
+Les tâches terminées sont incluses, pour que la page montre l'avancement,
+sauf avec -open. Les notes sont omises sauf avec -notes, et les pièces
+jointes, les métadonnées de synchronisation et les contextes GTD le sont
+toujours.`,
+
+	"include the tasks' notes":                                "inclure les notes des tâches",
+	"directory to write the files to":                         "répertoire où écrire les fichiers",
+	"leave out done and cancelled tasks":                      "omettre les tâches terminées et annulées",
+	"page title (default: the project, or \"Tasks\")":         "titre de la page (par défaut : le projet, ou \"Tasks\")",
+	"Serve the task store to other programs over the network": "Servir les tâches à d'autres programmes par le réseau",
+
+	`Serves the TaskService gRPC API (task-manager/taskpb/task.proto) until
+interrupted, and over HTTP the sync endpoint used by 'task sync', a
+read-only GraphQL endpoint at /graphql and a WebSocket endpoint at /ws
+(-http "" turns them off; GET /graphql without a query prints the schema).
+
+Clients connected to /ws receive every change made through the server as
+a JSON message shaped like a webhook payload, or with
+/ws?events=completed,deleted only those events, so they can live-update
+without polling. Web pages may only connect from the server's own origin.
+The server opens the task database only while it answers a request, so
+other task commands can use the same data directory meanwhile.
+
+Clients authenticate with an "authorization: Bearer <token>" metadata
+entry (Basic credentials for -auth htpasswd); sync clients send the same
+credentials as an HTTP Authorization header, as do GraphQL clients;
+browsers, which cannot set headers on WebSocket connections, may pass the
+token as /ws?access_token=<token> instead. With -auth static, create
+tokens with 'task token create'; read-only tokens may list and fetch tasks
+and pull with sync, but not change anything.`: `Sert l'API gRPC TaskService (task-manager/taskpb/task.proto) jusqu'à
+interruption et, en HTTP, le point d'accès de synchronisation utilisé par
+'task sync', un point d'accès GraphQL en lecture seule sur /graphql et un
+point d'accès WebSocket sur /ws (-http "" les désactive ; GET /graphql
+sans requête affiche le schéma).
+
+Les clients connectés à /ws reçoivent chaque modification faite par le
+serveur sous forme d'un message JSON de la même forme que la charge d'un
+webhook, ou avec /ws?events=completed,deleted seulement ces événements,
+pour se mettre à jour en direct sans interroger le serveur. Les pages web
+ne peuvent s'y connecter que depuis l'origine du serveur lui-même. Le
+serveur n'ouvre la base de tâches que le temps de répondre à une requête,
+si bien que les autres commandes task peuvent utiliser le même répertoire
+de données pendant ce temps.
+
+Les clients s'authentifient avec une entrée de métadonnées
+"authorization: Bearer <jeton>" (des identifiants Basic pour -auth
+htpasswd) ; les clients de synchronisation envoient les mêmes identifiants
+dans un en-tête HTTP Authorization, de même que les clients GraphQL ; les
+navigateurs, qui ne peuvent pas fixer d'en-têtes sur les connexions
+WebSocket, peuvent passer le jeton en /ws?access_token=<jeton> à la
+place. Avec -auth static, créez les jetons avec 'task token create' ; les
+jetons en lecture seule peuvent lister et lire les tâches et tirer les
+modifications avec sync, mais rien changer.`,
+
+	"authentication: none, static, htpasswd or oidc":               "authentification : none, static, htpasswd ou oidc",
+	"gRPC listen address":                                          "adresse d'écoute gRPC",
+	"htpasswd: password file":                                      "htpasswd : fichier de mots de passe",
+	"sync, GraphQL and WebSocket listen address (\"\" to disable)": "adresse d'écoute de la synchronisation, de GraphQL et de WebSocket (\"\" pour désactiver)",
+	"oidc: expected audience":                                      "oidc : audience attendue",
+	"oidc: issuer URL":                                             "oidc : URL de l'émetteur",
+	"static: token file (default: the one 'task token' manages)":   "static : fichier de jetons (par défaut celui que gère 'task token')",
+	"Manage the API tokens 'task serve -auth static' accepts":      "Gérer les jetons d'API qu'accepte 'task serve -auth static'",
+
+	`Tokens live in the tokens file of the data directory, which 'task serve
+-auth static' reads unless -tokens names another file. Only a hash of each
+token is stored, so a token is shown once, when it is created. Restart
+the server after changing tokens.`: `Les jetons sont conservés dans le fichier tokens du répertoire de
+données, que 'task serve -auth static' lit sauf si -tokens en désigne un
+autre. Seule une empreinte de chaque jeton est enregistrée, si bien qu'un
+jeton n'est affiché qu'une fois, à sa création. Redémarrez le serveur
+après avoir changé les jetons.`,
+
+	"Create a token and print it": "Créer un jeton et l'afficher",
+
+	`The name identifies the token in 'task token list' and is the user the
+token's changes are logged under. A -read-only token can list and fetch
+tasks and pull with 'task sync', but not change anything.`: `Le nom identifie le jeton dans 'task token list' et est l'utilisateur
+sous lequel les modifications faites avec le jeton sont consignées. Un
+jeton -read-only peut lister et lire les tâches et tirer les
+modifications avec 'task sync', mais rien changer.`,
+
+	"grant only the tasks:read scope":             "n'accorder que la portée tasks:read",
+	"List the tokens":                             "Lister les jetons",
+	"Delete tokens by name":                       "Supprimer des jetons par leur nom",
+	"Exchange changes with a task serve instance": "Échanger les modifications avec une instance de task serve",
+
+	`Sends the tasks changed since the last sync to the sync endpoint of
+'task serve -http' and takes back the merged list. A task changed on both
+sides is merged field by field; a field changed on both keeps the later
+change and records the other as a conflict for 'task conflicts'. A task
+changed on one side and deleted on the other is kept. The remote URL is
+remembered per list, so later syncs need no flags. Credentials come from -token ($TASK_SYNC_TOKEN), or user:password in
+the URL for a server using -auth htpasswd.
+
+The server syncs the list it was started with; sync each list against a
+server started with the matching -list.`: `Envoie les tâches modifiées depuis la dernière synchronisation au point
+d'accès de synchronisation de 'task serve -http' et récupère la liste
+fusionnée. Une tâche modifiée des deux côtés est fusionnée champ par
+champ ; un champ modifié des deux côtés garde la modification la plus
+récente et enregistre l'autre comme conflit pour 'task conflicts'. Une
+tâche modifiée d'un côté et supprimée de l'autre est conservée. L'URL
+distante est mémorisée par liste, si bien que les synchronisations
+suivantes n'ont besoin d'aucune option. Les identifiants viennent de
+-token ($TASK_SYNC_TOKEN), ou de user:password dans l'URL pour un serveur
+qui utilise -auth htpasswd.
+
+Le serveur synchronise la liste avec laquelle il a été lancé ;
+synchronisez chaque liste avec un serveur lancé avec le -list
+correspondant.`,
+
+	"server URL, e.g. http://desktop:7080 (remembered)": "URL du serveur, p. ex. http://desktop:7080 (mémorisée)",
+	"bearer token (env TASK_SYNC_TOKEN)":                "jeton bearer (env TASK_SYNC_TOKEN)",
+	"Run git in the repository of the git backend":      "Lancer git dans le dépôt du moteur git",
+
+	`With the git backend every list is a JSON file in <data dir>/git, and
+every change is committed there. 'task git' runs git in that directory, for
+its history and backups:
+
+  task git log -p             what changed, and when
+  task git remote add origin git@example.com:me/tasks.git
+  task git push -u origin HEAD`: `Avec le moteur git, chaque liste est un fichier JSON dans <data dir>/git,
+et chaque modification y est commitée. 'task git' lance git dans ce
+répertoire, pour son historique et ses sauvegardes :
+
+  task git log -p             ce qui a changé, et quand
+  task git remote add origin git@example.com:me/tasks.git
+  task git push -u origin HEAD`,
+
+	"Sync tasks with a CalDAV task list (Nextcloud, Fastmail, ...)": "Synchroniser les tâches avec une liste de tâches CalDAV (Nextcloud, Fastmail, ...)",
+
+	`Two-way sync between the list and the VTODOs of one CalDAV collection.
+Description, status, completion time, due date and priority are mapped
+both ways; other VTODO properties are kept as they are on the server.
+
+A task changed on one side since the last sync is copied to the other; one
+changed on both keeps the later change; one deleted on one side and
+unchanged on the other is deleted on both. The URL and user are remembered
+per list, so later syncs need no flags. The password comes from
+$TASK_CALDAV_PASSWORD, or is prompted for on the terminal; use an app
+password where the server offers them.
+
+The collection URL is the one the server shows for the calendar, e.g.
+  https://cloud.example.com/remote.php/dav/calendars/alice/tasks/
+  https://caldav.fastmail.com/dav/calendars/user/alice@fastmail.com/<id>/`: `Synchronisation dans les deux sens entre la liste et les VTODO d'une
+collection CalDAV. La description, le statut, l'heure de clôture,
+l'échéance et la priorité sont reportés dans les deux sens ; les autres
+propriétés VTODO sont laissées telles quelles sur le serveur.
+
+Une tâche modifiée d'un côté depuis la dernière synchronisation est
+copiée de l'autre ; une tâche modifiée des deux côtés garde la
+modification la plus récente ; une tâche supprimée d'un côté et inchangée
+de l'autre est supprimée des deux. L'URL et l'utilisateur sont mémorisés
+par liste, si bien que les synchronisations suivantes n'ont besoin
+d'aucune option. Le mot de passe vient de $TASK_CALDAV_PASSWORD, ou est
+demandé sur le terminal ; utilisez un mot de passe d'application si le
+serveur en propose.
+
+L'URL de la collection est celle que le serveur affiche pour le
+calendrier, p. ex.
+  https://cloud.example.com/remote.php/dav/calendars/alice/tasks/
+  https://caldav.fastmail.com/dav/calendars/user/alice@fastmail.com/<id>/`,
+
+	"collection URL (remembered)":                        "URL de la collection (mémorisée)",
+	"user name (remembered)":                             "nom d'utilisateur (mémorisé)",
+	"Mirror tasks tagged gh:owner/repo to GitHub issues": "Refléter les tâches étiquetées gh:owner/repo dans des tickets GitHub",
+
+	`Every task tagged gh:owner/repo gets an issue in that repository, titled
+with the task's description. On later runs the title and the open/closed
+state follow whichever side changed since the last sync; when both did,
+the later change wins. Closing the issue on GitHub completes the task, and
+completing the task closes the issue.
+
+Issues are never deleted: removing the tag or deleting the task only stops
+the mirroring, and tagging the task again picks its issue up where it left
+off. Issues opened on GitHub are not imported.
+
+The token comes from $TASK_GITHUB_TOKEN or $GITHUB_TOKEN and needs write
+access to the repositories' issues. -api points at a GitHub Enterprise
+server (https://host/api/v3) and is remembered per list.`: `Chaque tâche étiquetée gh:owner/repo reçoit un ticket dans ce dépôt,
+intitulé d'après la description de la tâche. Aux passages suivants, le
+titre et l'état ouvert ou fermé suivent le côté qui a changé depuis la
+dernière synchronisation ; quand les deux ont changé, la modification la
+plus récente l'emporte. Fermer le ticket sur GitHub termine la tâche, et
+terminer la tâche ferme le ticket.
+
+Les tickets ne sont jamais supprimés : retirer l'étiquette ou supprimer
+la tâche arrête seulement le reflet, et étiqueter à nouveau la tâche
+reprend son ticket là où il en était. Les tickets ouverts sur GitHub ne
+sont pas importés.
+
+Le jeton vient de $TASK_GITHUB_TOKEN ou de $GITHUB_TOKEN et doit avoir
+accès en écriture aux tickets des dépôts. -api désigne un serveur GitHub
+Enterprise (https://hôte/api/v3) et est mémorisé par liste.`,
+
+	"REST API root (remembered; default https://api.github.com)":   "racine de l'API REST (mémorisée ; https://api.github.com par défaut)",
+	"Import Jira issues as tasks and report their completion back": "Importer des tickets Jira comme tâches et y signaler leur clôture",
+
+	`The site and the mapping of fields come from the [jira] table of the
+config file:
+
+  [jira]
+  url = "https://acme.atlassian.net"
+  user = "alice@acme.com"         # leave out for a personal access token
+  jql = "assignee = currentUser() AND resolution = Unresolved"
+  done_transition = "Done"
+  [jira.fields]                   # task field = Jira field
+  context = "customfield_10042"
+
+The API token comes from $TASK_JIRA_TOKEN. Unmapped fields default to
+description = summary, due = duedate, priority = priority and
+tags = labels; map a field to "" to leave it alone. Fields: description, due, priority, project, context, tags, assignee.`: `Le site et la correspondance des champs viennent de la table [jira] du
+fichier de configuration :
+
+  [jira]
+  url = "https://acme.atlassian.net"
+  user = "alice@acme.com"         # à omettre pour un jeton d'accès personnel
+  jql = "assignee = currentUser() AND resolution = Unresolved"
+  done_transition = "Done"
+  [jira.fields]                   # champ de tâche = champ Jira
+  context = "customfield_10042"
+
+Le jeton d'API vient de $TASK_JIRA_TOKEN. Les champs non associés valent
+par défaut description = summary, due = duedate, priority = priority et
+tags = labels ; associez un champ à "" pour le laisser de côté. Champs : description, due, priority, project, context, tags, assignee.`,
+
+	"Add the issues a JQL search finds as tasks, or update them": "Ajouter comme tâches les tickets que trouve une recherche JQL, ou les mettre à jour",
+
+	`Every issue found becomes a task tagged jira:<key>; tasks pulled before
+take the issue's current values of the mapped fields, and gain its labels
+as tags. A task deleted here is not pulled again. The query defaults to
+the config file's jql, or issues assigned to you and unresolved; "me" as a
+value stands for currentUser(), so -jql "assignee=me" works.
+
+'task undo' reverts a pull.`: `Chaque ticket trouvé devient une tâche étiquetée jira:<clé> ; les tâches
+déjà importées prennent les valeurs actuelles des champs associés du
+ticket et reçoivent ses labels comme étiquettes. Une tâche supprimée ici
+n'est pas importée à nouveau. La requête vaut par défaut le jql du
+fichier de configuration, ou les tickets non résolus qui vous sont
+attribués ; "me" comme valeur tient lieu de currentUser(), si bien que
+-jql "assignee=me" fonctionne.
+
+'task undo' annule un import.`,
+
+	"JQL search for the issues to pull":                              "recherche JQL des tickets à importer",
+	"Move the issues of completed tasks through the done transition": "Faire passer les tickets des tâches terminées par la transition de clôture",
+
+	`Every task pulled from Jira and completed since is reported back once, by
+moving its issue through the config file's done_transition ("Done" unless
+set), which may name the transition or the status it leads to.`: `Chaque tâche importée de Jira et terminée depuis est signalée une fois,
+en faisant passer son ticket par le done_transition du fichier de
+configuration ("Done" par défaut), qui peut nommer la transition ou le
+statut auquel elle mène.`,
+
+	"Review and resolve fields changed on two machines": "Examiner et résoudre les champs modifiés sur deux machines",
+
+	`When sync finds a field changed on two machines since they last synced,
+it keeps the later change and records the other as a conflict. Without
+arguments this lists every task with conflicts; with an ID, that task's.
+
+-keep resolves them by keeping the current values; -take replaces them
+with the discarded ones. Both act on all of the task's conflicts, or only
+on the named fields. Resolutions are journalled and sync to the other
+machines like any change.`: `Quand la synchronisation trouve un champ modifié sur deux machines depuis
+leur dernière synchronisation, elle garde la modification la plus récente
+et enregistre l'autre comme conflit. Sans argument, la commande liste
+toutes les tâches en conflit ; avec un ID, les conflits de cette tâche.
+
+-keep les résout en gardant les valeurs actuelles ; -take les remplace
+par les valeurs écartées. Les deux agissent sur tous les conflits de la
+tâche, ou seulement sur les champs nommés. Les résolutions sont
+journalisées et se synchronisent vers les autres machines comme toute
+modification.`,
+
+	"resolve by keeping the current values":              "résoudre en gardant les valeurs actuelles",
+	"resolve by taking the discarded values":             "résoudre en reprenant les valeurs écartées",
+	"Show or send reminders for tasks that are due soon": "Afficher ou envoyer des rappels pour les tâches bientôt à rendre",
+
+	`Lists open tasks due within the window (overdue ones included). With
+-daemon it keeps running, checks the list every -interval and announces each
+task once (again if its due date changes) until interrupted.
+
+Reminders are printed, and also sent to -exec and -webhook when given
+(defaults: remind_within, remind_command and remind_webhook in the config
+file). -desktop (or remind_desktop = true) pops up a native notification
+as well, through notify-send on Linux, osascript on macOS or a PowerShell
+toast on Windows; overdue tasks are marked critical. The command runs through sh with TASK_ID, TASK_DESCRIPTION, TASK_DUE,
+TASK_PRIORITY, TASK_PROJECT, TASK_TAGS and TASK_MESSAGE set, e.g.
+  -exec 'notify-send "$TASK_MESSAGE"'
+The webhook receives a JSON POST with "message" and "task" fields.`: `Liste les tâches ouvertes à rendre dans la fenêtre (celles en retard
+comprises). Avec -daemon, la commande continue de tourner, vérifie la
+liste toutes les -interval et annonce chaque tâche une fois (à nouveau si
+son échéance change) jusqu'à interruption.
+
+Les rappels sont affichés, et aussi envoyés à -exec et -webhook s'ils sont
+donnés (par défaut : remind_within, remind_command et remind_webhook dans
+le fichier de configuration). -desktop (ou remind_desktop = true) affiche
+aussi une notification native, par notify-send sous Linux, osascript sous
+macOS ou une notification PowerShell sous Windows ; les tâches en retard
+sont marquées critiques. La commande est lancée par sh avec TASK_ID,
+TASK_DESCRIPTION, TASK_DUE, TASK_PRIORITY, TASK_PROJECT, TASK_TAGS et
+TASK_MESSAGE définies, p. ex.
+  -exec 'notify-send "$TASK_MESSAGE"'
+Le webhook reçoit un POST JSON avec les champs "message" et "task".`,
+
+	"keep running and send each reminder once":                                "continuer de tourner et envoyer chaque rappel une fois",
+	"also show a desktop notification per reminder (default from config)":     "afficher aussi une notification de bureau par rappel (par défaut selon la configuration)",
+	"shell command to run per reminder":                                       "commande shell à lancer pour chaque rappel",
+	"daemon: how often to check the list":                                     "daemon : fréquence de vérification de la liste",
+	"print the due tasks as a JSON array instead of notifying":                "afficher les tâches à rendre en tableau JSON au lieu de notifier",
+	"URL to POST each reminder to":                                            "URL à laquelle envoyer chaque rappel en POST",
+	"remind about tasks due within this long (default from config, else 24h)": "rappeler les tâches à rendre dans ce délai (par défaut celui de la configuration, sinon 24h)",
+	"Forget cached keys of encrypted task files":                              "Oublier les clés en cache des fichiers de tâches chiffrés",
+
+	`Encrypted task files ask for their passphrase once and remember the key
+for key_cache (default 15m). lock forgets every remembered key at once.`: `Les fichiers de tâches chiffrés demandent leur phrase secrète une fois et
+retiennent la clé pendant key_cache (15m par défaut). lock oublie d'un
+coup toutes les clés retenues.`,
+
+	"Print a shell completion script": "Afficher un script de complétion pour le shell",
+
+	`Load the script in your shell's startup file, e.g.
+
+  bash: source <(task completion bash)
+  zsh:  source <(task completion zsh)
+  fish: task completion fish | source`: `Chargez le script dans le fichier de démarrage de votre shell, p. ex.
+
+  bash: source <(task completion bash)
+  zsh:  source <(task completion zsh)
+  fish: task completion fish | source`,
+}
+
+func init() { maps.Copy(french, frenchHelp) }
@@ -0,0 +1,226 @@
+// Package i18n translates the messages the task command line prints.
+//
+// Messages are looked up by their English text, format verbs included, the
+// way gettext does it: code keeps calling Printf-style functions with
+// English formats, and a catalog maps each format to its translation. A
+// message missing from a catalog is printed in English, so a catalog can
+// lag behind the code without breaking anything.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// English is the language messages are written in, and the fallback.
+const English = "en"
+
+// catalog maps English formats to their translation in one language.
+type catalog struct {
+	msgs map[string]string
+	// one reports whether n takes the singular form.
+	one func(n int) bool
+}
+
+var catalogs = map[string]catalog{
+	English: {one: func(n int) bool { return n == 1 }},
+	"fr":    {msgs: french, one: func(n int) bool { return n == 0 || n == 1 }},
+}
+
+// Languages lists the supported language codes.
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for l := range catalogs {
+		langs = append(langs, l)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Parse reduces a locale name such as "fr_FR.UTF-8" or "fr-CA" to a
+// supported language code. It reports false for unsupported languages;
+// "C" and "POSIX" are English.
+func Parse(locale string) (string, bool) {
+	lang, _, _ := strings.Cut(locale, ".")
+	lang, _, _ = strings.Cut(lang, "@")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	lang = strings.ToLower(lang)
+	if lang == "c" || lang == "posix" {
+		return English, true
+	}
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Detect picks the language from the environment the way POSIX programs
+// do: the first of $LC_ALL, $LC_MESSAGES and $LANG that is set decides,
+// and an unsupported language means English.
+func Detect(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := getenv(name); v != "" {
+			if lang, ok := Parse(v); ok {
+				return lang
+			}
+			return English
+		}
+	}
+	return English
+}
+
+// Printer formats messages in one language. A nil *Printer prints English.
+type Printer struct {
+	lang string
+	cat  catalog
+}
+
+// New returns a Printer for lang, one of Languages; anything else gets
+// English.
+func New(lang string) *Printer {
+	cat, ok := catalogs[lang]
+	if !ok {
+		lang, cat = English, catalogs[English]
+	}
+	return &Printer{lang: lang, cat: cat}
+}
+
+// Lang returns the language p prints in.
+func (p *Printer) Lang() string {
+	if p == nil {
+		return English
+	}
+	return p.lang
+}
+
+// T returns the translation of msg.
+func (p *Printer) T(msg string) string {
+	if p == nil {
+		return msg
+	}
+	if s, ok := p.cat.msgs[msg]; ok {
+		return s
+	}
+	return msg
+}
+
+// Sprintf formats args according to the translation of format.
+func (p *Printer) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(p.T(format), args...)
+}
+
+// Fprintf writes the translation of format, formatted with args, to w.
+func (p *Printer) Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	return fmt.Fprintf(w, p.T(format), args...)
+}
+
+// Fprintln writes the translation of msg and a newline to w.
+func (p *Printer) Fprintln(w io.Writer, msg string) (int, error) {
+	return fmt.Fprintln(w, p.T(msg))
+}
+
+// Plural formats n with the translation of one or other, whichever form
+// the language uses for n. Both formats take n as their only argument,
+// as in Plural(n, "%d task", "%d tasks").
+func (p *Printer) Plural(n int, one, other string) string {
+	if p.singular(n) {
+		return p.Sprintf(one, n)
+	}
+	return p.Sprintf(other, n)
+}
+
+// singular reports whether p's language uses the singular for n.
+func (p *Printer) singular(n int) bool {
+	if p == nil {
+		return n == 1
+	}
+	return p.cat.one(n)
+}
+
+// Error is an error whose message can be translated after the fact. It
+// keeps its English format and arguments, so that code far from any
+// Printer can return it and the command line can print it in the user's
+// language with Printer.Error.
+type Error struct {
+	format string
+	other  string // the plural of format, for errors made by Pluralf
+	n      int    // the count that picks between format and other
+	args   []any
+	err    error // the message in English
+}
+
+// Errorf is fmt.Errorf for errors that Printer.Error can translate.
+func Errorf(format string, args ...any) error {
+	return &Error{format: format, args: args, err: fmt.Errorf(format, args...)}
+}
+
+// Pluralf is Errorf for a message about n things, in the form one or other
+// as Printer.Plural picks them. Both formats take all of args, as in
+// Pluralf(n, "task %d has %d open subtask", "task %d has %d open subtasks",
+// id, n).
+func Pluralf(n int, one, other string, args ...any) error {
+	format := other
+	if n == 1 {
+		format = one
+	}
+	return &Error{format: one, other: other, n: n, args: args, err: fmt.Errorf(format, args...)}
+}
+
+func (e *Error) Error() string { return e.err.Error() }
+
+// Unwrap returns the error in English, which wraps whatever the %w verbs
+// of the format wrap.
+func (e *Error) Unwrap() error { return e.err }
+
+// Word is an argument of Errorf that is translated with the message, such
+// as a status in "task %d is %s".
+type Word string
+
+// Error returns the message of err in p's language. Errors made by
+// Errorf are formatted with the translation of their format, with the
+// errors and Words among their arguments translated in turn; other errors
+// are looked up by their message and, failing that, translated through
+// the errors they wrap, as errors.Join and "path: %w" errors print them.
+func (p *Printer) Error(err error) string {
+	if e, ok := err.(*Error); ok {
+		args := make([]any, len(e.args))
+		for i, arg := range e.args {
+			switch arg := arg.(type) {
+			case error:
+				args[i] = errors.New(p.Error(arg))
+			case Word:
+				args[i] = p.T(string(arg))
+			default:
+				args[i] = arg
+			}
+		}
+		format := e.format
+		if e.other != "" && !p.singular(e.n) {
+			format = e.other
+		}
+		return fmt.Errorf(p.T(format), args...).Error()
+	}
+	msg := err.Error()
+	if s := p.T(msg); s != msg {
+		return s
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		// errors.Join puts one error per line.
+		var en, tr []string
+		for _, e := range joined.Unwrap() {
+			en = append(en, e.Error())
+			tr = append(tr, p.Error(e))
+		}
+		if strings.Join(en, "\n") == msg {
+			return strings.Join(tr, "\n")
+		}
+	}
+	if inner := errors.Unwrap(err); inner != nil {
+		if prefix, ok := strings.CutSuffix(msg, inner.Error()); ok {
+			return prefix + p.Error(inner)
+		}
+	}
+	return msg
+}
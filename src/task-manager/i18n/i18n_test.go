@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{English, 0, "0 tasks"},
+		{English, 1, "1 task"},
+		{English, 2, "2 tasks"},
+		{"fr", 0, "0 tâche"},
+		{"fr", 1, "1 tâche"},
+		{"fr", 2, "2 tâches"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.lang).Plural(tt.n, "%d task", "%d tasks"); got != tt.want {
+			t.Errorf("%s: Plural(%d) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	notFound := errors.New("task not found")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"sentinel", notFound, "tâche introuvable"},
+		{"format", Errorf("task %d: %w", 3, notFound), "tâche 3 : tâche introuvable"},
+		{"word", Errorf("%w: task %d is already %s", errors.New("invalid status change"), 3, Word("done")),
+			"changement de statut invalide : la tâche 3 est déjà terminée"},
+		{"wrapped", fmt.Errorf("tasks.json: %w", Errorf("task %d: %w", 3, notFound)), "tasks.json: tâche 3 : tâche introuvable"},
+		{"joined", errors.Join(notFound, errors.New("nothing to undo")), "tâche introuvable\nrien à annuler"},
+		{"unknown", Errorf("no such thing as %q", "x"), `no such thing as "x"`},
+		{"plural", Pluralf(2, "task %d has %d open subtask", "task %d has %d open subtasks", 3, 2),
+			"la tâche 3 a 2 sous-tâches ouvertes"},
+		{"plural one", Pluralf(1, "task %d has %d open subtask", "task %d has %d open subtasks", 3, 1),
+			"la tâche 3 a 1 sous-tâche ouverte"},
+	}
+	fr := New("fr")
+	for _, tt := range tests {
+		if got := fr.Error(tt.err); got != tt.want {
+			t.Errorf("%s: Error = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	err := Errorf("task %d: %w", 3, notFound)
+	if got, want := err.Error(), "task 3: task not found"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, notFound) {
+		t.Error("Errorf does not wrap its %w argument")
+	}
+	if got := New(English).Error(err); got != err.Error() {
+		t.Errorf("English Error = %q, want %q", got, err.Error())
+	}
+
+	if got, want := Pluralf(2, "%d task", "%d tasks", 2).Error(), "2 tasks"; got != want {
+		t.Errorf("Pluralf Error() = %q, want %q", got, want)
+	}
+}
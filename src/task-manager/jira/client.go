@@ -0,0 +1,135 @@
+// Package jira imports the issues a Jira search finds as tasks, and moves
+// an issue through a workflow transition once its task is completed.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to the REST API (version 2) of one Jira site.
+type Client struct {
+	URL   string       // site root, e.g. https://acme.atlassian.net
+	User  string       // account e-mail for Jira Cloud API tokens; "" sends Token as a bearer personal access token
+	Token string       // API token or personal access token
+	HTTP  *http.Client // nil means a client with a 30s timeout
+}
+
+// Issue is a Jira issue with the fields a search asked for, as decoded
+// from JSON.
+type Issue struct {
+	Key    string         `json:"key"`
+	Fields map[string]any `json:"fields"`
+}
+
+// Search returns every issue jql matches, with the given fields.
+func (c *Client) Search(ctx context.Context, jql string, fields []string) ([]Issue, error) {
+	var all []Issue
+	for {
+		q := url.Values{
+			"jql":        {jql},
+			"fields":     {strings.Join(fields, ",")},
+			"startAt":    {strconv.Itoa(len(all))},
+			"maxResults": {"100"},
+		}
+		var page struct {
+			Issues []Issue `json:"issues"`
+			Total  int     `json:"total"`
+		}
+		if err := c.call(ctx, http.MethodGet, "/rest/api/2/search?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Issues...)
+		if len(page.Issues) == 0 || len(all) >= page.Total {
+			return all, nil
+		}
+	}
+}
+
+// Transition moves issue key through the transition called name, or the
+// one leading to the status called name, ignoring case.
+func (c *Client) Transition(ctx context.Context, key, name string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	var list struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.call(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return err
+	}
+	var names []string
+	for _, t := range list.Transitions {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.To.Name, name) {
+			body := map[string]any{"transition": map[string]string{"id": t.ID}}
+			return c.call(ctx, http.MethodPost, path, body, nil)
+		}
+		names = append(names, t.Name)
+	}
+	return fmt.Errorf("jira: %s has no transition %q (available: %s)", key, name, strings.Join(names, ", "))
+}
+
+// call sends in as JSON to path and decodes the response into out, if
+// given.
+func (c *Client) call(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.User != "":
+		req.SetBasicAuth(c.User, c.Token)
+	case c.Token != "":
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Messages []string `json:"errorMessages"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&e) == nil && len(e.Messages) > 0 {
+			return fmt.Errorf("jira %s %s: %s: %s", method, req.URL.Path, resp.Status, strings.Join(e.Messages, "; "))
+		}
+		return fmt.Errorf("jira %s %s: %s", method, req.URL.Path, resp.Status)
+	}
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("jira %s %s: %w", method, req.URL.Path, err)
+	}
+	return nil
+}
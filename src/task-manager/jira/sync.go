@@ -0,0 +1,270 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// TagPrefix marks the tag a pulled task carries with its issue key, as in
+// jira:ABC-123.
+const TagPrefix = "jira:"
+
+// Fields maps task fields to the Jira fields they are read from, e.g.
+// "due" to "duedate" or "context" to "customfield_10042".
+type Fields map[string]string
+
+// TaskFields are the task fields a Jira field can be mapped to.
+var TaskFields = []string{"description", "due", "priority", "project", "context", "tags", "assignee"}
+
+// DefaultFields is the mapping used for fields the configuration leaves
+// out. Map a field to "" to leave it alone.
+var DefaultFields = Fields{
+	"description": "summary",
+	"due":         "duedate",
+	"priority":    "priority",
+	"tags":        "labels",
+}
+
+// WithDefaults returns DefaultFields overridden by f.
+func (f Fields) WithDefaults() Fields {
+	out := maps.Clone(DefaultFields)
+	for field, name := range f {
+		if name == "" {
+			delete(out, field)
+		} else {
+			out[field] = name
+		}
+	}
+	return out
+}
+
+// names lists the Jira fields to ask a search for.
+func (f Fields) names() []string {
+	var out []string
+	for _, name := range f {
+		if !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// State is what pulls remember about every imported issue, keyed by issue
+// key.
+type State map[string]Entry
+
+// Entry links an issue to the task imported from it.
+type Entry struct {
+	UUID string `json:"uuid"`
+	Done bool   `json:"done,omitempty"` // the completion was pushed
+}
+
+// PullStats summarises one pull.
+type PullStats struct {
+	Created int // tasks added for new issues
+	Updated int // tasks changed to match their issue
+}
+
+// Pull imports the issues jql finds into tx: new issues become tasks
+// tagged jira:<key>, and tasks imported before take the current values of
+// the mapped fields. Labels are added to a task's tags, never removed.
+// Tasks deleted locally are not brought back. New issues are added to
+// state, which should be saved once tx commits. Changes are journalled
+// under op, so `task undo` reverts a pull.
+func (c *Client) Pull(ctx context.Context, tx tasks.Tx, jql string, fields Fields, state State, op tasks.Operation) (PullStats, error) {
+	var stats PullStats
+	issues, err := c.Search(ctx, jql, fields.names())
+	if err != nil {
+		return stats, err
+	}
+	err = tasks.Record(tx, op, func(tx tasks.Tx) error {
+		list, err := tx.List()
+		if err != nil {
+			return err
+		}
+		byUUID := make(map[string]tasks.Task, len(list))
+		for _, t := range list {
+			byUUID[t.UUID] = t
+		}
+		for _, iss := range issues {
+			e, known := state[iss.Key]
+			t, exists := byUUID[e.UUID]
+			switch {
+			case known && !exists:
+				continue
+			case !known:
+				id, err := tx.NextID()
+				if err != nil {
+					return err
+				}
+				t = tasks.Task{ID: id, UUID: tasks.NewUUID(), CreatedAt: op.Time, Description: iss.Key}
+			}
+			before := t
+			if err := apply(&t, iss, fields); err != nil {
+				return err
+			}
+			t.AddTags(TagPrefix + iss.Key)
+			if known && len(tasks.Diff(&before, &t)) == 0 {
+				continue
+			}
+			if err := tx.Put(t); err != nil {
+				return err
+			}
+			if known {
+				stats.Updated++
+			} else {
+				stats.Created++
+				state[iss.Key] = Entry{UUID: t.UUID}
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Push moves the issue of every completed task through the transition
+// named done, once. It returns the keys it moved; state is updated as it
+// goes, so an error part way through loses nothing.
+func (c *Client) Push(ctx context.Context, tx tasks.Tx, done string, state State) ([]string, error) {
+	list, err := tx.List()
+	if err != nil {
+		return nil, err
+	}
+	completed := map[string]bool{}
+	for _, t := range list {
+		completed[t.UUID] = t.Done()
+	}
+	var moved []string
+	for _, key := range slices.Sorted(maps.Keys(state)) {
+		e := state[key]
+		if e.Done || !completed[e.UUID] {
+			continue
+		}
+		if err := c.Transition(ctx, key, done); err != nil {
+			return moved, err
+		}
+		e.Done = true
+		state[key] = e
+		moved = append(moved, key)
+	}
+	return moved, nil
+}
+
+// apply sets the mapped fields of t from iss.
+func apply(t *tasks.Task, iss Issue, fields Fields) error {
+	for _, field := range TaskFields {
+		name, ok := fields[field]
+		if !ok {
+			continue
+		}
+		v := iss.Fields[name]
+		switch field {
+		case "description":
+			if s := text(v, "value", "name"); s != "" {
+				t.Description = s
+			}
+		case "due":
+			due, err := date(text(v))
+			if err != nil {
+				return fmt.Errorf("jira %s: %s: %w", iss.Key, name, err)
+			}
+			t.Due = due
+		case "priority":
+			t.Priority = priority(text(v, "name", "value"))
+		case "project":
+			t.Project = text(v, "key", "name", "value")
+		case "context":
+			t.Context = tasks.NormalizeContext(text(v, "value", "name"))
+		case "tags":
+			for _, s := range texts(v) {
+				t.AddTags(strings.ReplaceAll(s, " ", "-"))
+			}
+		case "assignee":
+			t.Assignee = text(v, "name", "emailAddress", "displayName")
+		}
+	}
+	return nil
+}
+
+// text renders a Jira field value: strings and numbers as they are,
+// objects (users, options, projects) by the first of keys they have, lists
+// joined by commas.
+func text(v any, keys ...string) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		for _, k := range append(keys, "value", "name", "displayName", "key") {
+			if s, ok := v[k].(string); ok && s != "" {
+				return s
+			}
+		}
+	case []any:
+		return strings.Join(texts(v), ",")
+	}
+	return ""
+}
+
+func texts(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		if s := text(v); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	var out []string
+	for _, item := range list {
+		if s := text(item); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// date parses a Jira date ("2006-01-02", due at the end of that day) or
+// date-time.
+func date(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		d = d.Add(24*time.Hour - time.Second)
+		return &d, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.000-0700", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errors.New("invalid date " + strconv.Quote(s))
+}
+
+// priority maps Jira's default priority names, and anything ParsePriority
+// understands; other names leave the task without a priority.
+func priority(name string) tasks.Priority {
+	switch strings.ToLower(name) {
+	case "highest", "blocker", "critical":
+		name = "high"
+	case "major":
+		name = "medium"
+	case "lowest", "minor", "trivial":
+		name = "low"
+	}
+	p, err := tasks.ParsePriority(name)
+	if err != nil {
+		return tasks.PriorityNone
+	}
+	return p
+}
@@ -0,0 +1,93 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Desktop pops up a native notification per reminder: notify-send on Linux
+// and the BSDs, osascript on macOS and a PowerShell toast on Windows.
+// Overdue tasks are sent as critical where the platform has urgency levels.
+type Desktop struct {
+	GOOS string // platform to notify on; "" means runtime.GOOS
+}
+
+// toastScript shows a Windows toast with the title and text passed in the
+// environment, under PowerShell's own app ID so no registration is needed.
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:TASK_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:TASK_MESSAGE)) > $null
+$app = '{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($app).Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// Check reports an error if the platform's notification tool is missing,
+// so a daemon can fail at startup rather than on its first reminder.
+func (d Desktop) Check() error {
+	name, _ := d.command("", "", false)
+	if name == "" {
+		return fmt.Errorf("desktop notifications are not supported on %s", d.goos())
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("desktop notifications need %s: %w", name, err)
+	}
+	return nil
+}
+
+// Notify implements Notifier.
+func (d Desktop) Notify(ctx context.Context, t tasks.Task, now time.Time) error {
+	overdue := t.Due.Before(now)
+	title := "Task " + strconv.Itoa(t.ID) + " is due in " + approx(t.Due.Sub(now))
+	if overdue {
+		title = "Task " + strconv.Itoa(t.ID) + " is overdue"
+	}
+	name, args := d.command(title, t.Description, overdue)
+	if name == "" {
+		return fmt.Errorf("desktop notifications are not supported on %s", d.goos())
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), "TASK_TITLE="+title, "TASK_MESSAGE="+t.Description)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification: %w: %s", err, out)
+	}
+	return nil
+}
+
+// command returns the program and arguments that show a notification, or
+// "" when the platform has none.
+func (d Desktop) command(title, body string, urgent bool) (string, []string) {
+	switch d.goos() {
+	case "darwin":
+		// Arguments rather than string literals, so nothing needs quoting.
+		return "osascript", []string{
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body,
+		}
+	case "windows":
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", toastScript}
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		urgency := "normal"
+		if urgent {
+			urgency = "critical"
+		}
+		return "notify-send", []string{"--app-name=task", "--urgency=" + urgency, "--", title, body}
+	}
+	return "", nil
+}
+
+func (d Desktop) goos() string {
+	if d.GOOS != "" {
+		return d.GOOS
+	}
+	return runtime.GOOS
+}
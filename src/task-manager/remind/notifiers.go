@@ -0,0 +1,92 @@
+package remind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Writer prints reminders as lines of text.
+type Writer struct {
+	W io.Writer
+}
+
+// Notify implements Notifier.
+func (w Writer) Notify(_ context.Context, t tasks.Task, now time.Time) error {
+	_, err := fmt.Fprintf(w.W, "%s  %s\n", now.Format("2006-01-02 15:04"), Message(t, now))
+	return err
+}
+
+// Command runs a shell command per reminder. The task is described in the
+// environment: TASK_ID, TASK_DESCRIPTION, TASK_DUE (RFC 3339), TASK_PRIORITY,
+// TASK_PROJECT, TASK_TAGS (comma-separated) and TASK_MESSAGE.
+type Command struct {
+	Command string
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+// Notify implements Notifier.
+func (c Command) Notify(ctx context.Context, t tasks.Task, now time.Time) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Env = append(os.Environ(),
+		"TASK_ID="+strconv.Itoa(t.ID),
+		"TASK_DESCRIPTION="+t.Description,
+		"TASK_DUE="+t.Due.Format(time.RFC3339),
+		"TASK_PRIORITY="+t.Priority.String(),
+		"TASK_PROJECT="+t.Project,
+		"TASK_TAGS="+strings.Join(t.Tags, ","),
+		"TASK_MESSAGE="+Message(t, now),
+	)
+	cmd.Stdout, cmd.Stderr = c.Stdout, c.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("remind command: %w", err)
+	}
+	return nil
+}
+
+// Webhook POSTs each reminder as JSON: {"message": ..., "task": {...}}.
+type Webhook struct {
+	URL  string
+	HTTP *http.Client // nil means a client with a 10s timeout
+}
+
+// Notify implements Notifier.
+func (w Webhook) Notify(ctx context.Context, t tasks.Task, now time.Time) error {
+	body, err := json.Marshal(struct {
+		Message string     `json:"message"`
+		Task    tasks.Task `json:"task"`
+	}{Message(t, now), t})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	hc := w.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("remind webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remind webhook: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package remind finds tasks that are about to fall due and tells someone
+// about them: a log line, a desktop notification, a shell command or a
+// webhook.
+package remind
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Due returns the open tasks due before now+within, including overdue ones,
+// soonest first.
+func Due(list []tasks.Task, now time.Time, within time.Duration) []tasks.Task {
+	limit := now.Add(within)
+	var due []tasks.Task
+	for _, t := range list {
+		if !t.Closed() && t.Due != nil && !t.Due.After(limit) {
+			due = append(due, t)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Due.Before(*due[j].Due) })
+	return due
+}
+
+// Notifier delivers one reminder.
+type Notifier interface {
+	Notify(ctx context.Context, t tasks.Task, now time.Time) error
+}
+
+// Multi sends every reminder to each of its notifiers, returning the first
+// error after trying them all.
+type Multi []Notifier
+
+// Notify implements Notifier.
+func (m Multi) Notify(ctx context.Context, t tasks.Task, now time.Time) error {
+	var first error
+	for _, n := range m {
+		if err := n.Notify(ctx, t, now); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Tracker remembers which reminders have gone out, so each task is announced
+// once per due date rather than on every poll.
+type Tracker struct {
+	sent map[int]time.Time // task ID -> due date announced
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{sent: map[int]time.Time{}}
+}
+
+// Fresh returns the tasks in due that have not been announced yet. Tasks
+// whose due date moved are announced again; tasks no longer in due are
+// forgotten.
+func (tr *Tracker) Fresh(due []tasks.Task) []tasks.Task {
+	var fresh []tasks.Task
+	current := make(map[int]bool, len(due))
+	for _, t := range due {
+		current[t.ID] = true
+		if at, ok := tr.sent[t.ID]; !ok || !at.Equal(*t.Due) {
+			fresh = append(fresh, t)
+		}
+	}
+	for id := range tr.sent {
+		if !current[id] {
+			delete(tr.sent, id)
+		}
+	}
+	return fresh
+}
+
+// Sent records that t was announced.
+func (tr *Tracker) Sent(t tasks.Task) {
+	tr.sent[t.ID] = *t.Due
+}
+
+// Message is the one-line text of a reminder.
+func Message(t tasks.Task, now time.Time) string {
+	if t.Due.Before(now) {
+		return fmt.Sprintf("task %d is overdue since %s: %s", t.ID, t.Due.Format("2006-01-02 15:04"), t.Description)
+	}
+	return fmt.Sprintf("task %d is due %s (in %s): %s", t.ID, t.Due.Format("2006-01-02 15:04"),
+		approx(t.Due.Sub(now)), t.Description)
+}
+
+// approx formats d to the minute: "2h5m" rather than "2h5m13.2s".
+func approx(d time.Duration) string {
+	if d < time.Minute {
+		return "under a minute"
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
@@ -0,0 +1,88 @@
+package taskcli
+
+import (
+	"context"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+)
+
+// expandAlias replaces an alias from the config file's [alias] table with
+// the command line it stands for, keeping the arguments typed after it.
+// Built-in commands always win over aliases of the same name.
+func (a *app) expandAlias(ctx context.Context, env *cli.Env, name string, args []string) ([]string, bool, error) {
+	cfg, err := a.config(env)
+	if err != nil {
+		return nil, false, err
+	}
+	line, ok := cfg.Alias[name]
+	if !ok {
+		return nil, false, nil
+	}
+	expanded, err := splitArgs(line)
+	if err != nil {
+		return nil, false, i18n.Errorf("alias %s: %w", name, err)
+	}
+	env.Log.Debug("expanding alias", "alias", name, "to", line)
+	return append(expanded, args...), true, nil
+}
+
+// splitArgs splits a command line into words the way a POSIX shell does,
+// without expanding anything: whitespace separates words, single quotes
+// keep everything literal, and double quotes and backslashes escape.
+func splitArgs(line string) ([]string, error) {
+	var (
+		words []string
+		word  strings.Builder
+		in    bool // inside a word
+		quote rune
+	)
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(rs) && strings.ContainsRune(`"\$`+"`", rs[i+1]):
+				i++
+				word.WriteRune(rs[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, in = r, true
+		case r == '\\':
+			if i+1 == len(rs) {
+				return nil, i18n.Errorf("trailing backslash in %q", line)
+			}
+			i++
+			word.WriteRune(rs[i])
+			in = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if in {
+				words = append(words, word.String())
+				word.Reset()
+				in = false
+			}
+		default:
+			word.WriteRune(r)
+			in = true
+		}
+	}
+	if quote != 0 {
+		return nil, i18n.Errorf("unterminated %c quote in %q", quote, line)
+	}
+	if in {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,75 @@
+package taskcli
+
+import (
+	"context"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) assignCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "assign",
+		Summary: "Assign tasks to a user",
+		Usage:   "<id|from-to>... <user|none>",
+		Help: `Sets who owns the given tasks, e.g. "task assign 4 alice", so a list shared
+through 'task serve' shows who does what; "none" unassigns them. Filter with
+'task list -assignee alice'.`,
+		Complete: func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+			if flag == "" && len(args) == 0 {
+				return a.taskIDs(env, true)
+			}
+			return a.assignees(env)
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) < 2 {
+				return usagef("assign takes task IDs and a user")
+			}
+			who := user(args[len(args)-1])
+			ranges, err := a.taskRanges(env, args[:len(args)-1])
+			if err != nil {
+				return err
+			}
+			var assigned []tasks.Task
+			err = a.update(env, "assign", func(tx tasks.Tx) error {
+				ids, err := resolveIDs(tx, ranges)
+				if err != nil {
+					return err
+				}
+				for _, id := range ids {
+					t, err := tasks.Assign(tx, id, who)
+					if err != nil {
+						return err
+					}
+					assigned = append(assigned, t)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, t := range assigned {
+				if who == "" {
+					a.printer(env).Fprintf(env.Stdout, "Unassigned task %d: %s\n", t.ID, t.Description)
+				} else {
+					a.printer(env).Fprintf(env.Stdout, "Assigned task %d to %s: %s\n", t.ID, who, t.Description)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// user normalises an assignee argument; "none" means unassigned.
+func user(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if strings.EqualFold(arg, "none") {
+		return ""
+	}
+	return arg
+}
+
+func (a *app) assignees(env *cli.Env) []string {
+	return a.collect(env, tasks.Assignees)
+}
@@ -0,0 +1,240 @@
+package taskcli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) attachCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "attach",
+		Summary:  "Attach files or URLs to a task",
+		Complete: a.completeTasks(true, false),
+		Usage:    "<id> <file|url>...",
+		Help: `Only the reference is stored: files by absolute path, so they must exist
+and stay where they are, and URLs as given. Attachments are numbered in the
+order they were added; 'task show' lists them and 'task open' opens them.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) < 2 {
+				return usagef("attach takes a task ID and at least one file or URL")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			refs := make([]string, len(args)-1)
+			for i, arg := range args[1:] {
+				if refs[i], err = attachmentRef(arg); err != nil {
+					return err
+				}
+			}
+			var t tasks.Task
+			added := map[string]bool{}
+			err = a.update(env, "attach", func(tx tasks.Tx) error {
+				if t, err = tx.Get(id); err != nil {
+					return err
+				}
+				for _, ref := range refs {
+					added[ref] = t.Attach(a.now(), ref)
+				}
+				return tx.Put(t)
+			})
+			if err != nil {
+				return err
+			}
+			p := a.printer(env)
+			for i, att := range t.Attachments {
+				if added[att.Ref] {
+					p.Fprintf(env.Stdout, "Attached %d to task %d: %s\n", i+1, t.ID, att.Ref)
+				}
+			}
+			for _, ref := range refs {
+				if !added[ref] {
+					p.Fprintf(env.Stdout, "Skipped %s: already attached to task %d\n", ref, t.ID)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (a *app) detachCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "detach",
+		Summary:  "Remove an attachment from a task",
+		Complete: a.completeAttachments,
+		Usage:    "<id> <n>",
+		Help:     `The file itself is left alone; only the reference is removed.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) != 2 {
+				return usagef("detach takes a task ID and an attachment number")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				t       tasks.Task
+				removed tasks.Attachment
+			)
+			err = a.update(env, "detach", func(tx tasks.Tx) error {
+				if t, err = tx.Get(id); err != nil {
+					return err
+				}
+				n, err := attachmentNumber(t, args[1])
+				if err != nil {
+					return err
+				}
+				removed = t.Attachments[n-1]
+				t.Attachments = append(t.Attachments[:n-1:n-1], t.Attachments[n:]...)
+				return tx.Put(t)
+			})
+			if err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Detached %s from task %d: %s\n", removed.Ref, t.ID, t.Description)
+			return nil
+		},
+	}
+}
+
+func (a *app) openCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "open",
+		Summary:  "Open a task's attachment",
+		Complete: a.completeAttachments,
+		Usage:    "<id> [n]",
+		Help: `Opens attachment n (1 when the task has only one) with the desktop's
+default application: xdg-open on Linux and the BSDs, open on macOS.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return usagef("open takes a task ID and an attachment number")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			var t tasks.Task
+			if err := a.view(env, func(tx tasks.Tx) error {
+				t, err = tx.Get(id)
+				return err
+			}); err != nil {
+				return err
+			}
+			var n int
+			switch {
+			case len(args) == 2:
+				if n, err = attachmentNumber(t, args[1]); err != nil {
+					return err
+				}
+			case len(t.Attachments) == 1:
+				n = 1
+			case len(t.Attachments) == 0:
+				return i18n.Errorf("task %d has no attachments", t.ID)
+			default:
+				return usagef("task %d has %d attachments; say which to open", t.ID, len(t.Attachments))
+			}
+			ref := t.Attachments[n-1].Ref
+			if !isURL(ref) {
+				if _, err := os.Stat(ref); err != nil {
+					return i18n.Errorf("attachment %d of task %d: %w", n, t.ID, err)
+				}
+			}
+			name, cmdArgs := opener(runtime.GOOS, ref)
+			if name == "" {
+				return i18n.Errorf("opening files is not supported on %s", runtime.GOOS)
+			}
+			cmd := exec.CommandContext(ctx, name, cmdArgs...)
+			cmd.Stdout, cmd.Stderr = env.Stdout, env.Stderr
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("%s %s: %w", name, ref, err)
+			}
+			return nil
+		},
+	}
+}
+
+// attachmentRef turns a command-line argument into what is stored: URLs as
+// they are, files as absolute paths after checking they exist.
+func attachmentRef(arg string) (string, error) {
+	if isURL(arg) {
+		return arg, nil
+	}
+	path, err := filepath.Abs(arg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", i18n.Errorf("attach: %w", err)
+	}
+	return path, nil
+}
+
+// isURL reports whether ref is a URL rather than a file path.
+func isURL(ref string) bool {
+	u, err := url.Parse(ref)
+	// A one-letter scheme is a Windows drive, as in C:\notes.txt.
+	return err == nil && len(u.Scheme) > 1 && (u.Host != "" || u.Opaque != "")
+}
+
+// attachmentNumber parses the 1-based number of one of t's attachments.
+func attachmentNumber(t tasks.Task, arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 {
+		return 0, usagef("invalid attachment number %q", arg)
+	}
+	if n > len(t.Attachments) {
+		return 0, i18n.Errorf("task %d has no attachment %d", t.ID, n)
+	}
+	return n, nil
+}
+
+// opener returns the program and arguments that open ref with the default
+// application on goos, or "" when there is none.
+func opener(goos, ref string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{ref}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", ref}
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "xdg-open", []string{ref}
+	}
+	return "", nil
+}
+
+// completeAttachments completes a task ID, then the numbers of its
+// attachments described by their references.
+func (a *app) completeAttachments(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+	switch {
+	case flag != "":
+		return nil
+	case len(args) == 0:
+		return a.taskIDs(env, false)
+	case len(args) > 1:
+		return nil
+	}
+	id, err := a.taskID(env, args[0])
+	if err != nil {
+		return nil
+	}
+	var out []string
+	a.view(env, func(tx tasks.Tx) error {
+		t, err := tx.Get(id)
+		for i, att := range t.Attachments {
+			out = append(out, strconv.Itoa(i+1)+"\t"+att.Ref)
+		}
+		return err
+	})
+	return out
+}
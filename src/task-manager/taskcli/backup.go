@@ -0,0 +1,368 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+const (
+	// backupKeep is how many snapshots task backup keeps unless configured
+	// otherwise.
+	backupKeep = 10
+	// backupPrefix and backupExt frame the name of every snapshot, with its
+	// UTC time in backupStamp between them.
+	backupPrefix = "task-"
+	backupExt    = ".json.gz"
+	backupStamp  = "20060102-150405"
+)
+
+func (a *app) backupCmd() *cli.Command {
+	var (
+		keep   int
+		dir    string
+		asList bool
+	)
+	return &cli.Command{
+		Name:    "backup",
+		Summary: "Save a compressed snapshot of every task list",
+		Usage:   "[-keep n] [-dir path] [-list]",
+		Help: `Writes every list's tasks, trash and history to a timestamped file such
+as task-20260501-093000.json.gz in the backups directory next to the task
+data, or in backup_dir from the config file. Snapshots are read through the
+storage backend rather than copied from its files, so one taken with the
+bolt backend restores into the json backend and back; lists kept encrypted
+stay encrypted with their own passphrase.
+
+Only the newest snapshots are kept: -keep, or backup_keep in the config
+file, says how many (10 by default, 0 for all). Run it from cron for
+regular backups, and roll back with 'task restore <snapshot>'.
+
+-list shows the snapshots, newest first.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&keep, "keep", -1, "number of snapshots to keep, 0 for all (default from config or 10)")
+			fs.StringVar(&dir, "dir", "", "directory for the snapshots (default from config or <data dir>/backups)")
+			fs.BoolVar(&asList, "list", false, "list the snapshots instead of taking one")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("backup takes no arguments")
+			}
+			if dir == "" {
+				var err error
+				if dir, err = a.backupDir(env); err != nil {
+					return err
+				}
+			}
+			if asList {
+				return a.printBackups(env, dir)
+			}
+			if keep < 0 {
+				cfg, err := a.config(env)
+				if err != nil {
+					return err
+				}
+				keep = cfg.backupKeep()
+			}
+
+			names, err := a.listNames(env)
+			if err != nil {
+				return err
+			}
+			b := tasks.NewBackup(a.now())
+			count := 0
+			for _, name := range names {
+				list, err := a.dumpList(env, name)
+				if err != nil {
+					return err
+				}
+				var params *tasks.KeyParams
+				var key []byte
+				if a.usedKey != nil {
+					params, key = &a.usedKey.params, a.usedKey.key
+				}
+				if err := b.Add(name, list, params, key); err != nil {
+					return err
+				}
+				count += len(list.Tasks)
+			}
+			path, err := writeBackup(dir, b)
+			if err != nil {
+				return err
+			}
+			p := a.printer(env)
+			p.Fprintf(env.Stdout, "Backed up %s (%s) to %s\n",
+				p.Plural(len(names), "%d list", "%d lists"), p.Plural(count, "%d task", "%d tasks"), path)
+			removed, err := pruneBackups(dir, keep)
+			if len(removed) > 0 {
+				p.Fprintf(env.Stdout, "Removed %s\n", p.Plural(len(removed), "%d old backup", "%d old backups"))
+			}
+			return err
+		},
+	}
+}
+
+// dumpList reads list name for a backup, noting in a.usedKey the key it
+// is encrypted with, if any.
+func (a *app) dumpList(env *cli.Env, name string) (tasks.ListBackup, error) {
+	a.usedKey = nil
+	s, err := a.openList(env, name)
+	if err != nil {
+		return tasks.ListBackup{}, err
+	}
+	var list tasks.ListBackup
+	err = s.View(func(tx tasks.Tx) (err error) {
+		list, err = tasks.DumpList(tx)
+		return err
+	})
+	return list, errors.Join(err, s.Close())
+}
+
+// backupDir is backup_dir from the config file, or backups in the data
+// directory.
+func (a *app) backupDir(env *cli.Env) (string, error) {
+	cfg, err := a.config(env)
+	if err != nil {
+		return "", err
+	}
+	if cfg.BackupDir != "" {
+		return cfg.BackupDir, nil
+	}
+	dir, err := a.dir(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
+
+// writeBackup saves b in dir under a name made from its time and returns
+// the file's path. The file appears complete or not at all.
+func writeBackup(dir string, b *tasks.Backup) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", i18n.Errorf("create backup dir: %w", err)
+	}
+	base := backupPrefix + b.Time.UTC().Format(backupStamp)
+	path := filepath.Join(dir, base+backupExt)
+	names, err := backupFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(names) > 0 && strings.HasPrefix(names[0], base) {
+		// Number a second snapshot within the same second after the newest.
+		n := 1
+		fmt.Sscanf(strings.TrimPrefix(strings.TrimSuffix(names[0], backupExt), base), ".%d", &n)
+		path = filepath.Join(dir, fmt.Sprintf("%s.%d%s", base, n+1, backupExt))
+	}
+	tmp, err := os.CreateTemp(dir, ".backup-*")
+	if err != nil {
+		return "", i18n.Errorf("write backup: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tasks.WriteBackup(tmp, b); err != nil {
+		tmp.Close()
+		return "", i18n.Errorf("write backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", i18n.Errorf("write backup: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", i18n.Errorf("write backup: %w", err)
+	}
+	return path, nil
+}
+
+// backupFiles returns the names of the snapshots in dir, newest first.
+func backupFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.Type().IsRegular() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupExt) {
+			names = append(names, name)
+		}
+	}
+	// The UTC timestamps in the names sort in time order, and a second
+	// snapshot within the same second, task-<time>.2.json.gz, after the
+	// first once the extension is off.
+	slices.SortFunc(names, func(x, y string) int {
+		return strings.Compare(strings.TrimSuffix(y, backupExt), strings.TrimSuffix(x, backupExt))
+	})
+	return names, nil
+}
+
+// pruneBackups deletes all but the keep newest snapshots in dir, if keep
+// is positive, and returns the names of those it deleted.
+func pruneBackups(dir string, keep int) ([]string, error) {
+	names, err := backupFiles(dir)
+	if err != nil || keep <= 0 || len(names) <= keep {
+		return nil, err
+	}
+	var removed []string
+	var errs []error
+	for _, name := range names[keep:] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, name)
+	}
+	return removed, errors.Join(errs...)
+}
+
+func (a *app) printBackups(env *cli.Env, dir string) error {
+	names, err := backupFiles(dir)
+	if err != nil {
+		return err
+	}
+	p := a.printer(env)
+	if len(names) == 0 {
+		p.Fprintf(env.Stdout, "No backups in %s\n", dir)
+		return nil
+	}
+	st := a.style(env)
+	tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+	p.Fprintln(tw, "Snapshot\tTaken\tSize")
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		taken := "-"
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupExt)
+		if t, err := time.Parse(backupStamp, stamp[:min(len(stamp), len(backupStamp))]); err == nil {
+			taken = t.Local().Format(st.dateLayout + " 15:04:05")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, taken, byteSize(info.Size()))
+	}
+	return tw.Flush()
+}
+
+// byteSize renders n bytes for people.
+func byteSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// isSnapshot reports whether a task restore argument names a snapshot
+// rather than a trashed task: "latest", a path, or a snapshot file name.
+// Task IDs and UUID prefixes are none of these.
+func isSnapshot(arg string) bool {
+	return arg == "latest" || strings.ContainsRune(arg, filepath.Separator) || strings.HasSuffix(arg, backupExt)
+}
+
+// restoreBackup rolls the lists in the snapshot ref back to their state in
+// it: only the -list one if the flag is given, else every list it holds.
+// Each list is changed in one journalled step, so 'task undo -list name'
+// reverts it.
+func (a *app) restoreBackup(env *cli.Env, ref string, yes bool) error {
+	path, err := a.snapshotPath(env, ref)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	b, err := tasks.ReadBackup(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var names []string
+	if a.listName != "" {
+		names = []string{a.listName}
+	} else {
+		for name := range b.Lists {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+	}
+	p := a.printer(env)
+	if !yes {
+		when := formatDate(b.Time, a.style(env).dateLayout)
+		question := p.Sprintf("Replace %s with the snapshot taken %s?", strings.Join(names, ", "), when)
+		if err := a.ask(env, question); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		list, err := b.List(name, a.fileKey(env))
+		if err != nil {
+			return err
+		}
+		s, err := a.openList(env, name)
+		if err != nil {
+			return err
+		}
+		op := tasks.Operation{Name: "rollback", Time: a.now(), User: a.user(env)}
+		err = s.Update(func(tx tasks.Tx) error {
+			return tasks.Record(tx, op, func(tx tasks.Tx) error {
+				return tasks.RestoreList(tx, list)
+			})
+		})
+		if err = errors.Join(err, s.Close()); err != nil {
+			return i18n.Errorf("list %s: %w", name, err)
+		}
+		p.Fprintf(env.Stdout, "Rolled list %s back to %s\n", name, p.Plural(len(list.Tasks), "%d task", "%d tasks"))
+	}
+	return nil
+}
+
+// snapshotPath resolves a snapshot reference: "latest", a path, or the
+// name of a snapshot in the backups directory.
+func (a *app) snapshotPath(env *cli.Env, ref string) (string, error) {
+	if strings.ContainsRune(ref, filepath.Separator) {
+		return ref, nil
+	}
+	dir, err := a.backupDir(env)
+	if err != nil {
+		return "", err
+	}
+	if ref == "latest" {
+		names, err := backupFiles(dir)
+		if err != nil {
+			return "", err
+		}
+		if len(names) == 0 {
+			return "", i18n.Errorf("%w: no backups in %s", tasks.ErrNotFound, dir)
+		}
+		ref = names[0]
+	}
+	if _, err := os.Stat(ref); err == nil {
+		return ref, nil
+	}
+	return filepath.Join(dir, ref), nil
+}
+
+// completeBackups offers the snapshot names.
+func (a *app) completeBackups(env *cli.Env) []string {
+	dir, err := a.backupDir(env)
+	if err != nil {
+		return nil
+	}
+	names, _ := backupFiles(dir)
+	return append(names, "latest")
+}
@@ -0,0 +1,312 @@
+package taskcli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) startCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "start",
+		Summary:  "Mark tasks as in progress",
+		Complete: a.completeManyIDs(true),
+		Usage:    "<id|from-to>...",
+		Help: `Moves the given tasks from the backlog to "In Progress" on 'task board'.
+'task stop' moves them back; completing a task ends its progress too. The
+time in between is added to the task's tracked time, which 'task show'
+displays and 'task stats' compares with its estimate.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			return a.setProgress(env, args, "start", func(tx tasks.Tx, id int) (tasks.Task, error) {
+				return tasks.Start(tx, id, a.now())
+			})
+		},
+	}
+}
+
+func (a *app) stopCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "stop",
+		Summary:  "Move started or waiting tasks back to the backlog",
+		Complete: a.completeManyIDs(true),
+		Usage:    "<id|from-to>...",
+		Help:     `The time the tasks were in progress is added to their tracked time.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			return a.setProgress(env, args, "stop", func(tx tasks.Tx, id int) (tasks.Task, error) {
+				return tasks.Stop(tx, id, a.now())
+			})
+		},
+	}
+}
+
+func (a *app) holdCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "hold",
+		Summary:  "Mark tasks as waiting on someone or something else",
+		Complete: a.completeManyIDs(true),
+		Usage:    "<id|from-to>...",
+		Help: `Waiting tasks stay in the list, marked [~], and get their own lane on
+'task board'; 'task start' or 'task stop' picks them up again. To hide a
+task until a date instead, use 'task snooze'.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			return a.setProgress(env, args, "hold", func(tx tasks.Tx, id int) (tasks.Task, error) {
+				return tasks.Transition(tx, id, tasks.Waiting, a.now())
+			})
+		},
+	}
+}
+
+func (a *app) cancelCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "cancel",
+		Summary:  "Close tasks without completing them",
+		Complete: a.completeManyIDs(true),
+		Usage:    "<id|from-to>...",
+		Help: `Cancelled tasks are closed like completed ones, marked [-] in the list,
+but do not count as done in statistics. 'task reopen' undoes this.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			return a.setProgress(env, args, "cancel", func(tx tasks.Tx, id int) (tasks.Task, error) {
+				return tasks.Transition(tx, id, tasks.Cancelled, a.now())
+			})
+		},
+	}
+}
+
+func (a *app) reopenCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "reopen",
+		Summary:  "Move done or cancelled tasks back to the backlog",
+		Complete: a.completeManyIDs(false),
+		Usage:    "<id|from-to>...",
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			return a.setProgress(env, args, "reopen", func(tx tasks.Tx, id int) (tasks.Task, error) {
+				return tasks.Reopen(tx, id, a.now())
+			})
+		},
+	}
+}
+
+// setProgress applies a status change to every task named in args.
+func (a *app) setProgress(env *cli.Env, args []string, op string, fn func(tasks.Tx, int) (tasks.Task, error)) error {
+	if len(args) == 0 {
+		return usagef("missing task ID")
+	}
+	ranges, err := a.taskRanges(env, args)
+	if err != nil {
+		return err
+	}
+	var changed []tasks.Task
+	err = a.update(env, op, func(tx tasks.Tx) error {
+		ids, err := resolveIDs(tx, ranges)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			t, err := fn(tx, id)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	format := map[string]string{
+		"start":  "Started task %d: %s\n",
+		"stop":   "Stopped task %d: %s\n",
+		"hold":   "Task %d is waiting: %s\n",
+		"cancel": "Cancelled task %d: %s\n",
+		"reopen": "Reopened task %d: %s\n",
+	}[op]
+	for _, t := range changed {
+		a.printer(env).Fprintf(env.Stdout, format, t.ID, t.Description)
+	}
+	return nil
+}
+
+func (a *app) boardCmd() *cli.Command {
+	var (
+		project string
+		tags    cli.StringList
+		done    int
+	)
+	return &cli.Command{
+		Name:     "board",
+		Aliases:  []string{"kanban"},
+		Summary:  "Show tasks as a kanban board",
+		Complete: a.completeTasks(false, false),
+		Usage:    "[-project name] [-tag name]... [-done count]",
+		Help: `Lays tasks out in four columns by status: Backlog (pending), In Progress
+(started with 'task start'), Waiting ('task hold') and Done; snoozed and
+cancelled tasks are left out. Open tasks are ordered by priority and the
+most recently completed come first under Done. The board fills the
+terminal width, or $COLUMNS when output is not a terminal.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&project, "project", "", "only tasks in this project or its sub-projects")
+			fs.Var(&tags, "tag", "only tasks with this tag (repeatable, all must match)")
+			fs.IntVar(&done, "done", 10, "show at most this many completed tasks (0 for all)")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("board takes no arguments")
+			}
+			var filters []tasks.Filter
+			if project != "" {
+				filters = append(filters, tasks.InProject(strings.TrimSpace(project)))
+			}
+			if len(tags) > 0 {
+				filters = append(filters, tasks.WithTags(tasks.NormalizeTags(tags)...))
+			}
+			var list []tasks.Task
+			if err := a.view(env, func(tx tasks.Tx) (err error) {
+				list, err = tx.List()
+				return err
+			}); err != nil {
+				return err
+			}
+			now := a.now()
+			filters = append(filters, tasks.Actionable(now))
+			list = tasks.Select(list, tasks.And(filters...))
+
+			cols := boardColumns(list, done)
+			return printBoard(env.Stdout, cols, terminalWidth(env), now, a.style(env))
+		},
+	}
+}
+
+// column is one lane of the board.
+type column struct {
+	title string
+	tasks []tasks.Task
+	total int // before -done trimmed the Done lane
+}
+
+// boardColumns splits list into the Backlog, In Progress, Waiting and Done
+// lanes, keeping the done most recently completed tasks (all when done is
+// 0). Cancelled tasks are left out.
+func boardColumns(list []tasks.Task, done int) []column {
+	cols := []column{{title: "Backlog"}, {title: "In Progress"}, {title: "Waiting"}, {title: "Done"}}
+	lane := map[tasks.Status]int{tasks.Pending: 0, tasks.InProgress: 1, tasks.Waiting: 2, tasks.Done: 3}
+	tasks.SortByPriority(list)
+	for _, t := range list {
+		i, ok := lane[t.Status]
+		if !ok {
+			continue
+		}
+		c := &cols[i]
+		c.tasks = append(c.tasks, t)
+		c.total++
+	}
+	last := &cols[len(cols)-1]
+	slices.SortStableFunc(last.tasks, func(a, b tasks.Task) int {
+		return -compareCompleted(a, b)
+	})
+	if done > 0 && len(last.tasks) > done {
+		last.tasks = last.tasks[:done]
+	}
+	return cols
+}
+
+func compareCompleted(a, b tasks.Task) int {
+	switch {
+	case a.CompletedAt == nil || b.CompletedAt == nil:
+		return 0
+	default:
+		return a.CompletedAt.Compare(*b.CompletedAt)
+	}
+}
+
+// terminalWidth is the width to lay the board out in: the terminal's, then
+// $COLUMNS, then 80.
+func terminalWidth(env *cli.Env) int {
+	if f, ok := env.Stdout.(*os.File); ok && isTerminal(f) {
+		if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+			return w
+		}
+	}
+	if n, err := strconv.Atoi(env.EnvString("COLUMNS", "")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// printBoard renders cols side by side, each card truncated to its lane.
+func printBoard(w io.Writer, cols []column, width int, now time.Time, st style) error {
+	const gap = 3
+	lane := max((width-gap*(len(cols)-1))/len(cols), 12)
+
+	var buf bytes.Buffer
+	line := func(cells []string) {
+		var b strings.Builder
+		for _, c := range cells {
+			b.WriteString(c + strings.Repeat(" ", lane-utf8.RuneCountInString(c)+gap))
+		}
+		buf.WriteString(strings.TrimRight(b.String(), " ") + "\n")
+	}
+
+	heads := make([]string, len(cols))
+	rules := make([]string, len(cols))
+	rows := 0
+	for i, c := range cols {
+		head := fmt.Sprintf("%s (%d)", st.p.T(c.title), c.total)
+		heads[i] = fit(head, lane)
+		rules[i] = strings.Repeat("─", lane)
+		rows = max(rows, len(c.tasks))
+	}
+	line(heads)
+	line(rules)
+
+	// Colors apply to whole lines, which hold several cards, so only the
+	// header is colored; card marks urgent cards instead.
+	for r := range rows {
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			if r < len(c.tasks) {
+				cells[i] = fit(card(c.tasks[r], now), lane)
+			}
+		}
+		line(cells)
+	}
+	if rows == 0 {
+		st.p.Fprintln(&buf, "No tasks.")
+	}
+	if !st.color {
+		_, err := buf.WriteTo(w)
+		return err
+	}
+	return st.paintRows(w, buf.Bytes(), nil)
+}
+
+// card is the one-line summary of a task on the board; open tasks that are
+// overdue or of high priority are marked with "!".
+func card(t tasks.Task, now time.Time) string {
+	s := strconv.Itoa(t.ID) + " " + t.Description
+	if !t.Closed() && (t.IsOverdue(now) || t.Priority >= tasks.PriorityHigh) {
+		s = "!" + s
+	}
+	return s
+}
+
+// fit truncates s to n runes, marking the cut with an ellipsis.
+func fit(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
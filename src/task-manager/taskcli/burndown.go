@@ -0,0 +1,141 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+// burndownHeight is the number of rows the chart's bars are drawn in.
+const burndownHeight = 10
+
+func (a *app) burndownCmd() *cli.Command {
+	var (
+		days   int
+		asJSON bool
+	)
+	return &cli.Command{
+		Name:    "burndown",
+		Summary: "Chart the number of open tasks over the last days",
+		Usage:   "[-days n] [-json]",
+		Help: `Draws one bar per day with the number of tasks open at the end of it,
+worked out from the change history, and sums up how many tasks were added,
+reopened, closed and deleted in that time. A falling chart means the list
+is shrinking. When there are more days than fit the terminal, each bar
+stands for several days and shows the last of them.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&days, "days", 30, "chart this many days, ending today")
+			fs.BoolVar(&asJSON, "json", false, "print the daily counts as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("burndown takes no arguments")
+			}
+			if days < 1 {
+				return usagef("-days must be at least 1")
+			}
+			var (
+				list   []tasks.Task
+				events []tasks.Event
+			)
+			err := a.view(env, func(tx tasks.Tx) (err error) {
+				if list, err = tx.List(); err != nil {
+					return err
+				}
+				events, err = tx.History()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			now := a.now()
+			times := make([]time.Time, days)
+			for i := range times {
+				times[i] = endOfDay(now.AddDate(0, 0, i-days+1))
+			}
+			times[days-1] = now // today so far
+			counts := tasks.Burndown(list, events, times)
+
+			if asJSON {
+				type day struct {
+					Date string `json:"date"`
+					Open int    `json:"open"`
+				}
+				out := make([]day, days)
+				for i, t := range times {
+					out[i] = day{Date: t.Format("2006-01-02"), Open: counts[i]}
+				}
+				return printJSON(env.Stdout, out)
+			}
+
+			flow := tasks.BurndownFlow(events, times[0], now)
+			st := a.style(env)
+			st.p.Fprintf(env.Stdout, "Open tasks over the last %s: %d -> %d (%d added, %d reopened, %d closed, %d deleted)\n\n",
+				st.p.Plural(days, "%d day", "%d days"), counts[0], counts[days-1],
+				flow.Added, flow.Reopened, flow.Closed, flow.Deleted)
+			return printBurndown(env.Stdout, times, counts, terminalWidth(env), st)
+		},
+	}
+}
+
+// printBurndown draws counts as a bar chart no wider than width, with the
+// dates of the first and last bar under it.
+func printBurndown(w io.Writer, times []time.Time, counts []int, width int, st style) error {
+	top := 0
+	for _, n := range counts {
+		top = max(top, n)
+	}
+	label := len(strconv.Itoa(top))
+	// Each bar covers step days; the last bar is always today.
+	avail := max(width-label-3, 1)
+	step := (len(counts) + avail - 1) / avail
+	var bars []int
+	for i := len(counts) - 1; i >= 0; i -= step {
+		bars = append(bars, counts[i])
+	}
+	first := times[(len(counts)-1)%step]
+	for i, j := 0, len(bars)-1; i < j; i, j = i+1, j-1 {
+		bars[i], bars[j] = bars[j], bars[i]
+	}
+
+	height := min(top, burndownHeight)
+	var b strings.Builder
+	for row := height; row >= 1; row-- {
+		// A bar reaches this row once it is at least row/height of the top.
+		var y string
+		switch row {
+		case height:
+			y = strconv.Itoa(top)
+		case (height + 1) / 2:
+			y = strconv.Itoa((top*row + height - 1) / height)
+		}
+		fmt.Fprintf(&b, "%*s |", label, y)
+		line := make([]byte, len(bars))
+		for i, n := range bars {
+			line[i] = ' '
+			if n*height >= row*top {
+				line[i] = '#'
+			}
+		}
+		b.WriteString(strings.TrimRight(string(line), " ") + "\n")
+	}
+	fmt.Fprintf(&b, "%*d +%s\n", label, 0, strings.Repeat("-", len(bars)))
+
+	from, to := first.Local().Format(st.dateLayout), times[len(times)-1].Local().Format(st.dateLayout)
+	gap := len(bars) - len(from) - len(to)
+	if gap < 1 {
+		fmt.Fprintf(&b, "%*s  %s - %s\n", label, "", from, to)
+	} else {
+		fmt.Fprintf(&b, "%*s  %s%s%s\n", label, "", from, strings.Repeat(" ", gap), to)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,145 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/caldav"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// caldavState is what `task caldav` remembers about a list between runs.
+type caldavState struct {
+	URL      string       `json:"url"`
+	User     string       `json:"user,omitempty"`
+	Objects  caldav.State `json:"objects"`
+	LastSync time.Time    `json:"last_sync"`
+}
+
+func (a *app) caldavCmd() *cli.Command {
+	var url, user string
+	return &cli.Command{
+		Name:    "caldav",
+		Summary: "Sync tasks with a CalDAV task list (Nextcloud, Fastmail, ...)",
+		Usage:   "[-url collection-url] [-user name]",
+		Help: `Two-way sync between the list and the VTODOs of one CalDAV collection.
+Description, status, completion time, due date and priority are mapped
+both ways; other VTODO properties are kept as they are on the server.
+
+A task changed on one side since the last sync is copied to the other; one
+changed on both keeps the later change; one deleted on one side and
+unchanged on the other is deleted on both. The URL and user are remembered
+per list, so later syncs need no flags. The password comes from
+$TASK_CALDAV_PASSWORD, or is prompted for on the terminal; use an app
+password where the server offers them.
+
+The collection URL is the one the server shows for the calendar, e.g.
+  https://cloud.example.com/remote.php/dav/calendars/alice/tasks/
+  https://caldav.fastmail.com/dav/calendars/user/alice@fastmail.com/<id>/`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&url, "url", "", "collection URL (remembered)")
+			fs.StringVar(&user, "user", "", "user name (remembered)")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("caldav takes no arguments")
+			}
+			statePath, err := a.syncStatePath(env)
+			if err != nil {
+				return err
+			}
+			statePath = strings.TrimSuffix(statePath, ".json") + ".caldav.json"
+			state, err := loadCaldavState(statePath)
+			if err != nil {
+				return err
+			}
+			if url != "" && url != state.URL {
+				// A different collection shares nothing with the old one.
+				state = caldavState{URL: url, User: state.User}
+			}
+			if user != "" {
+				state.User = user
+			}
+			if state.URL == "" {
+				return usagef("no collection configured; pass -url collection-url")
+			}
+			password, err := caldavPassword(env, a.printer(env), state.User)
+			if err != nil {
+				return err
+			}
+
+			s, err := a.open(env)
+			if err != nil {
+				return err
+			}
+			defer func() { err = errors.Join(err, s.Close()) }()
+
+			client := &caldav.Client{URL: state.URL, User: state.User, Password: password}
+			op := tasks.Operation{Name: "caldav", Time: a.now(), User: a.user(env)}
+			var stats caldav.Stats
+			if err := s.Update(func(tx tasks.Tx) error {
+				state.Objects, stats, err = client.Sync(ctx, tx, state.Objects, op)
+				return err
+			}); err != nil {
+				return err
+			}
+			state.LastSync = a.now()
+			if err := saveCaldavState(statePath, state); err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Synced with %s: %d sent, %d received, %d removed, %d conflicts\n",
+				state.URL, stats.Sent, stats.Received, stats.Removed, stats.Conflicts)
+			return nil
+		},
+	}
+}
+
+// caldavPassword reads the password from $TASK_CALDAV_PASSWORD or the
+// terminal. No user means no authentication.
+func caldavPassword(env *cli.Env, msgs *i18n.Printer, user string) (string, error) {
+	if p := env.EnvString("TASK_CALDAV_PASSWORD", ""); p != "" || user == "" {
+		return p, nil
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", errors.New("caldav: set TASK_CALDAV_PASSWORD or run from a terminal")
+	}
+	defer tty.Close()
+	msgs.Fprintf(tty, "CalDAV password for %s: ", user)
+	defer fmt.Fprintln(tty)
+	p, err := term.ReadPassword(int(tty.Fd()))
+	return string(p), err
+}
+
+func loadCaldavState(path string) (caldavState, error) {
+	var st caldavState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("%s: %w", path, err)
+	}
+	return st, nil
+}
+
+func saveCaldavState(path string, st caldavState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeState(path, data)
+}
@@ -0,0 +1,176 @@
+package taskcli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// theme holds the ANSI SGR parameters ("31" red, "1" bold) for each kind of
+// row in a task table. An empty entry leaves those rows plain. Whole rows
+// are colored after the table is laid out, so any attribute works without
+// upsetting the column alignment.
+type theme struct {
+	Header    string
+	Overdue   string
+	High      string // high priority or above
+	Completed string
+}
+
+// themes are the built-in themes selectable with the theme setting.
+var themes = map[string]theme{
+	"default": {Header: "4", Overdue: "31", High: "1", Completed: "2"},
+	"dark":    {Header: "1;4", Overdue: "91", High: "1;97", Completed: "90"},
+	"light":   {Header: "1;4", Overdue: "31", High: "1;34", Completed: "37"},
+	"mono":    {Header: "4", Overdue: "7", High: "1", Completed: "2"},
+}
+
+// sgrNames are the words accepted in the [colors] config table.
+var sgrNames = map[string]int{
+	"bold": 1, "dim": 2, "italic": 3, "underline": 4, "reverse": 7,
+	"black": 30, "red": 31, "green": 32, "yellow": 33,
+	"blue": 34, "magenta": 35, "cyan": 36, "white": 37,
+}
+
+// parseColor turns a color setting such as "bright-red bold" or "on-blue"
+// into SGR parameters. Raw parameters ("38;5;208") pass through, and
+// "none" turns the color off.
+func parseColor(spec string) (string, error) {
+	var params []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool { return r == ' ' || r == ',' }) {
+		if word == "none" {
+			continue
+		}
+		if strings.Trim(word, "0123456789;") == "" {
+			params = append(params, word)
+			continue
+		}
+		name, offset := word, 0
+		if n, ok := strings.CutPrefix(name, "bright-"); ok {
+			name, offset = n, 60
+		}
+		if n, ok := strings.CutPrefix(name, "on-"); ok {
+			name, offset = n, offset+10
+		}
+		code, ok := sgrNames[name]
+		if !ok || (code < 30 && offset != 0) {
+			return "", i18n.Errorf("unknown color %q", word)
+		}
+		params = append(params, strconv.Itoa(code+offset))
+	}
+	return strings.Join(params, ";"), nil
+}
+
+// loadTheme resolves the theme and [colors] settings into cfg.theme.
+func (cfg *config) loadTheme() error {
+	name := cfg.Theme
+	if name == "" {
+		name = "default"
+	}
+	th, ok := themes[name]
+	if !ok {
+		names := make([]string, 0, len(themes))
+		for n := range themes {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return i18n.Errorf("theme: unknown theme %q (want %s)", name, strings.Join(names, ", "))
+	}
+	for role, spec := range cfg.Colors {
+		sgr, err := parseColor(spec)
+		if err != nil {
+			return i18n.Errorf("colors.%s: %w", role, err)
+		}
+		switch role {
+		case "header":
+			th.Header = sgr
+		case "overdue":
+			th.Overdue = sgr
+		case "high":
+			th.High = sgr
+		case "completed":
+			th.Completed = sgr
+		default:
+			return i18n.Errorf("colors: unknown entry %q (want header, overdue, high or completed)", role)
+		}
+	}
+	cfg.theme = th
+	return nil
+}
+
+// colorEnabled decides whether to color output for w: the color setting if
+// given, otherwise only on a terminal, and never when NO_COLOR is set
+// (https://no-color.org) or TERM is dumb.
+func colorEnabled(env *cli.Env, cfg *config, w io.Writer) bool {
+	switch {
+	case cfg.Color != nil:
+		return *cfg.Color
+	case env.EnvString("NO_COLOR", "") != "", env.EnvString("TERM", "") == "dumb":
+		return false
+	default:
+		return isTerminal(w)
+	}
+}
+
+func isTerminal(w any) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// rowColor returns the SGR parameters for a task's table row, combining
+// every attribute that applies.
+func (st style) rowColor(t tasks.Task, now time.Time) string {
+	var params []string
+	for _, p := range []struct {
+		on  bool
+		sgr string
+	}{
+		{t.Closed(), st.theme.Completed},
+		{t.IsOverdue(now), st.theme.Overdue},
+		{t.Priority >= tasks.PriorityHigh, st.theme.High},
+	} {
+		if p.on && p.sgr != "" {
+			params = append(params, p.sgr)
+		}
+	}
+	return strings.Join(params, ";")
+}
+
+// paintRows writes a laid-out table to w, wrapping the header line in the
+// theme's header color and each following line in the matching entry of
+// rows; lines beyond rows stay plain.
+func (st style) paintRows(w io.Writer, table []byte, rows []string) error {
+	lines := bytes.SplitAfter(table, []byte("\n"))
+	for i, line := range lines {
+		var sgr string
+		switch {
+		case i == 0:
+			sgr = st.theme.Header
+		case i <= len(rows):
+			sgr = rows[i-1]
+		}
+		body, nl := bytes.CutSuffix(line, []byte("\n"))
+		if sgr != "" && len(body) > 0 {
+			line = []byte("\x1b[" + sgr + "m" + string(body) + "\x1b[0m")
+			if nl {
+				line = append(line, '\n')
+			}
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,890 @@
+package taskcli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) addCmd() *cli.Command {
+	var (
+		due, priority       string
+		project, gtdContext string
+		assignee, estimate  string
+		tags                cli.StringList
+		parent              string
+		fromStdin, literal  bool
+		allowDuplicate      bool
+	)
+	return &cli.Command{
+		Name:     "add",
+		Summary:  "Add a new task",
+		Complete: a.completeTasks(false, false),
+		Usage:    "<description>|-stdin [-due date] [-priority level] [-estimate duration] [-tag name]... [-parent id] [-project name] [-context name] [-assignee user] [-literal] [-allow-duplicate]",
+		Help: `Fields can be written into the description, so quick capture needs a
+single argument:
+
+  task add "Buy milk +groceries @errands due:fri p:high"
+
+  +name          attach a tag
+  @name          set the context
+  due:friday     any date -due takes, with - for spaces: due:next-week
+  p:high         or pri:, priority:, any level -priority takes
+  est:30m        or estimate:, any effort -estimate takes
+  project:home   or proj:; also assignee:
+
+They override the flags. Other words, such as URLs, stay in the
+description; -literal keeps it exactly as typed.
+
+A description much like that of an open task is taken for a repeat: you
+are asked whether to add it as a note to that task instead, add it anyway
+or cancel. With -stdin such lines are skipped with a warning.
+-allow-duplicate adds them without asking.
+
+With -stdin, every non-blank line of standard input becomes a task, all
+added in one transaction and with the same flags:
+
+  cat todo.txt | task add -stdin -project house`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&fromStdin, "stdin", false, "add one task per line of standard input")
+			fs.StringVar(&project, "project", "", "project the task belongs to (dots nest: website.blog)")
+			fs.StringVar(&gtdContext, "context", "", "GTD context the task is done in, e.g. @home")
+			fs.StringVar(&assignee, "assignee", "", "user responsible for the task")
+			fs.StringVar(&parent, "parent", "", "make the new task a subtask of this task ID or UUID")
+			fs.Var(&tags, "tag", "attach a tag (repeatable)")
+			fs.StringVar(&due, "due", "", "due date (YYYY-MM-DD or YYYY-MM-DD HH:MM)")
+			fs.StringVar(&priority, "priority", "", "priority: low, medium, high, none or 0-9 (default from config)")
+			fs.StringVar(&estimate, "estimate", "", "expected effort, e.g. 45m or 2h")
+			fs.BoolVar(&literal, "literal", false, "keep +tag, @context and field:value words in the description")
+			fs.BoolVar(&allowDuplicate, "allow-duplicate", false, "add tasks even if they repeat an open task")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			desc := strings.TrimSpace(strings.Join(args, " "))
+			descs := []string{desc}
+			switch {
+			case fromStdin && desc != "":
+				return usagef("-stdin takes no description arguments")
+			case fromStdin:
+				var err error
+				if descs, err = readLines(env.Stdin); err != nil {
+					return err
+				}
+				if len(descs) == 0 {
+					return usagef("no tasks on standard input")
+				}
+			case desc == "":
+				return usagef("missing task description")
+			}
+			now := a.now()
+			dueAt, err := optionalDate(due, now)
+			if err != nil {
+				return usagef("-due: %v", err)
+			}
+			prio, err := tasks.ParsePriority(priority)
+			if err != nil {
+				return usagef("-priority: %v", err)
+			}
+			if priority == "" {
+				cfg, err := a.config(env)
+				if err != nil {
+					return err
+				}
+				prio = cfg.priority
+			}
+			effort, err := parseEstimate(estimate)
+			if err != nil {
+				return usagef("-estimate: %v", err)
+			}
+			var parentID int
+			if parent != "" {
+				if parentID, err = a.taskID(env, parent); err != nil {
+					return err
+				}
+			}
+			todo := make([]tasks.Task, len(descs))
+			for i, desc := range descs {
+				todo[i] = tasks.Task{
+					Description: desc,
+					CreatedAt:   now,
+					Due:         dueAt,
+					Priority:    prio,
+					Estimate:    effort,
+					Tags:        tasks.NormalizeTags(tags),
+					Parent:      parentID,
+					Project:     strings.TrimSpace(project),
+					Context:     tasks.NormalizeContext(gtdContext),
+					Assignee:    user(assignee),
+				}
+				if !literal {
+					if err := applyInline(&todo[i], now); err != nil {
+						return err
+					}
+				}
+			}
+			if !allowDuplicate {
+				open, err := a.openTasks(env)
+				if err != nil {
+					return err
+				}
+				p := a.printer(env)
+				kept := todo[:0]
+				for _, t := range todo {
+					dup, found := tasks.Similar(open, t.Description, duplicateSimilarity)
+					switch {
+					case !found:
+						kept = append(kept, t)
+					case fromStdin:
+						p.Fprintf(env.Stderr, "Skipped %q: task %d looks the same\n", t.Description, dup.ID)
+					default:
+						note, err := a.askDuplicate(env, dup)
+						if err != nil {
+							return err
+						}
+						if note {
+							return a.noteDuplicate(env, dup.ID, t.Description)
+						}
+						kept = append(kept, t)
+					}
+				}
+				if todo = kept; len(todo) == 0 {
+					return nil
+				}
+			}
+			added := make([]tasks.Task, 0, len(todo))
+			err = a.update(env, "add", func(tx tasks.Tx) error {
+				for _, t := range todo {
+					t, err := tasks.Insert(tx, t)
+					if err != nil {
+						return err
+					}
+					added = append(added, t)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, t := range added {
+				a.printer(env).Fprintf(env.Stdout, "Added task %d: %s\n", t.ID, t.Description)
+			}
+			return nil
+		},
+	}
+}
+
+func (a *app) listCmd() *cli.Command {
+	var (
+		dueBefore, dueAfter string
+		project, gtdContext string
+		assignee, order     string
+		tags                cli.StringList
+		tree, asJSON, all   bool
+		watch, absolute     bool
+		limit, offset, page int
+	)
+	return &cli.Command{
+		Name:     "list",
+		Summary:  "List all tasks, most important first",
+		Complete: a.completeTasks(false, false),
+		Usage:    "[<filter>] [-due-before date] [-due-after date] [-tag name]... [-project name] [-context name] [-assignee user|none] [-sort keys] [-all] [-tree] [-absolute] [-limit n] [-offset n] [-page n] [-json] [-watch]",
+		Help: `A filter expression narrows the list where the flags fall short:
+
+  task list 'status=pending and (tag=home or priority=high) and due<2025-01-01'
+
+Terms compare project, tag, context, assignee, status, priority, search,
+due, created or completed with a value using = (or :) and !=, and
+priority and the dates also with <, <=, > and >=. They combine with and,
+or and not, and parentheses; dates are read like -due-before's, in double
+quotes if they hold spaces or colons ("next friday"), and none matches
+tasks without one. The flags and the expression must all match.
+
+Due dates are shown relative to now ("today 17:00", "in 3 days",
+"2 weeks overdue"); -absolute, or absolute_dates in the config file, shows
+them as dates instead.
+
+Tasks snoozed with 'task snooze' are left out until their date passes,
+unless -all is given. The filter of the active 'task context', if any,
+applies on top of the flags.
+
+-limit shows at most that many tasks, after skipping -offset of them;
+-page 3 shows the third page of -limit tasks (20 if -limit is not given).
+A footer then says which of how many matching tasks are shown. With -tree
+the rows are counted, subtasks included.
+
+-watch keeps the list on screen and redraws it whenever the task data
+changes, from this or any other process, and once a minute so due dates
+stay current. Ctrl-C stops it.
+
+Without -sort the list is ordered by the config file's sort setting, or
+by descending priority. -sort takes comma-separated fields, each ascending
+unless prefixed with "-"; ties fall through to the next field and finally
+to the ID. Subtasks without a due date or priority of their own sort by
+their parent's; other tasks without a due date, project, context or
+assignee sort last on that field. Fields: ` + strings.Join(tasks.SortFields(), ", ") + `.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&project, "project", "", "only tasks in this project or its sub-projects")
+			fs.StringVar(&gtdContext, "context", "", "only tasks in this context")
+			fs.StringVar(&assignee, "assignee", "", "only tasks assigned to this user (\"none\": unassigned)")
+			fs.StringVar(&order, "sort", "", "sort by these comma-separated fields, e.g. due,-priority")
+			fs.BoolVar(&all, "all", false, "include snoozed tasks")
+			fs.BoolVar(&tree, "tree", false, "show subtasks indented under their parents")
+			fs.BoolVar(&absolute, "absolute", false, "show due dates as dates rather than relative to now")
+			fs.BoolVar(&asJSON, "json", false, "print the tasks as a JSON array")
+			fs.BoolVar(&watch, "watch", false, "redraw the list whenever the tasks change")
+			fs.Var(&tags, "tag", "only tasks with this tag (repeatable, all must match)")
+			fs.StringVar(&dueBefore, "due-before", "", "only tasks due before this date")
+			fs.StringVar(&dueAfter, "due-after", "", "only tasks due after this date")
+			fs.IntVar(&limit, "limit", 0, "show at most this many tasks (0 for all)")
+			fs.IntVar(&offset, "offset", 0, "skip this many matching tasks")
+			fs.IntVar(&page, "page", 0, "show this page of -limit tasks, counting from 1")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			switch {
+			case limit < 0 || offset < 0 || page < 0:
+				return usagef("-limit, -offset and -page must not be negative")
+			case page > 0 && offset > 0:
+				return usagef("-page and -offset cannot be combined")
+			case page > 0:
+				if limit == 0 {
+					limit = defaultPageSize
+				}
+				offset = (page - 1) * limit
+			}
+			paged := limit > 0 || offset > 0
+			now := a.now()
+			keys, err := a.sortKeys(env, order)
+			if err != nil {
+				return err
+			}
+			var filters []tasks.Filter
+			if len(args) > 0 {
+				f, err := a.parseFilter(strings.Join(args, " "))
+				if err != nil {
+					return usagef("%v", err)
+				}
+				filters = append(filters, f)
+			}
+			if dueBefore != "" {
+				when, err := parseDate(dueBefore, now)
+				if err != nil {
+					return usagef("-due-before: %v", err)
+				}
+				filters = append(filters, tasks.DueBefore(when))
+			}
+			if dueAfter != "" {
+				when, err := parseDate(dueAfter, now)
+				if err != nil {
+					return usagef("-due-after: %v", err)
+				}
+				filters = append(filters, tasks.DueAfter(when))
+			}
+			if len(tags) > 0 {
+				filters = append(filters, tasks.WithTags(tasks.NormalizeTags(tags)...))
+			}
+			if project != "" {
+				filters = append(filters, tasks.InProject(strings.TrimSpace(project)))
+			}
+			if gtdContext != "" {
+				filters = append(filters, tasks.InContext(gtdContext))
+			}
+			if assignee != "" {
+				filters = append(filters, tasks.AssignedTo(user(assignee)))
+			}
+			render := func(w io.Writer) error {
+				now := a.now()
+				active, filter, err := a.activeContext(env)
+				if err != nil {
+					return err
+				}
+				var list []tasks.Task
+				err = a.view(env, func(tx tasks.Tx) error {
+					var err error
+					list, err = tx.List()
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				// Sort before filtering, so subtasks still inherit from
+				// parents the filters leave out.
+				tasks.SortBy(list, keys)
+				list = tasks.Select(list, tasks.And(append(filters, filter)...))
+				var snoozed int
+				if !all {
+					n := len(list)
+					list = tasks.Select(list, tasks.Actionable(now))
+					snoozed = n - len(list)
+				}
+
+				st := a.style(env)
+				st.absolute = st.absolute || absolute
+				var shown, total int
+				switch {
+				case tree:
+					nodes := tasks.Tree(list)
+					total = len(nodes)
+					nodes = pageOf(nodes, offset, limit)
+					shown = len(nodes)
+					if asJSON {
+						return printJSON(w, nodes)
+					}
+					err = printNodes(w, nodes, now, st)
+				default:
+					total = len(list)
+					list = pageOf(list, offset, limit)
+					shown = len(list)
+					if asJSON {
+						return printJSON(w, list)
+					}
+					err = printTasks(w, list, now, st)
+				}
+				if err != nil {
+					return err
+				}
+				if paged {
+					p := a.printer(env)
+					if shown == 0 {
+						fmt.Fprint(w, p.Plural(total, "(none of %d matching task on this page)\n", "(none of %d matching tasks on this page)\n"))
+					} else {
+						p.Fprintf(w, "(%d-%d of %s)\n", offset+1, offset+shown, p.Plural(total, "%d matching task", "%d matching tasks"))
+					}
+				}
+				if snoozed > 0 {
+					p := a.printer(env)
+					fmt.Fprint(w, p.Plural(snoozed, "(%d snoozed task hidden until later; -all shows it)\n", "(%d snoozed tasks hidden until later; -all shows them)\n"))
+				}
+				if active != "" {
+					a.printer(env).Fprintf(w, "(context %s; 'task context none' clears it)\n", active)
+				}
+				return nil
+			}
+			if watch {
+				return a.watch(ctx, env, render)
+			}
+			return render(env.Stdout)
+		},
+	}
+}
+
+// defaultPageSize is the page length of list -page without -limit.
+const defaultPageSize = 20
+
+// pageOf returns the rows that remain after skipping offset of them, at
+// most limit of them (all when limit is 0).
+func pageOf[T any](rows []T, offset, limit int) []T {
+	rows = rows[min(offset, len(rows)):]
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// sortKeys resolves the list order: the -sort flag, then the config file,
+// then descending priority.
+func (a *app) sortKeys(env *cli.Env, flagValue string) ([]tasks.SortKey, error) {
+	if flagValue != "" {
+		keys, err := tasks.ParseSort(flagValue)
+		if err != nil {
+			return nil, usagef("-sort: %v", err)
+		}
+		return keys, nil
+	}
+	cfg, err := a.config(env)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.sort != nil {
+		return cfg.sort, nil
+	}
+	return []tasks.SortKey{{Field: "priority", Desc: true}}, nil
+}
+
+func (a *app) completeCmd() *cli.Command {
+	var force, yes bool
+	return &cli.Command{
+		Name:     "complete",
+		Summary:  "Mark tasks as completed",
+		Complete: a.completeManyIDs(true),
+		Usage:    "<id|from-to>... | <description> [-force] [-yes]",
+		Help: `Completes every listed task in one step, which undo reverts as a whole.
+Ranges such as 10-20 cover the tasks that exist in them. Subtasks given
+together with their parent are completed first; tasks that are already done
+are skipped.
+
+Instead of IDs, an open task can be named by (part of) its description:
+"grocer" finds "Buy groceries". When several tasks match equally well, you
+are asked which one to complete.
+
+Completing several tasks lists them and asks for confirmation first,
+unless -yes is given.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&force, "force", false, "complete tasks even if they have open subtasks")
+			yesFlags(fs, &yes)
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) == 0 {
+				return usagef("missing task ID")
+			}
+			args, err := a.uuidRefs(env, args)
+			if err != nil {
+				return err
+			}
+			if byDescription(args) {
+				id, err := a.pickTask(env, "Complete", strings.Join(args, " "), true)
+				if err != nil {
+					return err
+				}
+				args = []string{strconv.Itoa(id)}
+			}
+			ranges, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			var done, skipped []tasks.Task
+			single := len(ranges) == 1 && ranges[0].single()
+			if !single && !yes {
+				open, err := a.viewTasks(env, ranges, func(t tasks.Task) bool { return !t.Done() })
+				if err != nil {
+					return err
+				}
+				if len(open) > 1 {
+					if err := a.confirm(env, "Complete %s?", open); err != nil {
+						return err
+					}
+				}
+			}
+			err = a.update(env, "complete", func(tx tasks.Tx) error {
+				ids, err := resolveIDs(tx, ranges)
+				if err != nil {
+					return err
+				}
+				list, err := tx.List()
+				if err != nil {
+					return err
+				}
+				for _, id := range childrenFirst(list, ids) {
+					t, err := tasks.Complete(tx, id, a.now(), force)
+					if errors.Is(err, tasks.ErrAlreadyCompleted) && !single {
+						t, _ = tx.Get(id)
+						skipped = append(skipped, t)
+						continue
+					}
+					if err != nil {
+						return err
+					}
+					done = append(done, t)
+				}
+				return nil
+			})
+			if errors.Is(err, tasks.ErrOpenSubtasks) {
+				return i18n.Errorf("%w; complete them first or use -force", err)
+			}
+			if err != nil {
+				return err
+			}
+			p := a.printer(env)
+			for _, t := range done {
+				p.Fprintf(env.Stdout, "Completed task %d: %s\n", t.ID, t.Description)
+			}
+			for _, t := range skipped {
+				p.Fprintf(env.Stdout, "Skipped task %d: already completed\n", t.ID)
+			}
+			if !single {
+				p.Fprintf(env.Stdout, "Completed %s, skipped %d\n", p.Plural(len(done), "%d task", "%d tasks"), len(skipped))
+			}
+			return nil
+		},
+	}
+}
+
+func (a *app) deleteCmd() *cli.Command {
+	var yes bool
+	return &cli.Command{
+		Name:     "delete",
+		Summary:  "Delete tasks",
+		Complete: a.completeManyIDs(false),
+		Usage:    "<id|from-to>... [-yes]",
+		Help: `Deletes every listed task in one step, which undo reverts as a whole.
+Ranges such as 10-20 cover the tasks that exist in them.
+
+Deleted tasks go to the trash, where 'task trash' lists them and
+'task restore' brings them back until they are purged after trash_days
+days (30 by default).
+
+The tasks are listed and you are asked to confirm, unless -yes is given.`,
+		Flags: func(fs *flag.FlagSet) {
+			yesFlags(fs, &yes)
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) == 0 {
+				return usagef("missing task ID")
+			}
+			ranges, err := a.taskRanges(env, args)
+			if err != nil {
+				return err
+			}
+			if !yes {
+				list, err := a.viewTasks(env, ranges, nil)
+				if err != nil {
+					return err
+				}
+				if err := a.confirm(env, "Delete %s?", list); err != nil {
+					return err
+				}
+			}
+			cfg, err := a.config(env)
+			if err != nil {
+				return err
+			}
+			now, user := a.now(), a.user(env)
+			var deleted []tasks.Task
+			err = a.update(env, "delete", func(tx tasks.Tx) error {
+				ids, err := resolveIDs(tx, ranges)
+				if err != nil {
+					return err
+				}
+				for _, id := range ids {
+					t, err := tasks.Discard(tx, id, now, user)
+					if err != nil {
+						return err
+					}
+					deleted = append(deleted, t)
+				}
+				// Expired entries go now; with trash_days = 0 that includes
+				// the tasks just deleted.
+				_, err = tasks.PurgeTrash(tx, trashCutoff(now, cfg.trashDays()))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			p := a.printer(env)
+			for _, t := range deleted {
+				p.Fprintf(env.Stdout, "Deleted task %d: %s\n", t.ID, t.Description)
+			}
+			if len(ranges) > 1 || !ranges[0].single() {
+				p.Fprintf(env.Stdout, "Deleted %s\n", p.Plural(len(deleted), "%d task", "%d tasks"))
+			}
+			return nil
+		},
+	}
+}
+
+// printTasks renders list as the table shared by list, search and friends.
+func printTasks(w io.Writer, list []tasks.Task, now time.Time, st style) error {
+	nodes := make([]tasks.Node, len(list))
+	for i, t := range list {
+		nodes[i] = tasks.Node{Task: t}
+	}
+	return printNodes(w, nodes, now, st)
+}
+
+// printNodes is printTasks for a hierarchy: subtasks are indented under
+// their parent in the Description column.
+func printNodes(w io.Writer, nodes []tasks.Node, now time.Time, st style) error {
+	if len(nodes) == 0 {
+		st.p.Fprintln(w, "No tasks.")
+		return nil
+	}
+	// The Assignee column only appears once someone is assigned a task.
+	assigned := slices.ContainsFunc(nodes, func(n tasks.Node) bool { return n.Assignee != "" })
+	var table bytes.Buffer
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	if assigned {
+		st.p.Fprintln(tw, "ID\tStatus\tPri\tDue\tProject\tAssignee\tDescription\tTags")
+	} else {
+		st.p.Fprintln(tw, "ID\tStatus\tPri\tDue\tProject\tDescription\tTags")
+	}
+	for _, n := range nodes {
+		desc := n.Description
+		if n.Depth > 0 {
+			desc = strings.Repeat("  ", n.Depth-1) + "└ " + desc
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t", n.ID, checkbox(n.Task),
+			orDash(st.p.T(n.Priority.String())), dueCell(n.Task, now, st), orDash(n.Project))
+		if assigned {
+			fmt.Fprintf(tw, "%s\t", orDash(n.Assignee))
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", desc, labels(n.Task))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !st.color {
+		_, err := table.WriteTo(w)
+		return err
+	}
+	rows := make([]string, len(nodes))
+	for i, n := range nodes {
+		rows[i] = st.rowColor(n.Task, now)
+	}
+	return st.paintRows(w, table.Bytes(), rows)
+}
+
+// labels renders a task's context ("@home") followed by its tags.
+func labels(t tasks.Task) string {
+	l := t.Tags
+	if t.Context != "" {
+		l = append([]string{"@" + t.Context}, l...)
+	}
+	return strings.Join(l, ",")
+}
+
+// dueCell renders a task's due date for list output, relative to now unless
+// st asks for exact dates, flagging overdue tasks.
+func dueCell(t tasks.Task, now time.Time, st style) string {
+	switch {
+	case t.Due == nil:
+		return "-"
+	case !st.absolute:
+		return relativeDate(*t.Due, now, t.IsOverdue(now), st.p)
+	case t.IsOverdue(now):
+		return formatDate(*t.Due, st.dateLayout) + " " + st.p.T("OVERDUE")
+	default:
+		return formatDate(*t.Due, st.dateLayout)
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// printJSON writes rows as an indented JSON array for scripts; an empty
+// result is [] rather than null.
+func printJSON[T any](w io.Writer, rows []T) error {
+	if rows == nil {
+		rows = []T{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+// checkbox renders a task's status: "[ ]" pending, "[>]" in progress, "[~]"
+// waiting, "[x]" done and "[-]" cancelled.
+func checkbox(t tasks.Task) string {
+	switch t.Status {
+	case tasks.Done:
+		return "[x]"
+	case tasks.Cancelled:
+		return "[-]"
+	case tasks.InProgress:
+		return "[>]"
+	case tasks.Waiting:
+		return "[~]"
+	default:
+		return "[ ]"
+	}
+}
+
+func (a *app) tagsCmd() *cli.Command {
+	var asJSON bool
+	return &cli.Command{
+		Name:    "tags",
+		Summary: "List tags with their open and total task counts",
+		Usage:   "[-json]",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the counts as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("tags takes no arguments")
+			}
+			var list []tasks.Task
+			err := a.view(env, func(tx tasks.Tx) error {
+				var err error
+				list, err = tx.List()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			counts := tasks.CountTags(list)
+			if asJSON {
+				return printJSON(env.Stdout, counts)
+			}
+			if len(counts) == 0 {
+				a.printer(env).Fprintln(env.Stdout, "No tags.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			a.printer(env).Fprintln(tw, "Tag\tOpen\tTotal")
+			for _, c := range counts {
+				fmt.Fprintf(tw, "%s\t%d\t%d\n", c.Tag, c.Open, c.Total)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func (a *app) searchCmd() *cli.Command {
+	var asJSON bool
+	return &cli.Command{
+		Name:    "search",
+		Summary: "Find tasks whose description, notes, tags, project or context contain every query term",
+		Usage:   "<query> [-json]",
+		Help:    "Matching is case-insensitive and matches substrings, so \"doc\" finds \"Documentation\".",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the matches as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			query := strings.Join(args, " ")
+			if strings.TrimSpace(query) == "" {
+				return usagef("missing search query")
+			}
+			var list []tasks.Task
+			err := a.view(env, func(tx tasks.Tx) error {
+				var err error
+				list, err = tx.List()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			matches := tasks.Search(list, query)
+			tasks.SortByPriority(matches)
+			if asJSON {
+				return printJSON(env.Stdout, matches)
+			}
+			return printTasks(env.Stdout, matches, a.now(), a.style(env))
+		},
+	}
+}
+
+func (a *app) projectsCmd() *cli.Command {
+	var asJSON bool
+	return &cli.Command{
+		Name:    "projects",
+		Summary: "List projects with their pending and completed task counts",
+		Usage:   "[-json]",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the counts as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("projects takes no arguments")
+			}
+			var list []tasks.Task
+			err := a.view(env, func(tx tasks.Tx) error {
+				var err error
+				list, err = tx.List()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			counts := tasks.CountProjects(list)
+			if asJSON {
+				return printJSON(env.Stdout, counts)
+			}
+			if len(counts) == 0 {
+				a.printer(env).Fprintln(env.Stdout, "No projects.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			a.printer(env).Fprintln(tw, "Project\tPending\tDone")
+			for _, c := range counts {
+				fmt.Fprintf(tw, "%s\t%d\t%d\n", c.Project, c.Pending, c.Done)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// listSummary is one row of `task lists`.
+type listSummary struct {
+	Name    string `json:"name"`
+	Open    int    `json:"open"`
+	Total   int    `json:"total"`
+	Current bool   `json:"current"`
+}
+
+func (a *app) listsCmd() *cli.Command {
+	var asJSON bool
+	return &cli.Command{
+		Name:    "lists",
+		Summary: "List the task lists with their open and total task counts",
+		Usage:   "[-json]",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the lists as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("lists takes no arguments")
+			}
+			current, err := a.list(env)
+			if err != nil {
+				return err
+			}
+			names, err := a.listNames(env)
+			if err != nil {
+				return err
+			}
+			summaries := make([]listSummary, 0, len(names))
+			for _, name := range names {
+				s, err := a.openList(env, name)
+				if err != nil {
+					return err
+				}
+				var list []tasks.Task
+				err = errors.Join(s.View(func(tx tasks.Tx) (err error) {
+					list, err = tx.List()
+					return err
+				}), s.Close())
+				if err != nil {
+					return err
+				}
+				summaries = append(summaries, listSummary{
+					Name:    name,
+					Open:    len(tasks.Select(list, func(t tasks.Task) bool { return !t.Closed() })),
+					Total:   len(list),
+					Current: name == current,
+				})
+			}
+			if asJSON {
+				return printJSON(env.Stdout, summaries)
+			}
+
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			a.printer(env).Fprintln(tw, "\tList\tOpen\tTotal")
+			for _, s := range summaries {
+				mark := ""
+				if s.Current {
+					mark = "*"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", mark, s.Name, s.Open, s.Total)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// readLines returns the non-blank lines of r, trimmed.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
@@ -0,0 +1,132 @@
+package taskcli
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+// Shell completion candidates. Errors are swallowed: a missing or locked
+// database just means no suggestions.
+
+// completeTasks completes task-related flag values and, for the first
+// positional argument, task IDs (only open ones when open is set).
+func (a *app) completeTasks(ids, open bool) func(context.Context, *cli.Env, string, []string) []string {
+	return func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+		switch flag {
+		case "":
+			if ids && len(args) == 0 {
+				return a.taskIDs(env, open)
+			}
+		case "tag", "untag":
+			return a.collect(env, func(list []tasks.Task) (out []string) {
+				for _, c := range tasks.CountTags(list) {
+					out = append(out, c.Tag)
+				}
+				return out
+			})
+		case "project":
+			return a.collect(env, func(list []tasks.Task) (out []string) {
+				for _, c := range tasks.CountProjects(list) {
+					out = append(out, c.Project)
+				}
+				return out
+			})
+		case "context":
+			return a.collect(env, func(list []tasks.Task) (out []string) {
+				seen := map[string]bool{}
+				for _, t := range list {
+					if t.Context != "" && !seen[t.Context] {
+						seen[t.Context] = true
+						out = append(out, t.Context)
+					}
+				}
+				return out
+			})
+		case "assignee":
+			return a.assignees(env)
+		case "parent":
+			return a.taskIDs(env, true)
+		case "priority":
+			return []string{"high", "medium", "low", "none"}
+		case "sort":
+			return tasks.SortFields()
+		}
+		return nil
+	}
+}
+
+// completeManyIDs completes the positional arguments of commands that take
+// any number of task IDs, leaving out the ones already typed.
+func (a *app) completeManyIDs(open bool) func(context.Context, *cli.Env, string, []string) []string {
+	return func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+		if flag != "" {
+			return nil
+		}
+		var out []string
+		for _, c := range a.taskIDs(env, open) {
+			id, _, _ := strings.Cut(c, "\t")
+			if !slices.Contains(args, id) {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+}
+
+func (a *app) taskIDs(env *cli.Env, open bool) []string {
+	return a.collect(env, func(list []tasks.Task) (out []string) {
+		for _, t := range list {
+			if !open || !t.Closed() {
+				out = append(out, strconv.Itoa(t.ID)+"\t"+t.Description)
+			}
+		}
+		return out
+	})
+}
+
+func (a *app) collect(env *cli.Env, fn func([]tasks.Task) []string) []string {
+	var list []tasks.Task
+	if err := a.view(env, func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	}); err != nil {
+		return nil
+	}
+	return fn(list)
+}
+
+func (a *app) completeRoot(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+	switch flag {
+	case "backend":
+		return []string{"bolt", "json", "git"}
+	case "list":
+		names, _ := a.listNames(env)
+		return names
+	case "profile":
+		cfg, err := loadConfig(a.configPath(env), "")
+		if err != nil {
+			return nil
+		}
+		var names []string
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return names
+	}
+	return nil
+}
+
+func completeFormats(formats []string) func(context.Context, *cli.Env, string, []string) []string {
+	return func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+		if flag == "format" {
+			return formats
+		}
+		return nil
+	}
+}
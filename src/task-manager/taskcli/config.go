@@ -0,0 +1,367 @@
+package taskcli
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/jira"
+	"gopatterns/task-manager/taskio"
+	"gopatterns/task-manager/tasks"
+	"gopatterns/task-manager/webhook"
+)
+
+// config holds the defaults read from config.toml. Every setting is
+// optional; command-line flags and TASK_* environment variables take
+// precedence over it, and it takes precedence over built-in defaults.
+type config struct {
+	DataDir         string `toml:"data_dir"`
+	Backend         string `toml:"backend"`
+	DefaultList     string `toml:"default_list"`
+	DefaultPriority string `toml:"default_priority"`
+	DateFormat      string `toml:"date_format"`
+	AbsoluteDates   bool   `toml:"absolute_dates"` // due dates as dates, not "in 3 days"
+	Color           *bool  `toml:"color"`          // unset: color when writing to a terminal
+	Theme           string `toml:"theme"`
+	RemindWithin    string `toml:"remind_within"`
+	RemindCommand   string `toml:"remind_command"`
+	RemindWebhook   string `toml:"remind_webhook"`
+	RemindDesktop   bool   `toml:"remind_desktop"`
+	Encrypt         bool   `toml:"encrypt"`
+	KeyCache        string `toml:"key_cache"`
+	Sort            string `toml:"sort"`
+	TrashDays       *int   `toml:"trash_days"` // unset: tasks.TrashDays
+	BackupDir       string `toml:"backup_dir"`
+	BackupKeep      *int   `toml:"backup_keep"` // unset: backupKeep; 0 keeps every snapshot
+	Locale          string `toml:"locale"`      // unset: from $LC_ALL, $LC_MESSAGES or $LANG
+
+	Colors     map[string]string `toml:"colors"`      // per-row overrides of the theme
+	Alias      map[string]string `toml:"alias"`       // name -> command line it stands for
+	CSVColumns map[string]string `toml:"csv_columns"` // task field -> CSV column to import it from
+	Urgency    urgencyConfig     `toml:"urgency"`
+	Jira       jiraConfig        `toml:"jira"`
+
+	Webhooks []webhookConfig `toml:"webhooks"`
+
+	// Profiles are [profiles.<name>] tables of settings that replace the
+	// ones above when the profile is selected with -profile or $TASK_PROFILE.
+	Profiles map[string]toml.Primitive `toml:"profiles"`
+
+	profile        string // the selected profile, if any
+	profileDataDir bool   // the profile sets data_dir itself
+	priority       tasks.Priority
+	remindWithin   time.Duration
+	keyCache       time.Duration
+	sort           []tasks.SortKey
+	theme          theme
+}
+
+// jiraConfig is the [jira] table: the site 'task jira' talks to and how
+// issues become tasks.
+type jiraConfig struct {
+	URL            string            `toml:"url"`
+	User           string            `toml:"user"` // e-mail for Jira Cloud; empty for a personal access token
+	JQL            string            `toml:"jql"`
+	DoneTransition string            `toml:"done_transition"`
+	Fields         map[string]string `toml:"fields"` // task field -> Jira field
+}
+
+// urgencyConfig overrides the urgency coefficients; unset entries keep
+// their defaults.
+type urgencyConfig struct {
+	Due      *float64           `toml:"due"`
+	Priority *float64           `toml:"priority"`
+	Age      *float64           `toml:"age"`
+	Tags     *float64           `toml:"tags"`
+	Notes    *float64           `toml:"notes"`
+	Project  *float64           `toml:"project"`
+	Started  *float64           `toml:"started"`
+	Blocking *float64           `toml:"blocking"`
+	Blocked  *float64           `toml:"blocked"`
+	Tag      map[string]float64 `toml:"tag"`
+}
+
+// coefficients returns the default urgency coefficients with the config
+// file's overrides applied.
+func (cfg *config) coefficients() tasks.Coefficients {
+	c := tasks.DefaultCoefficients()
+	u := cfg.Urgency
+	for _, o := range []struct {
+		dst *float64
+		src *float64
+	}{
+		{&c.Due, u.Due}, {&c.Priority, u.Priority}, {&c.Age, u.Age},
+		{&c.Tags, u.Tags}, {&c.Notes, u.Notes}, {&c.Project, u.Project},
+		{&c.Started, u.Started}, {&c.Blocking, u.Blocking}, {&c.Blocked, u.Blocked},
+	} {
+		if o.src != nil {
+			*o.dst = *o.src
+		}
+	}
+	for tag, v := range u.Tag {
+		for _, t := range tasks.NormalizeTags([]string{tag}) {
+			c.Tag[t] = v
+		}
+	}
+	return c
+}
+
+// trashDays is how many days deleted tasks stay in the trash.
+func (cfg *config) trashDays() int {
+	if cfg.TrashDays == nil {
+		return tasks.TrashDays
+	}
+	return *cfg.TrashDays
+}
+
+// backupKeep is how many snapshots task backup keeps.
+func (cfg *config) backupKeep() int {
+	if cfg.BackupKeep == nil {
+		return backupKeep
+	}
+	return *cfg.BackupKeep
+}
+
+// sender delivers webhooks for list to the configured hooks.
+func (cfg *config) sender(list string) *webhook.Sender {
+	s := &webhook.Sender{List: list}
+	for _, h := range cfg.Webhooks {
+		s.Hooks = append(s.Hooks, webhook.Hook{URL: h.URL, Events: h.Events})
+	}
+	return s
+}
+
+// webhookConfig is one [[webhooks]] entry.
+type webhookConfig struct {
+	URL    string   `toml:"url"`
+	Events []string `toml:"events"` // default: created, completed, deleted
+}
+
+// namedDateFormats are shorthands accepted for date_format; anything else is
+// used as a Go time layout.
+var namedDateFormats = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "01/02/2006",
+	"eu":  "02/01/2006",
+}
+
+// configPath resolves the config file: the -config flag, then $TASK_CONFIG,
+// then $XDG_CONFIG_HOME/task/config.toml, then ~/.config/task/config.toml.
+func (a *app) configPath(env *cli.Env) string {
+	if a.configFile != "" {
+		return a.configFile
+	}
+	if p := env.EnvString("TASK_CONFIG", ""); p != "" {
+		return p
+	}
+	if d := env.EnvString("XDG_CONFIG_HOME", ""); d != "" {
+		return filepath.Join(d, "task", "config.toml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "task", "config.toml")
+}
+
+// config loads the config file once per invocation. A missing file is the
+// same as an empty one, unless it was named explicitly.
+func (a *app) config(env *cli.Env) (*config, error) {
+	if a.cfg != nil {
+		return a.cfg, nil
+	}
+	profile := a.profileName(env)
+	cfg, err := loadConfig(a.configPath(env), profile)
+	if errors.Is(err, fs.ErrNotExist) {
+		if a.configFile != "" || env.EnvString("TASK_CONFIG", "") != "" {
+			return nil, i18n.Errorf("config: %w", err)
+		}
+		cfg, err = loadConfig("", profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.cfg = cfg
+	return cfg, nil
+}
+
+// profileName is the selected profile: the -profile flag, then
+// $TASK_PROFILE. Empty means none.
+func (a *app) profileName(env *cli.Env) string {
+	if a.profile != "" {
+		return a.profile
+	}
+	return env.EnvString("TASK_PROFILE", "")
+}
+
+// loadConfig reads the config file at path with the settings of profile,
+// if not empty, laid over the top-level ones.
+func loadConfig(path, profile string) (*config, error) {
+	var cfg config
+	if path == "" {
+		if profile != "" {
+			return nil, i18n.Errorf("unknown profile %q: define [profiles.%s] in the config file", profile, profile)
+		}
+		return &cfg, cfg.loadTheme()
+	}
+	md, err := toml.DecodeFile(path, &cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, i18n.Errorf("config %s: %w", path, err)
+	}
+	// Decode every profile, so mistakes in one show up whichever is in
+	// use, then lay the selected one over the top-level settings.
+	for name, prim := range cfg.Profiles {
+		if tasks.ValidListName(name) != nil {
+			return nil, i18n.Errorf("config %s: invalid profile name %q (use letters, digits, - and _)", path, name)
+		}
+		var check config
+		if err := md.PrimitiveDecode(prim, &check); err != nil {
+			return nil, i18n.Errorf("config %s: profiles.%s: %w", path, name, err)
+		}
+		if check.Profiles != nil {
+			return nil, i18n.Errorf("config %s: profiles.%s: profiles cannot be nested", path, name)
+		}
+	}
+	if profile != "" {
+		prim, ok := cfg.Profiles[profile]
+		if !ok {
+			return nil, i18n.Errorf("config %s: unknown profile %q", path, profile)
+		}
+		if err := md.PrimitiveDecode(prim, &cfg); err != nil {
+			return nil, i18n.Errorf("config %s: profiles.%s: %w", path, profile, err)
+		}
+		cfg.profile = profile
+		cfg.profileDataDir = md.IsDefined("profiles", profile, "data_dir")
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, i18n.Errorf("config %s: unknown setting %q", path, undecoded[0].String())
+	}
+
+	if cfg.priority, err = tasks.ParsePriority(cfg.DefaultPriority); err != nil {
+		return nil, i18n.Errorf("config %s: default_priority: %w", path, err)
+	}
+	if cfg.RemindWithin != "" {
+		if cfg.remindWithin, err = time.ParseDuration(cfg.RemindWithin); err != nil {
+			return nil, i18n.Errorf("config %s: remind_within: %w", path, err)
+		}
+	}
+	if cfg.KeyCache != "" {
+		if cfg.keyCache, err = time.ParseDuration(cfg.KeyCache); err != nil {
+			return nil, i18n.Errorf("config %s: key_cache: %w", path, err)
+		}
+	}
+	if cfg.Sort != "" {
+		if cfg.sort, err = tasks.ParseSort(cfg.Sort); err != nil {
+			return nil, i18n.Errorf("config %s: sort: %w", path, err)
+		}
+	}
+	for name, line := range cfg.Alias {
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, i18n.Errorf("config %s: invalid alias name %q", path, name)
+		}
+		if args, err := splitArgs(line); err != nil || len(args) == 0 {
+			return nil, i18n.Errorf("config %s: alias.%s: want a command line, got %q", path, name, line)
+		}
+	}
+	for field := range cfg.CSVColumns {
+		if !slices.Contains(taskio.CSVFields(), field) {
+			return nil, i18n.Errorf("config %s: csv_columns: unknown field %q (want one of %s)", path, field, strings.Join(taskio.CSVFields(), ", "))
+		}
+	}
+	for field := range cfg.Jira.Fields {
+		if !slices.Contains(jira.TaskFields, field) {
+			return nil, i18n.Errorf("config %s: jira.fields: unknown task field %q (want one of %s)", path, field, strings.Join(jira.TaskFields, ", "))
+		}
+	}
+	if cfg.Locale != "" {
+		lang, ok := i18n.Parse(cfg.Locale)
+		if !ok {
+			return nil, i18n.Errorf("config %s: locale: unsupported language %q (want one of %s)", path, cfg.Locale, strings.Join(i18n.Languages(), ", "))
+		}
+		cfg.Locale = lang
+	}
+	if cfg.TrashDays != nil && *cfg.TrashDays < 0 {
+		return nil, i18n.Errorf("config %s: trash_days must not be negative", path)
+	}
+	if cfg.BackupKeep != nil && *cfg.BackupKeep < 0 {
+		return nil, i18n.Errorf("config %s: backup_keep must not be negative", path)
+	}
+	if err := cfg.loadTheme(); err != nil {
+		return nil, i18n.Errorf("config %s: %w", path, err)
+	}
+	for i, h := range cfg.Webhooks {
+		if h.URL == "" {
+			return nil, i18n.Errorf("config %s: webhooks[%d]: missing url", path, i)
+		}
+		for _, ev := range h.Events {
+			if !slices.Contains(webhook.Events, ev) {
+				return nil, i18n.Errorf("config %s: webhooks[%d]: unknown event %q (want %s)", path, i, ev, strings.Join(webhook.Events, ", "))
+			}
+		}
+	}
+	if layout, ok := namedDateFormats[strings.ToLower(cfg.DateFormat)]; ok {
+		cfg.DateFormat = layout
+	}
+	for _, d := range []struct {
+		key string
+		dir *string
+	}{{"data_dir", &cfg.DataDir}, {"backup_dir", &cfg.BackupDir}} {
+		if strings.HasPrefix(*d.dir, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, i18n.Errorf("config %s: %s: %w", path, d.key, err)
+			}
+			*d.dir = filepath.Join(home, (*d.dir)[2:])
+		}
+	}
+	return &cfg, nil
+}
+
+// printer returns the Printer for the user's language: locale in the config
+// file, or else the one $LC_ALL, $LC_MESSAGES or $LANG name.
+func (a *app) printer(env *cli.Env) *i18n.Printer {
+	if a.p != nil {
+		return a.p
+	}
+	lang := i18n.Detect(func(name string) string { return env.EnvString(name, "") })
+	if cfg, err := a.config(env); err == nil && cfg.Locale != "" {
+		lang = cfg.Locale
+	}
+	a.p = i18n.New(lang)
+	return a.p
+}
+
+// style controls how tasks are rendered for humans.
+type style struct {
+	dateLayout string // layout for the date part of due dates
+	absolute   bool   // due dates as dates rather than relative to now
+	color      bool
+	theme      theme
+	p          *i18n.Printer // the user's language
+}
+
+func (a *app) style(env *cli.Env) style {
+	st := style{dateLayout: isoDate, p: a.printer(env)}
+	cfg, err := a.config(env)
+	if err != nil {
+		return st
+	}
+	if cfg.DateFormat != "" {
+		st.dateLayout = cfg.DateFormat
+	}
+	st.absolute = cfg.AbsoluteDates
+	st.color = colorEnabled(env, cfg, env.Stdout)
+	st.theme = cfg.theme
+	return st
+}
@@ -0,0 +1,73 @@
+package taskcli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+// errNotConfirmed is returned when the user does not answer yes.
+var errNotConfirmed = errors.New("not confirmed, nothing was changed (-yes skips the question)")
+
+// yesFlags registers -yes and its short form -y on fs.
+func yesFlags(fs *flag.FlagSet, yes *bool) {
+	fs.BoolVar(yes, "yes", false, "do not ask for confirmation, e.g. in scripts")
+	fs.BoolVar(yes, "y", false, "short for -yes")
+}
+
+// confirm lists the tasks a destructive command is about to change on
+// stderr and asks question, a format taking their count. Anything but yes,
+// including the end of input, declines, so scripts must pass -yes.
+func (a *app) confirm(env *cli.Env, question string, list []tasks.Task) error {
+	p := a.printer(env)
+	for _, t := range list {
+		fmt.Fprintf(env.Stderr, "  %d  %s\n", t.ID, t.Description)
+	}
+	return a.ask(env, p.Sprintf(question, p.Plural(len(list), "%d task", "%d tasks")))
+}
+
+// ask puts question, already translated, to the user and returns
+// errNotConfirmed unless they answer yes.
+func (a *app) ask(env *cli.Env, question string) error {
+	p := a.printer(env)
+	fmt.Fprint(env.Stderr, question+" "+p.T("[y/N]")+" ")
+	line, err := bufio.NewReader(env.Stdin).ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		fmt.Fprintln(env.Stderr)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	if slices.Contains([]string{"y", "yes", p.T("y"), p.T("yes")}, answer) {
+		return nil
+	}
+	return errNotConfirmed
+}
+
+// viewTasks returns the tasks named by ranges that match keep, for showing
+// them before a change.
+func (a *app) viewTasks(env *cli.Env, ranges []idRange, keep tasks.Filter) ([]tasks.Task, error) {
+	var list []tasks.Task
+	err := a.view(env, func(tx tasks.Tx) error {
+		ids, err := resolveIDs(tx, ranges)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			t, err := tx.Get(id)
+			if err != nil {
+				return err
+			}
+			if keep == nil || keep(t) {
+				list = append(list, t)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
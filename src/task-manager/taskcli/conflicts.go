@@ -0,0 +1,122 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) conflictsCmd() *cli.Command {
+	var keep, take bool
+	return &cli.Command{
+		Name:    "conflicts",
+		Summary: "Review and resolve fields changed on two machines",
+		Usage:   "[<id> [-keep|-take] [field]...]",
+		Help: `When sync finds a field changed on two machines since they last synced,
+it keeps the later change and records the other as a conflict. Without
+arguments this lists every task with conflicts; with an ID, that task's.
+
+-keep resolves them by keeping the current values; -take replaces them
+with the discarded ones. Both act on all of the task's conflicts, or only
+on the named fields. Resolutions are journalled and sync to the other
+machines like any change.`,
+		Complete: a.completeTasks(true, false),
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&keep, "keep", false, "resolve by keeping the current values")
+			fs.BoolVar(&take, "take", false, "resolve by taking the discarded values")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if keep && take {
+				return usagef("-keep and -take are mutually exclusive")
+			}
+			if len(args) == 0 {
+				if keep || take {
+					return usagef("-keep and -take need a task ID")
+				}
+				return a.printConflicts(env, 0)
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			fields := args[1:]
+			if !keep && !take {
+				if len(fields) > 0 {
+					return usagef("fields need -keep or -take")
+				}
+				return a.printConflicts(env, id)
+			}
+
+			var (
+				t       tasks.Task
+				settled []tasks.Conflict
+			)
+			if err := a.update(env, "conflicts", func(tx tasks.Tx) (err error) {
+				t, settled, err = tasks.Resolve(tx, id, fields, take)
+				return err
+			}); err != nil {
+				return err
+			}
+			p := a.printer(env)
+			how := p.T("kept the current values")
+			if take {
+				how = p.T("took the discarded values")
+			}
+			p.Fprintf(env.Stdout, "Resolved %s on task %d (%s): %s\n",
+				p.Plural(len(settled), "%d conflict", "%d conflicts"), t.ID, how, t.Description)
+			return nil
+		},
+	}
+}
+
+// printConflicts lists the conflicts of task id, or of every task if id is
+// zero.
+func (a *app) printConflicts(env *cli.Env, id int) error {
+	var list []tasks.Task
+	if err := a.view(env, func(tx tasks.Tx) error {
+		if id != 0 {
+			t, err := tx.Get(id)
+			list = []tasks.Task{t}
+			return err
+		}
+		all, err := tx.List()
+		list = tasks.Select(all, func(t tasks.Task) bool { return len(t.Conflicts) > 0 })
+		return err
+	}); err != nil {
+		return err
+	}
+	if id == 0 && len(list) == 0 {
+		a.printer(env).Fprintln(env.Stdout, "No conflicts.")
+		return nil
+	}
+	for i, t := range list {
+		if i > 0 {
+			fmt.Fprintln(env.Stdout)
+		}
+		writeConflicts(env.Stdout, a.printer(env), t)
+	}
+	return nil
+}
+
+func writeConflicts(w io.Writer, p *i18n.Printer, t tasks.Task) {
+	p.Fprintf(w, "Task %d: %s\n", t.ID, t.Description)
+	if len(t.Conflicts) == 0 {
+		p.Fprintln(w, "  no conflicts")
+	}
+	for _, c := range t.Conflicts {
+		p.Fprintf(w, "  %s: kept %s, discarded %s (set %s)\n",
+			c.Field, quoteValue(tasks.FieldText(t, c.Field)), quoteValue(c.Value), c.Time.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+func quoteValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return quote(s)
+}
@@ -0,0 +1,234 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// savedContexts is the contexts file: named filter expressions and the one
+// in force, if any.
+type savedContexts struct {
+	Active  string            `json:"active,omitempty"`
+	Filters map[string]string `json:"filters,omitempty"`
+}
+
+// contextVerbs are the words 'task context' reserves, so no context can be
+// named after them.
+var contextVerbs = []string{"define", "delete", "none", "list"}
+
+func (a *app) contextCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "context",
+		Summary: "Save filters and apply one to every list until cleared",
+		Usage:   "[<name>|none|define <name> <filter>|delete <name>]",
+		Help: `A context is a saved filter expression that, once switched on, applies to
+every 'task list' and 'task next' until switched off:
+
+  task context define work "project=acme or tag=work"
+  task context work
+  task context none
+
+Without arguments this shows the defined contexts and the active one.
+Filters test project, tag, context (a task's @context), assignee, status,
+priority and search with = and !=, combined with and, or, not and
+parentheses. Contexts are kept in the data directory and shared by all
+lists.`,
+		Complete: func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+			saved, err := a.loadContexts(env)
+			if err != nil || flag != "" {
+				return nil
+			}
+			switch {
+			case len(args) == 0:
+				out := []string{"define", "delete", "none"}
+				for name := range saved.Filters {
+					out = append(out, name+"\t"+saved.Filters[name])
+				}
+				return out
+			case len(args) == 1 && args[0] == "delete":
+				return sortedNames(saved.Filters)
+			}
+			return nil
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			saved, err := a.loadContexts(env)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 || args[0] == "list" {
+				if len(args) > 1 {
+					return usagef("context list takes no arguments")
+				}
+				return printContexts(env, a.printer(env), saved)
+			}
+			switch verb := args[0]; verb {
+			case "define":
+				if len(args) < 3 {
+					return usagef("context define takes a name and a filter")
+				}
+				name, expr := args[1], strings.Join(args[2:], " ")
+				if slices.Contains(contextVerbs, name) || strings.ContainsAny(name, " \t") {
+					return usagef("invalid context name %q", name)
+				}
+				if _, err := a.parseFilter(expr); err != nil {
+					return err
+				}
+				if saved.Filters == nil {
+					saved.Filters = map[string]string{}
+				}
+				_, existed := saved.Filters[name]
+				saved.Filters[name] = expr
+				if err := a.saveContexts(env, saved); err != nil {
+					return err
+				}
+				if existed {
+					a.printer(env).Fprintf(env.Stdout, "Redefined context %s: %s\n", name, expr)
+				} else {
+					a.printer(env).Fprintf(env.Stdout, "Defined context %s: %s\n", name, expr)
+				}
+			case "delete":
+				if len(args) != 2 {
+					return usagef("context delete takes one name")
+				}
+				name := args[1]
+				if _, ok := saved.Filters[name]; !ok {
+					return i18n.Errorf("no context named %s", name)
+				}
+				delete(saved.Filters, name)
+				if saved.Active == name {
+					saved.Active = ""
+				}
+				if err := a.saveContexts(env, saved); err != nil {
+					return err
+				}
+				a.printer(env).Fprintf(env.Stdout, "Deleted context %s\n", name)
+			case "none":
+				if len(args) > 1 {
+					return usagef("context none takes no arguments")
+				}
+				saved.Active = ""
+				if err := a.saveContexts(env, saved); err != nil {
+					return err
+				}
+				a.printer(env).Fprintln(env.Stdout, "Context cleared")
+			default:
+				if len(args) > 1 {
+					return usagef("context takes one name (define a context with 'task context define')")
+				}
+				expr, ok := saved.Filters[verb]
+				if !ok {
+					return i18n.Errorf("no context named %s (see 'task context')", verb)
+				}
+				saved.Active = verb
+				if err := a.saveContexts(env, saved); err != nil {
+					return err
+				}
+				a.printer(env).Fprintf(env.Stdout, "Context %s is active: %s\n", verb, expr)
+			}
+			return nil
+		},
+	}
+}
+
+func printContexts(env *cli.Env, p *i18n.Printer, saved savedContexts) error {
+	if len(saved.Filters) == 0 {
+		p.Fprintln(env.Stdout, "No contexts. Define one with 'task context define <name> <filter>'.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+	p.Fprintln(tw, "Name\tFilter")
+	for _, name := range sortedNames(saved.Filters) {
+		marker := ""
+		if name == saved.Active {
+			marker = p.T(" (active)")
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\n", name, marker, saved.Filters[name])
+	}
+	return tw.Flush()
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (a *app) contextsPath(env *cli.Env) (string, error) {
+	dir, err := a.dir(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contexts.json"), nil
+}
+
+// loadContexts reads the contexts file; a missing one defines nothing.
+func (a *app) loadContexts(env *cli.Env) (savedContexts, error) {
+	var saved savedContexts
+	path, err := a.contextsPath(env)
+	if err != nil {
+		return saved, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return saved, err
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return saved, fmt.Errorf("%s: %w", path, err)
+	}
+	return saved, nil
+}
+
+func (a *app) saveContexts(env *cli.Env, saved savedContexts) error {
+	path, err := a.contextsPath(env)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeState(path, append(data, '\n'))
+}
+
+// activeContext returns the name and filter of the context in force, or
+// "" and a nil filter when there is none.
+func (a *app) activeContext(env *cli.Env) (string, tasks.Filter, error) {
+	saved, err := a.loadContexts(env)
+	if err != nil || saved.Active == "" {
+		return "", nil, err
+	}
+	expr, ok := saved.Filters[saved.Active]
+	if !ok {
+		return "", nil, nil
+	}
+	f, err := a.parseFilter(expr)
+	if err != nil {
+		return "", nil, i18n.Errorf("context %s: %w", saved.Active, err)
+	}
+	return saved.Active, f, nil
+}
+
+// parseFilter compiles a filter expression, reading dates the way the
+// command line does, relative to now.
+func (a *app) parseFilter(expr string) (tasks.Filter, error) {
+	now := a.now()
+	return tasks.ParseFilter(expr, func(s string) (time.Time, error) { return parseDate(s, now) })
+}
@@ -0,0 +1,231 @@
+package taskcli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/term"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// defaultKeyCache is how long a derived key is remembered without
+// key_cache in the config file.
+const defaultKeyCache = 15 * time.Minute
+
+// encrypted reports whether the task files should be encrypted: $TASK_ENCRYPT
+// (true/false), then encrypt in the config file.
+func (a *app) encrypted(env *cli.Env) (bool, error) {
+	if v := env.EnvString("TASK_ENCRYPT", ""); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, usagef("TASK_ENCRYPT: invalid boolean %q", v)
+		}
+		return on, nil
+	}
+	cfg, err := a.config(env)
+	if err != nil {
+		return false, err
+	}
+	return cfg.Encrypt, nil
+}
+
+// fileKey returns the key function for encrypted task files: a key cached
+// by an earlier command, else one derived from $TASK_PASSPHRASE or a
+// passphrase typed at the terminal.
+func (a *app) fileKey(env *cli.Env) tasks.KeyFunc {
+	return func(params tasks.KeyParams, create bool) ([]byte, error) {
+		cache, err := a.keyCache(env)
+		if err != nil {
+			return nil, err
+		}
+		if !create {
+			if key, ok := cache.get(params, a.now()); ok && params.Verify(key) {
+				a.usedKey = &fileKeyUse{params, key}
+				return key, nil
+			}
+		}
+		pass, err := passphrase(env, a.printer(env), create)
+		if err != nil {
+			return nil, err
+		}
+		key, err := params.Key(pass)
+		if err != nil {
+			return nil, err
+		}
+		if !create && !params.Verify(key) {
+			return nil, tasks.ErrWrongKey
+		}
+		if err := cache.put(params, key, a.now()); err != nil {
+			env.Log.Warn("caching key", "err", err)
+		}
+		if !create {
+			a.usedKey = &fileKeyUse{params, key}
+		}
+		return key, nil
+	}
+}
+
+// fileKeyUse is the key of the last encrypted task file opened, so a backup
+// of it can be encrypted alike.
+type fileKeyUse struct {
+	params tasks.KeyParams
+	key    []byte
+}
+
+// passphrase reads the passphrase from $TASK_PASSPHRASE or, without echo,
+// from the terminal, asking twice when a new one is being chosen.
+func passphrase(env *cli.Env, msgs *i18n.Printer, confirm bool) ([]byte, error) {
+	if p := env.EnvString("TASK_PASSPHRASE", ""); p != "" {
+		return []byte(p), nil
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, errors.New("task file is encrypted: set TASK_PASSPHRASE or run from a terminal")
+	}
+	defer tty.Close()
+	read := func(prompt string) ([]byte, error) {
+		fmt.Fprint(tty, msgs.T(prompt))
+		defer fmt.Fprintln(tty)
+		return term.ReadPassword(int(tty.Fd()))
+	}
+	if !confirm {
+		return read("Passphrase: ")
+	}
+	first, err := read("New passphrase for the task file: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(first) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	again, err := read("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if string(first) != string(again) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return first, nil
+}
+
+// keyCache remembers derived keys for a while, so a passphrase is typed once
+// per session rather than once per command. Keys live in files readable
+// only by the user in $XDG_RUNTIME_DIR, a directory private to the user
+// (normally a tmpfs cleared at logout); without one nothing is cached, as
+// a shared directory such as /tmp could be prepared by another user to
+// capture them.
+type keyCache struct {
+	dir string        // "" disables caching
+	ttl time.Duration // 0 disables caching
+}
+
+type cachedKey struct {
+	Key     []byte    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+func (a *app) keyCache(env *cli.Env) (keyCache, error) {
+	cfg, err := a.config(env)
+	if err != nil {
+		return keyCache{}, err
+	}
+	ttl := defaultKeyCache
+	if cfg.KeyCache != "" {
+		ttl = cfg.keyCache
+	}
+	return keyCache{dir: keyCacheDir(env), ttl: ttl}, nil
+}
+
+// keyCacheDir returns where keys are cached, or "" if there is no private
+// runtime directory for them.
+func keyCacheDir(env *cli.Env) string {
+	if d := env.EnvString("XDG_RUNTIME_DIR", ""); d != "" {
+		return filepath.Join(d, "task", "keys")
+	}
+	return ""
+}
+
+// path names a cache entry after the file's salt, which identifies its key
+// without revealing anything about it.
+func (c keyCache) path(params tasks.KeyParams) string {
+	sum := sha256.Sum256(params.Salt)
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16]))
+}
+
+func (c keyCache) get(params tasks.KeyParams, now time.Time) ([]byte, bool) {
+	if c.ttl <= 0 || c.dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(params))
+	if err != nil {
+		return nil, false
+	}
+	var entry cachedKey
+	if json.Unmarshal(data, &entry) != nil || now.After(entry.Expires) {
+		os.Remove(c.path(params))
+		return nil, false
+	}
+	return entry.Key, true
+}
+
+func (c keyCache) put(params tasks.KeyParams, key []byte, now time.Time) error {
+	if c.ttl <= 0 || c.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	if info, err := os.Lstat(c.dir); err != nil {
+		return err
+	} else if !info.IsDir() || info.Mode().Perm() != 0o700 {
+		return i18n.Errorf("key cache %s is not a private directory", c.dir)
+	}
+	data, err := json.Marshal(cachedKey{Key: key, Expires: now.Add(c.ttl)})
+	if err != nil {
+		return err
+	}
+	// A new file renamed into place, so a planted symlink is replaced
+	// rather than followed.
+	f, err := os.CreateTemp(c.dir, ".key-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if err = errors.Join(err, f.Close()); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path(params))
+}
+
+func (a *app) lockCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "lock",
+		Summary: "Forget cached keys of encrypted task files",
+		Help: `Encrypted task files ask for their passphrase once and remember the key
+for key_cache (default 15m). lock forgets every remembered key at once.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("lock takes no arguments")
+			}
+			if dir := keyCacheDir(env); dir != "" {
+				if err := os.RemoveAll(dir); err != nil {
+					return err
+				}
+			}
+			a.printer(env).Fprintln(env.Stdout, "Forgot cached keys")
+			return nil
+		},
+	}
+}
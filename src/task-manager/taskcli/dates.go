@@ -0,0 +1,153 @@
+package taskcli
+
+import (
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// isoDate is the date layout used for input and, by default, for output.
+const isoDate = "2006-01-02"
+
+// Accepted absolute date layouts, tried in order.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	isoDate,
+}
+
+// parseDate parses a date given on the command line in the local zone,
+// either in one of dateLayouts or in the words parseNatural understands.
+// A date without a time of day means the end of that day, so a task due
+// "2025-03-01" is not overdue until that day is over.
+func parseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, s, now.Location())
+		if err != nil {
+			continue
+		}
+		if layout == isoDate {
+			t = endOfDay(t)
+		}
+		return t, nil
+	}
+	if t, ok := parseNatural(s, now); ok {
+		return t, nil
+	}
+	return time.Time{}, i18n.Errorf("invalid date %q (want YYYY-MM-DD, YYYY-MM-DD HH:MM or e.g. \"tomorrow\", \"next friday 5pm\", \"in 3 days\")", s)
+}
+
+func endOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
+
+// formatDate is the inverse of parseDate: end-of-day times print as a bare
+// date. dateLayout formats the date part (isoDate round-trips).
+func formatDate(t time.Time, dateLayout string) string {
+	t = t.Local()
+	if t.Equal(endOfDay(t)) {
+		return t.Format(dateLayout)
+	}
+	return t.Format(dateLayout + " 15:04")
+}
+
+// relativeDate renders a due date relative to now for the task table:
+// "today 17:00", "tomorrow", "in 3 days", "2 weeks overdue". Past dates of
+// tasks that are not overdue, such as completed ones, read "yesterday" or
+// "2 weeks ago". Dates more than a day away lose their time of day.
+func relativeDate(t, now time.Time, overdue bool, p *i18n.Printer) string {
+	t, now = t.Local(), now.Local()
+	clock := ""
+	if !t.Equal(endOfDay(t)) {
+		clock = t.Format("15:04")
+	}
+	switch days := dayDiff(now, t); {
+	case days == 0 && clock == "":
+		return p.T("today")
+	case days == 0 && overdue:
+		return p.Sprintf("today %s", clock) + " " + p.T("OVERDUE")
+	case days == 0:
+		return p.Sprintf("today %s", clock)
+	case days == 1 && clock == "":
+		return p.T("tomorrow")
+	case days == 1:
+		return p.Sprintf("tomorrow %s", clock)
+	case days > 1:
+		return p.Sprintf("in %s", span(days, p))
+	case overdue:
+		return p.Sprintf("%s overdue", span(-days, p))
+	case days == -1:
+		return p.T("yesterday")
+	default:
+		return p.Sprintf("%s ago", span(-days, p))
+	}
+}
+
+// dayDiff is the number of calendar days from from to to.
+func dayDiff(from, to time.Time) int {
+	midnight := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(midnight(to).Sub(midnight(from)).Hours() / 24)
+}
+
+// span renders a number of days in the largest unit that keeps it above
+// one: days up to two weeks, then weeks, months and years.
+func span(days int, p *i18n.Printer) string {
+	switch {
+	case days < 14:
+		return p.Plural(days, "%d day", "%d days")
+	case days < 60:
+		return p.Plural(days/7, "%d week", "%d weeks")
+	case days < 365:
+		return p.Plural(days/30, "%d month", "%d months")
+	default:
+		return p.Plural(days/365, "%d year", "%d years")
+	}
+}
+
+// optionalDate parses the value of a flag that sets or clears a date;
+// "none" or an empty value clears it.
+func optionalDate(s string, now time.Time) (*time.Time, error) {
+	if s == "" || strings.EqualFold(s, "none") {
+		return nil, nil
+	}
+	t, err := parseDate(s, now)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseEstimate parses an effort such as "2h" or "1h30m"; "none" or an empty
+// value means no estimate.
+func parseEstimate(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "none") {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, i18n.Errorf("invalid estimate %q (want e.g. 45m, 2h or 1h30m)", s)
+	}
+	return d, nil
+}
+
+// formatDuration renders a tracked or estimated time to the minute, as
+// "2h30m", "45m" or "3h".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	out := strings.TrimSuffix(d.String(), "0s")
+	if hours, ok := strings.CutSuffix(out, "h0m"); ok {
+		return hours + "h"
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package taskcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+	"gopatterns/task-manager/webhook"
+)
+
+func (a *app) digestCmd() *cli.Command {
+	var cron, post, asJSON bool
+	return &cli.Command{
+		Name:    "digest",
+		Summary: "Summarise overdue, due today and completed yesterday",
+		Usage:   "[-cron] [-post] [-json]",
+		Help: `Prints a daily summary of the list: open tasks that are overdue, open
+tasks due today, and tasks completed yesterday. Snoozed tasks are left out.
+
+-cron prints plain text without color, and nothing at all when every
+section is empty, so cron only mails a digest worth reading:
+
+  0 7 * * *  task digest -cron
+
+-post also sends the digest to every [[webhooks]] entry in the config file
+that lists the "digest" event, with the plain text as its message.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&cron, "cron", false, "plain output, and none when there is nothing to report")
+			fs.BoolVar(&post, "post", false, "send the digest to webhooks subscribed to the digest event")
+			fs.BoolVar(&asJSON, "json", false, "print the digest as JSON")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("digest takes no arguments")
+			}
+			var sender *webhook.Sender
+			if post {
+				cfg, err := a.config(env)
+				if err != nil {
+					return err
+				}
+				list, err := a.list(env)
+				if err != nil {
+					return err
+				}
+				sender = cfg.sender(list)
+				if !wantsDigest(sender.Hooks) {
+					return usagef("-post: no [[webhooks]] entry in the config file lists the %q event", webhook.Digest)
+				}
+			}
+
+			var list []tasks.Task
+			if err := a.view(env, func(tx tasks.Tx) (err error) {
+				list, err = tx.List()
+				return err
+			}); err != nil {
+				return err
+			}
+			now := a.now()
+			d := tasks.NewDigest(list, now)
+
+			st := a.style(env)
+			if sender != nil {
+				plain := st
+				plain.color = false
+				var text bytes.Buffer
+				printDigest(&text, d, now, plain)
+				if err := sender.SendDigest(ctx, d, text.String(), now); err != nil {
+					return err
+				}
+			}
+			switch {
+			case asJSON:
+				data, err := json.MarshalIndent(d, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintf(env.Stdout, "%s\n", data)
+				return err
+			case cron && d.Empty():
+				return nil
+			case cron:
+				st.color = false
+			}
+			printDigest(env.Stdout, d, now, st)
+			return nil
+		},
+	}
+}
+
+func wantsDigest(hooks []webhook.Hook) bool {
+	for _, h := range hooks {
+		if h.Wants(webhook.Digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// printDigest renders d as a heading and one block per section.
+func printDigest(w io.Writer, d tasks.Digest, now time.Time, st style) {
+	heading := func(s string) string {
+		if st.color && st.theme.Header != "" {
+			return "\x1b[" + st.theme.Header + "m" + s + "\x1b[0m"
+		}
+		return s
+	}
+	fmt.Fprintln(w, heading(st.p.Sprintf("Digest for %s, %s", st.p.T(d.Date.Weekday().String()), d.Date.Format(st.dateLayout))))
+	for _, sec := range []struct {
+		title string
+		list  []tasks.Task
+	}{
+		{"Overdue", d.Overdue},
+		{"Due today", d.DueToday},
+		{"Completed yesterday", d.CompletedYesterday},
+	} {
+		fmt.Fprintf(w, "\n%s\n", heading(fmt.Sprintf("%s (%d)", st.p.T(sec.title), len(sec.list))))
+		if len(sec.list) == 0 {
+			st.p.Fprintln(w, "  nothing")
+		}
+		for _, t := range sec.list {
+			var extra []string
+			if t.Due != nil && !t.Closed() {
+				extra = append(extra, st.p.Sprintf("due %s", formatDate(*t.Due, st.dateLayout)))
+			}
+			if t.Priority >= tasks.PriorityHigh {
+				extra = append(extra, st.p.T(t.Priority.String()))
+			}
+			if t.Project != "" {
+				extra = append(extra, t.Project)
+			}
+			line := fmt.Sprintf("  %d  %s", t.ID, t.Description)
+			if len(extra) > 0 {
+				line += "  (" + strings.Join(extra, ", ") + ")"
+			}
+			if sgr := st.rowColor(t, now); st.color && sgr != "" {
+				line = "\x1b[" + sgr + "m" + line + "\x1b[0m"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
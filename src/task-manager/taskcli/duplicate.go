@@ -0,0 +1,65 @@
+package taskcli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+// duplicateSimilarity is the tasks.Similarity from which task add takes a
+// new description for a repeat of an open task.
+const duplicateSimilarity = 0.75
+
+// errDuplicate is returned when the user cancels adding a duplicate.
+var errDuplicate = errors.New("not added, nothing was changed (-allow-duplicate adds it anyway)")
+
+// openTasks returns the tasks that are not done or cancelled.
+func (a *app) openTasks(env *cli.Env) ([]tasks.Task, error) {
+	var list []tasks.Task
+	err := a.view(env, func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	})
+	return tasks.Select(list, func(t tasks.Task) bool { return !t.Closed() }), err
+}
+
+// askDuplicate warns that a new task looks like the open task dup and asks
+// what to do: note it on dup (true), add it anyway (false) or give up
+// with errDuplicate, which is also the answer at the end of input.
+func (a *app) askDuplicate(env *cli.Env, dup tasks.Task) (bool, error) {
+	p := a.printer(env)
+	p.Fprintf(env.Stderr, "Task %d looks the same: %s\n", dup.ID, dup.Description)
+	p.Fprintf(env.Stderr, "Add a [n]ote to task %d instead, [a]dd anyway or [c]ancel? [n/a/C] ", dup.ID)
+	line, err := bufio.NewReader(env.Stdin).ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(env.Stderr)
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "n", "note", p.T("note"):
+		return true, nil
+	case "a", "add", p.T("add"):
+		return false, nil
+	}
+	return false, errDuplicate
+}
+
+// noteDuplicate records desc as a note on task id instead of adding it.
+func (a *app) noteDuplicate(env *cli.Env, id int, desc string) error {
+	var t tasks.Task
+	err := a.update(env, "note", func(tx tasks.Tx) (err error) {
+		if t, err = tx.Get(id); err != nil {
+			return err
+		}
+		t.Annotate(a.now(), desc)
+		return tx.Put(t)
+	})
+	if err != nil {
+		return err
+	}
+	a.printer(env).Fprintf(env.Stdout, "Added note %d to task %d: %s\n", len(t.Notes), t.ID, t.Description)
+	return nil
+}
@@ -0,0 +1,229 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) editCmd() *cli.Command {
+	var (
+		desc, due, priority cli.OptionalString
+		parent, estimate    cli.OptionalString
+		status              cli.OptionalString
+		project, gtdContext cli.OptionalString
+		assignee            cli.OptionalString
+		addTags, rmTags     cli.StringList
+	)
+	return &cli.Command{
+		Name:     "edit",
+		Aliases:  []string{"modify"},
+		Summary:  "Change a task's fields, or open it in $EDITOR",
+		Complete: a.completeTasks(true, false),
+		DashArgs: true,
+		Usage:    "<id> [modifications...] [-description text] [-due date|none] [-priority level] [-estimate duration|none] [-status name] [-tag name] [-untag name] [-parent id|none] [-project name] [-context name] [-assignee user|none]",
+		Help: `With field flags or modifications the task is updated directly. Without
+any, the task is written to a temporary file and opened in $VISUAL or
+$EDITOR (default vi); the saved file is applied when the editor exits.
+
+Modifications are Taskwarrior's, and may be mixed with the flags:
+
+  due:+2d        move the due date 2 days later (from today if it has
+                 none); also -3d, and min, h, w, mo (months) and y
+  due:friday     any date -due takes; due: alone clears it
+  priority:H     or pri:, any level -priority takes
+  estimate:2h    or est:, any effort -estimate takes
+  status:waiting any status -status takes
+  project:home   or proj:; also description: (desc:), context:,
+                 assignee: and parent:
+  +urgent        attach a tag
+  -waiting       detach a tag (not one named like a flag, e.g. -project)
+
+Other words together become the new description:
+
+  task modify 12 due:+2d priority:H +urgent -waiting`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.Var(&desc, "description", "new description")
+			fs.Var(&due, "due", "new due date, or \"none\" to clear it")
+			fs.Var(&priority, "priority", "new priority: low, medium, high, 0-9 or none")
+			fs.Var(&estimate, "estimate", "expected effort, e.g. 45m or 2h, or \"none\" to clear it")
+			fs.Var(&status, "status", "new status: pending, in-progress, waiting, done or cancelled")
+			fs.Var(&addTags, "tag", "attach a tag (repeatable)")
+			fs.Var(&rmTags, "untag", "detach a tag (repeatable)")
+			fs.Var(&project, "project", "move the task to this project (empty to clear)")
+			fs.Var(&gtdContext, "context", "set the task's context (empty to clear)")
+			fs.Var(&assignee, "assignee", "assign the task to this user, or \"none\" to unassign it")
+			fs.Var(&parent, "parent", "make the task a subtask of this task ID or UUID, or \"none\" to detach it")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) == 0 {
+				return usagef("edit takes a task ID")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			shift, err := modifications(args[1:], map[string]*cli.OptionalString{
+				"description": &desc, "due": &due, "priority": &priority, "estimate": &estimate,
+				"status":  &status,
+				"project": &project, "context": &gtdContext,
+				"assignee": &assignee, "parent": &parent,
+			}, &addTags, &rmTags)
+			if err != nil {
+				return err
+			}
+
+			now := a.now()
+			var dueAt *time.Time
+			if due.IsSet {
+				if dueAt, err = optionalDate(due.Value, now); err != nil {
+					return usagef("due: %v", err)
+				}
+			}
+
+			var prio tasks.Priority
+			if priority.IsSet {
+				if prio, err = tasks.ParsePriority(priority.Value); err != nil {
+					return usagef("priority: %v", err)
+				}
+			}
+
+			var effort time.Duration
+			if estimate.IsSet {
+				if effort, err = parseEstimate(estimate.Value); err != nil {
+					return usagef("estimate: %v", err)
+				}
+			}
+
+			var newStatus tasks.Status
+			if status.IsSet {
+				if newStatus, err = tasks.ParseStatus(status.Value); err != nil {
+					return usagef("status: %v", err)
+				}
+			}
+
+			var parentID int
+			if parent.IsSet && parent.Value != "none" {
+				if parentID, err = a.taskID(env, parent.Value); err != nil {
+					return err
+				}
+			}
+
+			interactive := !desc.IsSet && !due.IsSet && shift == nil && !priority.IsSet && !estimate.IsSet && !status.IsSet && !parent.IsSet &&
+				!project.IsSet && !gtdContext.IsSet && !assignee.IsSet &&
+				len(addTags) == 0 && len(rmTags) == 0
+			var form string
+			if interactive {
+				// Read the task and run the editor outside any transaction so
+				// the store is not locked while the user types.
+				var t tasks.Task
+				if err := a.view(env, func(tx tasks.Tx) error {
+					t, err = tx.Get(id)
+					return err
+				}); err != nil {
+					return err
+				}
+				form, err = runEditor(ctx, env, renderForm(a.printer(env), t))
+				if err != nil {
+					return err
+				}
+			}
+
+			var edited tasks.Task
+			err = a.update(env, "edit", func(tx tasks.Tx) error {
+				t, err := tx.Get(id)
+				if err != nil {
+					return err
+				}
+				if interactive {
+					if err := applyForm(&t, form, now); err != nil {
+						return err
+					}
+				}
+				if desc.IsSet {
+					if strings.TrimSpace(desc.Value) == "" {
+						return usagef("description cannot be empty")
+					}
+					t.Description = strings.TrimSpace(desc.Value)
+				}
+				if due.IsSet {
+					t.Due = dueAt
+				}
+				if shift != nil {
+					shifted := shift.apply(t.Due, now)
+					t.Due = &shifted
+				}
+				if priority.IsSet {
+					t.Priority = prio
+				}
+				if estimate.IsSet {
+					t.Estimate = effort
+				}
+				if status.IsSet && t.Status != newStatus {
+					if err := t.SetStatus(newStatus, now); err != nil {
+						return err
+					}
+				}
+				if project.IsSet {
+					t.Project = strings.TrimSpace(project.Value)
+				}
+				if gtdContext.IsSet {
+					t.Context = tasks.NormalizeContext(gtdContext.Value)
+				}
+				if assignee.IsSet {
+					t.Assignee = user(assignee.Value)
+				}
+				if parent.IsSet {
+					if err := tasks.CheckParent(tx, id, parentID); err != nil {
+						return err
+					}
+					t.Parent = parentID
+				}
+				t.AddTags(addTags...)
+				t.RemoveTags(tasks.NormalizeTags(rmTags)...)
+				edited = t
+				return tx.Put(t)
+			})
+			if err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Updated task %d: %s\n", edited.ID, edited.Description)
+			return nil
+		},
+	}
+}
+
+// runEditor writes text to a temporary file, opens it in the user's editor
+// and returns the saved contents.
+func runEditor(ctx context.Context, env *cli.Env, text string) (string, error) {
+	editor := env.EnvString("VISUAL", env.EnvString("EDITOR", "vi"))
+
+	f, err := os.CreateTemp("", "task-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// Go through the shell so EDITOR values with arguments ("code -w") work.
+	cmd := exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", f.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = env.Stdin, env.Stdout, env.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", i18n.Errorf("editor %q: %w", editor, err)
+	}
+	out, err := os.ReadFile(f.Name())
+	return string(out), err
+}
@@ -0,0 +1,119 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// Exit statuses, so scripts can tell failures apart without parsing
+// messages. 0 is success and 2 a malformed command line, as for every
+// command built on package cli.
+const (
+	exitFailure  = 1 // anything not listed below
+	exitUsage    = 2 // bad flags or arguments
+	exitNotFound = 3 // no task has the given ID, UUID or description
+	exitInvalid  = 4 // the input was understood but rejected, e.g. an empty description or a status change the task cannot make
+	exitCorrupt  = 5 // the database or task file cannot be decoded
+)
+
+// errorKind classifies err for scripts: its name in -json-errors output
+// and the exit status.
+func errorKind(err error) (string, int) {
+	var usage *cli.UsageError
+	switch {
+	case errors.As(err, &usage):
+		return "usage", exitUsage
+	case errors.Is(err, tasks.ErrNotFound):
+		return "not_found", exitNotFound
+	case errors.Is(err, tasks.ErrInvalid), errors.Is(err, tasks.ErrTransition):
+		return "invalid_input", exitInvalid
+	case errors.Is(err, tasks.ErrCorrupt):
+		return "storage_corrupt", exitCorrupt
+	default:
+		return "failure", exitFailure
+	}
+}
+
+// usagef is cli.Usagef with a message that can be translated.
+func usagef(format string, args ...any) error {
+	err := i18n.Errorf(format, args...)
+	return &cli.UsageError{Msg: err.Error(), Err: err}
+}
+
+// localized is an error with its message in the user's language.
+type localized struct {
+	msg string
+	err error
+}
+
+func (e *localized) Error() string { return e.msg }
+
+func (e *localized) Unwrap() error { return e.err }
+
+// localize returns err with its message in the user's language.
+func (a *app) localize(env *cli.Env, err error) error {
+	p := a.printer(env)
+	if p.Lang() == i18n.English {
+		return err
+	}
+	return &localized{msg: p.Error(err), err: err}
+}
+
+// jsonError is what -json-errors writes to stderr.
+type jsonError struct {
+	Error string `json:"error"`
+	Kind  string `json:"kind"`
+	Exit  int    `json:"exit_code"`
+}
+
+// reportErrors wraps the Run function of every command under cmd so that
+// its errors are printed in the user's language, end the process with the
+// status errorKind gives them, and with -json-errors (or
+// $TASK_JSON_ERRORS) are printed as a JSON object.
+func (a *app) reportErrors(cmd *cli.Command) {
+	for _, sub := range cmd.Subcommands {
+		a.reportErrors(sub)
+	}
+	if cmd.Run == nil {
+		return
+	}
+	run := cmd.Run
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		err := run(ctx, env, args)
+		if err == nil {
+			return nil
+		}
+		kind, code := errorKind(err)
+		err = a.localize(env, err)
+		if !a.jsonErrors(env) {
+			if code == exitFailure || code == exitUsage {
+				// Main prints these as it always has, usage included.
+				return err
+			}
+			return &cli.ExitError{Code: code, Err: err}
+		}
+		data, jerr := json.Marshal(jsonError{Error: err.Error(), Kind: kind, Exit: code})
+		if jerr != nil {
+			return &cli.ExitError{Code: code, Err: err}
+		}
+		fmt.Fprintf(env.Stderr, "%s\n", data)
+		return &cli.ExitError{Code: code}
+	}
+}
+
+// jsonErrors reports whether errors should be printed as JSON: the
+// -json-errors flag, or else $TASK_JSON_ERRORS.
+func (a *app) jsonErrors(env *cli.Env) bool {
+	if a.jsonErrs {
+		return true
+	}
+	on, _ := strconv.ParseBool(env.EnvString("TASK_JSON_ERRORS", ""))
+	return on
+}
@@ -0,0 +1,64 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/taskio"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) exportCmd() *cli.Command {
+	var format, output string
+	return &cli.Command{
+		Name:     "export",
+		Summary:  "Write every task in another format",
+		Complete: completeFormats(taskio.ExportFormats()),
+		Usage:    "[-format name] [-o file]",
+		Help:     "Formats: " + strings.Join(taskio.ExportFormats(), ", ") + ".",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&format, "format", "csv", "output format: "+strings.Join(taskio.ExportFormats(), ", "))
+			fs.StringVar(&output, "o", "", "write to this file instead of stdout")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("export takes no arguments")
+			}
+			if !slices.Contains(taskio.ExportFormats(), format) {
+				return usagef("unknown format %q (want %s)", format, strings.Join(taskio.ExportFormats(), ", "))
+			}
+			var list []tasks.Task
+			if err := a.view(env, func(tx tasks.Tx) error {
+				var err error
+				list, err = tx.List()
+				return err
+			}); err != nil {
+				return err
+			}
+
+			var w io.Writer = env.Stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer func() { err = errors.Join(err, f.Close()) }()
+				w = f
+			}
+			if err := taskio.Export(w, format, list, a.now()); err != nil {
+				return i18n.Errorf("export %s: %w", format, err)
+			}
+			if output != "" {
+				env.Log.Info("exported tasks", "count", len(list), "format", format, "to", output)
+			}
+			return nil
+		},
+	}
+}
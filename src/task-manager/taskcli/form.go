@@ -0,0 +1,121 @@
+package taskcli
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// The edit form is the plain-text rendering of a task opened in $EDITOR:
+// one "field: value" line per editable field, '#' lines are comments.
+
+func renderForm(p *i18n.Printer, t tasks.Task) string {
+	var b strings.Builder
+	p.Fprintf(&b, "# Editing task %d. Lines starting with '#' are ignored.\n", t.ID)
+	p.Fprintf(&b, "# Save and quit to apply; clear the description to abort.\n")
+	fmt.Fprintf(&b, "description: %s\n", t.Description)
+	fmt.Fprintf(&b, "status: %s\n", t.Status)
+	due := "none"
+	if t.Due != nil {
+		due = formatDate(*t.Due, isoDate)
+	}
+	fmt.Fprintf(&b, "due: %s\n", due)
+	fmt.Fprintf(&b, "priority: %s\n", orNone(t.Priority.String()))
+	estimate := "none"
+	if t.Estimate > 0 {
+		estimate = formatDuration(t.Estimate)
+	}
+	fmt.Fprintf(&b, "estimate: %s\n", estimate)
+	fmt.Fprintf(&b, "tags: %s\n", strings.Join(t.Tags, ", "))
+	fmt.Fprintf(&b, "project: %s\n", t.Project)
+	fmt.Fprintf(&b, "context: %s\n", t.Context)
+	fmt.Fprintf(&b, "assignee: %s\n", t.Assignee)
+	return b.String()
+}
+
+// applyForm updates t from an edited form. Fields missing from the form are
+// left unchanged.
+func applyForm(t *tasks.Task, form string, now time.Time) error {
+	sc := bufio.NewScanner(strings.NewReader(form))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return i18n.Errorf("line %d: expected \"field: value\"", line)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "description":
+			if value == "" {
+				return i18n.Errorf("empty description, edit aborted")
+			}
+			t.Description = value
+		case "status":
+			s, err := tasks.ParseStatus(value)
+			if err != nil {
+				return i18n.Errorf("line %d: %v", line, err)
+			}
+			if s != t.Status {
+				if err := t.SetStatus(s, now); err != nil {
+					return i18n.Errorf("line %d: %v", line, err)
+				}
+			}
+		case "completed":
+			// The form had this line before it had a status.
+			done, err := strconv.ParseBool(value)
+			if err != nil {
+				return i18n.Errorf("line %d: completed must be true or false", line)
+			}
+			switch {
+			case done && !t.Done():
+				t.Complete(now)
+			case !done && t.Closed():
+				t.Reopen()
+			}
+		case "due":
+			d, err := optionalDate(value, now)
+			if err != nil {
+				return i18n.Errorf("line %d: %v", line, err)
+			}
+			t.Due = d
+		case "priority":
+			p, err := tasks.ParsePriority(value)
+			if err != nil {
+				return i18n.Errorf("line %d: %v", line, err)
+			}
+			t.Priority = p
+		case "estimate":
+			d, err := parseEstimate(value)
+			if err != nil {
+				return i18n.Errorf("line %d: %v", line, err)
+			}
+			t.Estimate = d
+		case "tags":
+			t.Tags = tasks.NormalizeTags(strings.Split(value, ","))
+		case "project":
+			t.Project = value
+		case "context":
+			t.Context = tasks.NormalizeContext(value)
+		case "assignee":
+			t.Assignee = user(value)
+		default:
+			return i18n.Errorf("line %d: unknown field %q", line, key)
+		}
+	}
+	return sc.Err()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package taskcli
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// byDescription reports whether args name a task by description rather than
+// by ID: none of them starts with a digit.
+func byDescription(args []string) bool {
+	for _, arg := range args {
+		if r := []rune(arg); len(r) > 0 && unicode.IsDigit(r[0]) {
+			return false
+		}
+	}
+	return len(args) > 0
+}
+
+// pickTask resolves a description query to a single task, asking on the
+// terminal which one is meant when several match equally well. Only open
+// tasks are considered when open is set. It runs outside any transaction so
+// the store is not locked while the user chooses.
+func (a *app) pickTask(env *cli.Env, verb, query string, open bool) (int, error) {
+	var list []tasks.Task
+	if err := a.view(env, func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	if open {
+		list = tasks.Select(list, func(t tasks.Task) bool { return !t.Closed() })
+	}
+
+	matches := tasks.Fuzzy(list, query)
+	switch len(matches) {
+	case 0:
+		return 0, i18n.Errorf("%w: nothing matches %q", tasks.ErrNotFound, query)
+	case 1:
+		return matches[0].ID, nil
+	}
+
+	p := a.printer(env)
+	p.Fprintf(env.Stderr, "Several tasks match %q:\n", query)
+	for i, t := range matches {
+		p.Fprintf(env.Stderr, "  %d) task %d: %s\n", i+1, t.ID, t.Description)
+	}
+	p.Fprintf(env.Stderr, "%s which? [1-%d]: ", p.T(verb), len(matches))
+	line, err := bufio.NewReader(env.Stdin).ReadString('\n')
+	if strings.TrimSpace(line) == "" && err != nil {
+		fmt.Fprintln(env.Stderr)
+		return 0, i18n.Errorf("%q is ambiguous; give a task ID", query)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(matches) {
+		return 0, usagef("invalid choice %q", strings.TrimSpace(line))
+	}
+	return matches[n-1].ID, nil
+}
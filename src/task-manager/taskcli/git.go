@@ -0,0 +1,72 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) gitCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "git",
+		Summary: "Run git in the repository of the git backend",
+		Usage:   "<git arguments...>",
+		Help: `With the git backend every list is a JSON file in <data dir>/git, and
+every change is committed there. 'task git' runs git in that directory, for
+its history and backups:
+
+  task git log -p             what changed, and when
+  task git remote add origin git@example.com:me/tasks.git
+  task git push -u origin HEAD`,
+		Raw: true,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) == 0 {
+				return usagef("git needs arguments, e.g. task git log")
+			}
+			backend, err := a.backendName(env)
+			if err != nil {
+				return err
+			}
+			if backend != "git" {
+				return usagef("task git needs the git backend (-backend git or backend = \"git\")")
+			}
+			dir, err := a.dir(env)
+			if err != nil {
+				return err
+			}
+			repo := tasks.GitDir(dir)
+			if _, err := os.Stat(filepath.Join(repo, ".git")); errors.Is(err, os.ErrNotExist) {
+				return i18n.Errorf("no task history in %s yet", repo)
+			}
+			cmd := exec.CommandContext(ctx, "git", args...)
+			cmd.Dir = repo
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = env.Stdin, env.Stdout, env.Stderr
+			if err := cmd.Run(); err != nil {
+				return i18n.Errorf("git: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// gitStore reports a change that git failed to commit as a warning: the
+// change itself was saved, and the next commit records it.
+type gitStore struct {
+	*tasks.GitStore
+	env *cli.Env
+}
+
+func (s gitStore) Update(fn func(tasks.Tx) error) error {
+	err := s.GitStore.Update(fn)
+	if errors.Is(err, tasks.ErrNotCommitted) {
+		s.env.Log.Warn("committing task change", "err", err)
+		return nil
+	}
+	return err
+}
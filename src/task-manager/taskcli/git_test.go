@@ -0,0 +1,51 @@
+package taskcli
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGitBackend(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	// git itself reads the process environment.
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	env := taskEnv(dir, "TASK_BACKEND", "git")
+
+	task(t, env, "add", "Buy milk")
+	task(t, env, "-list", "groceries", "add", "Bread")
+	task(t, env, "complete", "1")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"git", "log", "--format=%s"}, "complete 1: Buy milk\nadd 1: Bread\nadd 1: Buy milk\n"},
+		{[]string{"git", "ls-files"}, "tasks-groceries.json\ntasks.json\n"},
+		{[]string{"git", "status", "--porcelain"}, ""},
+	}
+	for _, tt := range tests {
+		if got := task(t, env, tt.args...); got != tt.want {
+			t.Errorf("task %s = %q, want %q", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+	if got := task(t, env, "lists"); !strings.Contains(got, "groceries") {
+		t.Errorf("task lists = %q, want the groceries list", got)
+	}
+}
+
+func TestGitCommandNeedsGitBackend(t *testing.T) {
+	env := taskEnv(t.TempDir(), "TASK_BACKEND", "json")
+	if out, err := tryTask(env, "git", "log"); err == nil || !strings.Contains(err.Error(), "needs the git backend") {
+		t.Fatalf("task git log = %v\n%s; want a usage error", err, out)
+	}
+	env = taskEnv(t.TempDir(), "TASK_BACKEND", "git")
+	if _, err := tryTask(env, "git", "log"); err == nil || !strings.Contains(err.Error(), "no task history") {
+		t.Fatalf("task git log before any change = %v", err)
+	}
+}
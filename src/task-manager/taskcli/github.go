@@ -0,0 +1,120 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/github"
+	"gopatterns/task-manager/tasks"
+)
+
+// githubState is what `task github` remembers about a list between runs.
+type githubState struct {
+	API      string       `json:"api,omitempty"`
+	Issues   github.State `json:"issues"`
+	LastSync time.Time    `json:"last_sync"`
+}
+
+func (a *app) githubCmd() *cli.Command {
+	var api string
+	return &cli.Command{
+		Name:    "github",
+		Summary: "Mirror tasks tagged gh:owner/repo to GitHub issues",
+		Usage:   "[-api url]",
+		Help: `Every task tagged gh:owner/repo gets an issue in that repository, titled
+with the task's description. On later runs the title and the open/closed
+state follow whichever side changed since the last sync; when both did,
+the later change wins. Closing the issue on GitHub completes the task, and
+completing the task closes the issue.
+
+Issues are never deleted: removing the tag or deleting the task only stops
+the mirroring, and tagging the task again picks its issue up where it left
+off. Issues opened on GitHub are not imported.
+
+The token comes from $TASK_GITHUB_TOKEN or $GITHUB_TOKEN and needs write
+access to the repositories' issues. -api points at a GitHub Enterprise
+server (https://host/api/v3) and is remembered per list.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&api, "api", "", "REST API root (remembered; default "+github.DefaultAPI+")")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("github takes no arguments")
+			}
+			token := env.EnvString("TASK_GITHUB_TOKEN", env.EnvString("GITHUB_TOKEN", ""))
+			if token == "" {
+				return errors.New("github: set TASK_GITHUB_TOKEN or GITHUB_TOKEN to a token with access to the issues")
+			}
+			statePath, err := a.syncStatePath(env)
+			if err != nil {
+				return err
+			}
+			statePath = strings.TrimSuffix(statePath, ".json") + ".github.json"
+			state, err := loadGithubState(statePath)
+			if err != nil {
+				return err
+			}
+			if api != "" && api != state.API {
+				// Issues on another server share nothing with these.
+				state = githubState{API: api}
+			}
+
+			s, err := a.open(env)
+			if err != nil {
+				return err
+			}
+			defer func() { err = errors.Join(err, s.Close()) }()
+
+			client := &github.Client{API: state.API, Token: token}
+			op := tasks.Operation{Name: "github", Time: a.now(), User: a.user(env)}
+			var stats github.Stats
+			if err := s.Update(func(tx tasks.Tx) error {
+				state.Issues, stats, err = client.Sync(ctx, tx, state.Issues, op)
+				return err
+			}); err != nil {
+				return err
+			}
+			state.LastSync = a.now()
+			if err := saveGithubState(statePath, state); err != nil {
+				return err
+			}
+			p := a.printer(env)
+			p.Fprintf(env.Stdout, "Synced %s with GitHub: %d opened, %d sent, %d received, %d conflicts\n",
+				p.Plural(stats.Mirrored, "%d task", "%d tasks"), stats.Created, stats.Sent, stats.Received, stats.Conflicts)
+			if stats.Gone > 0 {
+				fmt.Fprint(env.Stdout, p.Plural(stats.Gone, "%d issue no longer exists; its tasks are no longer mirrored\n", "%d issues no longer exist; their tasks are no longer mirrored\n"))
+			}
+			return nil
+		},
+	}
+}
+
+func loadGithubState(path string) (githubState, error) {
+	var st githubState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("%s: %w", path, err)
+	}
+	return st, nil
+}
+
+func saveGithubState(path string, st githubState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeState(path, data)
+}
@@ -0,0 +1,167 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) historyCmd() *cli.Command {
+	var asJSON bool
+	return &cli.Command{
+		Name:     "history",
+		Summary:  "Show every change made to a task",
+		Complete: a.completeTasks(true, false),
+		Usage:    "<id> [-json]",
+		Help: `Lists, oldest first, every change to the task: when, by whom, through
+which command, and each field's old and new value. The history is kept for
+the life of the list, even after the task is deleted.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the events as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) != 1 {
+				return usagef("history takes exactly one task ID")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			events, err := a.history(env)
+			if err != nil {
+				return err
+			}
+			events = tasks.TaskHistory(events, id)
+			if asJSON {
+				return printJSON(env.Stdout, events)
+			}
+			if len(events) == 0 {
+				a.printer(env).Fprintf(env.Stdout, "No history for task %d.\n", id)
+				return nil
+			}
+			return printHistory(env.Stdout, events, a.style(env))
+		},
+	}
+}
+
+func (a *app) logCmd() *cli.Command {
+	var (
+		limit  int
+		who    string
+		asJSON bool
+	)
+	return &cli.Command{
+		Name:    "log",
+		Summary: "Show recent changes to every task, newest first",
+		Usage:   "[-n count] [-user name] [-json]",
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&limit, "n", 20, "show at most this many changes (0 for all)")
+			fs.StringVar(&who, "user", "", "only changes made by this user")
+			fs.BoolVar(&asJSON, "json", false, "print the events as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("log takes no arguments")
+			}
+			events, err := a.history(env)
+			if err != nil {
+				return err
+			}
+			if who != "" {
+				events = slices.DeleteFunc(events, func(ev tasks.Event) bool { return ev.User != who })
+			}
+			slices.Reverse(events)
+			if limit > 0 && len(events) > limit {
+				events = events[:limit]
+			}
+			if asJSON {
+				return printJSON(env.Stdout, events)
+			}
+			if len(events) == 0 {
+				a.printer(env).Fprintln(env.Stdout, "No changes.")
+				return nil
+			}
+			st := a.style(env)
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			st.p.Fprintln(tw, "Time\tUser\tCommand\tTask\tChange")
+			for _, ev := range events {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", ev.Time.Local().Format(st.dateLayout+" 15:04"),
+					orDash(ev.User), ev.Op, ev.TaskID, summary(st.p, ev))
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func (a *app) history(env *cli.Env) ([]tasks.Event, error) {
+	var events []tasks.Event
+	err := a.view(env, func(tx tasks.Tx) (err error) {
+		events, err = tx.History()
+		return err
+	})
+	return events, err
+}
+
+// printHistory renders one line per field change, under the time, user and
+// command that made it.
+func printHistory(w io.Writer, events []tasks.Event, st style) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	st.p.Fprintln(tw, "Time\tUser\tCommand\tChange")
+	for _, ev := range events {
+		lines := []string{st.p.T(ev.Action)}
+		if ev.Action != tasks.Deleted {
+			lines = lines[:0]
+			if ev.Action == tasks.Created {
+				lines = append(lines, st.p.T(tasks.Created))
+			}
+			for _, c := range ev.Changes {
+				lines = append(lines, change(c))
+			}
+		}
+		for i, line := range lines {
+			if i == 0 {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ev.Time.Local().Format(st.dateLayout+" 15:04"), orDash(ev.User), ev.Op, line)
+			} else {
+				fmt.Fprintf(tw, "\t\t\t%s\n", line)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// change renders a field change as "field: old → new".
+func change(c tasks.FieldChange) string {
+	if c.Field == "note" {
+		return "note: " + c.New
+	}
+	return fmt.Sprintf("%s: %s → %s", c.Field, orDash(c.Old), orDash(c.New))
+}
+
+// summary describes an event in one line for the activity feed.
+func summary(p *i18n.Printer, ev tasks.Event) string {
+	switch ev.Action {
+	case tasks.Created, tasks.Deleted:
+		return p.T(ev.Action) + " " + quote(ev.Description)
+	}
+	fields := make([]string, len(ev.Changes))
+	for i, c := range ev.Changes {
+		fields[i] = c.Field
+	}
+	return quote(ev.Description) + ": " + strings.Join(fields, ", ")
+}
+
+func quote(s string) string {
+	const max = 40
+	if r := []rune(s); len(r) > max {
+		s = string(r[:max-1]) + "…"
+	}
+	return `"` + s + `"`
+}
@@ -0,0 +1,154 @@
+package taskcli
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+// idRange is an inclusive range of task IDs from the command line; a single
+// ID has lo == hi.
+type idRange struct {
+	lo, hi int
+}
+
+func (r idRange) single() bool { return r.lo == r.hi }
+
+// parseIDs parses task ID arguments for bulk commands: plain IDs ("3") and
+// inclusive ranges ("10-20"). UUIDs must have gone through uuidRefs.
+func parseIDs(args []string) ([]idRange, error) {
+	ranges := make([]idRange, 0, len(args))
+	for _, arg := range args {
+		lo, hi, isRange := strings.Cut(arg, "-")
+		if !isRange {
+			id, err := parseID(arg)
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, idRange{id, id})
+			continue
+		}
+		from, err1 := strconv.Atoi(lo)
+		to, err2 := strconv.Atoi(hi)
+		if err1 != nil || err2 != nil || from < 1 || to < from {
+			return nil, usagef("invalid task ID range %q", arg)
+		}
+		ranges = append(ranges, idRange{from, to})
+	}
+	return ranges, nil
+}
+
+// resolveIDs expands ranges to the IDs of existing tasks, in argument order
+// and without duplicates. Ranges match whatever tasks exist in them; a
+// single ID is kept even if it does not exist, so the operation reports it.
+func resolveIDs(tx tasks.Tx, ranges []idRange) ([]int, error) {
+	list, err := tx.List()
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]int, len(list))
+	for i, t := range list {
+		existing[i] = t.ID
+	}
+	slices.Sort(existing)
+
+	var ids []int
+	seen := map[int]bool{}
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, r := range ranges {
+		if r.single() {
+			add(r.lo)
+			continue
+		}
+		for _, id := range existing {
+			if id >= r.lo && id <= r.hi {
+				add(id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// childrenFirst orders ids so that subtasks come before their parents,
+// letting one command complete a parent together with its subtasks.
+func childrenFirst(list []tasks.Task, ids []int) []int {
+	parents := make(map[int]int, len(list))
+	for _, t := range list {
+		parents[t.ID] = t.Parent
+	}
+	depth := func(id int) int {
+		d := 0
+		for p := parents[id]; p != 0 && d <= len(list); p = parents[p] {
+			d++
+		}
+		return d
+	}
+	ordered := slices.Clone(ids)
+	slices.SortStableFunc(ordered, func(a, b int) int { return depth(b) - depth(a) })
+	return ordered
+}
+
+// uuidRefs replaces the arguments that name a task by UUID, or a unique
+// prefix of one, with the task's short ID and leaves the others alone. It
+// only opens the store when some argument looks like a UUID.
+func (a *app) uuidRefs(env *cli.Env, args []string) ([]string, error) {
+	if !slices.ContainsFunc(args, tasks.IsUUIDRef) {
+		return args, nil
+	}
+	var list []tasks.Task
+	if err := a.view(env, func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return refsIn(list, args)
+}
+
+// refsIn is uuidRefs over list. A hex word that matches no UUID is kept
+// unless it has a digit in it, so a description such as "decade" still
+// reaches the fuzzy search.
+func refsIn(list []tasks.Task, args []string) ([]string, error) {
+	out := slices.Clone(args)
+	for i, arg := range args {
+		if !tasks.IsUUIDRef(arg) {
+			continue
+		}
+		t, err := tasks.FindUUID(list, arg)
+		switch {
+		case err == nil:
+			out[i] = strconv.Itoa(t.ID)
+		case errors.Is(err, tasks.ErrNotFound) && !strings.ContainsAny(arg, "0123456789"):
+		default:
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// taskID parses a single task argument, a short ID or a UUID (prefix).
+func (a *app) taskID(env *cli.Env, arg string) (int, error) {
+	args, err := a.uuidRefs(env, []string{arg})
+	if err != nil {
+		return 0, err
+	}
+	return parseID(args[0])
+}
+
+// taskRanges is parseIDs for arguments that may also be UUIDs.
+func (a *app) taskRanges(env *cli.Env, args []string) ([]idRange, error) {
+	args, err := a.uuidRefs(env, args)
+	if err != nil {
+		return nil, err
+	}
+	return parseIDs(args)
+}
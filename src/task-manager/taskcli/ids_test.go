@@ -0,0 +1,140 @@
+package taskcli
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func TestParseIDs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []idRange
+	}{
+		{nil, []idRange{}},
+		{[]string{"3"}, []idRange{{3, 3}}},
+		{[]string{"3", "10-20", "7-7"}, []idRange{{3, 3}, {10, 20}, {7, 7}}},
+	}
+	for _, tt := range tests {
+		got, err := parseIDs(tt.args)
+		if err != nil {
+			t.Errorf("parseIDs(%q): %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseIDs(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+
+	for _, arg := range []string{"0", "-3", "x", "3-", "-", "5-2", "0-4", "1-x", "1-2-3", "1.5"} {
+		_, err := parseIDs([]string{"1", arg})
+		var usage *cli.UsageError
+		if !errors.As(err, &usage) {
+			t.Errorf("parseIDs(%q) err = %v, want a usage error", arg, err)
+		}
+	}
+}
+
+func TestResolveIDs(t *testing.T) {
+	s := tasks.NewMemStore()
+	err := s.Update(func(tx tasks.Tx) error {
+		for _, id := range []int{1, 2, 4, 5, 9} {
+			if err := tx.Put(tasks.Task{ID: id, Description: "task"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ranges []idRange
+		want   []int
+	}{
+		{[]idRange{{2, 2}}, []int{2}},
+		{[]idRange{{3, 3}}, []int{3}}, // kept, so the operation reports it
+		{[]idRange{{1, 5}}, []int{1, 2, 4, 5}},
+		{[]idRange{{6, 8}}, nil},
+		{[]idRange{{9, 9}, {1, 2}, {2, 4}}, []int{9, 1, 2, 4}},
+		{[]idRange{{4, 100}, {1, 1}}, []int{4, 5, 9, 1}},
+	}
+	for _, tt := range tests {
+		var got []int
+		err := s.View(func(tx tasks.Tx) (err error) {
+			got, err = resolveIDs(tx, tt.ranges)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("resolveIDs(%v) = %v, want %v", tt.ranges, got, tt.want)
+		}
+	}
+}
+
+func TestChildrenFirst(t *testing.T) {
+	list := []tasks.Task{
+		{ID: 1},
+		{ID: 2, Parent: 1},
+		{ID: 3, Parent: 2},
+		{ID: 4},
+		{ID: 5, Parent: 1},
+	}
+	tests := []struct {
+		ids  []int
+		want []int
+	}{
+		{[]int{1, 2, 3}, []int{3, 2, 1}},
+		{[]int{4, 1, 5, 2}, []int{5, 2, 4, 1}},
+		{[]int{1, 4}, []int{1, 4}},
+		{[]int{7, 3}, []int{3, 7}},
+	}
+	for _, tt := range tests {
+		if got := childrenFirst(list, tt.ids); !slices.Equal(got, tt.want) {
+			t.Errorf("childrenFirst(%v) = %v, want %v", tt.ids, got, tt.want)
+		}
+	}
+}
+
+func TestRefsIn(t *testing.T) {
+	list := []tasks.Task{
+		{ID: 1, UUID: "3f2a9c1e-0000-4000-8000-000000000001"},
+		{ID: 2, UUID: "3f2a9c2b-0000-4000-8000-000000000002"},
+		{ID: 3, UUID: "de0c0a00-0000-4000-8000-000000000003"},
+		{ID: 4, UUID: "66666666-2222-3333-4444-555555555555"},
+		{ID: 5, UUID: "12345678-0000-4000-8000-000000000005"},
+	}
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"1", "2-3"}, []string{"1", "2-3"}},
+		{[]string{"3f2a9c1e-0000-4000-8000-000000000001"}, []string{"1"}},
+		{[]string{"3f2a9c1", "DE0C0A", "4"}, []string{"1", "3", "4"}},
+		{[]string{"decade"}, []string{"decade"}},
+		{[]string{"66666666-2222-3333-4444-555555555555"}, []string{"4"}},
+		{[]string{"12345678", "66666666-2", "10-20"}, []string{"5", "4", "10-20"}},
+	}
+	for _, tt := range tests {
+		got, err := refsIn(list, tt.args)
+		if err != nil {
+			t.Errorf("refsIn(%q): %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("refsIn(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	for _, arg := range []string{"3f2a9c", "ffff1234", "87654321", "11111111-2222-3333-4444-555555555555"} {
+		if _, err := refsIn(list, []string{arg}); err == nil {
+			t.Errorf("refsIn(%q) succeeded, want an error", arg)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/taskio"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) importCmd() *cli.Command {
+	var (
+		format  string
+		mapping cli.StringList
+	)
+	return &cli.Command{
+		Name:     "import",
+		Summary:  "Add tasks from a file written by another tool",
+		Complete: completeFormats(taskio.ImportFormats()),
+		Usage:    "[-format name] [-map field=column]... <file|->",
+		Help: `Imported tasks get new IDs. The format is guessed from the file name when
+-format is not given (*.txt is todo.txt, *.json Taskwarrior's 'task export',
+*.csv and *.tsv CSV); "-" reads standard input. Tasks whose UUID is already
+in the list are skipped, so importing the same export twice adds nothing.
+Formats: ` + strings.Join(taskio.ImportFormats(), ", ") + `.
+
+CSV files need a header row. Columns named after a task field, or a common
+synonym such as "Title" or "Due Date", are picked up on their own; others
+are mapped with -map, or with [csv_columns] in the config file:
+
+  task import -map description=Summary -map due=Deadline tasks.csv
+
+Fields: ` + strings.Join(taskio.CSVFields(), ", ") + ".",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&format, "format", "", "input format: "+strings.Join(taskio.ImportFormats(), ", "))
+			fs.Var(&mapping, "map", "read a CSV `field=column` from that column (repeatable)")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) != 1 {
+				return usagef("import takes exactly one file")
+			}
+			name := args[0]
+			if format == "" {
+				format = taskio.DetectFormat(name)
+				if format == "" {
+					return usagef("cannot tell the format of %q; use -format", name)
+				}
+			}
+			if !slices.Contains(taskio.ImportFormats(), format) {
+				return usagef("unknown format %q (want %s)", format, strings.Join(taskio.ImportFormats(), ", "))
+			}
+
+			importer, err := a.csvImporter(env, format, mapping)
+			if err != nil {
+				return err
+			}
+
+			var r io.Reader = env.Stdin
+			if name != "-" {
+				f, err := os.Open(name)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			list, err := importer(r, a.now())
+			if err != nil {
+				return i18n.Errorf("import %s: %w", name, err)
+			}
+
+			var added, skipped int
+			err = a.update(env, "import", func(tx tasks.Tx) error {
+				existing, err := tx.List()
+				if err != nil {
+					return err
+				}
+				known := make(map[string]int, len(existing))
+				for _, t := range existing {
+					if t.UUID != "" {
+						known[t.UUID] = t.ID
+					}
+				}
+				// Assign every ID first, so parent positions can be mapped.
+				ids := make([]int, len(list))
+				fresh := make([]bool, len(list))
+				for i, t := range list {
+					if id, ok := known[t.UUID]; ok && t.UUID != "" {
+						ids[i] = id
+						continue
+					}
+					if ids[i], err = tx.NextID(); err != nil {
+						return err
+					}
+					fresh[i] = true
+				}
+				for i, t := range list {
+					if !fresh[i] {
+						skipped++
+						continue
+					}
+					t.ID = ids[i]
+					if t.Parent != 0 {
+						t.Parent = ids[t.Parent-1]
+					}
+					if err := tx.Put(t); err != nil {
+						return err
+					}
+					added++
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			p := a.printer(env)
+			p.Fprintf(env.Stdout, "Imported %s from %s\n", p.Plural(added, "%d task", "%d tasks"), name)
+			if skipped > 0 {
+				p.Fprintf(env.Stdout, "Skipped %s already in the list\n", p.Plural(skipped, "%d task", "%d tasks"))
+			}
+			return nil
+		},
+	}
+}
+
+// csvImporter returns the importer for format; for csv it reads the columns
+// set in the config file, overridden by -map.
+func (a *app) csvImporter(env *cli.Env, format string, mapping []string) (taskio.Importer, error) {
+	if format != "csv" {
+		if len(mapping) > 0 {
+			return nil, usagef("-map applies only to the csv format")
+		}
+		return func(r io.Reader, now time.Time) ([]tasks.Task, error) {
+			return taskio.Import(r, format, now)
+		}, nil
+	}
+	cfg, err := a.config(env)
+	if err != nil {
+		return nil, err
+	}
+	columns := maps.Clone(taskio.CSVMapping(cfg.CSVColumns))
+	if columns == nil {
+		columns = taskio.CSVMapping{}
+	}
+	for _, m := range mapping {
+		field, column, ok := strings.Cut(m, "=")
+		if !ok || field == "" || column == "" {
+			return nil, usagef("-map wants field=column, got %q", m)
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(taskio.CSVFields(), field) {
+			return nil, usagef("-map: unknown field %q (want one of %s)", field, strings.Join(taskio.CSVFields(), ", "))
+		}
+		columns[field] = column
+	}
+	return taskio.CSVImporter(columns), nil
+}
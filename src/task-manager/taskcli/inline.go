@@ -0,0 +1,95 @@
+package taskcli
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// inlineAttrs maps the key:value words task add reads from a description,
+// with their abbreviations, to the field they set.
+var inlineAttrs = map[string]string{
+	"due": "due",
+	"p":   "priority", "pri": "priority", "priority": "priority",
+	"project": "project", "proj": "project",
+	"est": "estimate", "estimate": "estimate",
+	"assignee": "assignee",
+}
+
+// applyInline moves the metadata written into t's description to its
+// fields: +tag adds a tag, @name sets the context and key:value sets one of
+// inlineAttrs. They win over the flags, which act as defaults. Other words,
+// including key:value words with other keys such as URLs, stay in the
+// description.
+func applyInline(t *tasks.Task, now time.Time) error {
+	var words []string
+	seen := map[string]bool{}
+	for _, w := range strings.Fields(t.Description) {
+		if rest, ok := strings.CutPrefix(w, "+"); ok && startsWord(rest) {
+			t.AddTags(rest)
+			continue
+		}
+		if rest, ok := strings.CutPrefix(w, "@"); ok && startsWord(rest) {
+			if seen["context"] {
+				return usagef("context given twice: %s", w)
+			}
+			seen["context"] = true
+			t.Context = tasks.NormalizeContext(rest)
+			continue
+		}
+		key, value, _ := strings.Cut(w, ":")
+		field, known := inlineAttrs[strings.ToLower(key)]
+		if !known || value == "" {
+			words = append(words, w)
+			continue
+		}
+		if seen[field] {
+			return usagef("%s given twice: %s", field, w)
+		}
+		seen[field] = true
+		var err error
+		switch field {
+		case "due":
+			var d time.Time
+			if d, err = inlineDate(value, now); err == nil {
+				t.Due = &d
+			}
+		case "priority":
+			t.Priority, err = tasks.ParsePriority(value)
+		case "estimate":
+			t.Estimate, err = parseEstimate(value)
+		case "project":
+			t.Project = value
+		case "assignee":
+			t.Assignee = user(value)
+		}
+		if err != nil {
+			return usagef("%s: %v", w, err)
+		}
+	}
+	t.Description = strings.Join(words, " ")
+	return nil
+}
+
+// inlineDate parses the value of due:, where a date in words is written
+// with - or _ for spaces: due:next-friday.
+func inlineDate(value string, now time.Time) (time.Time, error) {
+	d, err := parseDate(value, now)
+	if err == nil {
+		return d, nil
+	}
+	if d, err2 := parseDate(strings.NewReplacer("-", " ", "_", " ").Replace(value), now); err2 == nil {
+		return d, nil
+	}
+	return time.Time{}, err
+}
+
+// startsWord reports whether s begins with a letter, so "+1" and "@ 5pm"
+// are left in the description.
+func startsWord(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
@@ -0,0 +1,216 @@
+package taskcli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/jira"
+	"gopatterns/task-manager/tasks"
+)
+
+// Defaults for the [jira] settings.
+const (
+	defaultJQL            = "assignee = currentUser() AND resolution = Unresolved"
+	defaultDoneTransition = "Done"
+)
+
+// jiraState is what `task jira` remembers about a list between runs.
+type jiraState struct {
+	URL      string     `json:"url"`
+	Issues   jira.State `json:"issues"`
+	LastPull time.Time  `json:"last_pull,omitempty"`
+}
+
+func (a *app) jiraCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "jira",
+		Summary: "Import Jira issues as tasks and report their completion back",
+		Help: `The site and the mapping of fields come from the [jira] table of the
+config file:
+
+  [jira]
+  url = "https://acme.atlassian.net"
+  user = "alice@acme.com"         # leave out for a personal access token
+  jql = "assignee = currentUser() AND resolution = Unresolved"
+  done_transition = "Done"
+  [jira.fields]                   # task field = Jira field
+  context = "customfield_10042"
+
+The API token comes from $TASK_JIRA_TOKEN. Unmapped fields default to
+description = summary, due = duedate, priority = priority and
+tags = labels; map a field to "" to leave it alone. Fields: ` + strings.Join(jira.TaskFields, ", ") + `.`,
+		Subcommands: []*cli.Command{
+			a.jiraPullCmd(),
+			a.jiraPushCmd(),
+		},
+	}
+}
+
+func (a *app) jiraPullCmd() *cli.Command {
+	var jql string
+	return &cli.Command{
+		Name:    "pull",
+		Summary: "Add the issues a JQL search finds as tasks, or update them",
+		Usage:   "[-jql query]",
+		Help: `Every issue found becomes a task tagged jira:<key>; tasks pulled before
+take the issue's current values of the mapped fields, and gain its labels
+as tags. A task deleted here is not pulled again. The query defaults to
+the config file's jql, or issues assigned to you and unresolved; "me" as a
+value stands for currentUser(), so -jql "assignee=me" works.
+
+'task undo' reverts a pull.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&jql, "jql", "", "JQL search for the issues to pull")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("jira pull takes no arguments")
+			}
+			client, cfg, err := a.jiraClient(env)
+			if err != nil {
+				return err
+			}
+			if jql == "" {
+				jql = cmp.Or(cfg.JQL, defaultJQL)
+			}
+			statePath, state, err := a.loadJiraState(env, client.URL)
+			if err != nil {
+				return err
+			}
+
+			s, err := a.open(env)
+			if err != nil {
+				return err
+			}
+			defer func() { err = errors.Join(err, s.Close()) }()
+
+			fields := jira.Fields(cfg.Fields).WithDefaults()
+			op := tasks.Operation{Name: "jira pull", Time: a.now(), User: a.user(env)}
+			var stats jira.PullStats
+			if err := s.Update(func(tx tasks.Tx) error {
+				stats, err = client.Pull(ctx, tx, jiraQuery(jql), fields, state.Issues, op)
+				return err
+			}); err != nil {
+				return err
+			}
+			state.LastPull = a.now()
+			if err := saveJiraState(statePath, state); err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Pulled from %s: %d added, %d updated\n", client.URL, stats.Created, stats.Updated)
+			return nil
+		},
+	}
+}
+
+func (a *app) jiraPushCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "push",
+		Summary: "Move the issues of completed tasks through the done transition",
+		Help: `Every task pulled from Jira and completed since is reported back once, by
+moving its issue through the config file's done_transition ("Done" unless
+set), which may name the transition or the status it leads to.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("jira push takes no arguments")
+			}
+			client, cfg, err := a.jiraClient(env)
+			if err != nil {
+				return err
+			}
+			statePath, state, err := a.loadJiraState(env, client.URL)
+			if err != nil {
+				return err
+			}
+			var moved []string
+			err = a.view(env, func(tx tasks.Tx) (err error) {
+				moved, err = client.Push(ctx, tx, cmp.Or(cfg.DoneTransition, defaultDoneTransition), state.Issues)
+				return err
+			})
+			if len(moved) > 0 {
+				if err := saveJiraState(statePath, state); err != nil {
+					return err
+				}
+			}
+			for _, key := range moved {
+				a.printer(env).Fprintf(env.Stdout, "Moved %s to %s\n", key, cmp.Or(cfg.DoneTransition, defaultDoneTransition))
+			}
+			if err != nil {
+				return err
+			}
+			if len(moved) == 0 {
+				a.printer(env).Fprintln(env.Stdout, "No completed tasks to report")
+			}
+			return nil
+		},
+	}
+}
+
+// jiraClient sets up a client from the [jira] table and $TASK_JIRA_TOKEN.
+func (a *app) jiraClient(env *cli.Env) (*jira.Client, jiraConfig, error) {
+	cfg, err := a.config(env)
+	if err != nil {
+		return nil, jiraConfig{}, err
+	}
+	jc := cfg.Jira
+	if jc.URL == "" {
+		return nil, jc, errors.New("jira: set url in the [jira] table of the config file")
+	}
+	token := env.EnvString("TASK_JIRA_TOKEN", "")
+	if token == "" {
+		return nil, jc, errors.New("jira: set TASK_JIRA_TOKEN to an API token")
+	}
+	return &jira.Client{URL: jc.URL, User: jc.User, Token: token}, jc, nil
+}
+
+// meValue matches "me" as the value of a JQL comparison.
+var meValue = regexp.MustCompile(`(?i)(!?=|\bin\s*\(|,)\s*me\b`)
+
+// jiraQuery lets "me" stand for currentUser() in jql.
+func jiraQuery(jql string) string {
+	return meValue.ReplaceAllString(jql, "${1} currentUser()")
+}
+
+func (a *app) loadJiraState(env *cli.Env, url string) (string, jiraState, error) {
+	path, err := a.syncStatePath(env)
+	if err != nil {
+		return "", jiraState{}, err
+	}
+	path = strings.TrimSuffix(path, ".json") + ".jira.json"
+	var st jiraState
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return "", st, err
+	default:
+		if err := json.Unmarshal(data, &st); err != nil {
+			return "", st, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if st.URL != url {
+		// Issues of another site share nothing with these.
+		st = jiraState{URL: url}
+	}
+	if st.Issues == nil {
+		st.Issues = jira.State{}
+	}
+	return path, st, nil
+}
+
+func saveJiraState(path string, st jiraState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeState(path, data)
+}
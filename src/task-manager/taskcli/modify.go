@@ -0,0 +1,132 @@
+package taskcli
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+)
+
+// Taskwarrior-style modifications, accepted by edit after the task ID:
+//
+//	due:+2d       move the due date 2 days later (from today if it has none);
+//	              also -3d, and min, h, w, mo (months) and y
+//	due:friday    any date the -due flag takes; due: alone clears it
+//	priority:H    or pri:, with any level the -priority flag takes
+//	estimate:2h   or est:, with any effort the -estimate flag takes
+//	project:home  or proj:; also description: (desc:), context:, assignee:
+//	              and parent:, as their flags
+//	+urgent       attach a tag
+//	-waiting      detach it
+//
+// Any other words together become the new description, as in Taskwarrior.
+
+// modifyAttrs maps the attribute names a modification may use, with
+// Taskwarrior's abbreviations, to the edit flag they stand for.
+var modifyAttrs = map[string]string{
+	"description": "description", "desc": "description",
+	"due":      "due",
+	"priority": "priority", "pri": "priority",
+	"estimate": "estimate", "est": "estimate",
+	"status":  "status",
+	"project": "project", "proj": "project",
+	"context":  "context",
+	"assignee": "assignee",
+	"parent":   "parent",
+}
+
+var shiftRE = regexp.MustCompile(`^([+-])(\d+)(min|mo|h|d|w|m|y)$`)
+
+// dateShift is a relative date such as "+2d", applied to a task's current
+// due date.
+type dateShift struct {
+	n    int
+	unit string
+}
+
+func parseShift(s string) (dateShift, bool) {
+	m := shiftRE.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return dateShift{}, false
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return dateShift{}, false
+	}
+	if m[1] == "-" {
+		n = -n
+	}
+	return dateShift{n: n, unit: m[3]}, true
+}
+
+// apply returns due moved by the shift. Without a due date it counts from
+// now for minutes and hours and from the end of today otherwise, like "in
+// 3 hours" and "in 3 days".
+func (d dateShift) apply(due *time.Time, now time.Time) time.Time {
+	t := endOfDay(now)
+	switch {
+	case due != nil:
+		t = *due
+	case d.unit == "min" || d.unit == "h":
+		t = now
+	}
+	switch d.unit {
+	case "min":
+		return t.Add(time.Duration(d.n) * time.Minute)
+	case "h":
+		return t.Add(time.Duration(d.n) * time.Hour)
+	case "d":
+		return t.AddDate(0, 0, d.n)
+	case "w":
+		return t.AddDate(0, 0, 7*d.n)
+	case "m", "mo":
+		return t.AddDate(0, d.n, 0)
+	default:
+		return t.AddDate(d.n, 0, 0)
+	}
+}
+
+// modifications applies edit's arguments after the task ID to its flags:
+// fields maps flag names to their values, and add and rm collect tags. It
+// returns the due date shift, if one was given.
+func modifications(args []string, fields map[string]*cli.OptionalString, add, rm *cli.StringList) (*dateShift, error) {
+	var (
+		words []string
+		shift *dateShift
+	)
+	for _, arg := range args {
+		if len(arg) > 1 && (arg[0] == '+' || arg[0] == '-') {
+			if arg[0] == '+' {
+				add.Set(arg[1:])
+			} else {
+				rm.Set(arg[1:])
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(arg, ":")
+		name, known := modifyAttrs[strings.ToLower(key)]
+		if !ok || !known {
+			words = append(words, arg)
+			continue
+		}
+		if fields[name].IsSet || name == "due" && shift != nil {
+			return nil, usagef("%s given twice", name)
+		}
+		if name == "due" {
+			if s, ok := parseShift(value); ok {
+				shift = &s
+				continue
+			}
+		}
+		fields[name].Set(value)
+	}
+	if len(words) > 0 {
+		if fields["description"].IsSet {
+			return nil, usagef("description given twice (%q)", strings.Join(words, " "))
+		}
+		fields["description"].Set(strings.Join(words, " "))
+	}
+	return shift, nil
+}
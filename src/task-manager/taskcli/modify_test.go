@@ -0,0 +1,144 @@
+package taskcli
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gopatterns/internal/cli"
+)
+
+func TestModifications(t *testing.T) {
+	tests := []struct {
+		args   []string
+		fields map[string]string // the fields set, by flag name
+		add    []string
+		rm     []string
+		shift  *dateShift
+	}{
+		{
+			args:   []string{"Buy", "oat", "milk"},
+			fields: map[string]string{"description": "Buy oat milk"},
+		},
+		{
+			args:   []string{"pri:H", "proj:home", "+errand", "-waiting", "+urgent"},
+			fields: map[string]string{"priority": "H", "project": "home"},
+			add:    []string{"errand", "urgent"},
+			rm:     []string{"waiting"},
+		},
+		{
+			args:   []string{"due:friday", "est:2h", "context:@town", "assignee:ana", "parent:3", "status:waiting"},
+			fields: map[string]string{"due": "friday", "estimate": "2h", "context": "@town", "assignee": "ana", "parent": "3", "status": "waiting"},
+		},
+		{
+			args:   []string{"due:", "Project:"},
+			fields: map[string]string{"due": "", "project": ""},
+		},
+		{
+			args:  []string{"due:+2d"},
+			shift: &dateShift{n: 2, unit: "d"},
+		},
+		{
+			args:   []string{"DUE:-3W", "desc:Call mom"},
+			fields: map[string]string{"description": "Call mom"},
+			shift:  &dateShift{n: -3, unit: "w"},
+		},
+		{
+			// Unknown attributes and lone signs are description words.
+			args:   []string{"note:", "ratio", "1:2", "-", "+"},
+			fields: map[string]string{"description": "note: ratio 1:2 - +"},
+		},
+	}
+	for _, tt := range tests {
+		fields, add, rm, shift, err := modify(tt.args)
+		if err != nil {
+			t.Errorf("modifications(%q): %v", tt.args, err)
+			continue
+		}
+		if tt.fields == nil {
+			tt.fields = map[string]string{}
+		}
+		if !maps.Equal(fields, tt.fields) {
+			t.Errorf("modifications(%q) fields = %q, want %q", tt.args, fields, tt.fields)
+		}
+		if !slices.Equal(add, tt.add) || !slices.Equal(rm, tt.rm) {
+			t.Errorf("modifications(%q) tags = +%q -%q, want +%q -%q", tt.args, add, rm, tt.add, tt.rm)
+		}
+		if (shift == nil) != (tt.shift == nil) || shift != nil && *shift != *tt.shift {
+			t.Errorf("modifications(%q) shift = %v, want %v", tt.args, shift, tt.shift)
+		}
+	}
+}
+
+func TestModificationsErrors(t *testing.T) {
+	tests := [][]string{
+		{"pri:H", "priority:L"},
+		{"due:friday", "due:+1d"},
+		{"due:+1d", "due:+2d"},
+		{"desc:Call mom", "today"},
+	}
+	for _, args := range tests {
+		_, _, _, _, err := modify(args)
+		var usage *cli.UsageError
+		if !errors.As(err, &usage) {
+			t.Errorf("modifications(%q) err = %v, want a usage error", args, err)
+		}
+	}
+}
+
+// modify runs modifications with edit's flags and returns the fields set.
+func modify(args []string) (map[string]string, []string, []string, *dateShift, error) {
+	fields := map[string]*cli.OptionalString{}
+	for name := range maps.Values(modifyAttrs) {
+		fields[name] = &cli.OptionalString{}
+	}
+	var add, rm cli.StringList
+	shift, err := modifications(args, fields, &add, &rm)
+	set := map[string]string{}
+	for name, f := range fields {
+		if f.IsSet {
+			set[name] = f.Value
+		}
+	}
+	return set, add, rm, shift, err
+}
+
+func TestDateShift(t *testing.T) {
+	now := time.Date(2025, 3, 5, 10, 30, 0, 0, time.UTC)
+	due := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		due  *time.Time
+		want time.Time
+	}{
+		{"+2d", &due, time.Date(2025, 2, 2, 9, 0, 0, 0, time.UTC)},
+		{"-1w", &due, time.Date(2025, 1, 24, 9, 0, 0, 0, time.UTC)},
+		{"+1mo", &due, time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"+1m", &due, time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"+1y", &due, time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)},
+		{"+90min", &due, time.Date(2025, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"-2h", &due, time.Date(2025, 1, 31, 7, 0, 0, 0, time.UTC)},
+		{"+3d", nil, time.Date(2025, 3, 8, 23, 59, 59, 0, time.UTC)},
+		{"+3h", nil, time.Date(2025, 3, 5, 13, 30, 0, 0, time.UTC)},
+		{"+30MIN", nil, time.Date(2025, 3, 5, 11, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, ok := parseShift(tt.in)
+		if !ok {
+			t.Errorf("parseShift(%q) failed", tt.in)
+			continue
+		}
+		if got := s.apply(tt.due, now); !got.Equal(tt.want) {
+			t.Errorf("%s applied to %v = %v, want %v", tt.in, tt.due, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"2d", "+d", "+2", "+2x", "+-2d", "friday", strings.Repeat("9", 30) + "d"} {
+		if s, ok := parseShift(in); ok {
+			t.Errorf("parseShift(%q) = %v, want failure", in, s)
+		}
+	}
+}
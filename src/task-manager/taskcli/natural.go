@@ -0,0 +1,233 @@
+package taskcli
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Natural-language dates accepted wherever a date is, as a fallback after
+// the ISO layouts:
+//
+//	today, tomorrow, yesterday
+//	friday, this friday   the coming Friday (today if it is Friday)
+//	next friday           the Friday after today, a week out on Fridays
+//	next week|month|year  the same day a week, month or year from now
+//	in 3 days, 2 weeks    also hours and minutes, which give an exact time
+//	march 5, 5 mar        the next such date
+//
+// Any of the day forms may be preceded or followed by a time of day ("5pm",
+// "at 17:30", "noon"); a time alone means today. Without a time the date
+// means the end of the day, like an ISO date.
+
+var (
+	clockRE  = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	numberRE = regexp.MustCompile(`^\d+$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// parseNatural parses s as a natural-language date relative to now.
+func parseNatural(s string, now time.Time) (time.Time, bool) {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		if w != "at" && w != "on" {
+			words = append(words, strings.TrimSuffix(w, ","))
+		}
+	}
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+
+	// A time of day at either end, possibly split as "5 pm".
+	hour, min, hasClock := 0, 0, false
+	if n := len(words); n >= 2 && (words[n-1] == "am" || words[n-1] == "pm") {
+		words = append(words[:n-2], words[n-2]+words[n-1])
+	}
+	if h, m, ok := parseClock(words[len(words)-1]); ok {
+		hour, min, hasClock = h, m, true
+		words = words[:len(words)-1]
+	} else if h, m, ok := parseClock(words[0]); ok {
+		hour, min, hasClock = h, m, true
+		words = words[1:]
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var day time.Time
+	switch {
+	case len(words) == 0 && hasClock:
+		day = today
+	case len(words) == 1 && words[0] == "today":
+		day = today
+	case len(words) == 1 && words[0] == "tomorrow":
+		day = today.AddDate(0, 0, 1)
+	case len(words) == 1 && words[0] == "yesterday":
+		day = today.AddDate(0, 0, -1)
+	case len(words) == 2 && words[0] == "next" && words[1] == "week":
+		day = today.AddDate(0, 0, 7)
+	case len(words) == 2 && words[0] == "next" && words[1] == "month":
+		day = today.AddDate(0, 1, 0)
+	case len(words) == 2 && words[0] == "next" && words[1] == "year":
+		day = today.AddDate(1, 0, 0)
+	default:
+		if d, ok := weekdayDate(words, today); ok {
+			day = d
+		} else if d, ok := monthDate(words, today); ok {
+			day = d
+		} else if t, exact, ok := offsetDate(words, now); ok {
+			if exact {
+				if hasClock {
+					return time.Time{}, false
+				}
+				return t, true
+			}
+			day = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		} else {
+			return time.Time{}, false
+		}
+	}
+	if !hasClock {
+		return endOfDay(day), true
+	}
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute), true
+}
+
+// parseClock parses "17", "17:30", "5pm", "5:30pm", "noon" and "midnight".
+// A bare number is only a time when followed by am/pm, so "in 5 days" is
+// not misread.
+func parseClock(w string) (hour, min int, ok bool) {
+	switch w {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	m := clockRE.FindStringSubmatch(w)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		min, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || min > 59 {
+		return 0, 0, false
+	}
+	return hour, min, true
+}
+
+// weekdayDate handles "friday", "this friday" and "next friday".
+func weekdayDate(words []string, today time.Time) (time.Time, bool) {
+	next := false
+	switch {
+	case len(words) == 2 && words[0] == "next":
+		next = true
+		words = words[1:]
+	case len(words) == 2 && words[0] == "this":
+		words = words[1:]
+	case len(words) != 1:
+		return time.Time{}, false
+	}
+	wd, ok := weekdays[words[0]]
+	if !ok {
+		return time.Time{}, false
+	}
+	ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+	if next && ahead == 0 {
+		ahead = 7
+	}
+	return today.AddDate(0, 0, ahead), true
+}
+
+// monthDate handles "march 5" and "5 march", choosing the next such date.
+func monthDate(words []string, today time.Time) (time.Time, bool) {
+	if len(words) != 2 {
+		return time.Time{}, false
+	}
+	name, num := words[0], words[1]
+	if numberRE.MatchString(name) {
+		name, num = num, name
+	}
+	month, ok := months[name]
+	if !ok || !numberRE.MatchString(num) {
+		return time.Time{}, false
+	}
+	d, _ := strconv.Atoi(num)
+	date := time.Date(today.Year(), month, d, 0, 0, 0, 0, today.Location())
+	if date.Day() != d {
+		return time.Time{}, false // e.g. february 30
+	}
+	if date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, true
+}
+
+// offsetDate handles "in 3 days", "2 weeks", "in an hour". exact is set for
+// hours and minutes, which keep the time of day.
+func offsetDate(words []string, now time.Time) (t time.Time, exact, ok bool) {
+	if len(words) > 0 && words[0] == "in" {
+		words = words[1:]
+	}
+	if len(words) != 2 {
+		return time.Time{}, false, false
+	}
+	n := 0
+	switch {
+	case words[0] == "a" || words[0] == "an":
+		n = 1
+	case numberRE.MatchString(words[0]):
+		n, _ = strconv.Atoi(words[0])
+	default:
+		return time.Time{}, false, false
+	}
+	switch strings.TrimSuffix(words[1], "s") {
+	case "minute", "min":
+		return now.Add(time.Duration(n) * time.Minute), true, true
+	case "hour":
+		return now.Add(time.Duration(n) * time.Hour), true, true
+	case "day":
+		return now.AddDate(0, 0, n), false, true
+	case "week":
+		return now.AddDate(0, 0, 7*n), false, true
+	case "month":
+		return now.AddDate(0, n, 0), false, true
+	case "year":
+		return now.AddDate(n, 0, 0), false, true
+	}
+	return time.Time{}, false, false
+}
@@ -0,0 +1,96 @@
+package taskcli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// A Wednesday morning.
+	now := time.Date(2025, 3, 5, 10, 30, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, time.UTC)
+	}
+	end := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 0, time.UTC)
+	}
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2025-03-10", end(2025, 3, 10)},
+		{"2025-03-10 14:15", at(3, 10, 14, 15)},
+		{"2025-03-10T14:15", at(3, 10, 14, 15)},
+		{"2025-03-10T14:15:00Z", at(3, 10, 14, 15)},
+		{"today", end(2025, 3, 5)},
+		{"Tomorrow", end(2025, 3, 6)},
+		{"yesterday", end(2025, 3, 4)},
+		{"next week", end(2025, 3, 12)},
+		{"next month", end(2025, 4, 5)},
+		{"next year", end(2026, 3, 5)},
+		{"friday", end(2025, 3, 7)},
+		{"fri", end(2025, 3, 7)},
+		{"this wednesday", end(2025, 3, 5)},
+		{"next wednesday", end(2025, 3, 12)},
+		{"next friday", end(2025, 3, 7)},
+		{"monday", end(2025, 3, 10)},
+		{"in 3 days", end(2025, 3, 8)},
+		{"2 weeks", end(2025, 3, 19)},
+		{"in a month", end(2025, 4, 5)},
+		{"in 1 year", end(2026, 3, 5)},
+		{"in an hour", at(3, 5, 11, 30)},
+		{"in 90 minutes", at(3, 5, 12, 0)},
+		{"march 10", end(2025, 3, 10)},
+		{"10 mar", end(2025, 3, 10)},
+		{"5 march", end(2025, 3, 5)},
+		{"march 1", end(2026, 3, 1)},
+		{"5pm", at(3, 5, 17, 0)},
+		{"5 pm", at(3, 5, 17, 0)},
+		{"17:30", at(3, 5, 17, 30)},
+		{"noon", at(3, 5, 12, 0)},
+		{"midnight", at(3, 5, 0, 0)},
+		{"12am", at(3, 5, 0, 0)},
+		{"12pm", at(3, 5, 12, 0)},
+		{"tomorrow at 9:30am", at(3, 6, 9, 30)},
+		{"at noon friday", at(3, 7, 12, 0)},
+		{"next friday 5pm", at(3, 7, 17, 0)},
+		{"friday, 17:30", at(3, 7, 17, 30)},
+		{"on march 10 at 8am", at(3, 10, 8, 0)},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.in, now)
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateErrors(t *testing.T) {
+	now := time.Date(2025, 3, 5, 10, 30, 0, 0, time.UTC)
+	tests := []string{
+		"",
+		"someday",
+		"5",
+		"in 5",
+		"next decade",
+		"february 30",
+		"march",
+		"13pm",
+		"0am",
+		"25:00",
+		"10:75",
+		"in 3 hours at 5pm",
+		"2025-13-01",
+		"friday saturday",
+	}
+	for _, in := range tests {
+		if got, err := parseDate(in, now); err == nil {
+			t.Errorf("parseDate(%q) = %v, want an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package taskcli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) nextCmd() *cli.Command {
+	var (
+		limit            int
+		asJSON, absolute bool
+	)
+	return &cli.Command{
+		Name:    "next",
+		Summary: "List the most urgent open tasks",
+		Usage:   "[-n count] [-absolute] [-json]",
+		Help: `Ranks open, unsnoozed tasks by an urgency score in the style of
+Taskwarrior: the sum of weighted terms for due date proximity, priority,
+age, tags, notes, project, being in progress, blocking an open parent task
+and being blocked by open subtasks, plus a bonus for tags such as "next".
+Subtasks without a due date or priority of their own count their parent's.
+The weights can be changed in the [urgency] table of the config file.
+Only tasks matching the active 'task context' are ranked.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&limit, "n", 10, "show at most this many tasks (0 for all)")
+			fs.BoolVar(&absolute, "absolute", false, "show due dates as dates rather than relative to now")
+			fs.BoolVar(&asJSON, "json", false, "print the tasks, with their urgency, as a JSON array")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("next takes no arguments")
+			}
+			cfg, err := a.config(env)
+			if err != nil {
+				return err
+			}
+			var list []tasks.Task
+			if err := a.view(env, func(tx tasks.Tx) (err error) {
+				list, err = tx.List()
+				return err
+			}); err != nil {
+				return err
+			}
+			_, filter, err := a.activeContext(env)
+			if err != nil {
+				return err
+			}
+			now := a.now()
+			scores := tasks.Urgency(list, now, cfg.coefficients())
+			list = tasks.Select(list, tasks.And(filter, func(t tasks.Task) bool { return !t.Closed() && !t.IsSnoozed(now) }))
+			tasks.SortByUrgency(list, scores)
+			if limit > 0 && len(list) > limit {
+				list = list[:limit]
+			}
+
+			if asJSON {
+				type scored struct {
+					tasks.Task
+					Urgency float64 `json:"urgency"`
+				}
+				rows := make([]scored, len(list))
+				for i, t := range list {
+					rows[i] = scored{t, scores[t.ID]}
+				}
+				return printJSON(env.Stdout, rows)
+			}
+			st := a.style(env)
+			st.absolute = st.absolute || absolute
+			return printUrgent(env.Stdout, list, scores, now, st)
+		},
+	}
+}
+
+// printUrgent renders the next table: the task table with an urgency column.
+func printUrgent(w io.Writer, list []tasks.Task, scores map[int]float64, now time.Time, st style) error {
+	if len(list) == 0 {
+		st.p.Fprintln(w, "No tasks.")
+		return nil
+	}
+	var table bytes.Buffer
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	st.p.Fprintln(tw, "ID\tUrg\tPri\tDue\tProject\tDescription\tTags")
+	rows := make([]string, len(list))
+	for i, t := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, strconv.FormatFloat(scores[t.ID], 'f', 1, 64),
+			orDash(st.p.T(t.Priority.String())), dueCell(t, now, st), orDash(t.Project), t.Description, labels(t))
+		rows[i] = st.rowColor(t, now)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !st.color {
+		_, err := table.WriteTo(w)
+		return err
+	}
+	return st.paintRows(w, table.Bytes(), rows)
+}
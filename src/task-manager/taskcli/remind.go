@@ -0,0 +1,156 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/lifecycle"
+	"gopatterns/task-manager/remind"
+	"gopatterns/task-manager/tasks"
+)
+
+// defaultRemindWithin is the reminder window without -within or
+// remind_within in the config file.
+const defaultRemindWithin = 24 * time.Hour
+
+func (a *app) remindCmd() *cli.Command {
+	var (
+		within, interval time.Duration
+		daemon, asJSON   bool
+		desktop          bool
+		command, webhook string
+	)
+	return &cli.Command{
+		Name:    "remind",
+		Summary: "Show or send reminders for tasks that are due soon",
+		Usage:   "[-within duration] [-daemon [-interval duration]] [-desktop] [-exec command] [-webhook url] [-json]",
+		Help: `Lists open tasks due within the window (overdue ones included). With
+-daemon it keeps running, checks the list every -interval and announces each
+task once (again if its due date changes) until interrupted.
+
+Reminders are printed, and also sent to -exec and -webhook when given
+(defaults: remind_within, remind_command and remind_webhook in the config
+file). -desktop (or remind_desktop = true) pops up a native notification
+as well, through notify-send on Linux, osascript on macOS or a PowerShell
+toast on Windows; overdue tasks are marked critical. The command runs through sh with TASK_ID, TASK_DESCRIPTION, TASK_DUE,
+TASK_PRIORITY, TASK_PROJECT, TASK_TAGS and TASK_MESSAGE set, e.g.
+  -exec 'notify-send "$TASK_MESSAGE"'
+The webhook receives a JSON POST with "message" and "task" fields.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.DurationVar(&within, "within", 0, "remind about tasks due within this long (default from config, else 24h)")
+			fs.BoolVar(&daemon, "daemon", false, "keep running and send each reminder once")
+			fs.DurationVar(&interval, "interval", time.Minute, "daemon: how often to check the list")
+			fs.BoolVar(&desktop, "desktop", false, "also show a desktop notification per reminder (default from config)")
+			fs.StringVar(&command, "exec", "", "shell command to run per reminder")
+			fs.StringVar(&webhook, "webhook", "", "URL to POST each reminder to")
+			fs.BoolVar(&asJSON, "json", false, "print the due tasks as a JSON array instead of notifying")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("remind takes no arguments")
+			}
+			if interval <= 0 {
+				return usagef("-interval must be positive")
+			}
+			cfg, err := a.config(env)
+			if err != nil {
+				return err
+			}
+			if within == 0 {
+				within = cfg.remindWithin
+			}
+			if within == 0 {
+				within = defaultRemindWithin
+			}
+			if command == "" {
+				command = cfg.RemindCommand
+			}
+			if webhook == "" {
+				webhook = cfg.RemindWebhook
+			}
+			notify := remind.Multi{remind.Writer{W: env.Stdout}}
+			if desktop || cfg.RemindDesktop {
+				d := remind.Desktop{}
+				if err := d.Check(); err != nil && !asJSON {
+					return err
+				}
+				notify = append(notify, d)
+			}
+			if command != "" {
+				notify = append(notify, remind.Command{Command: command, Stdout: env.Stderr, Stderr: env.Stderr})
+			}
+			if webhook != "" {
+				notify = append(notify, remind.Webhook{URL: webhook})
+			}
+
+			if asJSON && daemon {
+				return usagef("-json cannot be combined with -daemon")
+			}
+			if !daemon {
+				due, err := a.due(env, within)
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					return printJSON(env.Stdout, due)
+				}
+				now := a.now()
+				for _, t := range due {
+					if err := notify.Notify(ctx, t, now); err != nil {
+						return err
+					}
+				}
+				if len(due) == 0 {
+					a.printer(env).Fprintf(env.Stdout, "Nothing due within %s\n", within)
+				}
+				return nil
+			}
+
+			lc := lifecycle.New(ctx)
+			tracker := remind.NewTracker()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			env.Log.Info("reminder daemon started", "within", within, "interval", interval)
+			for {
+				a.remindOnce(lc.Context(), env, within, tracker, notify)
+				select {
+				case <-ticker.C:
+				case <-lc.Context().Done():
+					return lc.Wait()
+				}
+			}
+		},
+	}
+}
+
+// due reads the open tasks due within the window from a freshly opened
+// store, so the daemon never holds the database between checks.
+func (a *app) due(env *cli.Env, within time.Duration) ([]tasks.Task, error) {
+	var due []tasks.Task
+	err := a.view(env, func(tx tasks.Tx) error {
+		list, err := tx.List()
+		due = remind.Due(list, a.now(), within)
+		return err
+	})
+	return due, err
+}
+
+// remindOnce announces the tasks that became due since the last check. A
+// reminder that fails to send is retried on the next check.
+func (a *app) remindOnce(ctx context.Context, env *cli.Env, within time.Duration, tracker *remind.Tracker, notify remind.Notifier) {
+	due, err := a.due(env, within)
+	if err != nil {
+		env.Log.Warn("reading tasks", "err", err)
+		return
+	}
+	now := a.now()
+	for _, t := range tracker.Fresh(due) {
+		if err := notify.Notify(ctx, t, now); err != nil {
+			env.Log.Warn("sending reminder", "task", t.ID, "err", err)
+			continue
+		}
+		tracker.Sent(t)
+	}
+}
@@ -0,0 +1,225 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"gopatterns/internal/cli"
+	"gopatterns/lifecycle"
+	"gopatterns/task-manager/auth"
+	"gopatterns/task-manager/taskgql"
+	"gopatterns/task-manager/taskpb"
+	"gopatterns/task-manager/taskrpc"
+	"gopatterns/task-manager/tasks"
+	"gopatterns/task-manager/tasksync"
+	"gopatterns/task-manager/taskws"
+)
+
+func (a *app) serveCmd() *cli.Command {
+	var (
+		grpcAddr string
+		httpAddr string
+		authCfg  auth.Config
+	)
+	return &cli.Command{
+		Name:    "serve",
+		Summary: "Serve the task store to other programs over the network",
+		Complete: func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+			if flag == "auth" {
+				return []string{"none", "static", "htpasswd", "oidc"}
+			}
+			return nil
+		},
+		Usage: "[-grpc addr] [-http addr] [-auth none|static|htpasswd|oidc] [auth flags]",
+		Help: `Serves the TaskService gRPC API (task-manager/taskpb/task.proto) until
+interrupted, and over HTTP the sync endpoint used by 'task sync', a
+read-only GraphQL endpoint at /graphql and a WebSocket endpoint at /ws
+(-http "" turns them off; GET /graphql without a query prints the schema).
+
+Clients connected to /ws receive every change made through the server as
+a JSON message shaped like a webhook payload, or with
+/ws?events=completed,deleted only those events, so they can live-update
+without polling. Web pages may only connect from the server's own origin.
+The server opens the task database only while it answers a request, so
+other task commands can use the same data directory meanwhile.
+
+Clients authenticate with an "authorization: Bearer <token>" metadata
+entry (Basic credentials for -auth htpasswd); sync clients send the same
+credentials as an HTTP Authorization header, as do GraphQL clients;
+browsers, which cannot set headers on WebSocket connections, may pass the
+token as /ws?access_token=<token> instead. With -auth static, create
+tokens with 'task token create'; read-only tokens may list and fetch tasks
+and pull with sync, but not change anything.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&grpcAddr, "grpc", "localhost:7070", "gRPC listen address")
+			fs.StringVar(&httpAddr, "http", "localhost:7080", "sync, GraphQL and WebSocket listen address (\"\" to disable)")
+			fs.StringVar(&authCfg.Type, "auth", "none", "authentication: none, static, htpasswd or oidc")
+			fs.StringVar(&authCfg.TokenFile, "tokens", "", "static: token file (default: the one 'task token' manages)")
+			fs.StringVar(&authCfg.HtpasswdFile, "htpasswd", "", "htpasswd: password file")
+			fs.StringVar(&authCfg.Issuer, "oidc-issuer", "", "oidc: issuer URL")
+			fs.StringVar(&authCfg.ClientID, "oidc-client-id", "", "oidc: expected audience")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("serve takes no arguments")
+			}
+			if authCfg.Type == "static" && authCfg.TokenFile == "" {
+				if authCfg.TokenFile, err = a.tokensPath(env); err != nil {
+					return err
+				}
+			}
+			provider, err := auth.New(authCfg)
+			if err != nil {
+				return err
+			}
+			if _, anon := provider.(auth.Anonymous); anon {
+				for _, addr := range []string{grpcAddr, httpAddr} {
+					if addr != "" && !isLoopback(addr) {
+						env.Log.Warn("serving without authentication on a non-loopback address", "addr", addr)
+					}
+				}
+			}
+
+			list, err := a.list(env)
+			if err != nil {
+				return err
+			}
+			store, err := a.serveStore(env, list)
+			if err != nil {
+				return err
+			}
+			defer func() { err = errors.Join(err, store.Close()) }()
+			hub := taskws.NewHub(list)
+			store = tasks.Notify(store, hub.Publish)
+
+			runner, err := a.hooks(env, "serve")
+			if err != nil {
+				return err
+			}
+			lis, err := net.Listen("tcp", grpcAddr)
+			if err != nil {
+				return err
+			}
+			srv := grpc.NewServer(grpc.UnaryInterceptor(taskrpc.UnaryAuth(provider)))
+			taskpb.RegisterTaskServiceServer(srv, taskrpc.NewServer(store, runner))
+
+			lc := lifecycle.New(ctx)
+			lc.Register("grpc", func(ctx context.Context) error {
+				stopped := make(chan struct{})
+				go func() {
+					srv.GracefulStop()
+					close(stopped)
+				}()
+				select {
+				case <-stopped:
+				case <-ctx.Done():
+					srv.Stop()
+				}
+				return nil
+			})
+
+			serveErr := make(chan error, 2)
+			go func() { serveErr <- srv.Serve(lis) }()
+			env.Log.Info("serving", "grpc", lis.Addr().String(), "auth", authCfg.Type)
+			a.printer(env).Fprintf(env.Stdout, "Serving tasks over gRPC on %s\n", lis.Addr())
+
+			if httpAddr != "" {
+				hlis, err := net.Listen("tcp", httpAddr)
+				if err != nil {
+					lc.Stop()
+					return errors.Join(err, lc.Wait())
+				}
+				mux := http.NewServeMux()
+				mux.Handle(tasksync.Path, auth.Middleware(provider, tasksync.Handler(store, runner, a.now)))
+				mux.Handle(taskgql.Path, auth.Middleware(provider, taskgql.Handler(store)))
+				mux.Handle(taskws.Path, auth.Middleware(provider, hub.Handler()))
+				hsrv := &http.Server{Handler: mux}
+				lc.Register("http", hsrv.Shutdown)
+				lc.Register("websocket", hub.Close)
+				go func() {
+					if err := hsrv.Serve(hlis); !errors.Is(err, http.ErrServerClosed) {
+						serveErr <- err
+					}
+				}()
+				env.Log.Info("serving", "http", hlis.Addr().String())
+				a.printer(env).Fprintf(env.Stdout, "Serving sync on http://%s%s\n", hlis.Addr(), tasksync.Path)
+				a.printer(env).Fprintf(env.Stdout, "Serving GraphQL on http://%s%s\n", hlis.Addr(), taskgql.Path)
+				a.printer(env).Fprintf(env.Stdout, "Serving changes on ws://%s%s\n", hlis.Addr(), taskws.Path)
+			}
+
+			select {
+			case err := <-serveErr:
+				lc.Stop()
+				return errors.Join(err, lc.Wait())
+			case <-lc.Context().Done():
+				return lc.Wait()
+			}
+		},
+	}
+}
+
+// isLoopback reports whether addr only accepts local connections.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveStore opens list for serve. The bolt database is locked while open,
+// so it is opened afresh for every transaction rather than held for as long
+// as the server runs; the file backends lock only during a transaction
+// anyway.
+func (a *app) serveStore(env *cli.Env, list string) (tasks.Store, error) {
+	backend, err := a.backendName(env)
+	if err != nil {
+		return nil, err
+	}
+	if backend != "bolt" {
+		return a.openList(env, list)
+	}
+	s := &transientStore{open: func() (tasks.Store, error) { return a.openList(env, list) }}
+	// Fail now rather than on the first request if it cannot be opened.
+	if err := s.View(func(tasks.Tx) error { return nil }); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// transientStore is a Store opened for each transaction and closed after.
+type transientStore struct {
+	mu   sync.Mutex // bolt locks the file per handle: one open at a time
+	open func() (tasks.Store, error)
+}
+
+func (s *transientStore) View(fn func(tasks.Tx) error) error {
+	return s.with(func(st tasks.Store) error { return st.View(fn) })
+}
+
+func (s *transientStore) Update(fn func(tasks.Tx) error) error {
+	return s.with(func(st tasks.Store) error { return st.Update(fn) })
+}
+
+func (s *transientStore) Close() error { return nil }
+
+func (s *transientStore) with(fn func(tasks.Store) error) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, st.Close()) }()
+	return fn(st)
+}
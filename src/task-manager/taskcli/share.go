@@ -0,0 +1,120 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/taskio"
+	"gopatterns/task-manager/tasks"
+)
+
+// Names of the files in a share directory.
+const (
+	shareHTML = "index.html"
+	shareJSON = "tasks.json"
+)
+
+func (a *app) shareCmd() *cli.Command {
+	var (
+		project, assignee string
+		tags              cli.StringList
+		title, output     string
+		open, notes       bool
+	)
+	return &cli.Command{
+		Name:     "share",
+		Summary:  "Publish a read-only view of some tasks as static HTML and JSON",
+		Complete: a.completeTasks(false, false),
+		Usage:    "[-project name] [-tag name]... [-assignee user|none] [-open] [-notes] [-title text] [-o dir]",
+		Help: `Writes index.html, a page summing up the selected tasks with a sortable
+table per project, and tasks.json, the same tasks as JSON, to the -o
+directory (share by default). Neither needs the task command or a server,
+so the directory can be dropped onto any static host for people who just
+want to see where things stand:
+
+  task share -project website -o /var/www/status
+
+Completed tasks are included, so the page shows progress, unless -open is
+given. Notes are left out unless -notes is given, and attachments, sync
+metadata and GTD contexts always are.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&project, "project", "", "only tasks in this project or its sub-projects")
+			fs.Var(&tags, "tag", "only tasks with this tag (repeatable, all must match)")
+			fs.StringVar(&assignee, "assignee", "", "only tasks assigned to this user (\"none\": unassigned)")
+			fs.BoolVar(&open, "open", false, "leave out done and cancelled tasks")
+			fs.BoolVar(&notes, "notes", false, "include the tasks' notes")
+			fs.StringVar(&title, "title", "", "page title (default: the project, or \"Tasks\")")
+			fs.StringVar(&output, "o", "share", "directory to write the files to")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("share takes no arguments")
+			}
+			filters := []tasks.Filter{}
+			if project = strings.TrimSpace(project); project != "" {
+				filters = append(filters, tasks.InProject(project))
+			}
+			if len(tags) > 0 {
+				filters = append(filters, tasks.WithTags(tasks.NormalizeTags(tags)...))
+			}
+			if assignee != "" {
+				filters = append(filters, tasks.AssignedTo(user(assignee)))
+			}
+			if open {
+				filters = append(filters, func(t tasks.Task) bool { return !t.Closed() })
+			}
+			if title == "" {
+				title = project
+			}
+			if title == "" {
+				title = "Tasks"
+			}
+
+			var list []tasks.Task
+			if err := a.view(env, func(tx tasks.Tx) (err error) {
+				list, err = tx.List()
+				return err
+			}); err != nil {
+				return err
+			}
+			list = tasks.Select(list, tasks.And(filters...))
+			share := taskio.NewShare(title, list, a.now(), notes)
+
+			if err := os.MkdirAll(output, 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(filepath.Join(output, shareJSON), share.WriteJSON); err != nil {
+				return err
+			}
+			err := writeFile(filepath.Join(output, shareHTML), func(w io.Writer) error {
+				return share.WriteHTML(w, shareJSON)
+			})
+			if err != nil {
+				return err
+			}
+			p := a.printer(env)
+			p.Fprintf(env.Stdout, "Shared %s in %s\n", p.Plural(len(list), "%d task", "%d tasks"), output)
+			return nil
+		},
+	}
+}
+
+// writeFile creates path and fills it with write.
+func writeFile(path string, write func(io.Writer) error) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, f.Close()) }()
+	if err := write(f); err != nil {
+		return i18n.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
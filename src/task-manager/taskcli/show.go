@@ -0,0 +1,176 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) noteCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "note",
+		Aliases:  []string{"annotate"},
+		Summary:  "Add a timestamped note to a task",
+		Complete: a.completeTasks(true, false),
+		Usage:    "<id> <text>",
+		Help:     `Notes are kept in the order they were added and shown by 'task show'.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) < 2 {
+				return usagef("note takes a task ID and the note text")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			text := strings.TrimSpace(strings.Join(args[1:], " "))
+			if text == "" {
+				return usagef("missing note text")
+			}
+			var t tasks.Task
+			err = a.update(env, "note", func(tx tasks.Tx) error {
+				if t, err = tx.Get(id); err != nil {
+					return err
+				}
+				t.Annotate(a.now(), text)
+				return tx.Put(t)
+			})
+			if err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Added note %d to task %d: %s\n", len(t.Notes), t.ID, t.Description)
+			return nil
+		},
+	}
+}
+
+func (a *app) showCmd() *cli.Command {
+	var asJSON bool
+	return &cli.Command{
+		Name:     "show",
+		Aliases:  []string{"info"},
+		Summary:  "Show every field of a task, with its notes and attachments",
+		Complete: a.completeTasks(true, false),
+		Usage:    "<id> [-json]",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the task as a JSON object")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) != 1 {
+				return usagef("show takes exactly one task ID")
+			}
+			id, err := a.taskID(env, args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				t        tasks.Task
+				parent   *tasks.Task
+				children []tasks.Task
+			)
+			err = a.view(env, func(tx tasks.Tx) error {
+				if t, err = tx.Get(id); err != nil {
+					return err
+				}
+				if t.Parent != 0 {
+					if p, err := tx.Get(t.Parent); err == nil {
+						parent = &p
+					}
+				}
+				list, err := tx.List()
+				children = tasks.Children(list, id)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(env.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(t)
+			}
+			return printTask(env.Stdout, t, parent, children, a.now(), a.style(env))
+		},
+	}
+}
+
+// printTask renders one task in full for `task show`.
+func printTask(w io.Writer, t tasks.Task, parent *tasks.Task, children []tasks.Task, now time.Time, st style) error {
+	stamp := func(at time.Time) string { return at.Local().Format(st.dateLayout + " 15:04") }
+
+	st.p.Fprintf(w, "Task %d: %s\n\n", t.ID, t.Description)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	status := st.p.T(string(t.Status))
+	switch {
+	case t.Closed() && t.CompletedAt != nil:
+		status += " " + stamp(*t.CompletedAt)
+	case t.StartedAt != nil:
+		status += " " + stamp(*t.StartedAt)
+	}
+	st.p.Fprintf(tw, "Status:\t%s\n", status)
+	st.p.Fprintf(tw, "Created:\t%s\n", stamp(t.CreatedAt))
+	due := "-"
+	if t.Due != nil {
+		due = formatDate(*t.Due, st.dateLayout) + " (" + relativeDate(*t.Due, now, t.IsOverdue(now), st.p) + ")"
+	}
+	st.p.Fprintf(tw, "Due:\t%s\n", due)
+	if t.IsSnoozed(now) {
+		st.p.Fprintf(tw, "Snoozed:\tuntil %s\n", formatWait(*t.Wait, st.dateLayout))
+	}
+	st.p.Fprintf(tw, "Priority:\t%s\n", orDash(st.p.T(t.Priority.String())))
+	if t.Estimate > 0 {
+		st.p.Fprintf(tw, "Estimate:\t%s\n", formatDuration(t.Estimate))
+	}
+	if tracked := t.Tracked(now); tracked > 0 {
+		st.p.Fprintf(tw, "Tracked:\t%s\n", formatDuration(tracked))
+	}
+	st.p.Fprintf(tw, "Project:\t%s\n", orDash(t.Project))
+	if t.Context != "" {
+		st.p.Fprintf(tw, "Context:\t@%s\n", t.Context)
+	} else {
+		st.p.Fprintf(tw, "Context:\t-\n")
+	}
+	st.p.Fprintf(tw, "Assignee:\t%s\n", orDash(t.Assignee))
+	st.p.Fprintf(tw, "Tags:\t%s\n", orDash(strings.Join(t.Tags, ", ")))
+	if parent != nil {
+		st.p.Fprintf(tw, "Parent:\t%d %s\n", parent.ID, parent.Description)
+	}
+	if len(children) > 0 {
+		ids := make([]string, len(children))
+		for i, c := range children {
+			ids[i] = strconv.Itoa(c.ID)
+		}
+		st.p.Fprintf(tw, "Subtasks:\t%s\n", strings.Join(ids, ", "))
+	}
+	if t.UUID != "" {
+		st.p.Fprintf(tw, "UUID:\t%s\n", t.UUID)
+	}
+	if n := len(t.Conflicts); n > 0 {
+		st.p.Fprintf(tw, "Conflicts:\t%d (review with 'task conflicts %d')\n", n, t.ID)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(t.Attachments) > 0 {
+		st.p.Fprintln(w, "\nAttachments:")
+		for i, att := range t.Attachments {
+			fmt.Fprintf(w, "  %d  %s\n", i+1, att.Ref)
+		}
+	}
+	if len(t.Notes) > 0 {
+		st.p.Fprintln(w, "\nNotes:")
+		for _, n := range t.Notes {
+			fmt.Fprintf(w, "  %s  %s\n", stamp(n.Time), n.Text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package taskcli
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) snoozeCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "snooze",
+		Aliases:  []string{"wait"},
+		Summary:  "Hide tasks from the list until a date",
+		Complete: a.completeManyIDs(true),
+		Usage:    "<id|from-to>... <date|none>",
+		Help: `Keeps the given tasks out of 'task list' and 'task board' until the date
+passes, so the list only shows what can be acted on now. A bare date wakes
+the tasks at the start of that day; "none" wakes them at once. 'task list
+-all' shows snoozed tasks too.`,
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) < 2 {
+				return usagef("snooze takes task IDs and a date")
+			}
+			now := a.now()
+			until, err := waitDate(args[len(args)-1], now)
+			if err != nil {
+				return usagef("%v", err)
+			}
+			ranges, err := a.taskRanges(env, args[:len(args)-1])
+			if err != nil {
+				return err
+			}
+			var snoozed []tasks.Task
+			err = a.update(env, "snooze", func(tx tasks.Tx) error {
+				ids, err := resolveIDs(tx, ranges)
+				if err != nil {
+					return err
+				}
+				for _, id := range ids {
+					t, err := tasks.Snooze(tx, id, until)
+					if err != nil {
+						return err
+					}
+					snoozed = append(snoozed, t)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			st := a.style(env)
+			for _, t := range snoozed {
+				if until == nil {
+					a.printer(env).Fprintf(env.Stdout, "Woke task %d: %s\n", t.ID, t.Description)
+				} else {
+					a.printer(env).Fprintf(env.Stdout, "Snoozed task %d until %s: %s\n", t.ID, formatWait(*until, st.dateLayout), t.Description)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// waitDate parses a snooze date. Unlike due dates, a bare date means the
+// start of that day: the task should be back when the day begins.
+func waitDate(s string, now time.Time) (*time.Time, error) {
+	if strings.EqualFold(s, "none") {
+		return nil, nil
+	}
+	t, err := parseDate(s, now)
+	if err != nil {
+		return nil, err
+	}
+	if t.Equal(endOfDay(t)) {
+		y, m, d := t.Date()
+		t = time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+	return &t, nil
+}
+
+// formatWait prints a snooze date, as a bare date at the start of a day.
+func formatWait(t time.Time, dateLayout string) string {
+	t = t.Local()
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format(dateLayout)
+	}
+	return t.Format(dateLayout + " 15:04")
+}
@@ -0,0 +1,81 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) statsCmd() *cli.Command {
+	var (
+		project string
+		asJSON  bool
+	)
+	return &cli.Command{
+		Name:     "stats",
+		Summary:  "Compare estimates with tracked time, per project",
+		Complete: a.completeTasks(false, false),
+		Usage:    "[-project name] [-json]",
+		Help: `Counts the completed tasks that have both an estimate (-estimate on add
+or edit) and tracked time (from 'task start' to 'task stop' or completion).
+Ratio is tracked over estimated time, so 1.25x means the work took a
+quarter longer than planned. Accuracy is how close each estimate came, on
+average: 100% for exact estimates, 50% for ones off by a factor of two.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&project, "project", "", "only this project and its sub-projects")
+			fs.BoolVar(&asJSON, "json", false, "print the statistics as a JSON object")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("stats takes no arguments")
+			}
+			var list []tasks.Task
+			err := a.view(env, func(tx tasks.Tx) error {
+				var err error
+				list, err = tx.List()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if project != "" {
+				list = tasks.Select(list, tasks.InProject(project))
+			}
+			perProject, total := tasks.Estimates(list)
+			if asJSON {
+				if perProject == nil {
+					perProject = []tasks.EstimateStat{}
+				}
+				enc := json.NewEncoder(env.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Projects []tasks.EstimateStat `json:"projects"`
+					Total    tasks.EstimateStat   `json:"total"`
+				}{perProject, total})
+			}
+			p := a.printer(env)
+			if total.Tasks == 0 {
+				p.Fprintln(env.Stdout, "No completed tasks with both an estimate and tracked time.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			p.Fprintln(tw, "Project\tTasks\tEstimated\tTracked\tRatio\tAccuracy")
+			row := func(name string, s tasks.EstimateStat) {
+				fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%.2fx\t%.0f%%\n", name, s.Tasks,
+					formatDuration(s.Estimated), formatDuration(s.Tracked), s.Ratio(), 100*s.Accuracy)
+			}
+			for _, s := range perProject {
+				row(orDash(s.Project), s)
+			}
+			if len(perProject) > 1 {
+				row(p.T("Total"), total)
+			}
+			return tw.Flush()
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package taskcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+	"gopatterns/task-manager/tasksync"
+)
+
+// syncState is what `task sync` remembers about a list between runs.
+type syncState struct {
+	Remote   string         `json:"remote"`
+	Base     map[string]int `json:"base"` // UUID -> revision after the last sync
+	LastSync time.Time      `json:"last_sync"`
+}
+
+func (a *app) syncCmd() *cli.Command {
+	var remote, token string
+	return &cli.Command{
+		Name:    "sync",
+		Summary: "Exchange changes with a task serve instance",
+		Usage:   "[-remote url] [-token token]",
+		Help: `Sends the tasks changed since the last sync to the sync endpoint of
+'task serve -http' and takes back the merged list. A task changed on both
+sides is merged field by field; a field changed on both keeps the later
+change and records the other as a conflict for 'task conflicts'. A task
+changed on one side and deleted on the other is kept. The remote URL is
+remembered per list, so later syncs need no flags. Credentials come from -token ($TASK_SYNC_TOKEN), or user:password in
+the URL for a server using -auth htpasswd.
+
+The server syncs the list it was started with; sync each list against a
+server started with the matching -list.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&remote, "remote", "", "server URL, e.g. http://desktop:7080 (remembered)")
+			fs.StringVar(&token, "token", "", "bearer token (env TASK_SYNC_TOKEN)")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("sync takes no arguments")
+			}
+			statePath, err := a.syncStatePath(env)
+			if err != nil {
+				return err
+			}
+			state, err := loadSyncState(statePath)
+			if err != nil {
+				return err
+			}
+			if remote != "" {
+				state.Remote = remote
+			}
+			if state.Remote == "" {
+				return usagef("no remote configured; pass -remote url")
+			}
+			if token == "" {
+				token = env.EnvString("TASK_SYNC_TOKEN", "")
+			}
+
+			s, err := a.open(env)
+			if err != nil {
+				return err
+			}
+			defer func() { err = errors.Join(err, s.Close()) }()
+
+			client := &tasksync.Client{URL: state.Remote, Token: token, User: a.user(env)}
+			var stats tasksync.Stats
+			if err := s.Update(func(tx tasks.Tx) error {
+				state.Base, stats, err = client.Sync(ctx, tx, state.Base)
+				return err
+			}); err != nil {
+				return err
+			}
+			state.LastSync = a.now()
+			if err := saveSyncState(statePath, state); err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Synced with %s: %d sent, %d received, %d removed, %d conflicts\n",
+				state.Remote, stats.Sent, stats.Received, stats.Removed, stats.Conflicts)
+			if stats.Conflicts > 0 {
+				a.printer(env).Fprintln(env.Stdout, "Review them with 'task conflicts'.")
+			}
+			return nil
+		},
+	}
+}
+
+// syncStatePath is sync/<list>.json in the data directory.
+func (a *app) syncStatePath(env *cli.Env) (string, error) {
+	dir, err := a.dir(env)
+	if err != nil {
+		return "", err
+	}
+	list, err := a.list(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sync", list+".json"), nil
+}
+
+func loadSyncState(path string) (syncState, error) {
+	var st syncState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("%s: %w", path, err)
+	}
+	return st, nil
+}
+
+func saveSyncState(path string, st syncState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeState(path, data)
+}
+
+// writeState replaces the file at path with data, atomically.
+func writeState(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,394 @@
+// Package taskcli implements the `task` command line on top of package tasks.
+// It is used both by the standalone task binary and by `gopatterns task`.
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	osuser "os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/hooks"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// app holds the state shared by every task subcommand for one invocation.
+type app struct {
+	dataDir    string
+	backend    string
+	listName   string
+	configFile string
+	profile    string
+	jsonErrs   bool
+	now        func() time.Time
+
+	cfg     *config       // loaded on first use
+	p       *i18n.Printer // set up on first use
+	usedKey *fileKeyUse   // set by fileKey
+}
+
+// Root returns the `task` command tree.
+func Root() *cli.Command {
+	a := &app{now: time.Now}
+	root := &cli.Command{
+		Name:     "task",
+		Summary:  "Manage tasks from the command line",
+		Complete: a.completeRoot,
+		Expand:   a.expandAlias,
+		Translate: func(env *cli.Env, msg string) string {
+			return a.printer(env).T(msg)
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&a.dataDir, "data-dir", "", "directory holding the task database (env TASK_DATA_DIR)")
+			fs.StringVar(&a.backend, "backend", "", "storage backend: bolt, json or git (env TASK_BACKEND, default bolt)")
+			fs.StringVar(&a.listName, "list", "", "task list to use (env TASK_LIST, default from config or \"default\")")
+			fs.StringVar(&a.configFile, "config", "", "config file (env TASK_CONFIG, default ~/.config/task/config.toml)")
+			fs.StringVar(&a.profile, "profile", "", "config profile to use, e.g. work (env TASK_PROFILE)")
+			fs.BoolVar(&a.jsonErrs, "json-errors", false, "print errors to stderr as JSON objects (env TASK_JSON_ERRORS)")
+		},
+		Subcommands: []*cli.Command{
+			a.addCmd(),
+			a.listCmd(),
+			a.boardCmd(),
+			a.nextCmd(),
+			a.digestCmd(),
+			a.completeCmd(),
+			a.startCmd(),
+			a.stopCmd(),
+			a.holdCmd(),
+			a.cancelCmd(),
+			a.reopenCmd(),
+			a.snoozeCmd(),
+			a.deleteCmd(),
+			a.trashCmd(),
+			a.restoreCmd(),
+			a.backupCmd(),
+			a.editCmd(),
+			a.assignCmd(),
+			a.noteCmd(),
+			a.attachCmd(),
+			a.detachCmd(),
+			a.openCmd(),
+			a.showCmd(),
+			a.undoCmd(),
+			a.historyCmd(),
+			a.logCmd(),
+			a.burndownCmd(),
+			a.statsCmd(),
+			a.tagsCmd(),
+			a.projectsCmd(),
+			a.contextCmd(),
+			a.listsCmd(),
+			a.searchCmd(),
+			a.importCmd(),
+			a.exportCmd(),
+			a.shareCmd(),
+			a.serveCmd(),
+			a.tokenCmd(),
+			a.syncCmd(),
+			a.gitCmd(),
+			a.caldavCmd(),
+			a.githubCmd(),
+			a.jiraCmd(),
+			a.conflictsCmd(),
+			a.remindCmd(),
+			a.lockCmd(),
+		},
+	}
+	a.reportErrors(root)
+	root.Subcommands = append(root.Subcommands, cli.CompletionCommands(root)...)
+	return root
+}
+
+// dir resolves the data directory: the -data-dir flag, then $TASK_DATA_DIR,
+// then data_dir in the config file, then $XDG_DATA_HOME/task, then
+// ~/.local/share/task. A profile keeps its tasks apart: in its own data_dir,
+// which only the flag overrides, or else in profiles/<name> under the
+// directory found after the flag.
+func (a *app) dir(env *cli.Env) (string, error) {
+	if a.dataDir != "" {
+		return a.dataDir, nil
+	}
+	cfg, err := a.config(env)
+	if err != nil {
+		return "", err
+	}
+	if cfg.profileDataDir {
+		return cfg.DataDir, nil
+	}
+	dir, err := baseDir(env, cfg)
+	if err != nil {
+		return "", err
+	}
+	if cfg.profile != "" {
+		dir = filepath.Join(dir, "profiles", cfg.profile)
+	}
+	return dir, nil
+}
+
+func baseDir(env *cli.Env, cfg *config) (string, error) {
+	if d := env.EnvString("TASK_DATA_DIR", ""); d != "" {
+		return d, nil
+	}
+	if cfg.DataDir != "" {
+		return cfg.DataDir, nil
+	}
+	if d := env.EnvString("XDG_DATA_HOME", ""); d != "" {
+		return filepath.Join(d, "task"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "task"), nil
+}
+
+// open opens the selected task list.
+func (a *app) open(env *cli.Env) (tasks.Store, error) {
+	list, err := a.list(env)
+	if err != nil {
+		return nil, err
+	}
+	return a.openList(env, list)
+}
+
+// list resolves the task list: the -list flag, then $TASK_LIST, then
+// default_list in the config file, then tasks.DefaultList.
+func (a *app) list(env *cli.Env) (string, error) {
+	cfg, err := a.config(env)
+	if err != nil {
+		return "", err
+	}
+	name := a.listName
+	if name == "" {
+		name = env.EnvString("TASK_LIST", cfg.DefaultList)
+	}
+	if name == "" {
+		name = tasks.DefaultList
+	}
+	if err := tasks.ValidListName(name); err != nil {
+		return "", usagef("%v", err)
+	}
+	return name, nil
+}
+
+func (a *app) backendName(env *cli.Env) (string, error) {
+	cfg, err := a.config(env)
+	if err != nil {
+		return "", err
+	}
+	backend := a.backend
+	if backend == "" {
+		backend = env.EnvString("TASK_BACKEND", cfg.Backend)
+	}
+	switch backend {
+	case "":
+		return "bolt", nil
+	case "bolt", "json", "git":
+		return backend, nil
+	default:
+		return "", usagef("unknown backend %q (want bolt, json or git)", backend)
+	}
+}
+
+func (a *app) openList(env *cli.Env, list string) (tasks.Store, error) {
+	s, err := a.openBackend(env, list)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := a.config(env)
+	if err != nil {
+		return nil, errors.Join(err, s.Close())
+	}
+	if len(cfg.Webhooks) == 0 {
+		return s, nil
+	}
+	sender := cfg.sender(list)
+	// Deliveries happen after the change has committed, so a failing hook
+	// is only worth a warning.
+	return tasks.Notify(s, func(notices []tasks.Notice) {
+		if err := sender.Send(context.Background(), notices); err != nil {
+			env.Log.Warn("delivering webhooks", "err", err)
+		}
+	}), nil
+}
+
+func (a *app) openBackend(env *cli.Env, list string) (tasks.Store, error) {
+	dir, err := a.dir(env)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := a.backendName(env)
+	if err != nil {
+		return nil, err
+	}
+	encrypt, err := a.encrypted(env)
+	if err != nil {
+		return nil, err
+	}
+	if backend == "json" {
+		if encrypt {
+			return tasks.OpenEncryptedFile(tasks.FileListPath(dir, list), a.fileKey(env))
+		}
+		return tasks.OpenFile(tasks.FileListPath(dir, list))
+	}
+	if encrypt {
+		return nil, usagef("encryption needs the json backend (-backend json or backend = \"json\")")
+	}
+	if backend == "git" {
+		s, err := tasks.OpenGit(tasks.FileListPath(tasks.GitDir(dir), list))
+		if err != nil {
+			return nil, err
+		}
+		return gitStore{s, env}, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, i18n.Errorf("create data dir: %w", err)
+	}
+	boltPath := filepath.Join(dir, "tasks.db")
+	_, statErr := os.Stat(boltPath)
+	if errors.Is(statErr, os.ErrNotExist) {
+		// Seed a new database from the JSON files written by earlier
+		// versions or the json backend, whichever list was asked for.
+		if err := importJSON(env, boltPath, dir); err != nil {
+			os.Remove(boltPath) // try again next time
+			return nil, err
+		}
+	}
+	return tasks.OpenBolt(boltPath, list)
+}
+
+// listNames returns the names of the lists that hold data in the selected
+// backend, always including the default list.
+func (a *app) listNames(env *cli.Env) ([]string, error) {
+	dir, err := a.dir(env)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := a.backendName(env)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	switch backend {
+	case "json":
+		names, err = tasks.FileLists(dir)
+	case "git":
+		names, err = tasks.FileLists(tasks.GitDir(dir))
+	default:
+		names, err = tasks.BoltLists(filepath.Join(dir, "tasks.db"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(names, tasks.DefaultList) {
+		names = append([]string{tasks.DefaultList}, names...)
+	}
+	return names, nil
+}
+
+// importJSON seeds a newly created bolt database from the task files in
+// dir, each list into its own bucket, so switching backends keeps
+// existing tasks.
+func importJSON(env *cli.Env, boltPath, dir string) error {
+	lists, err := tasks.FileLists(dir)
+	if err != nil {
+		return err
+	}
+	for _, list := range lists {
+		jsonPath := tasks.FileListPath(dir, list)
+		src, err := tasks.OpenFile(jsonPath)
+		if err != nil {
+			return err
+		}
+		dst, err := tasks.OpenBolt(boltPath, list)
+		if err != nil {
+			return err
+		}
+		n, err := tasks.Copy(dst, src)
+		if err = errors.Join(err, dst.Close()); err != nil {
+			return i18n.Errorf("import %s: %w", jsonPath, err)
+		}
+		env.Log.Info("imported tasks from JSON store", "count", n, "from", jsonPath, "list", list)
+	}
+	return nil
+}
+
+// view runs fn in a read-only transaction on a freshly opened store.
+func (a *app) view(env *cli.Env, fn func(tasks.Tx) error) error {
+	s, err := a.open(env)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.View(fn)
+}
+
+// update runs fn in a read-write transaction on a freshly opened store,
+// journalling its changes under op so `task undo` can revert them. The
+// hook scripts see every task fn writes or deletes.
+func (a *app) update(env *cli.Env, op string, fn func(tasks.Tx) error) (err error) {
+	runner, err := a.hooks(env, op)
+	if err != nil {
+		return err
+	}
+	s, err := a.open(env)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, s.Close()) }()
+	return s.Update(func(tx tasks.Tx) error {
+		return tasks.Record(tx, tasks.Operation{Name: op, Time: a.now(), User: a.user(env)}, func(tx tasks.Tx) error {
+			return fn(runner.Tx(context.Background(), tx))
+		})
+	})
+}
+
+// hooks returns the runner for the hook scripts in $TASK_HOOKS_DIR, or the
+// hooks directory next to the config file.
+func (a *app) hooks(env *cli.Env, op string) (*hooks.Runner, error) {
+	dir := env.EnvString("TASK_HOOKS_DIR", "")
+	if dir == "" {
+		if p := a.configPath(env); p != "" {
+			dir = filepath.Join(filepath.Dir(p), "hooks")
+		}
+	}
+	list, err := a.list(env)
+	if err != nil {
+		return nil, err
+	}
+	return &hooks.Runner{
+		Dir:    dir,
+		Env:    []string{"TASK_COMMAND=" + op, "TASK_LIST=" + list, "TASK_USER=" + a.user(env)},
+		Stderr: env.Stderr,
+	}, nil
+}
+
+// user names whoever runs the command, for the history: $TASK_USER, then
+// the login name.
+func (a *app) user(env *cli.Env) string {
+	if u := env.EnvString("TASK_USER", env.EnvString("USER", "")); u != "" {
+		return u
+	}
+	if u, err := osuser.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// parseID parses a task ID argument.
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return 0, usagef("invalid task ID %q", arg)
+	}
+	return id, nil
+}
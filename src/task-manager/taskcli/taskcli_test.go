@@ -0,0 +1,119 @@
+package taskcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+// taskEnv is the environment task commands see in tests: a data directory
+// and config of their own, and nothing from the user's.
+func taskEnv(dir string, vars ...string) func(string) string {
+	env := map[string]string{
+		"HOME":            dir,
+		"TASK_DATA_DIR":   filepath.Join(dir, "data"),
+		"XDG_CONFIG_HOME": filepath.Join(dir, "config"),
+	}
+	for i := 0; i+1 < len(vars); i += 2 {
+		env[vars[i]] = vars[i+1]
+	}
+	return func(key string) string { return env[key] }
+}
+
+// task runs a task command line with getenv and returns its output,
+// failing the test if the command fails.
+func task(t *testing.T, getenv func(string) string, args ...string) string {
+	t.Helper()
+	out, err := tryTask(getenv, args...)
+	if err != nil {
+		t.Fatalf("task %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+// tryTask is task for command lines that may fail.
+func tryTask(getenv func(string) string, args ...string) (string, error) {
+	var out bytes.Buffer
+	env := &cli.Env{
+		Stdin:  strings.NewReader(""),
+		Stdout: &out,
+		Stderr: &out,
+		Log:    slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		Getenv: getenv,
+	}
+	err := cli.Execute(context.Background(), env, Root(), args)
+	return out.String(), err
+}
+
+func TestHelpIsTranslated(t *testing.T) {
+	fr := i18n.New("fr")
+	var walk func(path string, cmd *cli.Command)
+	walk = func(path string, cmd *cli.Command) {
+		texts := []string{cmd.Summary, cmd.Help}
+		if cmd.Flags != nil {
+			fs := flag.NewFlagSet(path, flag.ContinueOnError)
+			cmd.Flags(fs)
+			fs.VisitAll(func(f *flag.Flag) { texts = append(texts, f.Usage) })
+		}
+		for _, s := range texts {
+			if s != "" && fr.T(s) == s {
+				t.Errorf("%s: no French for %q", path, s)
+			}
+		}
+		for _, sub := range cmd.Subcommands {
+			walk(path+" "+sub.Name, sub)
+		}
+	}
+	walk("task", Root())
+}
+
+func TestFrenchMessages(t *testing.T) {
+	env := taskEnv(t.TempDir(), "LANG", "fr_FR.UTF-8")
+	task(t, env, "add", "Buy milk")
+	task(t, env, "add", "Call mom")
+	task(t, env, "snooze", "1", "2099-01-01")
+	task(t, env, "complete", "2")
+	task(t, env, "add", "-parent", "1", "Pay the bill")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list"}, "(1 tâche reportée masquée pour l'instant ; -all l'affiche)\n"},
+		{[]string{"help", "complete"}, "Marquer des tâches comme terminées"},
+		{[]string{"show", "9"}, "tâche 9 : tâche introuvable"},
+		{[]string{"complete", "2"}, "la tâche 2 est déjà terminée"},
+		{[]string{"reopen", "1"}, "la tâche 1 est à faire, ni terminée ni annulée"},
+		{[]string{"complete", "1"}, "la tâche 1 a 1 sous-tâche ouverte ; terminez-les"},
+		{[]string{"list", "foo=bar"}, `filtre : champ inconnu "foo"`},
+		{[]string{"bogus"}, `commande inconnue "bogus"`},
+	}
+	for _, tt := range tests {
+		out, err := tryTask(env, tt.args...)
+		if err != nil {
+			out += err.Error()
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("task %s = %q, want %q in it", strings.Join(tt.args, " "), out, tt.want)
+		}
+	}
+	if _, err := tryTask(env, "show", "9"); !errors.Is(err, tasks.ErrNotFound) {
+		t.Errorf("translated error %v does not wrap ErrNotFound", err)
+	}
+
+	bolt := taskEnv(t.TempDir(), "LANG", "fr_FR.UTF-8", "TASK_BACKEND", "bolt", "TASK_ENCRYPT", "true")
+	_, err := tryTask(bolt, "list")
+	var usage *cli.UsageError
+	if !errors.As(err, &usage) || !strings.Contains(err.Error(), "le chiffrement a besoin du moteur json") {
+		t.Errorf("encrypting the bolt backend: %v, want a French usage error", err)
+	}
+}
@@ -0,0 +1,184 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/auth"
+	"gopatterns/task-manager/i18n"
+)
+
+func (a *app) tokenCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "token",
+		Summary: "Manage the API tokens 'task serve -auth static' accepts",
+		Help: `Tokens live in the tokens file of the data directory, which 'task serve
+-auth static' reads unless -tokens names another file. Only a hash of each
+token is stored, so a token is shown once, when it is created. Restart
+the server after changing tokens.`,
+		Subcommands: []*cli.Command{
+			a.tokenCreateCmd(),
+			a.tokenListCmd(),
+			a.tokenRevokeCmd(),
+		},
+	}
+}
+
+func (a *app) tokenCreateCmd() *cli.Command {
+	var readOnly bool
+	return &cli.Command{
+		Name:    "create",
+		Summary: "Create a token and print it",
+		Usage:   "<name> [-read-only]",
+		Help: `The name identifies the token in 'task token list' and is the user the
+token's changes are logged under. A -read-only token can list and fetch
+tasks and pull with 'task sync', but not change anything.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&readOnly, "read-only", false, "grant only the tasks:read scope")
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) != 1 {
+				return usagef("token create takes one name")
+			}
+			name := args[0]
+			if strings.ContainsAny(name, " \t#") {
+				return usagef("invalid token name %q", name)
+			}
+			path, tokens, err := a.loadTokens(env)
+			if err != nil {
+				return err
+			}
+			if slices.ContainsFunc(tokens, func(t auth.Token) bool { return t.Subject == name }) {
+				return i18n.Errorf("a token named %s already exists; revoke it first", name)
+			}
+			secret, err := auth.GenerateToken()
+			if err != nil {
+				return err
+			}
+			scope := auth.ScopeWrite
+			if readOnly {
+				scope = auth.ScopeRead
+			}
+			tokens = append(tokens, auth.Token{Secret: auth.HashToken(secret), Subject: name, Scopes: []string{scope}})
+			if err := auth.SaveTokens(path, tokens); err != nil {
+				return err
+			}
+			p := a.printer(env)
+			p.Fprintf(env.Stdout, "Created %s token %s. It is not shown again:\n%s\n", p.T(access(scope)), name, secret)
+			return nil
+		},
+	}
+}
+
+func (a *app) tokenListCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "list",
+		Summary: "List the tokens",
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("token list takes no arguments")
+			}
+			_, tokens, err := a.loadTokens(env)
+			if err != nil {
+				return err
+			}
+			if len(tokens) == 0 {
+				a.printer(env).Fprintln(env.Stdout, "No tokens.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			p := a.printer(env)
+			p.Fprintln(tw, "Name\tAccess\tStored")
+			for _, t := range tokens {
+				stored := p.T("hashed")
+				if !strings.HasPrefix(t.Secret, auth.HashPrefix) {
+					stored = p.T("plain text")
+				}
+				id := auth.Identity{Scopes: t.Scopes}
+				scope := auth.ScopeRead
+				if id.Can(auth.ScopeWrite) {
+					scope = auth.ScopeWrite
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Subject, p.T(access(scope)), stored)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func (a *app) tokenRevokeCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "revoke",
+		Summary: "Delete tokens by name",
+		Usage:   "<name>...",
+		Complete: func(ctx context.Context, env *cli.Env, flag string, args []string) []string {
+			_, tokens, err := a.loadTokens(env)
+			if err != nil {
+				return nil
+			}
+			names := make([]string, len(tokens))
+			for i, t := range tokens {
+				names[i] = t.Subject
+			}
+			return names
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) == 0 {
+				return usagef("token revoke takes token names")
+			}
+			path, tokens, err := a.loadTokens(env)
+			if err != nil {
+				return err
+			}
+			for _, name := range args {
+				if !slices.ContainsFunc(tokens, func(t auth.Token) bool { return t.Subject == name }) {
+					return i18n.Errorf("no token named %s", name)
+				}
+			}
+			tokens = slices.DeleteFunc(tokens, func(t auth.Token) bool { return slices.Contains(args, t.Subject) })
+			if err := auth.SaveTokens(path, tokens); err != nil {
+				return err
+			}
+			p := a.printer(env)
+			p.Fprintf(env.Stdout, "Revoked %s\n", p.Plural(len(args), "%d token", "%d tokens"))
+			return nil
+		},
+	}
+}
+
+func access(scope string) string {
+	if scope == auth.ScopeRead {
+		return "read-only"
+	}
+	return "read-write"
+}
+
+// tokensPath is the tokens file in the data directory.
+func (a *app) tokensPath(env *cli.Env) (string, error) {
+	dir, err := a.dir(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tokens"), nil
+}
+
+// loadTokens reads the tokens file; a missing one holds no tokens.
+func (a *app) loadTokens(env *cli.Env) (string, []auth.Token, error) {
+	path, err := a.tokensPath(env)
+	if err != nil {
+		return "", nil, err
+	}
+	tokens, err := auth.LoadTokens(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return path, nil, nil
+	}
+	return path, tokens, err
+}
@@ -0,0 +1,231 @@
+package taskcli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/i18n"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) trashCmd() *cli.Command {
+	var (
+		asJSON bool
+		empty  bool
+		yes    bool
+	)
+	return &cli.Command{
+		Name:    "trash",
+		Summary: "List deleted tasks that can still be restored",
+		Usage:   "[-json] [-empty [-yes]]",
+		Help: `Deleted tasks stay in the trash for trash_days days (30 unless the config
+file says otherwise) and are purged for good the next time something is
+deleted or restored after that. 'task restore <id>' brings one back.
+
+-empty purges the whole trash at once, after listing it and asking for
+confirmation unless -yes is given; 'task undo' reverts that too.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&asJSON, "json", false, "print the trashed tasks as JSON")
+			fs.BoolVar(&empty, "empty", false, "purge every trashed task now")
+			yesFlags(fs, &yes)
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) > 0 {
+				return usagef("trash takes no arguments")
+			}
+			cfg, err := a.config(env)
+			if err != nil {
+				return err
+			}
+			now := a.now()
+			if empty {
+				if !yes {
+					var trashed []tasks.Trashed
+					if err := a.view(env, func(tx tasks.Tx) (err error) {
+						trashed, err = tx.Trash()
+						return err
+					}); err != nil {
+						return err
+					}
+					list := make([]tasks.Task, len(trashed))
+					for i, e := range trashed {
+						list[i] = e.Task
+					}
+					if len(list) > 0 {
+						if err := a.confirm(env, "Purge %s from the trash for good?", list); err != nil {
+							return err
+						}
+					}
+				}
+				var purged []tasks.Trashed
+				err := a.update(env, "purge", func(tx tasks.Tx) (err error) {
+					purged, err = tasks.PurgeTrash(tx, now)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				p := a.printer(env)
+				p.Fprintf(env.Stdout, "Purged %s\n", p.Plural(len(purged), "%d task", "%d tasks"))
+				return nil
+			}
+
+			var list []tasks.Trashed
+			err = a.view(env, func(tx tasks.Tx) (err error) {
+				list, err = tx.Trash()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			// Expired entries linger until the next delete purges them.
+			cutoff := trashCutoff(now, cfg.trashDays())
+			list = slices.DeleteFunc(list, func(e tasks.Trashed) bool { return !e.DeletedAt.After(cutoff) })
+			slices.SortStableFunc(list, func(x, y tasks.Trashed) int { return y.DeletedAt.Compare(x.DeletedAt) })
+			if asJSON {
+				return printJSON(env.Stdout, list)
+			}
+			if len(list) == 0 {
+				a.printer(env).Fprintln(env.Stdout, "The trash is empty.")
+				return nil
+			}
+			st := a.style(env)
+			tw := tabwriter.NewWriter(env.Stdout, 0, 0, 2, ' ', 0)
+			st.p.Fprintln(tw, "ID\tDeleted\tBy\tPurged in\tDescription")
+			for _, e := range list {
+				left := e.DeletedAt.AddDate(0, 0, cfg.trashDays()).Sub(now)
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", e.Task.ID, formatDate(e.DeletedAt, st.dateLayout),
+					orDash(e.User), st.p.Plural(int(math.Ceil(left.Hours()/24)), "%d day", "%d days"), e.Task.Description)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func (a *app) restoreCmd() *cli.Command {
+	var yes bool
+	return &cli.Command{
+		Name:     "restore",
+		Summary:  "Bring deleted tasks back from the trash, or roll back to a backup",
+		Complete: a.completeTrash,
+		Usage:    "<id>... | <snapshot> [-yes]",
+		Help: `Moves the listed tasks from the trash back into the list, in one step that
+undo reverts as a whole. A task keeps its ID unless another task has taken
+it since, and becomes a top-level task if its parent is gone.
+
+Given a snapshot from 'task backup' instead (its file name, a path, or
+"latest"), rolls every list it holds, or only the one chosen with -list,
+back to its tasks and trash in the snapshot, after asking for
+confirmation unless -yes is given. The history is kept, with the rollback
+logged in it, and 'task undo' reverts the rollback of a list.`,
+		Flags: func(fs *flag.FlagSet) {
+			yesFlags(fs, &yes)
+		},
+		Run: func(ctx context.Context, env *cli.Env, args []string) error {
+			if len(args) == 0 {
+				return usagef("missing task ID")
+			}
+			if len(args) == 1 && isSnapshot(args[0]) {
+				return a.restoreBackup(env, args[0], yes)
+			}
+			args, err := a.trashRefs(env, args)
+			if err != nil {
+				return err
+			}
+			ids := make([]int, len(args))
+			for i, arg := range args {
+				id, err := parseID(arg)
+				if err != nil {
+					return err
+				}
+				ids[i] = id
+			}
+			cfg, err := a.config(env)
+			if err != nil {
+				return err
+			}
+			now := a.now()
+			var restored []tasks.Task
+			err = a.update(env, "restore", func(tx tasks.Tx) error {
+				if _, err := tasks.PurgeTrash(tx, trashCutoff(now, cfg.trashDays())); err != nil {
+					return err
+				}
+				for _, id := range ids {
+					t, err := tasks.Restore(tx, id)
+					if err != nil {
+						return i18n.Errorf("restore: %w (see 'task trash')", err)
+					}
+					restored = append(restored, t)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for i, t := range restored {
+				if t.ID != ids[i] {
+					a.printer(env).Fprintf(env.Stdout, "Restored task %d as %d: %s\n", ids[i], t.ID, t.Description)
+				} else {
+					a.printer(env).Fprintf(env.Stdout, "Restored task %d: %s\n", t.ID, t.Description)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// completeTrash offers the IDs of trashed tasks and, for the first
+// argument, the snapshots.
+func (a *app) completeTrash(ctx context.Context, env *cli.Env, flag string, args []string) (out []string) {
+	if flag != "" {
+		return nil
+	}
+	if len(args) == 0 {
+		defer func() { out = append(out, a.completeBackups(env)...) }()
+	}
+	var list []tasks.Trashed
+	if err := a.view(env, func(tx tasks.Tx) (err error) {
+		list, err = tx.Trash()
+		return err
+	}); err != nil {
+		return nil
+	}
+	for _, e := range list {
+		id := strconv.Itoa(e.Task.ID)
+		if !slices.Contains(args, id) {
+			out = append(out, id+"\t"+strings.TrimSpace(e.Task.Description))
+		}
+	}
+	return out
+}
+
+// trashCutoff is the deletion time up to which trashed tasks are purged.
+func trashCutoff(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+// trashRefs is uuidRefs for the tasks in the trash.
+func (a *app) trashRefs(env *cli.Env, args []string) ([]string, error) {
+	if !slices.ContainsFunc(args, tasks.IsUUIDRef) {
+		return args, nil
+	}
+	var list []tasks.Task
+	if err := a.view(env, func(tx tasks.Tx) error {
+		trashed, err := tx.Trash()
+		for _, e := range trashed {
+			list = append(list, e.Task)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return refsIn(list, args)
+}
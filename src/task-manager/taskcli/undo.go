@@ -0,0 +1,52 @@
+package taskcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopatterns/internal/cli"
+	"gopatterns/task-manager/tasks"
+)
+
+func (a *app) undoCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "undo",
+		Summary: "Revert the last add, complete, delete or edit",
+		Help: fmt.Sprintf(`Every mutating command is journalled together with the previous state of
+the tasks it touched. undo pops the newest entry and restores that state;
+run it again to step further back (up to %d operations).`, tasks.JournalLimit),
+		Run: func(ctx context.Context, env *cli.Env, args []string) (err error) {
+			if len(args) > 0 {
+				return usagef("undo takes no arguments")
+			}
+			s, err := a.open(env)
+			if err != nil {
+				return err
+			}
+			defer func() { err = errors.Join(err, s.Close()) }()
+
+			var op tasks.Operation
+			if err := s.Update(func(tx tasks.Tx) error {
+				op, err = tasks.Undo(tx, a.now(), a.user(env))
+				return err
+			}); err != nil {
+				return err
+			}
+			a.printer(env).Fprintf(env.Stdout, "Undid %s from %s\n", op.Name, op.Time.Format("2006-01-02 15:04:05"))
+			for _, c := range op.Changes {
+				if c.Before == nil {
+					a.printer(env).Fprintf(env.Stdout, "  removed task %d\n", c.ID)
+				} else {
+					a.printer(env).Fprintf(env.Stdout, "  restored task %d: %s\n", c.ID, c.Before.Description)
+				}
+			}
+			for _, c := range op.Trash {
+				if c.Before != nil {
+					a.printer(env).Fprintf(env.Stdout, "  put task %d back in the trash: %s\n", c.ID, c.Before.Task.Description)
+				}
+			}
+			return nil
+		},
+	}
+}
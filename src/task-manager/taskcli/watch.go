@@ -0,0 +1,108 @@
+package taskcli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopatterns/internal/cli"
+	"gopatterns/lifecycle"
+	"gopatterns/task-manager/tasks"
+)
+
+// Watch mode checks the data files this often, and redraws at least every
+// watchRefresh regardless.
+const (
+	watchPoll    = 500 * time.Millisecond
+	watchRefresh = time.Minute
+)
+
+// watch calls render, then again whenever the list's data files change,
+// until interrupted. On a terminal each rendering replaces the previous
+// one; elsewhere they follow each other.
+func (a *app) watch(ctx context.Context, env *cli.Env, render func(io.Writer) error) error {
+	files, err := a.dataFiles(env)
+	if err != nil {
+		return err
+	}
+	lc := lifecycle.New(ctx)
+	clear := isTerminal(env.Stdout)
+	ticker := time.NewTicker(watchPoll)
+	defer ticker.Stop()
+	for {
+		stamp := fileStamp(files)
+		var buf bytes.Buffer
+		if clear {
+			buf.WriteString("\x1b[H\x1b[2J")
+		}
+		if err := render(&buf); err != nil {
+			return err
+		}
+		if clear {
+			a.printer(env).Fprintf(&buf, "\nWatching for changes (updated %s); Ctrl-C stops.\n", a.now().Format("15:04:05"))
+		}
+		if _, err := env.Stdout.Write(buf.Bytes()); err != nil {
+			return err
+		}
+
+		drawn := time.Now()
+	wait:
+		for {
+			select {
+			case <-lc.Context().Done():
+				return lc.Wait()
+			case <-ticker.C:
+				if fileStamp(files) != stamp || time.Since(drawn) >= watchRefresh {
+					break wait
+				}
+			}
+		}
+	}
+}
+
+// dataFiles lists the files whose changes can alter what the list shows:
+// the store and the saved contexts.
+func (a *app) dataFiles(env *cli.Env) ([]string, error) {
+	dir, err := a.dir(env)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := a.backendName(env)
+	if err != nil {
+		return nil, err
+	}
+	list, err := a.list(env)
+	if err != nil {
+		return nil, err
+	}
+	store := filepath.Join(dir, "tasks.db")
+	switch backend {
+	case "json":
+		store = tasks.FileListPath(dir, list)
+	case "git":
+		store = tasks.FileListPath(tasks.GitDir(dir), list)
+	}
+	contexts, err := a.contextsPath(env)
+	if err != nil {
+		return nil, err
+	}
+	return []string{store, contexts}, nil
+}
+
+// fileStamp sums up the size and modification time of files; a missing
+// file counts as empty.
+func fileStamp(files []string) string {
+	var b bytes.Buffer
+	for _, name := range files {
+		if info, err := os.Stat(name); err == nil {
+			fmt.Fprintf(&b, "%d/%d;", info.Size(), info.ModTime().UnixNano())
+		} else {
+			b.WriteString("-;")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,682 @@
+package taskgql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Schema describes what Execute serves, in GraphQL SDL.
+const Schema = `type Query {
+  "The task with this ID, or null."
+  task(id: Int!): Task
+  "Tasks matching every given filter, by descending priority unless sort is set."
+  tasks(
+    completed: Boolean
+    status: String
+    project: String
+    context: String
+    assignee: String
+    tags: [String!]
+    search: String
+    "Only tasks without a parent."
+    roots: Boolean
+    "Sort keys as for 'task list -sort', e.g. \"due,-priority\"."
+    sort: String
+    "At most this many tasks, after skipping offset of them."
+    first: Int
+    offset: Int
+  ): [Task!]!
+  "How many tasks the same filters match, for paging through tasks."
+  taskCount(
+    completed: Boolean
+    status: String
+    project: String
+    context: String
+    assignee: String
+    tags: [String!]
+    search: String
+    roots: Boolean
+  ): Int!
+}
+
+type Task {
+  id: Int!
+  uuid: String
+  description: String!
+  "pending, in-progress, waiting, done or cancelled."
+  status: String!
+  "Whether status is done."
+  completed: Boolean!
+  "0-9; null when unset."
+  priority: Int
+  due: String
+  wait: String
+  createdAt: String!
+  startedAt: String
+  completedAt: String
+  updatedAt: String
+  tags: [String!]!
+  project: String
+  context: String
+  assignee: String
+  notes: [Note!]!
+  parent: Task
+  subtasks(completed: Boolean): [Task!]!
+}
+
+type Note {
+  time: String!
+  text: String!
+}
+`
+
+// Request is a GraphQL request as clients POST it.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL response. Data is null when the request failed.
+type Response struct {
+	Data   any      `json:"data"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Execute runs the query in req against list. Times are formatted as
+// RFC 3339. Only queries are served; mutations and subscriptions are
+// rejected, since changes go through the gRPC API and sync.
+func Execute(list []tasks.Task, req Request) Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return Response{Errors: []*Error{err.(*Error)}}
+	}
+	op, gerr := pick(doc, req.OperationName)
+	if gerr != nil {
+		return Response{Errors: []*Error{gerr}}
+	}
+	if op.kind != "query" {
+		return Response{Errors: []*Error{errorf("%s operations are not supported; use the gRPC API to change tasks", op.kind)}}
+	}
+	x := &executor{
+		doc:      doc,
+		vars:     map[string]any{},
+		declared: map[string]bool{},
+		byID:     make(map[int]tasks.Task, len(list)),
+		children: map[int][]tasks.Task{},
+		list:     list,
+	}
+	for _, v := range op.vars {
+		x.declared[v.name] = true
+		if val, ok := req.Variables[v.name]; ok {
+			x.vars[v.name] = val
+		} else if v.def != nil {
+			x.vars[v.name], _ = x.resolve(v.def) // constant, so it cannot fail
+		}
+	}
+	for _, t := range list {
+		x.byID[t.ID] = t
+		if t.Parent != 0 {
+			x.children[t.Parent] = append(x.children[t.Parent], t)
+		}
+	}
+	for _, sub := range x.children {
+		tasks.SortByPriority(sub)
+	}
+	data, gerr := x.query(op.sel)
+	if gerr != nil {
+		return Response{Errors: []*Error{gerr}}
+	}
+	return Response{Data: data}
+}
+
+// pick selects the operation to run: the named one, or the only one.
+func pick(doc *document, name string) (*operation, *Error) {
+	if name == "" {
+		if len(doc.ops) > 1 {
+			return nil, errorf("the document has several operations; set operationName")
+		}
+		return doc.ops[0], nil
+	}
+	for _, op := range doc.ops {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, errorf("no operation named %q", name)
+}
+
+type executor struct {
+	doc      *document
+	vars     map[string]any
+	declared map[string]bool
+	list     []tasks.Task
+	byID     map[int]tasks.Task
+	children map[int][]tasks.Task
+}
+
+func (x *executor) query(sel []selection) (*object, *Error) {
+	fields, err := x.collect(sel, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	obj := &object{}
+	for _, f := range fields {
+		args, err := x.args(f)
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		switch f.name {
+		case "__typename":
+			v = "Query"
+		case "task":
+			if _, ok := args["id"]; !ok {
+				return nil, errorf("task: argument id is required")
+			}
+			id, err := args.int("id", 0)
+			if err != nil {
+				return nil, err
+			}
+			if t, ok := x.byID[int(id)]; ok {
+				if v, err = x.task(t, f); err != nil {
+					return nil, err
+				}
+			}
+		case "tasks":
+			list, err := x.tasks(args)
+			if err != nil {
+				return nil, err
+			}
+			if v, err = x.taskList(list, f); err != nil {
+				return nil, err
+			}
+		case "taskCount":
+			list, err := x.matching(args)
+			if err != nil {
+				return nil, err
+			}
+			v = len(list)
+		default:
+			return nil, errorf("Query has no field %q", f.name)
+		}
+		obj.set(f.key(), v)
+	}
+	return obj, nil
+}
+
+// tasks applies the filters, order and paging of the tasks field.
+func (x *executor) tasks(args arguments) ([]tasks.Task, *Error) {
+	list, err := x.matching(args)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok, err := args.string("sort")
+	if err != nil {
+		return nil, err
+	}
+	if ok && spec != "" {
+		keys, perr := tasks.ParseSort(spec)
+		if perr != nil {
+			return nil, errorf("tasks: %v", perr)
+		}
+		tasks.SortBy(list, keys)
+	} else {
+		tasks.SortByPriority(list)
+	}
+	offset, err := args.int("offset", 0)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, errorf("tasks: offset must not be negative")
+	}
+	list = list[min(int(offset), len(list)):]
+	if _, ok := args["first"]; ok {
+		n, err := args.int("first", 0)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, errorf("tasks: first must not be negative")
+		}
+		if int(n) < len(list) {
+			list = list[:n]
+		}
+	}
+	return list, nil
+}
+
+// matching applies the filters of the tasks and taskCount fields.
+func (x *executor) matching(args arguments) ([]tasks.Task, *Error) {
+	var filters []tasks.Filter
+	if done, ok, err := args.bool("completed"); err != nil {
+		return nil, err
+	} else if ok {
+		filters = append(filters, func(t tasks.Task) bool { return t.Done() == done })
+	}
+	for _, name := range []string{"status", "project", "context", "assignee"} {
+		s, ok, err := args.string(name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		switch name {
+		case "status":
+			status, err := tasks.ParseStatus(s)
+			if err != nil {
+				return nil, errorf("tasks: %v", err)
+			}
+			filters = append(filters, func(t tasks.Task) bool { return t.Status == status })
+		case "project":
+			filters = append(filters, tasks.InProject(s))
+		case "context":
+			filters = append(filters, tasks.InContext(s))
+		case "assignee":
+			if s == "none" {
+				s = ""
+			}
+			filters = append(filters, tasks.AssignedTo(s))
+		}
+	}
+	tags, err := args.strings("tags")
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 0 {
+		filters = append(filters, tasks.WithTags(tasks.NormalizeTags(tags)...))
+	}
+	if roots, ok, err := args.bool("roots"); err != nil {
+		return nil, err
+	} else if ok && roots {
+		filters = append(filters, func(t tasks.Task) bool { return t.Parent == 0 })
+	}
+
+	// Callers sort the result, so it must not share x.list with Select.
+	list := slices.Clone(tasks.Select(x.list, tasks.And(filters...)))
+	if q, ok, err := args.string("search"); err != nil {
+		return nil, err
+	} else if ok && q != "" {
+		list = tasks.Search(list, q)
+	}
+	return list, nil
+}
+
+func (x *executor) taskList(list []tasks.Task, f selection) ([]any, *Error) {
+	out := make([]any, 0, len(list))
+	for _, t := range list {
+		obj, err := x.task(t, f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func (x *executor) task(t tasks.Task, parent selection) (*object, *Error) {
+	if parent.sel == nil {
+		return nil, errorf("field %q of type Task needs a selection of subfields", parent.name)
+	}
+	fields, err := x.collect(parent.sel, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	obj := &object{}
+	for _, f := range fields {
+		args, err := x.args(f)
+		if err != nil {
+			return nil, err
+		}
+		if f.sel != nil && f.name != "notes" && f.name != "parent" && f.name != "subtasks" {
+			return nil, errorf("field %q of type Task is a scalar and takes no subfields", f.name)
+		}
+		var v any
+		switch f.name {
+		case "__typename":
+			v = "Task"
+		case "id":
+			v = t.ID
+		case "uuid":
+			v = nullString(t.UUID)
+		case "description":
+			v = t.Description
+		case "status":
+			v = string(t.Status)
+		case "completed":
+			v = t.Done()
+		case "priority":
+			if t.Priority != tasks.PriorityNone {
+				v = int(t.Priority)
+			}
+		case "due":
+			v = timeString(t.Due)
+		case "wait":
+			v = timeString(t.Wait)
+		case "createdAt":
+			v = t.CreatedAt.Format(time.RFC3339)
+		case "startedAt":
+			v = timeString(t.StartedAt)
+		case "completedAt":
+			v = timeString(t.CompletedAt)
+		case "updatedAt":
+			v = timeString(t.UpdatedAt)
+		case "tags":
+			tags := t.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			v = tags
+		case "project":
+			v = nullString(t.Project)
+		case "context":
+			v = nullString(t.Context)
+		case "assignee":
+			v = nullString(t.Assignee)
+		case "notes":
+			if v, err = x.notes(t.Notes, f); err != nil {
+				return nil, err
+			}
+		case "parent":
+			if p, ok := x.byID[t.Parent]; ok && t.Parent != 0 {
+				if v, err = x.task(p, f); err != nil {
+					return nil, err
+				}
+			}
+		case "subtasks":
+			sub := x.children[t.ID]
+			if done, ok, err := args.bool("completed"); err != nil {
+				return nil, err
+			} else if ok {
+				sub = tasks.Select(sub, func(t tasks.Task) bool { return t.Done() == done })
+			}
+			if v, err = x.taskList(sub, f); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errorf("Task has no field %q", f.name)
+		}
+		if len(args) > 0 && f.name != "subtasks" {
+			return nil, errorf("field %q of type Task takes no arguments", f.name)
+		}
+		obj.set(f.key(), v)
+	}
+	return obj, nil
+}
+
+func (x *executor) notes(notes []tasks.Note, parent selection) ([]any, *Error) {
+	if parent.sel == nil {
+		return nil, errorf("field %q of type [Note!]! needs a selection of subfields", parent.name)
+	}
+	fields, err := x.collect(parent.sel, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0, len(notes))
+	for _, n := range notes {
+		obj := &object{}
+		for _, f := range fields {
+			if len(f.args) > 0 || f.sel != nil {
+				return nil, errorf("field %q of type Note takes no arguments or subfields", f.name)
+			}
+			switch f.name {
+			case "__typename":
+				obj.set(f.key(), "Note")
+			case "time":
+				obj.set(f.key(), n.Time.Format(time.RFC3339))
+			case "text":
+				obj.set(f.key(), n.Text)
+			default:
+				return nil, errorf("Note has no field %q", f.name)
+			}
+		}
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+// collect flattens fragments and applies @skip and @include, merging the
+// subselections of fields that share a response key. visiting guards
+// against fragments that spread themselves.
+func (x *executor) collect(sel []selection, visiting map[string]bool) ([]selection, *Error) {
+	var out []selection
+	index := map[string]int{}
+	add := func(fields []selection) {
+		for _, f := range fields {
+			if i, ok := index[f.key()]; ok {
+				out[i].sel = append(out[i].sel, f.sel...)
+				continue
+			}
+			index[f.key()] = len(out)
+			out = append(out, f)
+		}
+	}
+	for _, s := range sel {
+		include, err := x.included(s.dirs)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			continue
+		}
+		switch {
+		case s.spread != "":
+			frag, ok := x.doc.frags[s.spread]
+			if !ok {
+				return nil, errorf("unknown fragment %q", s.spread)
+			}
+			if visiting[s.spread] {
+				return nil, errorf("fragment %q spreads itself", s.spread)
+			}
+			visiting[s.spread] = true
+			fields, err := x.collect(frag.sel, visiting)
+			delete(visiting, s.spread)
+			if err != nil {
+				return nil, err
+			}
+			add(fields)
+		case s.inline:
+			fields, err := x.collect(s.sel, visiting)
+			if err != nil {
+				return nil, err
+			}
+			add(fields)
+		default:
+			add([]selection{s})
+		}
+	}
+	return out, nil
+}
+
+func (x *executor) included(dirs []directive) (bool, *Error) {
+	for _, d := range dirs {
+		if d.name != "skip" && d.name != "include" {
+			return false, errorf("unknown directive @%s", d.name)
+		}
+		v, err := x.resolve(d.args["if"])
+		if err != nil {
+			return false, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return false, errorf("@%s needs a Boolean if argument", d.name)
+		}
+		if b == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// arguments holds a field's argument values with variables substituted.
+// Numbers from JSON variables arrive as float64.
+type arguments map[string]any
+
+func (x *executor) args(f selection) (arguments, *Error) {
+	args := arguments{}
+	for name, val := range f.args {
+		v, err := x.resolve(val)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			args[name] = v
+		}
+	}
+	return args, nil
+}
+
+func (x *executor) resolve(val value) (any, *Error) {
+	switch val := val.(type) {
+	case variable:
+		if !x.declared[string(val)] {
+			return nil, errorf("variable $%s is not declared", val)
+		}
+		return x.vars[string(val)], nil
+	case []value:
+		list := make([]any, len(val))
+		for i, item := range val {
+			v, err := x.resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case map[string]value:
+		obj := make(map[string]any, len(val))
+		for k, item := range val {
+			v, err := x.resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
+		return obj, nil
+	case enum:
+		return string(val), nil
+	}
+	return val, nil
+}
+
+func (a arguments) bool(name string) (v, ok bool, err *Error) {
+	raw, ok := a[name]
+	if !ok {
+		return false, false, nil
+	}
+	v, isBool := raw.(bool)
+	if !isBool {
+		return false, false, errorf("argument %s must be a Boolean", name)
+	}
+	return v, true, nil
+}
+
+func (a arguments) string(name string) (v string, ok bool, err *Error) {
+	raw, ok := a[name]
+	if !ok {
+		return "", false, nil
+	}
+	v, isString := raw.(string)
+	if !isString {
+		return "", false, errorf("argument %s must be a String", name)
+	}
+	return v, true, nil
+}
+
+func (a arguments) int(name string, def int64) (int64, *Error) {
+	switch v := a[name].(type) {
+	case nil:
+		return def, nil
+	case int64:
+		return v, nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v), nil
+		}
+	}
+	return 0, errorf("argument %s must be an Int", name)
+}
+
+// strings accepts a list of strings, or a single string as GraphQL's input
+// coercion allows.
+func (a arguments) strings(name string) ([]string, *Error) {
+	switch v := a[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		out := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, errorf("argument %s must be a list of Strings", name)
+			}
+			out[i] = s
+		}
+		return out, nil
+	}
+	return nil, errorf("argument %s must be a list of Strings", name)
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func timeString(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// object is a response object; unlike a map it keeps its fields in the
+// order the query selected them, as GraphQL requires.
+type object struct {
+	keys []string
+	vals map[string]any
+}
+
+func (o *object) set(key string, v any) {
+	if o.vals == nil {
+		o.vals = map[string]any{}
+	}
+	if _, ok := o.vals[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.vals[key] = v
+}
+
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(o.vals[k])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", k, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+// Package taskgql serves a read-only GraphQL view of a task list, so
+// clients can fetch exactly the fields and nested subtasks they need in
+// one request. The query language is implemented here rather than with a
+// library; it covers queries, variables, fragments and the @skip and
+// @include directives, but not introspection.
+package taskgql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Path is where Handler is mounted.
+const Path = "/graphql"
+
+// maxRequest bounds the size of a request body.
+const maxRequest = 1 << 20
+
+// Handler serves GraphQL requests against store: POST with a JSON
+// Request body, or GET with query, operationName and variables (JSON)
+// parameters. A GET without a query returns Schema. Wrap it in
+// auth.Middleware; since nothing can change, read-only tokens suffice.
+func Handler(store tasks.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			if !q.Has("query") {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				w.Write([]byte(Schema))
+				return
+			}
+			req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+			if vars := q.Get("variables"); vars != "" {
+				if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+					http.Error(w, "bad variables: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequest)).Decode(&req); err != nil {
+				http.Error(w, "bad GraphQL request: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var list []tasks.Task
+		err := store.View(func(tx tasks.Tx) (err error) {
+			list, err = tx.List()
+			return err
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Execute(list, req))
+	})
+}
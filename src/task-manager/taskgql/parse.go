@@ -0,0 +1,495 @@
+package taskgql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The parser covers the executable part of the GraphQL grammar: operations
+// with variables, fields with aliases and arguments, fragments, inline
+// fragments and directives. Type definitions are not accepted.
+
+type document struct {
+	ops   []*operation
+	frags map[string]*fragment
+}
+
+type operation struct {
+	kind string // query, mutation or subscription
+	name string
+	vars []varDef
+	sel  []selection
+}
+
+type varDef struct {
+	name string
+	def  value // nil without a default
+}
+
+type fragment struct {
+	name, on string
+	sel      []selection
+}
+
+// selection is a field, a fragment spread (spread set) or an inline
+// fragment (inline set, its selections in sel).
+type selection struct {
+	alias, name string
+	args        map[string]value
+	dirs        []directive
+	sel         []selection
+	spread      string
+	inline      bool
+}
+
+// key is the field's name in the response.
+func (s selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type directive struct {
+	name string
+	args map[string]value
+}
+
+// value is a literal: nil, bool, int64, float64, string, enum, []value,
+// map[string]value, or a variable reference.
+type value any
+
+type (
+	enum     string
+	variable string
+)
+
+// Error is a GraphQL error, reported in the response's errors list.
+type Error struct {
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func errorf(format string, args ...any) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...)}
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind tokKind
+	val  string
+	pos  int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+func parse(src string) (doc *document, err error) {
+	p := &parser{src: src}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Error)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, e
+		}
+	}()
+	p.next()
+	doc = &document{frags: map[string]*fragment{}}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.is(tokPunct, "{"):
+			doc.ops = append(doc.ops, &operation{kind: "query", sel: p.selectionSet()})
+		case p.is(tokName, "query"), p.is(tokName, "mutation"), p.is(tokName, "subscription"):
+			doc.ops = append(doc.ops, p.operation())
+		case p.is(tokName, "fragment"):
+			f := p.fragment()
+			if _, dup := doc.frags[f.name]; dup {
+				p.fail("fragment %q is defined twice", f.name)
+			}
+			doc.frags[f.name] = f
+		default:
+			p.unexpected()
+		}
+	}
+	if len(doc.ops) == 0 {
+		return nil, errorf("the document has no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) operation() *operation {
+	op := &operation{kind: p.name()}
+	if p.tok.kind == tokName {
+		op.name = p.name()
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			p.expect("$")
+			v := varDef{name: p.name()}
+			p.expect(":")
+			p.typeRef()
+			if p.skip("=") {
+				v.def = p.value(true)
+			}
+			op.vars = append(op.vars, v)
+		}
+	}
+	p.directives()
+	op.sel = p.selectionSet()
+	return op
+}
+
+func (p *parser) fragment() *fragment {
+	p.name() // "fragment"
+	f := &fragment{name: p.name()}
+	if f.name == "on" {
+		p.fail("a fragment cannot be named \"on\"")
+	}
+	if !p.is(tokName, "on") {
+		p.unexpected()
+	}
+	p.name()
+	f.on = p.name()
+	p.directives()
+	f.sel = p.selectionSet()
+	return f
+}
+
+// typeRef parses and discards a variable's type; values are checked when
+// the arguments are read.
+func (p *parser) typeRef() {
+	if p.skip("[") {
+		p.typeRef()
+		p.expect("]")
+	} else {
+		p.name()
+	}
+	p.skip("!")
+}
+
+func (p *parser) selectionSet() []selection {
+	p.expect("{")
+	var sel []selection
+	for !p.skip("}") {
+		sel = append(sel, p.selection())
+	}
+	if len(sel) == 0 {
+		p.fail("empty selection set")
+	}
+	return sel
+}
+
+func (p *parser) selection() selection {
+	if p.skip("...") {
+		switch {
+		case p.is(tokName, "on"):
+			p.name()
+			p.name() // type condition; every selection here is on one type
+			return selection{inline: true, dirs: p.directives(), sel: p.selectionSet()}
+		case p.tok.kind == tokName:
+			return selection{spread: p.name(), dirs: p.directives()}
+		default:
+			return selection{inline: true, dirs: p.directives(), sel: p.selectionSet()}
+		}
+	}
+	s := selection{name: p.name()}
+	if p.skip(":") {
+		s.alias, s.name = s.name, p.name()
+	}
+	s.args = p.arguments(false)
+	s.dirs = p.directives()
+	if p.is(tokPunct, "{") {
+		s.sel = p.selectionSet()
+	}
+	return s
+}
+
+func (p *parser) arguments(constant bool) map[string]value {
+	if !p.skip("(") {
+		return nil
+	}
+	args := map[string]value{}
+	for !p.skip(")") {
+		name := p.name()
+		p.expect(":")
+		args[name] = p.value(constant)
+	}
+	return args
+}
+
+func (p *parser) directives() []directive {
+	var dirs []directive
+	for p.skip("@") {
+		dirs = append(dirs, directive{name: p.name(), args: p.arguments(false)})
+	}
+	return dirs
+}
+
+func (p *parser) value(constant bool) value {
+	t := p.tok
+	switch t.kind {
+	case tokPunct:
+		switch t.val {
+		case "$":
+			if constant {
+				p.fail("variables are not allowed in default values")
+			}
+			p.next()
+			return variable(p.name())
+		case "[":
+			p.next()
+			list := []value{}
+			for !p.skip("]") {
+				list = append(list, p.value(constant))
+			}
+			return list
+		case "{":
+			p.next()
+			obj := map[string]value{}
+			for !p.skip("}") {
+				name := p.name()
+				p.expect(":")
+				obj[name] = p.value(constant)
+			}
+			return obj
+		}
+	case tokInt:
+		p.next()
+		n, err := strconv.ParseInt(t.val, 10, 64)
+		if err != nil {
+			p.fail("invalid integer %s", t.val)
+		}
+		return n
+	case tokFloat:
+		p.next()
+		f, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			p.fail("invalid number %s", t.val)
+		}
+		return f
+	case tokString:
+		p.next()
+		return t.val
+	case tokName:
+		p.next()
+		switch t.val {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return enum(t.val)
+	}
+	p.unexpected()
+	return nil
+}
+
+func (p *parser) is(kind tokKind, val string) bool {
+	return p.tok.kind == kind && p.tok.val == val
+}
+
+// skip consumes the punctuator val if it is next.
+func (p *parser) skip(val string) bool {
+	if p.is(tokPunct, val) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(val string) {
+	if !p.skip(val) {
+		p.unexpected()
+	}
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokName {
+		p.unexpected()
+	}
+	name := p.tok.val
+	p.next()
+	return name
+}
+
+func (p *parser) unexpected() {
+	if p.tok.kind == tokEOF {
+		p.fail("unexpected end of document")
+	}
+	p.fail("unexpected %q", p.tok.val)
+}
+
+func (p *parser) fail(format string, args ...any) {
+	line := 1 + strings.Count(p.src[:p.tok.pos], "\n")
+	col := 1 + p.tok.pos - (strings.LastIndex(p.src[:p.tok.pos], "\n") + 1)
+	panic(errorf("syntax error at %d:%d: %s", line, col, fmt.Sprintf(format, args...)))
+}
+
+// next reads the next token into p.tok, skipping whitespace, commas and
+// comments.
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+			continue
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		case strings.HasPrefix(p.src[p.pos:], "\uFEFF"):
+			p.pos += len("\uFEFF")
+			continue
+		}
+		break
+	}
+	start := p.pos
+	p.tok = token{pos: start}
+	if p.pos >= len(p.src) {
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok.kind, p.tok.val = tokPunct, "..."
+	case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+		p.pos++
+		p.tok.kind, p.tok.val = tokPunct, string(c)
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for p.pos < len(p.src) && isNameByte(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok.kind, p.tok.val = tokName, p.src[start:p.pos]
+	case c == '-' || c >= '0' && c <= '9':
+		p.number()
+	case c == '"':
+		p.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.fail("unexpected character %q", r)
+	}
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (p *parser) number() {
+	start := p.pos
+	digits := func() int {
+		n := 0
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+			n++
+		}
+		return n
+	}
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	if digits() == 0 {
+		p.fail("invalid number")
+	}
+	kind := tokInt
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		kind = tokFloat
+		if digits() == 0 {
+			p.fail("invalid number")
+		}
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		p.pos++
+		kind = tokFloat
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		if digits() == 0 {
+			p.fail("invalid number")
+		}
+	}
+	p.tok.kind, p.tok.val = kind, p.src[start:p.pos]
+}
+
+func (p *parser) string() {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end < 0 {
+			p.fail("unterminated block string")
+		}
+		p.tok.kind, p.tok.val = tokString, strings.TrimSpace(p.src[p.pos+3:p.pos+3+end])
+		p.pos += 3 + end + 3
+		return
+	}
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.src) || p.src[p.pos] == '\n' {
+			p.fail("unterminated string")
+		}
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			p.tok.kind, p.tok.val = tokString, b.String()
+			return
+		case '\\':
+			if p.pos >= len(p.src) {
+				p.fail("unterminated string")
+			}
+			esc := p.src[p.pos]
+			p.pos++
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					p.fail("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					p.fail("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				p.fail("invalid escape \\%c", esc)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
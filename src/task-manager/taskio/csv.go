@@ -0,0 +1,288 @@
+package taskio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopatterns/task-manager/tasks"
+)
+
+var csvHeader = []string{
+	"id", "description", "status", "priority", "due", "project", "context",
+	"tags", "parent", "created_at", "completed_at", "assignee",
+}
+
+// ExportCSV writes one row per task with a header row. Timestamps are
+// RFC 3339 and tags are space-separated, so spreadsheets can split them.
+func ExportCSV(w io.Writer, list []tasks.Task, _ time.Time) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range list {
+		parent := ""
+		if t.Parent != 0 {
+			parent = strconv.Itoa(t.Parent)
+		}
+		row := []string{
+			strconv.Itoa(t.ID),
+			t.Description,
+			string(t.Status),
+			t.Priority.String(),
+			timestamp(t.Due),
+			t.Project,
+			t.Context,
+			strings.Join(t.Tags, " "),
+			parent,
+			t.CreatedAt.Format(time.RFC3339),
+			timestamp(t.CompletedAt),
+			t.Assignee,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// CSVFields returns the task fields a CSV column can be mapped to.
+func CSVFields() []string { return slices.Clone(csvHeader) }
+
+// CSVMapping maps task fields (the column names ExportCSV writes) to the
+// header of the column that holds them in a file to import.
+type CSVMapping map[string]string
+
+// csvSynonyms are headers other tools use for a field, tried when the
+// mapping does not name a column and none is named after the field.
+var csvSynonyms = map[string][]string{
+	"description":  {"title", "name", "summary", "task", "subject", "content"},
+	"status":       {"state", "done", "completed"},
+	"due":          {"due date", "due_date", "deadline", "due on"},
+	"project":      {"list", "section"},
+	"tags":         {"tag", "labels", "label", "categories"},
+	"created_at":   {"created", "created at", "entry", "date added"},
+	"completed_at": {"completed at", "done at", "finished", "end"},
+	"assignee":     {"assigned to", "owner", "responsible"},
+}
+
+// ImportCSV reads a CSV file with a header row, such as one ExportCSV
+// wrote, mapping columns by their headers.
+func ImportCSV(r io.Reader, now time.Time) ([]tasks.Task, error) {
+	return CSVImporter(nil)(r, now)
+}
+
+// CSVImporter returns an Importer for CSV files whose columns m maps to
+// task fields. Fields m leaves out are read from the column named after
+// the field, or a common synonym such as "title" for description, ignoring
+// case; only description is required. The delimiter (comma, semicolon or
+// tab) is detected from the header row. Dates may be RFC 3339 or
+// YYYY-MM-DD with an optional HH:MM[:SS]; a due date without a time means
+// the end of that day. tags may be separated by spaces, commas or
+// semicolons, and a parent column refers to the id column of another row.
+func CSVImporter(m CSVMapping) Importer {
+	return func(r io.Reader, now time.Time) ([]tasks.Task, error) {
+		for field := range m {
+			if !slices.Contains(csvHeader, field) {
+				return nil, fmt.Errorf("csv: cannot map to unknown field %q (want one of %s)", field, strings.Join(csvHeader, ", "))
+			}
+		}
+		br := bufio.NewReader(r)
+		first, _ := br.Peek(4096)
+		cr := csv.NewReader(br)
+		cr.Comma = sniffDelimiter(first)
+		cr.FieldsPerRecord = -1
+		header, err := cr.Read()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		cols, err := csvColumns(header, m)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			list    []tasks.Task
+			parents []string // parent column per task
+			pos     = map[string]int{}
+		)
+		for line := 2; ; line++ {
+			row, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("csv: %w", err)
+			}
+			get := func(field string) string {
+				if i, ok := cols[field]; ok && i < len(row) {
+					return strings.TrimSpace(row[i])
+				}
+				return ""
+			}
+			if slices.IndexFunc(row, func(s string) bool { return strings.TrimSpace(s) != "" }) < 0 {
+				continue
+			}
+			t, err := csvTask(get, now)
+			if err != nil {
+				return nil, fmt.Errorf("csv line %d: %w", line, err)
+			}
+			list = append(list, t)
+			parents = append(parents, get("parent"))
+			if id := get("id"); id != "" {
+				pos[id] = len(list)
+			}
+		}
+		for i, ref := range parents {
+			if ref == "" {
+				continue
+			}
+			p, ok := pos[ref]
+			if !ok {
+				return nil, fmt.Errorf("csv: task %q has unknown parent %q", list[i].Description, ref)
+			}
+			if ancestor(list, i+1, p) {
+				return nil, fmt.Errorf("csv: task %q is its own ancestor", list[i].Description)
+			}
+			list[i].Parent = p
+		}
+		return list, nil
+	}
+}
+
+// csvColumns finds the column index of every field present in the file.
+func csvColumns(header []string, m CSVMapping) (map[string]int, error) {
+	index := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\uFEFF")))
+		if _, dup := index[h]; !dup {
+			index[h] = i
+		}
+	}
+	cols := map[string]int{}
+	for _, field := range csvHeader {
+		if name, ok := m[field]; ok {
+			i, found := index[strings.ToLower(strings.TrimSpace(name))]
+			if !found {
+				return nil, fmt.Errorf("csv: no column %q for %s (columns: %s)", name, field, strings.Join(header, ", "))
+			}
+			cols[field] = i
+			continue
+		}
+		for _, name := range append([]string{field}, csvSynonyms[field]...) {
+			if i, found := index[name]; found {
+				cols[field] = i
+				break
+			}
+		}
+	}
+	if _, ok := cols["description"]; !ok {
+		return nil, fmt.Errorf("csv: no description column (columns: %s); map one with description=<column>", strings.Join(header, ", "))
+	}
+	return cols, nil
+}
+
+func csvTask(get func(string) string, now time.Time) (tasks.Task, error) {
+	t := tasks.Task{
+		Description: get("description"),
+		CreatedAt:   now,
+		Project:     get("project"),
+		Context:     tasks.NormalizeContext(get("context")),
+		Assignee:    get("assignee"),
+	}
+	if t.Description == "" {
+		return tasks.Task{}, fmt.Errorf("empty description")
+	}
+	switch status := strings.ToLower(get("status")); status {
+	case "", "pending", "open", "todo", "to do", "no", "false", "0", "needs-action":
+	case "started", "in progress", "in-progress", "doing", "active":
+		t.Start(now)
+	case "waiting", "blocked", "on hold":
+		t.Move(tasks.Waiting, now)
+	case "completed", "complete", "done", "closed", "x", "yes", "true", "1":
+		t.Complete(now)
+	case "cancelled", "canceled", "wontfix", "won't do":
+		t.Move(tasks.Cancelled, now)
+	default:
+		return tasks.Task{}, fmt.Errorf("unknown status %q", status)
+	}
+	var err error
+	if t.Priority, err = tasks.ParsePriority(get("priority")); err != nil {
+		return tasks.Task{}, err
+	}
+	if t.Due, err = csvTime(get("due"), true); err != nil {
+		return tasks.Task{}, fmt.Errorf("due: %w", err)
+	}
+	if created, err := csvTime(get("created_at"), false); err != nil {
+		return tasks.Task{}, fmt.Errorf("created_at: %w", err)
+	} else if created != nil {
+		t.CreatedAt = *created
+	}
+	if t.Closed() {
+		done, err := csvTime(get("completed_at"), false)
+		if err != nil {
+			return tasks.Task{}, fmt.Errorf("completed_at: %w", err)
+		}
+		if done != nil {
+			t.CompletedAt = done
+		}
+	}
+	t.Tags = tasks.NormalizeTags(strings.FieldsFunc(get("tags"), func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	}))
+	return t, nil
+}
+
+var csvTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"}
+
+// csvTime parses s, which may be empty. With endOfDay a bare date means
+// the last second of that day.
+func csvTime(s string, endOfDay bool) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	d, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q (want YYYY-MM-DD [HH:MM] or RFC 3339)", s)
+	}
+	if endOfDay {
+		d = d.Add(24*time.Hour - time.Second)
+	}
+	return &d, nil
+}
+
+// sniffDelimiter picks the most frequent of comma, semicolon and tab in
+// the first line of data, preferring comma.
+func sniffDelimiter(data []byte) rune {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	best, n := ',', bytes.Count(line, []byte(","))
+	for _, c := range []rune{';', '\t'} {
+		if k := bytes.Count(line, []byte(string(c))); k > n {
+			best, n = c, k
+		}
+	}
+	return best
+}
@@ -0,0 +1,198 @@
+package taskio
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// ExportHTML writes a self-contained report page: a summary, then a table
+// per project whose columns sort when their heading is clicked. The page
+// needs no network access, so it can be mailed or dropped on a file share
+// for people who don't use the command line.
+func ExportHTML(w io.Writer, list []tasks.Task, now time.Time) error {
+	return htmlTemplate.Execute(w, reportPage("Tasks", list, now))
+}
+
+// reportPage lays list out for htmlTemplate under title.
+func reportPage(title string, list []tasks.Task, now time.Time) htmlPage {
+	groups := map[string][]tasks.Task{}
+	for _, t := range list {
+		groups[t.Project] = append(groups[t.Project], t)
+	}
+	projects := make([]string, 0, len(groups))
+	for p := range groups {
+		projects = append(projects, p)
+	}
+	// Unassigned tasks ("") sort first.
+	sort.Strings(projects)
+
+	page := htmlPage{Title: title, Exported: now.Format("2006-01-02 15:04"), Total: len(list)}
+	for _, p := range projects {
+		group := groups[p]
+		tasks.SortByPriority(group)
+		sec := htmlSection{Name: p, Anchor: "project-" + htmlAnchor(p)}
+		if p == "" {
+			sec.Name, sec.Anchor = "No project", "no-project"
+		}
+		for _, n := range tasks.Tree(group) {
+			if n.Done() {
+				sec.Done++
+			}
+			sec.Rows = append(sec.Rows, htmlRow(n, now))
+		}
+		page.Sections = append(page.Sections, sec)
+		page.Done += sec.Done
+	}
+	return page
+}
+
+type htmlPage struct {
+	Title       string
+	Exported    string
+	Total, Done int
+	Sections    []htmlSection
+	JSON        string // a file holding the same tasks as JSON, if any
+}
+
+type htmlSection struct {
+	Name, Anchor string
+	Done         int
+	Rows         []htmlTaskRow
+}
+
+// htmlTaskRow is a task as the report shows it. The Sort fields are what
+// a column sorts by where its text would sort wrongly.
+type htmlTaskRow struct {
+	ID, Depth         int
+	Status, Class     string
+	Description       string
+	Priority          string
+	PrioritySort      int
+	Due, DueSort      string
+	Context, Assignee string
+	Tags              string
+	Notes             []string
+}
+
+func htmlRow(n tasks.Node, now time.Time) htmlTaskRow {
+	r := htmlTaskRow{
+		ID:           n.ID,
+		Depth:        n.Depth,
+		Status:       string(n.Status),
+		Class:        string(n.Status),
+		Description:  n.Description,
+		Priority:     n.Priority.String(),
+		PrioritySort: int(n.Priority),
+		Assignee:     n.Assignee,
+		Tags:         strings.Join(n.Tags, ", "),
+	}
+	if n.IsOverdue(now) {
+		r.Class += " overdue"
+	}
+	if n.Due != nil {
+		r.Due = n.Due.Format("2006-01-02")
+		r.DueSort = n.Due.UTC().Format(time.RFC3339)
+	}
+	if n.Context != "" {
+		r.Context = "@" + n.Context
+	}
+	for _, note := range n.Notes {
+		r.Notes = append(r.Notes, note.Text)
+	}
+	return r
+}
+
+// htmlAnchor turns a project name into a fragment identifier.
+func htmlAnchor(project string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(project) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			fmt.Fprintf(&b, "-%x", r)
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"indent": func(depth int) template.CSS {
+		return template.CSS(fmt.Sprintf("padding-left: %.1fem", 0.5+1.5*float64(depth)))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2em auto; max-width: 72em; padding: 0 1em; color: #222; }
+h1 { margin-bottom: 0; }
+.meta { color: #666; margin-top: .2em; }
+nav a { margin-right: 1em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: .3em .5em; border-bottom: 1px solid #ddd; vertical-align: top; }
+th { cursor: pointer; user-select: none; background: #f4f4f4; white-space: nowrap; }
+th[aria-sort=ascending]::after { content: " \25B2"; }
+th[aria-sort=descending]::after { content: " \25BC"; }
+tr.done td, tr.cancelled td { color: #888; }
+tr.done .desc, tr.cancelled .desc { text-decoration: line-through; }
+tr.in-progress .status { color: #06c; font-weight: bold; }
+tr.waiting .status { color: #b60; }
+tr.overdue .due { color: #c00; font-weight: bold; }
+.notes { margin: .2em 0 0; padding-left: 1.2em; color: #555; font-size: 90%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Exported {{.Exported}} &middot; {{.Done}} of {{.Total}} completed
+{{- if .JSON}} &middot; <a href="{{.JSON}}">JSON</a>{{end}}</p>
+{{- if gt (len .Sections) 1}}
+<nav>{{range .Sections}}<a href="#{{.Anchor}}">{{.Name}}</a>{{end}}</nav>
+{{- end}}
+{{- range .Sections}}
+<h2 id="{{.Anchor}}">{{.Name}} <small class="meta">{{.Done}} of {{len .Rows}} completed</small></h2>
+<table>
+<thead><tr><th data-type="number">ID</th><th>Status</th><th data-type="number">Priority</th><th>Due</th><th>Description</th><th>Context</th><th>Assignee</th><th>Tags</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr class="{{.Class}}"><td>{{.ID}}</td><td class="status">{{.Status}}</td><td data-sort="{{.PrioritySort}}">{{.Priority}}</td><td class="due" data-sort="{{.DueSort}}">{{.Due}}</td><td class="desc" style="{{indent .Depth}}">{{.Description}}
+{{- if .Notes}}<ul class="notes">{{range .Notes}}<li>{{.}}</li>{{end}}</ul>{{end}}</td><td>{{.Context}}</td><td>{{.Assignee}}</td><td>{{.Tags}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+<script>
+// Clicking a heading sorts its table by that column; clicking again
+// reverses the order. Empty cells always sort last.
+document.querySelectorAll("th").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var table = th.closest("table"), col = th.cellIndex;
+    var numeric = th.dataset.type === "number";
+    var dir = th.getAttribute("aria-sort") === "ascending" ? -1 : 1;
+    table.querySelectorAll("th").forEach(function (h) { h.removeAttribute("aria-sort"); });
+    th.setAttribute("aria-sort", dir > 0 ? "ascending" : "descending");
+    var key = function (tr) {
+      var td = tr.cells[col];
+      return td.dataset.sort !== undefined ? td.dataset.sort : td.textContent.trim();
+    };
+    var body = table.tBodies[0];
+    Array.from(body.rows).sort(function (a, b) {
+      var x = key(a), y = key(b);
+      if (x === "" || y === "") return (x === "") - (y === "");
+      if (numeric) return dir * (Number(x) - Number(y));
+      return dir * x.localeCompare(y);
+    }).forEach(function (tr) { body.appendChild(tr); });
+  });
+});
+</script>
+</body>
+</html>
+`))
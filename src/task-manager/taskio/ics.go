@@ -0,0 +1,148 @@
+package taskio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// iCalendar (RFC 5545) export. Tasks without a due date are skipped. The
+// "ics" format writes open tasks as VEVENTs, which every calendar app
+// shows; "ics-todo" writes VTODOs, which task-aware clients (Apple
+// Reminders, Thunderbird) import as to-dos with status and priority.
+
+const (
+	icsDate     = "20060102"
+	icsDateTime = "20060102T150405Z"
+)
+
+// ExportICSEvents writes one VEVENT per open task with a due date. A
+// date-only due becomes an all-day event.
+func ExportICSEvents(w io.Writer, list []tasks.Task, now time.Time) error {
+	return writeCalendar(w, list, now, false)
+}
+
+// ExportICSTodos writes one VTODO per task with a due date, completed
+// ones included.
+func ExportICSTodos(w io.Writer, list []tasks.Task, now time.Time) error {
+	return writeCalendar(w, list, now, true)
+}
+
+func writeCalendar(w io.Writer, list []tasks.Task, now time.Time, todos bool) error {
+	cw := &icsWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//gopatterns//task//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	stamp := now.UTC().Format(icsDateTime)
+	for _, t := range list {
+		if t.Due == nil || (!todos && t.Closed()) {
+			continue
+		}
+		if todos {
+			cw.line("BEGIN:VTODO")
+		} else {
+			cw.line("BEGIN:VEVENT")
+		}
+		cw.line(fmt.Sprintf("UID:task-%d-%d@gopatterns", t.ID, t.CreatedAt.Unix()))
+		cw.line("DTSTAMP:" + stamp)
+		cw.line("SUMMARY:" + icsEscape(t.Description))
+		if t.Project != "" {
+			cw.line("DESCRIPTION:" + icsEscape("Project: "+t.Project))
+		}
+		if len(t.Tags) > 0 {
+			escaped := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				escaped[i] = icsEscape(tag)
+			}
+			cw.line("CATEGORIES:" + strings.Join(escaped, ","))
+		}
+		due := t.Due.Local()
+		allDay := isEndOfDay(due)
+		if todos {
+			if allDay {
+				cw.line("DUE;VALUE=DATE:" + due.Format(icsDate))
+			} else {
+				cw.line("DUE:" + t.Due.UTC().Format(icsDateTime))
+			}
+			if p := icsPriority(t.Priority); p != 0 {
+				cw.line(fmt.Sprintf("PRIORITY:%d", p))
+			}
+			switch t.Status {
+			case tasks.Done:
+				cw.line("STATUS:COMPLETED")
+				if t.CompletedAt != nil {
+					cw.line("COMPLETED:" + t.CompletedAt.UTC().Format(icsDateTime))
+				}
+			case tasks.Cancelled:
+				cw.line("STATUS:CANCELLED")
+			case tasks.InProgress:
+				cw.line("STATUS:IN-PROCESS")
+			default:
+				cw.line("STATUS:NEEDS-ACTION")
+			}
+			cw.line("END:VTODO")
+			continue
+		}
+		if allDay {
+			cw.line("DTSTART;VALUE=DATE:" + due.Format(icsDate))
+			cw.line("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format(icsDate))
+		} else {
+			cw.line("DTSTART:" + t.Due.UTC().Format(icsDateTime))
+			cw.line("DTEND:" + t.Due.UTC().Format(icsDateTime))
+		}
+		cw.line("END:VEVENT")
+	}
+	cw.line("END:VCALENDAR")
+	return cw.w.Flush()
+}
+
+// icsWriter emits CRLF-terminated content lines folded at 75 octets, as
+// RFC 5545 section 3.1 requires.
+type icsWriter struct {
+	w *bufio.Writer
+}
+
+func (cw *icsWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		// Never split a UTF-8 sequence.
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		cw.w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	cw.w.WriteString(s + "\r\n")
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func icsEscape(s string) string { return icsEscaper.Replace(s) }
+
+// isEndOfDay reports whether t is 23:59:59 on its clock, which is how a
+// date-only due date is stored.
+func isEndOfDay(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h == 23 && m == 59 && s == 59
+}
+
+// icsPriority maps to the iCalendar scale, where 1 is highest, 9 lowest and
+// 0 undefined.
+func icsPriority(p tasks.Priority) int {
+	switch {
+	case p >= tasks.PriorityHigh:
+		return 1
+	case p == tasks.PriorityMedium:
+		return 5
+	case p == tasks.PriorityLow:
+		return 9
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package taskio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// ExportMarkdown writes a checklist document with one section per project,
+// subtasks nested under their parents, ready to paste into a wiki or PR.
+func ExportMarkdown(w io.Writer, list []tasks.Task, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Tasks\n\n_Exported %s._\n", now.Format("2006-01-02 15:04"))
+
+	groups := map[string][]tasks.Task{}
+	for _, t := range list {
+		groups[t.Project] = append(groups[t.Project], t)
+	}
+	projects := make([]string, 0, len(groups))
+	for p := range groups {
+		projects = append(projects, p)
+	}
+	// Unassigned tasks ("") sort first.
+	sort.Strings(projects)
+
+	for _, p := range projects {
+		heading := p
+		if heading == "" {
+			heading = "No project"
+		}
+		fmt.Fprintf(bw, "\n## %s\n\n", heading)
+		group := groups[p]
+		tasks.SortByPriority(group)
+		for _, n := range tasks.Tree(group) {
+			box := " "
+			if n.Closed() {
+				box = "x"
+			}
+			fmt.Fprintf(bw, "%s- [%s] %s%s\n", strings.Repeat("  ", n.Depth), box, markdownEscape(n.Description), markdownDetails(n.Task))
+		}
+	}
+	return bw.Flush()
+}
+
+// markdownDetails renders due date, priority, context and tags after the
+// description.
+func markdownDetails(t tasks.Task) string {
+	var meta []string
+	if t.Due != nil {
+		meta = append(meta, "due "+t.Due.Format("2006-01-02"))
+	}
+	if p := t.Priority.String(); p != "" {
+		meta = append(meta, "priority "+p)
+	}
+	var b strings.Builder
+	if len(meta) > 0 {
+		fmt.Fprintf(&b, " _(%s)_", strings.Join(meta, ", "))
+	}
+	if t.Context != "" {
+		fmt.Fprintf(&b, " `@%s`", t.Context)
+	}
+	for _, tag := range t.Tags {
+		fmt.Fprintf(&b, " `#%s`", tag)
+	}
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`,
+)
+
+// markdownEscape keeps descriptions from being read as markup.
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,97 @@
+package taskio
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Share is a read-only view of some tasks for people who only want to see
+// where things stand, as 'task share' publishes it. It holds what a
+// stakeholder needs and nothing private to the list: no attachments, whose
+// paths are local, no sync metadata and, unless asked for, no notes.
+type Share struct {
+	Title     string       `json:"title"`
+	Generated time.Time    `json:"generated"`
+	Total     int          `json:"total"`
+	Done      int          `json:"done"`
+	Tasks     []SharedTask `json:"tasks"`
+
+	list []tasks.Task // the tasks as shared, for the HTML page
+}
+
+// SharedTask is a task as a Share shows it.
+type SharedTask struct {
+	ID          int          `json:"id"`
+	Parent      int          `json:"parent,omitempty"`
+	Description string       `json:"description"`
+	Status      tasks.Status `json:"status"`
+	Priority    string       `json:"priority,omitempty"`
+	Due         *time.Time   `json:"due,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	Project     string       `json:"project,omitempty"`
+	Assignee    string       `json:"assignee,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Notes       []string     `json:"notes,omitempty"`
+}
+
+// NewShare builds the share of list titled title, with the tasks' notes
+// if notes is set. Subtasks whose parent is not shared become top-level.
+func NewShare(title string, list []tasks.Task, now time.Time, notes bool) *Share {
+	s := &Share{Title: title, Generated: now, Total: len(list), Tasks: []SharedTask{}}
+	shared := make(map[int]bool, len(list))
+	for _, t := range list {
+		shared[t.ID] = true
+	}
+	for _, t := range list {
+		if t.Done() {
+			s.Done++
+		}
+		st := SharedTask{
+			ID:          t.ID,
+			Description: t.Description,
+			Status:      t.Status,
+			Priority:    t.Priority.String(),
+			Due:         t.Due,
+			CompletedAt: t.CompletedAt,
+			Project:     t.Project,
+			Assignee:    t.Assignee,
+			Tags:        t.Tags,
+		}
+		if shared[t.Parent] {
+			st.Parent = t.Parent
+		}
+		shown := tasks.Task{
+			ID: st.ID, Parent: st.Parent, Description: t.Description, Status: t.Status,
+			Priority: t.Priority, Due: t.Due, CompletedAt: t.CompletedAt,
+			Project: t.Project, Assignee: t.Assignee, Tags: t.Tags,
+		}
+		if notes {
+			shown.Notes = t.Notes
+			for _, n := range t.Notes {
+				st.Notes = append(st.Notes, n.Text)
+			}
+		}
+		s.Tasks = append(s.Tasks, st)
+		s.list = append(s.list, shown)
+	}
+	return s
+}
+
+// WriteJSON writes s as indented JSON.
+func (s *Share) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteHTML writes s as a report page like ExportHTML's, linking to
+// jsonName, the file WriteJSON's output is published under, unless it is
+// empty.
+func (s *Share) WriteHTML(w io.Writer, jsonName string) error {
+	page := reportPage(s.Title, s.list, s.Generated)
+	page.JSON = jsonName
+	return htmlTemplate.Execute(w, page)
+}
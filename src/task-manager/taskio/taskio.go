@@ -0,0 +1,88 @@
+// Package taskio converts task lists to and from the file formats other
+// tools understand.
+package taskio
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Exporter writes list to w in one format. now is the export time, for
+// formats that stamp their output.
+type Exporter func(w io.Writer, list []tasks.Task, now time.Time) error
+
+var exporters = map[string]Exporter{
+	"csv":      ExportCSV,
+	"html":     ExportHTML,
+	"ics":      ExportICSEvents,
+	"ics-todo": ExportICSTodos,
+	"markdown": ExportMarkdown,
+	"todotxt":  ExportTodoTxt,
+}
+
+// Export writes list to w in the named format.
+func Export(w io.Writer, format string, list []tasks.Task, now time.Time) error {
+	fn, ok := exporters[format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q (want one of %v)", format, ExportFormats())
+	}
+	return fn(w, list, now)
+}
+
+// Importer parses tasks from r. The returned tasks have no IDs; the caller
+// assigns them when storing, and maps a non-zero Parent, which holds the
+// 1-based position of the parent within the returned slice, to the
+// parent's new ID. now is used for fields the format leaves out, such as
+// creation time.
+type Importer func(r io.Reader, now time.Time) ([]tasks.Task, error)
+
+var importers = map[string]Importer{
+	"csv":         ImportCSV,
+	"taskwarrior": ImportTaskwarrior,
+	"todotxt":     ImportTodoTxt,
+}
+
+// Import parses r in the named format.
+func Import(r io.Reader, format string, now time.Time) ([]tasks.Task, error) {
+	fn, ok := importers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q (want one of %v)", format, ImportFormats())
+	}
+	return fn(r, now)
+}
+
+// ImportFormats lists the format names Import accepts.
+func ImportFormats() []string { return sortedKeys(importers) }
+
+// ExportFormats lists the format names Export accepts.
+func ExportFormats() []string { return sortedKeys(exporters) }
+
+func sortedKeys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DetectFormat guesses an import format from a file name, returning "" if
+// it cannot tell.
+func DetectFormat(name string) string {
+	base := strings.ToLower(filepath.Base(name))
+	switch {
+	case strings.HasSuffix(base, ".txt"):
+		return "todotxt"
+	case strings.HasSuffix(base, ".json"):
+		return "taskwarrior"
+	case strings.HasSuffix(base, ".csv"), strings.HasSuffix(base, ".tsv"):
+		return "csv"
+	}
+	return ""
+}
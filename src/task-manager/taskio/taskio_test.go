@@ -0,0 +1,349 @@
+package taskio
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"gopatterns/task-manager/tasks"
+)
+
+var now = time.Date(2025, 3, 5, 10, 30, 0, 0, time.Local)
+
+func date(y int, m time.Month, d int) *time.Time {
+	t := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return &t
+}
+
+func endOf(y int, m time.Month, d int) *time.Time {
+	t := time.Date(y, m, d, 23, 59, 59, 0, time.Local)
+	return &t
+}
+
+// summary is the part of a task the formats carry, for comparing.
+type summary struct {
+	Description string
+	Status      tasks.Status
+	Priority    tasks.Priority
+	Project     string
+	Context     string
+	Tags        string
+	Due         string
+	Completed   string
+	Parent      int
+}
+
+func summarize(t tasks.Task) summary {
+	day := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return summary{
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		Project:     t.Project,
+		Context:     t.Context,
+		Tags:        strings.Join(t.Tags, " "),
+		Due:         day(t.Due),
+		Completed:   day(t.CompletedAt),
+		Parent:      t.Parent,
+	}
+}
+
+func TestImportTodoTxt(t *testing.T) {
+	tests := []struct {
+		line string
+		want summary
+	}{
+		{"Buy milk", summary{Description: "Buy milk"}},
+		{"(A) 2025-03-01 Call mom +family @phone due:2025-03-07", summary{
+			Description: "Call mom", Priority: tasks.PriorityHigh, Project: "family", Context: "phone", Due: "2025-03-07 23:59:59",
+		}},
+		{"(B) Plan trip +travel +summer @home @laptop tag:fun", summary{
+			Description: "Plan trip", Priority: tasks.PriorityMedium, Project: "travel", Context: "home", Tags: "fun laptop summer",
+		}},
+		{"(Z) Someday", summary{Description: "Someday", Priority: tasks.PriorityLow}},
+		{"x 2025-03-02 2025-03-01 Pay rent pri:A", summary{
+			Description: "Pay rent", Status: tasks.Done, Priority: tasks.PriorityHigh, Completed: "2025-03-02 00:00:00",
+		}},
+		{"x Water plants", summary{Description: "Water plants", Status: tasks.Done, Completed: "2025-03-05 10:30:00"}},
+		{"xylophone lesson", summary{Description: "xylophone lesson"}},
+		{"Email + notes @", summary{Description: "Email + notes @"}},
+	}
+	for _, tt := range tests {
+		list, err := ImportTodoTxt(strings.NewReader(tt.line+"\n"), now)
+		if err != nil {
+			t.Errorf("ImportTodoTxt(%q): %v", tt.line, err)
+			continue
+		}
+		if len(list) != 1 {
+			t.Errorf("ImportTodoTxt(%q) = %d tasks, want 1", tt.line, len(list))
+			continue
+		}
+		if got := summarize(list[0]); got != tt.want {
+			t.Errorf("ImportTodoTxt(%q) =\n%+v, want\n%+v", tt.line, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"(A) 2025-03-01", "Call mom due:friday", "ok\n\nx"} {
+		if _, err := ImportTodoTxt(strings.NewReader(in), now); err == nil {
+			t.Errorf("ImportTodoTxt(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestExportTodoTxt(t *testing.T) {
+	list := []tasks.Task{
+		{Description: "Call  mom", Priority: tasks.PriorityHigh, CreatedAt: *date(2025, 3, 1), Project: "family", Context: "phone", Due: endOf(2025, 3, 7), Tags: []string{"weekly"}},
+		{Description: "Pay rent", Status: tasks.Done, CompletedAt: date(2025, 3, 2), CreatedAt: *date(2025, 3, 1), Priority: 7},
+		{Description: "Buy milk", Status: tasks.Cancelled},
+	}
+	want := "(A) 2025-03-01 Call mom +family @phone due:2025-03-07 tag:weekly\n" +
+		"x 2025-03-02 2025-03-01 Pay rent pri:A\n" +
+		"x Buy milk\n"
+	var buf bytes.Buffer
+	if err := ExportTodoTxt(&buf, list, now); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Fatalf("ExportTodoTxt =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	back, err := ImportTodoTxt(&buf, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := summarize(back[0]), summarize(list[0]); got.Description != "Call mom" || got.Due != want.Due || got.Tags != want.Tags || got.Priority != want.Priority {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+	if got := back[1]; !got.Done() || got.Priority != tasks.PriorityHigh {
+		t.Errorf("round trip of a done task = %+v", summarize(got))
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	in := "\uFEFFid;Title;State;Priority;Deadline;List;Context;Labels;Parent;Created;Completed at;Owner\n" +
+		"1;Move house;open;high;2025-04-01;home;@town;big, family;;2025-03-01 08:00;;ana\n" +
+		"2;Pack books;done;1;2025-03-20 18:00;home;;;1;;2025-03-04T12:00:00Z;\n" +
+		";;;;;;;;;;;\n" +
+		"3;Book van;in progress;;;;;;1;;;\n" +
+		"4;Call landlord;wontfix;;;;;;;;;\n"
+	list, err := ImportCSV(strings.NewReader(in), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC).Local().Format("2006-01-02 15:04:05")
+	want := []summary{
+		{Description: "Move house", Priority: tasks.PriorityHigh, Project: "home", Context: "town", Tags: "big family", Due: "2025-04-01 23:59:59"},
+		{Description: "Pack books", Status: tasks.Done, Priority: tasks.PriorityLow, Project: "home", Due: "2025-03-20 18:00:00", Completed: completed, Parent: 1},
+		{Description: "Book van", Status: tasks.InProgress, Parent: 1},
+		{Description: "Call landlord", Status: tasks.Cancelled, Completed: "2025-03-05 10:30:00"},
+	}
+	var got []summary
+	for _, t := range list {
+		got = append(got, summarize(t))
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ImportCSV =\n%+v\nwant\n%+v", got, want)
+	}
+	if list[0].Assignee != "ana" || !list[0].CreatedAt.Equal(time.Date(2025, 3, 1, 8, 0, 0, 0, time.Local)) {
+		t.Errorf("ImportCSV lost the assignee or creation time: %+v", list[0])
+	}
+}
+
+func TestCSVImporterMapping(t *testing.T) {
+	in := "Summary,What,Bucket\nignored,Buy milk,groceries\n"
+	list, err := CSVImporter(CSVMapping{"description": "what", "project": "Bucket"})(strings.NewReader(in), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Description != "Buy milk" || list[0].Project != "groceries" {
+		t.Fatalf("mapped import = %+v", list)
+	}
+
+	tests := []struct {
+		mapping CSVMapping
+		in      string
+	}{
+		{nil, "id,notes\n1,x\n"},
+		{nil, "description,status\nx,someday\n"},
+		{nil, "description,priority\nx,urgent\n"},
+		{nil, "description,due\nx,next week\n"},
+		{nil, "id,description,parent\n1,x,2\n"},
+		{nil, "id,description,parent\n1,x,2\n2,y,1\n"},
+		{nil, "description\n\"unterminated\n"},
+		{CSVMapping{"colour": "c"}, "description\nx\n"},
+		{CSVMapping{"description": "what"}, "description\nx\n"},
+	}
+	for _, tt := range tests {
+		if _, err := CSVImporter(tt.mapping)(strings.NewReader(tt.in), now); err == nil {
+			t.Errorf("import of %q with %v succeeded, want an error", tt.in, tt.mapping)
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	list := []tasks.Task{
+		{ID: 1, Description: "Move house, finally", Priority: tasks.PriorityHigh, Due: endOf(2025, 4, 1), Project: "home", Tags: []string{"big", "family"}, CreatedAt: *date(2025, 3, 1), Assignee: "ana"},
+		{ID: 2, Description: `Pack "books"`, Status: tasks.Done, Parent: 1, CreatedAt: *date(2025, 3, 1), CompletedAt: date(2025, 3, 4)},
+	}
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, list, now); err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(buf.String(), "\n"); header != strings.Join(CSVFields(), ",") {
+		t.Errorf("header = %q", header)
+	}
+	back, err := ImportCSV(&buf, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back) != len(list) {
+		t.Fatalf("round trip gave %d tasks, want %d", len(back), len(list))
+	}
+	for i := range list {
+		if got, want := summarize(back[i]), summarize(list[i]); got != want {
+			t.Errorf("round trip of task %d =\n%+v, want\n%+v", i+1, got, want)
+		}
+		if !back[i].CreatedAt.Equal(list[i].CreatedAt) || back[i].Assignee != list[i].Assignee {
+			t.Errorf("round trip of task %d lost the creation time or assignee", i+1)
+		}
+	}
+}
+
+func TestExportICS(t *testing.T) {
+	long := strings.Repeat("é", 50)
+	list := []tasks.Task{
+		{ID: 1, Description: "Dentist; bring card", Due: date(2025, 3, 7), CreatedAt: *date(2025, 3, 1), Priority: tasks.PriorityHigh, Tags: []string{"health", "a,b"}},
+		{ID: 2, Description: "Pay rent", Due: endOf(2025, 4, 1), CreatedAt: *date(2025, 3, 1), Project: "home"},
+		{ID: 3, Description: "No date", CreatedAt: *date(2025, 3, 1)},
+		{ID: 4, Description: "Done already", Due: endOf(2025, 3, 1), Status: tasks.Done, CompletedAt: date(2025, 3, 1), CreatedAt: *date(2025, 3, 1)},
+		{ID: 5, Description: long, Due: endOf(2025, 3, 9), Status: tasks.InProgress, CreatedAt: *date(2025, 3, 1)},
+	}
+	utc := func(t *time.Time) string { return t.UTC().Format(icsDateTime) }
+
+	tests := []struct {
+		name    string
+		export  Exporter
+		want    []string
+		without []string
+	}{
+		{"events", ExportICSEvents, []string{
+			"BEGIN:VEVENT\r\nUID:task-1-",
+			"SUMMARY:Dentist\\; bring card\r\n",
+			"CATEGORIES:health,a\\,b\r\n",
+			"DTSTART:" + utc(list[0].Due) + "\r\nDTEND:" + utc(list[0].Due) + "\r\n",
+			"DESCRIPTION:Project: home\r\n",
+			"DTSTART;VALUE=DATE:20250401\r\nDTEND;VALUE=DATE:20250402\r\n",
+		}, []string{"No date", "Done already", "VTODO"}},
+		{"todos", ExportICSTodos, []string{
+			"BEGIN:VTODO\r\nUID:task-1-",
+			"DUE:" + utc(list[0].Due) + "\r\nPRIORITY:1\r\nSTATUS:NEEDS-ACTION\r\n",
+			"DUE;VALUE=DATE:20250401\r\nSTATUS:NEEDS-ACTION\r\n",
+			"SUMMARY:Done already\r\n",
+			"STATUS:COMPLETED\r\nCOMPLETED:" + utc(list[3].CompletedAt) + "\r\n",
+			"STATUS:IN-PROCESS\r\n",
+		}, []string{"No date", "VEVENT"}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.export(&buf, list, now); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+			t.Errorf("%s: not a calendar:\n%s", tt.name, out)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(out, s) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, s, out)
+			}
+		}
+		for _, s := range tt.without {
+			if strings.Contains(out, s) {
+				t.Errorf("%s: output has %q:\n%s", tt.name, s, out)
+			}
+		}
+		// Long lines are folded at 75 octets without splitting characters.
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			if len(line) > 75 || !utf8.ValidString(line) {
+				t.Errorf("%s: line of %d octets: %q", tt.name, len(line), line)
+			}
+		}
+		if unfolded := strings.ReplaceAll(out, "\r\n ", ""); !strings.Contains(unfolded, "SUMMARY:"+long+"\r\n") {
+			t.Errorf("%s: the long summary does not unfold to the original", tt.name)
+		}
+	}
+}
+
+func TestImportTaskwarrior(t *testing.T) {
+	array := `[
+{"uuid":"a1","description":"Move house","status":"pending","entry":"20250301T080000Z","due":"20250401T120000Z","priority":"H","project":"home","tags":["big"],
+ "annotations":[{"entry":"20250302T090000Z","description":"ask Ana"}]},
+{"uuid":"b2","description":"Pack books","status":"completed","entry":"20250301T080000Z","end":"20250304T120000Z","start":"20250303T120000Z","depends":"a1"},
+{"uuid":"c3","description":"Book van","status":"pending","start":"20250303T120000Z","priority":"M","depends":["a1","b2"]},
+{"uuid":"d4","description":"Old","status":"deleted"},
+{"uuid":"e5","description":"Weekly review","status":"recurring"},
+{"uuid":"f6","description":"  Sell sofa ","status":"waiting","wait":"20250310T000000Z","priority":"X"}
+]`
+	lines := strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(array, "[\n"), "\n]"), "\n ", " ")
+	tw := func(s string) string { t, _ := time.Parse(twLayout, s); return t.Local().Format("2006-01-02 15:04:05") }
+
+	want := []summary{
+		{Description: "Move house", Status: tasks.Pending, Priority: tasks.PriorityHigh, Project: "home", Tags: "big", Due: tw("20250401T120000Z"), Parent: 2},
+		{Description: "Pack books", Status: tasks.Done, Completed: tw("20250304T120000Z"), Parent: 3},
+		{Description: "Book van", Status: tasks.InProgress, Priority: tasks.PriorityMedium},
+		{Description: "Sell sofa", Status: tasks.Pending},
+	}
+	for name, in := range map[string]string{"array": array, "lines": lines} {
+		list, err := ImportTaskwarrior(strings.NewReader(in), now)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		var got []summary
+		for _, t := range list {
+			got = append(got, summarize(t))
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: ImportTaskwarrior =\n%+v\nwant\n%+v", name, got, want)
+			continue
+		}
+		if list[0].UUID != "a1" || len(list[0].Notes) != 1 || list[0].Notes[0].Text != "ask Ana" {
+			t.Errorf("%s: lost the UUID or annotations: %+v", name, list[0])
+		}
+		if list[3].Wait == nil || list[1].StartedAt != nil || list[2].StartedAt == nil {
+			t.Errorf("%s: wrong wait or start times", name)
+		}
+	}
+
+	for _, in := range []string{`[{"description":"x","due":"tomorrow"}]`, `{"description":"x"}` + "\n" + `{oops}`, `[{]`} {
+		if _, err := ImportTaskwarrior(strings.NewReader(in), now); err == nil {
+			t.Errorf("ImportTaskwarrior(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"todo.txt":         "todotxt",
+		"/tmp/Export.JSON": "taskwarrior",
+		"list.csv":         "csv",
+		"list.tsv":         "csv",
+		"calendar.ics":     "",
+		"README":           "",
+		"archive.json.gz":  "",
+		"dir.txt/notes.md": "",
+	}
+	for name, want := range tests {
+		if got := DetectFormat(name); got != want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
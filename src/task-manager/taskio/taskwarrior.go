@@ -0,0 +1,186 @@
+package taskio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// twTask is a task as written by `task export` in Taskwarrior 2.x and 3.x.
+type twTask struct {
+	UUID        string         `json:"uuid"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Entry       twTime         `json:"entry"`
+	Start       twTime         `json:"start"`
+	End         twTime         `json:"end"`
+	Due         twTime         `json:"due"`
+	Wait        twTime         `json:"wait"`
+	Priority    string         `json:"priority"`
+	Project     string         `json:"project"`
+	Tags        []string       `json:"tags"`
+	Annotations []twAnnotation `json:"annotations"`
+	Depends     twDepends      `json:"depends"`
+}
+
+type twAnnotation struct {
+	Entry       twTime `json:"entry"`
+	Description string `json:"description"`
+}
+
+// twTime is Taskwarrior's compact UTC timestamp, 20240131T170000Z.
+type twTime struct{ t *time.Time }
+
+const twLayout = "20060102T150405Z"
+
+func (tt *twTime) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(twLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid Taskwarrior date %q", s)
+	}
+	t = t.Local()
+	tt.t = &t
+	return nil
+}
+
+// twDepends accepts both forms of the depends attribute: a JSON array of
+// UUIDs (2.6 and later) and a comma-separated string (earlier versions).
+type twDepends []string
+
+func (d *twDepends) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*d = strings.FieldsFunc(s, func(r rune) bool { return r == ',' })
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(d))
+}
+
+// ImportTaskwarrior parses the output of Taskwarrior's `task export`: a
+// JSON array, or one JSON object per line as older versions wrote. UUIDs,
+// tags, annotations (as notes), start, wait and due dates carry over;
+// deleted tasks and recurrence templates are skipped. Taskwarrior
+// dependencies become subtasks: a task that others depend on is placed
+// under the first of them, since it must be done before they can be. The
+// returned tasks' Parent fields hold such links as 1-based positions in the
+// returned slice.
+func ImportTaskwarrior(r io.Reader, now time.Time) ([]tasks.Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var raw []twTask
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, err
+		}
+	} else {
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		sc.Buffer(nil, 1<<20)
+		for line := 1; sc.Scan(); line++ {
+			text := strings.TrimSuffix(strings.TrimSpace(sc.Text()), ",")
+			if text == "" {
+				continue
+			}
+			var tw twTask
+			if err := json.Unmarshal([]byte(text), &tw); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			raw = append(raw, tw)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var list []tasks.Task
+	var depends [][]string
+	pos := map[string]int{} // UUID -> 1-based position in list
+	for _, tw := range raw {
+		if tw.Status == "deleted" || tw.Status == "recurring" {
+			continue
+		}
+		t := tasks.Task{
+			UUID:        tw.UUID,
+			Description: strings.TrimSpace(tw.Description),
+			CreatedAt:   now,
+			CompletedAt: tw.End.t,
+			StartedAt:   tw.Start.t,
+			Due:         tw.Due.t,
+			Wait:        tw.Wait.t,
+			Priority:    twPriority(tw.Priority),
+			Tags:        tasks.NormalizeTags(tw.Tags),
+			Project:     tw.Project,
+		}
+		if tw.Entry.t != nil {
+			t.CreatedAt = *tw.Entry.t
+		}
+		switch {
+		case tw.Status == "completed":
+			t.Status = tasks.Done
+			t.StartedAt = nil
+			if t.CompletedAt == nil {
+				t.CompletedAt = &now
+			}
+		case t.StartedAt != nil:
+			t.Status = tasks.InProgress
+			t.CompletedAt = nil
+		default:
+			t.Status = tasks.Pending
+			t.CompletedAt = nil
+		}
+		for _, a := range tw.Annotations {
+			when := now
+			if a.Entry.t != nil {
+				when = *a.Entry.t
+			}
+			t.Annotate(when, a.Description)
+		}
+		list = append(list, t)
+		depends = append(depends, tw.Depends)
+		if tw.UUID != "" {
+			pos[tw.UUID] = len(list)
+		}
+	}
+
+	for i, deps := range depends {
+		for _, uuid := range deps {
+			child, ok := pos[uuid]
+			if !ok || list[child-1].Parent != 0 || ancestor(list, child, i+1) {
+				continue
+			}
+			list[child-1].Parent = i + 1
+		}
+	}
+	return list, nil
+}
+
+// twPriority maps H, M and L; Taskwarrior has no other levels by default.
+func twPriority(s string) tasks.Priority {
+	p, err := tasks.ParsePriority(s)
+	if err != nil {
+		return tasks.PriorityNone
+	}
+	return p
+}
+
+// ancestor reports whether position a is pos or one of its ancestors, so
+// that linking a under pos would make a cycle.
+func ancestor(list []tasks.Task, a, pos int) bool {
+	for p, n := pos, 0; p != 0 && n <= len(list); p, n = list[p-1].Parent, n+1 {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
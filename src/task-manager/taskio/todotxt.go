@@ -0,0 +1,181 @@
+package taskio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// The todo.txt format (https://github.com/todotxt/todo.txt) is one task per
+// line:
+//
+//	x 2024-03-02 2024-03-01 (A) Call mom +family @phone due:2024-03-05
+//
+// A leading "x" marks completion and is followed by the completion date;
+// then come the optional priority (only on open tasks) and creation date.
+// +project and @context words may appear anywhere in the text, as may
+// key:value extensions. Priorities map A, B and C to high, medium and low;
+// numeric priorities above high also export as A. Extra projects and
+// contexts beyond the first, and tag:name extensions, become tags.
+
+const todoDate = "2006-01-02"
+
+var todoPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+// ExportTodoTxt writes list in todo.txt format.
+func ExportTodoTxt(w io.Writer, list []tasks.Task, _ time.Time) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range list {
+		var parts []string
+		if t.Closed() {
+			parts = append(parts, "x")
+			if t.CompletedAt != nil {
+				parts = append(parts, t.CompletedAt.Format(todoDate))
+			}
+		} else if letter := todoLetter(t.Priority); letter != "" {
+			parts = append(parts, "("+letter+")")
+		}
+		if !t.CreatedAt.IsZero() {
+			parts = append(parts, t.CreatedAt.Format(todoDate))
+		}
+		parts = append(parts, strings.Join(strings.Fields(t.Description), " "))
+		if t.Project != "" {
+			parts = append(parts, "+"+t.Project)
+		}
+		if t.Context != "" {
+			parts = append(parts, "@"+t.Context)
+		}
+		if t.Due != nil {
+			parts = append(parts, "due:"+t.Due.Format(todoDate))
+		}
+		if t.Closed() && t.Priority != tasks.PriorityNone {
+			// The spec drops "(A)" on completion; keep it as an extension.
+			if letter := todoLetter(t.Priority); letter != "" {
+				parts = append(parts, "pri:"+letter)
+			}
+		}
+		for _, tag := range t.Tags {
+			parts = append(parts, "tag:"+tag)
+		}
+		fmt.Fprintln(bw, strings.Join(parts, " "))
+	}
+	return bw.Flush()
+}
+
+// ImportTodoTxt parses a todo.txt file. Blank lines are skipped.
+func ImportTodoTxt(r io.Reader, now time.Time) ([]tasks.Task, error) {
+	var list []tasks.Task
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		t, err := parseTodoLine(text, now)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		list = append(list, t)
+	}
+	return list, sc.Err()
+}
+
+func parseTodoLine(line string, now time.Time) (tasks.Task, error) {
+	words := strings.Fields(line)
+	t := tasks.Task{CreatedAt: now}
+
+	if len(words) > 0 && words[0] == "x" {
+		t.Status = tasks.Done
+		words = words[1:]
+		if d, ok := todoParseDate(words); ok {
+			t.CompletedAt = &d
+			words = words[1:]
+		} else {
+			t.CompletedAt = &now
+		}
+	}
+	if len(words) > 0 {
+		if m := todoPriority.FindStringSubmatch(words[0]); m != nil {
+			t.Priority = todoPriorityOf(m[1])
+			words = words[1:]
+		}
+	}
+	if d, ok := todoParseDate(words); ok {
+		t.CreatedAt = d
+		words = words[1:]
+	}
+
+	var desc []string
+	for _, w := range words {
+		switch {
+		case len(w) > 1 && w[0] == '+':
+			if t.Project == "" {
+				t.Project = w[1:]
+			} else {
+				t.Tags = append(t.Tags, w[1:])
+			}
+		case len(w) > 1 && w[0] == '@':
+			if t.Context == "" {
+				t.Context = w[1:]
+			} else {
+				t.Tags = append(t.Tags, w[1:])
+			}
+		case strings.HasPrefix(w, "due:"):
+			d, err := time.ParseInLocation(todoDate, w[len("due:"):], time.Local)
+			if err != nil {
+				return tasks.Task{}, fmt.Errorf("invalid due date %q", w)
+			}
+			// A date-only due means the end of that day, as on the command line.
+			d = d.Add(24*time.Hour - time.Second)
+			t.Due = &d
+		case strings.HasPrefix(w, "pri:") && len(w) == len("pri:")+1:
+			t.Priority = todoPriorityOf(w[len("pri:"):])
+		case strings.HasPrefix(w, "tag:") && len(w) > len("tag:"):
+			t.Tags = append(t.Tags, w[len("tag:"):])
+		default:
+			desc = append(desc, w)
+		}
+	}
+	t.Description = strings.Join(desc, " ")
+	if t.Description == "" {
+		return tasks.Task{}, fmt.Errorf("empty description")
+	}
+	t.Tags = tasks.NormalizeTags(t.Tags)
+	return t, nil
+}
+
+func todoParseDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(todoDate, words[0], time.Local)
+	return d, err == nil
+}
+
+func todoLetter(p tasks.Priority) string {
+	switch {
+	case p >= tasks.PriorityHigh:
+		return "A"
+	case p == tasks.PriorityMedium:
+		return "B"
+	case p == tasks.PriorityLow:
+		return "C"
+	}
+	return ""
+}
+
+func todoPriorityOf(letter string) tasks.Priority {
+	switch strings.ToUpper(letter) {
+	case "A":
+		return tasks.PriorityHigh
+	case "B":
+		return tasks.PriorityMedium
+	default:
+		return tasks.PriorityLow
+	}
+}
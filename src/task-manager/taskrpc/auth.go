@@ -0,0 +1,48 @@
+package taskrpc
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gopatterns/task-manager/auth"
+	"gopatterns/task-manager/taskpb"
+)
+
+// readMethods are the calls that only need auth.ScopeRead; every other
+// call needs auth.ScopeWrite.
+var readMethods = map[string]bool{
+	taskpb.TaskService_GetTask_FullMethodName:   true,
+	taskpb.TaskService_ListTasks_FullMethodName: true,
+}
+
+// UnaryAuth authenticates every call with p, reading credentials from the
+// "authorization" metadata key exactly as an HTTP Authorization header,
+// checks the caller's scopes and stores its Identity in the handler's
+// context.
+func UnaryAuth(p auth.Provider) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		r := &http.Request{Header: http.Header{}}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, v := range md.Get("authorization") {
+				r.Header.Add("Authorization", v)
+			}
+		}
+		id, err := p.Authenticate(r.WithContext(ctx))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		scope := auth.ScopeWrite
+		if readMethods[info.FullMethod] {
+			scope = auth.ScopeRead
+		}
+		if !id.Can(scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "%s needs the %s scope", info.FullMethod, scope)
+		}
+		return handler(auth.WithIdentity(ctx, id), req)
+	}
+}
@@ -0,0 +1,230 @@
+// Package taskrpc serves taskpb.TaskService from a tasks.Store, so other
+// services can manage tasks with a typed client instead of the CLI.
+package taskrpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"gopatterns/task-manager/auth"
+	"gopatterns/task-manager/hooks"
+	"gopatterns/task-manager/taskpb"
+	"gopatterns/task-manager/tasks"
+)
+
+// Server implements taskpb.TaskServiceServer. Mutations are journalled like
+// CLI commands, so `task undo` reverts them too.
+type Server struct {
+	taskpb.UnimplementedTaskServiceServer
+
+	store tasks.Store
+	hooks *hooks.Runner
+	now   func() time.Time
+}
+
+// NewServer returns a Server backed by store whose changes go through the
+// hook scripts of runner, which may be nil. The caller keeps ownership of
+// the store and closes it after the gRPC server has stopped.
+func NewServer(store tasks.Store, runner *hooks.Runner) *Server {
+	return &Server{store: store, hooks: runner, now: time.Now}
+}
+
+// CreateTask implements taskpb.TaskServiceServer.
+func (s *Server) CreateTask(ctx context.Context, req *taskpb.CreateTaskRequest) (*taskpb.Task, error) {
+	prio := tasks.Priority(req.GetPriority())
+	if !prio.Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid priority %d", prio)
+	}
+	t := tasks.Task{
+		Description: req.GetDescription(),
+		CreatedAt:   s.now(),
+		Due:         optionalTime(req.GetDue()),
+		Priority:    prio,
+		Tags:        tasks.NormalizeTags(req.GetTags()),
+		Parent:      int(req.GetParent()),
+		Project:     req.GetProject(),
+		Context:     tasks.NormalizeContext(req.GetContext()),
+		Assignee:    strings.TrimSpace(req.GetAssignee()),
+	}
+	err := s.update(ctx, "add", func(tx tasks.Tx) (err error) {
+		t, err = tasks.Insert(tx, t)
+		return err
+	})
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return toProto(t), nil
+}
+
+// GetTask implements taskpb.TaskServiceServer.
+func (s *Server) GetTask(ctx context.Context, req *taskpb.GetTaskRequest) (*taskpb.Task, error) {
+	var t tasks.Task
+	err := s.store.View(func(tx tasks.Tx) (err error) {
+		t, err = tx.Get(int(req.GetId()))
+		return err
+	})
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return toProto(t), nil
+}
+
+// ListTasks implements taskpb.TaskServiceServer.
+func (s *Server) ListTasks(ctx context.Context, req *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
+	var list []tasks.Task
+	err := s.store.View(func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	})
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	var filters []tasks.Filter
+	if len(req.GetTags()) > 0 {
+		filters = append(filters, tasks.WithTags(tasks.NormalizeTags(req.GetTags())...))
+	}
+	if req.GetProject() != "" {
+		filters = append(filters, tasks.InProject(req.GetProject()))
+	}
+	if req.GetContext() != "" {
+		filters = append(filters, tasks.InContext(req.GetContext()))
+	}
+	switch who := req.GetAssignee(); who {
+	case "":
+	case "none":
+		filters = append(filters, tasks.AssignedTo(""))
+	default:
+		filters = append(filters, tasks.AssignedTo(who))
+	}
+	if req.GetExcludeCompleted() {
+		filters = append(filters, func(t tasks.Task) bool { return !completed(t) })
+	}
+	list = tasks.Select(list, tasks.And(filters...))
+	if req.GetQuery() != "" {
+		list = tasks.Search(list, req.GetQuery())
+	}
+	tasks.SortByPriority(list)
+
+	resp := &taskpb.ListTasksResponse{Tasks: make([]*taskpb.Task, len(list))}
+	for i, t := range list {
+		resp.Tasks[i] = toProto(t)
+	}
+	return resp, nil
+}
+
+// CompleteTask implements taskpb.TaskServiceServer.
+func (s *Server) CompleteTask(ctx context.Context, req *taskpb.CompleteTaskRequest) (*taskpb.Task, error) {
+	var t tasks.Task
+	err := s.update(ctx, "complete", func(tx tasks.Tx) (err error) {
+		t, err = tasks.Complete(tx, int(req.GetId()), s.now(), req.GetForce())
+		return err
+	})
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return toProto(t), nil
+}
+
+// DeleteTask implements taskpb.TaskServiceServer.
+func (s *Server) DeleteTask(ctx context.Context, req *taskpb.DeleteTaskRequest) (*taskpb.Task, error) {
+	var t tasks.Task
+	err := s.update(ctx, "delete", func(tx tasks.Tx) (err error) {
+		id, _ := auth.FromContext(ctx)
+		t, err = tasks.Discard(tx, int(req.GetId()), s.now(), id.Subject)
+		return err
+	})
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return toProto(t), nil
+}
+
+// AssignTask implements taskpb.TaskServiceServer.
+func (s *Server) AssignTask(ctx context.Context, req *taskpb.AssignTaskRequest) (*taskpb.Task, error) {
+	var t tasks.Task
+	err := s.update(ctx, "assign", func(tx tasks.Tx) (err error) {
+		t, err = tasks.Assign(tx, int(req.GetId()), req.GetAssignee())
+		return err
+	})
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return toProto(t), nil
+}
+
+// update runs fn as a journalled operation attributed to the caller. The
+// hook scripts see every task fn writes or deletes, with op as the command.
+func (s *Server) update(ctx context.Context, op string, fn func(tasks.Tx) error) error {
+	id, _ := auth.FromContext(ctx)
+	runner := s.hooks.With("TASK_COMMAND="+op, "TASK_USER="+id.Subject)
+	return s.store.Update(func(tx tasks.Tx) error {
+		return tasks.Record(tx, tasks.Operation{Name: op, Time: s.now(), User: id.Subject}, func(tx tasks.Tx) error {
+			return fn(runner.Tx(ctx, tx))
+		})
+	})
+}
+
+// statusOf maps task errors to gRPC status codes.
+func statusOf(err error) error {
+	var rejected *hooks.RejectedError
+	switch {
+	case errors.Is(err, tasks.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, tasks.ErrInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, tasks.ErrAlreadyCompleted), errors.Is(err, tasks.ErrOpenSubtasks),
+		errors.Is(err, tasks.ErrTransition), errors.As(err, &rejected):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// completed reports whether t counts as completed to clients: both
+// Task.completed and ListTasksRequest.exclude_completed go by it.
+func completed(t tasks.Task) bool { return t.Closed() }
+
+func toProto(t tasks.Task) *taskpb.Task {
+	st := t.Status
+	if st == "" {
+		st = tasks.Pending // tasks written before statuses existed
+	}
+	return &taskpb.Task{
+		Id:          int64(t.ID),
+		Description: t.Description,
+		Completed:   completed(t),
+		Status:      string(st),
+		Uuid:        t.UUID,
+		CreatedAt:   timestamppb.New(t.CreatedAt),
+		CompletedAt: optionalTimestamp(t.CompletedAt),
+		Due:         optionalTimestamp(t.Due),
+		Priority:    int32(t.Priority),
+		Tags:        t.Tags,
+		Parent:      int64(t.Parent),
+		Project:     t.Project,
+		Assignee:    t.Assignee,
+		Context:     t.Context,
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime().Local()
+	return &t
+}
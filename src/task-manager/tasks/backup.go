@@ -0,0 +1,195 @@
+package tasks
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// backupVersion is the format version written by WriteBackup.
+const backupVersion = 1
+
+// ListBackup is what one task list holds, except its undo journal.
+type ListBackup struct {
+	Tasks   []Task    `json:"tasks"`
+	Trash   []Trashed `json:"trash,omitempty"`
+	History []Event   `json:"history,omitempty"`
+}
+
+// Backup is a point-in-time copy of task lists. It is read and written
+// through Tx, so a backup taken from one backend restores into any other.
+type Backup struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// Lists maps list names to their ListBackup as JSON, or as the
+	// encrypted envelope of that JSON for lists kept encrypted.
+	Lists map[string]json.RawMessage `json:"lists"`
+}
+
+// DumpList reads everything tx holds into a ListBackup.
+func DumpList(tx Tx) (ListBackup, error) {
+	var (
+		b   ListBackup
+		err error
+	)
+	if b.Tasks, err = tx.List(); err != nil {
+		return ListBackup{}, err
+	}
+	if b.Trash, err = tx.Trash(); err != nil {
+		return ListBackup{}, err
+	}
+	if b.History, err = tx.History(); err != nil {
+		return ListBackup{}, err
+	}
+	return b, nil
+}
+
+// RestoreList makes the tasks and trash in tx those of b, writing only what
+// differs. Run it inside Record so the rollback can be undone and syncs like
+// any change. The history is append-only, so b's is only copied into a
+// list that has none, such as a new one; otherwise the rollback is logged
+// on top of it.
+func RestoreList(tx Tx, b ListBackup) error {
+	current, err := tx.List()
+	if err != nil {
+		return err
+	}
+	want := make(map[int]Task, len(b.Tasks))
+	for _, t := range b.Tasks {
+		want[t.ID] = t
+	}
+	have := make(map[int]Task, len(current))
+	for _, t := range current {
+		have[t.ID] = t
+		if _, ok := want[t.ID]; !ok {
+			if err := tx.Delete(t.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range b.Tasks {
+		if old, ok := have[t.ID]; ok && len(Diff(&old, &t)) == 0 {
+			continue
+		}
+		if err := tx.Put(t); err != nil {
+			return err
+		}
+	}
+
+	trash, err := tx.Trash()
+	if err != nil {
+		return err
+	}
+	keep := make(map[int]bool, len(b.Trash))
+	for _, e := range b.Trash {
+		keep[e.Task.ID] = true
+	}
+	for _, e := range trash {
+		if !keep[e.Task.ID] {
+			if err := tx.DeleteTrash(e.Task.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range b.Trash {
+		if err := tx.PutTrash(e); err != nil {
+			return err
+		}
+	}
+
+	history, err := tx.History()
+	if err != nil || len(history) > 0 {
+		return err
+	}
+	for _, ev := range b.History {
+		if err := tx.AppendHistory(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewBackup returns an empty backup taken at now.
+func NewBackup(now time.Time) *Backup {
+	return &Backup{Version: backupVersion, Time: now, Lists: map[string]json.RawMessage{}}
+}
+
+// Add stores list name in b. With params, the list is encrypted with key
+// the way an encrypted task file is, so the backup is no easier to read
+// than the list itself.
+func (b *Backup) Add(name string, list ListBackup, params *KeyParams, key []byte) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if params != nil {
+		if data, err = seal(*params, key, data); err != nil {
+			return i18n.Errorf("backup of %s: %w", name, err)
+		}
+	}
+	b.Lists[name] = data
+	return nil
+}
+
+// List returns list name from b, asking key for the key if it is
+// encrypted.
+func (b *Backup) List(name string, key KeyFunc) (ListBackup, error) {
+	data, ok := b.Lists[name]
+	if !ok {
+		return ListBackup{}, i18n.Errorf("list %q: %w", name, ErrNotFound)
+	}
+	var env envelope
+	if json.Unmarshal(data, &env) == nil && env.Encrypted != nil {
+		if key == nil {
+			return ListBackup{}, i18n.Errorf("list %q: %w", name, ErrEncrypted)
+		}
+		k, err := key(*env.Encrypted, false)
+		if err != nil {
+			return ListBackup{}, err
+		}
+		if !env.Encrypted.Verify(k) {
+			return ListBackup{}, ErrWrongKey
+		}
+		if data, err = unseal(env, k); err != nil {
+			return ListBackup{}, err
+		}
+	}
+	var list ListBackup
+	if err := json.Unmarshal(data, &list); err != nil {
+		return ListBackup{}, corrupt("backup of list "+name, err)
+	}
+	return list, nil
+}
+
+// WriteBackup writes b to w as gzip-compressed JSON.
+func WriteBackup(w io.Writer, b *Backup) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadBackup reads a backup written by WriteBackup.
+func ReadBackup(r io.Reader) (*Backup, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, corrupt("backup", err)
+	}
+	defer zr.Close()
+	var b Backup
+	if err := json.NewDecoder(zr).Decode(&b); err != nil {
+		return nil, corrupt("backup", err)
+	}
+	if b.Version > backupVersion {
+		return nil, i18n.Errorf("backup format version %d is newer than this binary supports", b.Version)
+	}
+	if b.Lists == nil {
+		return nil, corrupt("backup", errors.New("no lists"))
+	}
+	return &b, nil
+}
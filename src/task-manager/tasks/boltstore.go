@@ -0,0 +1,374 @@
+package tasks
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// DefaultList is the bucket used when no list name is given.
+const DefaultList = "default"
+
+var (
+	metaBucket    = []byte("meta")
+	listsBucket   = []byte("lists")
+	journalBucket = []byte("journal")
+	historyBucket = []byte("history")
+	trashBucket   = []byte("trash")
+	versionKey    = []byte("schema_version")
+)
+
+// boltMigrations bring a database up to the current schema. Entry i moves
+// the schema from version i to i+1; they run automatically on open, inside
+// a single transaction, so a failed upgrade leaves the file untouched.
+var boltMigrations = []func(tx *bolt.Tx) error{
+	// 0 -> 1: top-level buckets.
+	func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(metaBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(listsBucket)
+		return err
+	},
+	// 1 -> 2: undo journal, one nested bucket per list.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(journalBucket)
+		return err
+	},
+	// 2 -> 3: change history, one nested bucket per list.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(historyBucket)
+		return err
+	},
+	// 3 -> 4: trash, one nested bucket per list, keyed by task ID.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(trashBucket)
+		return err
+	},
+	// 4 -> 5: a UUID for every task written before each got one.
+	func(tx *bolt.Tx) error {
+		lists := tx.Bucket(listsBucket)
+		var names [][]byte
+		if err := lists.ForEachBucket(func(name []byte) error {
+			names = append(names, name)
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, name := range names {
+			b := &boltTx{tx: tx, list: name}
+			list, err := b.List()
+			if err != nil {
+				return err
+			}
+			if !assignUUIDs(list) {
+				continue
+			}
+			for _, t := range list {
+				if err := b.Put(t); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// BoltStore keeps tasks in an embedded bbolt database: one bucket per task
+// list, one key per task ID, JSON values. Every Update is a real ACID
+// transaction, so a crash never leaves the database half-written.
+type BoltStore struct {
+	db   *bolt.DB
+	list []byte
+}
+
+// OpenBolt opens (creating if needed) the database at path and selects the
+// named list, migrating the schema if the file was written by an older
+// version.
+func OpenBolt(path, list string) (*BoltStore, error) {
+	if list == "" {
+		list = DefaultList
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, i18n.Errorf("open %s: database is locked by another task process", path)
+		}
+		if errors.Is(err, bolt.ErrInvalid) || errors.Is(err, bolt.ErrChecksum) || errors.Is(err, bolt.ErrVersionMismatch) {
+			return nil, corrupt("open "+path, err)
+		}
+		return nil, i18n.Errorf("open %s: %w", path, err)
+	}
+	s := &BoltStore{db: db, list: []byte(list)}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *BoltStore) migrate() error {
+	// Opening an up-to-date database must not write to it, or every read
+	// would look like a change to anyone watching the file.
+	var version int
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if version == len(boltMigrations) {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		version := schemaVersion(tx)
+		if version > len(boltMigrations) {
+			return i18n.Errorf("database schema version %d is newer than this binary supports", version)
+		}
+		for ; version < len(boltMigrations); version++ {
+			if err := boltMigrations[version](tx); err != nil {
+				return i18n.Errorf("migrate schema to version %d: %w", version+1, err)
+			}
+		}
+		return tx.Bucket(metaBucket).Put(versionKey, itob(uint64(version)))
+	})
+}
+
+func schemaVersion(tx *bolt.Tx) int {
+	if meta := tx.Bucket(metaBucket); meta != nil {
+		if v := meta.Get(versionKey); v != nil {
+			return int(binary.BigEndian.Uint64(v))
+		}
+	}
+	return 0
+}
+
+// View implements Store.
+func (s *BoltStore) View(fn func(Tx) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx, list: s.list})
+	})
+}
+
+// Update implements Store.
+func (s *BoltStore) Update(fn func(Tx) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.Bucket(listsBucket).CreateBucketIfNotExists(s.list); err != nil {
+			return err
+		}
+		if _, err := tx.Bucket(journalBucket).CreateBucketIfNotExists(s.list); err != nil {
+			return err
+		}
+		if _, err := tx.Bucket(historyBucket).CreateBucketIfNotExists(s.list); err != nil {
+			return err
+		}
+		if _, err := tx.Bucket(trashBucket).CreateBucketIfNotExists(s.list); err != nil {
+			return err
+		}
+		return fn(&boltTx{tx: tx, list: s.list})
+	})
+}
+
+// Close implements Store.
+func (s *BoltStore) Close() error { return s.db.Close() }
+
+type boltTx struct {
+	tx   *bolt.Tx
+	list []byte
+}
+
+// bucket returns the list's bucket, or nil if it was never written to.
+func (b *boltTx) bucket() *bolt.Bucket {
+	return b.tx.Bucket(listsBucket).Bucket(b.list)
+}
+
+func (b *boltTx) Get(id int) (Task, error) {
+	bk := b.bucket()
+	if bk == nil {
+		return Task{}, NotFound(id)
+	}
+	v := bk.Get(itob(uint64(id)))
+	if v == nil {
+		return Task{}, NotFound(id)
+	}
+	var t Task
+	if err := json.Unmarshal(v, &t); err != nil {
+		return Task{}, corrupt(fmt.Sprintf("decode task %d", id), err)
+	}
+	return t, nil
+}
+
+func (b *boltTx) List() ([]Task, error) {
+	bk := b.bucket()
+	if bk == nil {
+		return nil, nil
+	}
+	var list []Task
+	// Keys are big-endian IDs, so cursor order is ID order.
+	err := bk.ForEach(func(k, v []byte) error {
+		var t Task
+		if err := json.Unmarshal(v, &t); err != nil {
+			return corrupt(fmt.Sprintf("decode task %d", binary.BigEndian.Uint64(k)), err)
+		}
+		list = append(list, t)
+		return nil
+	})
+	return list, err
+}
+
+func (b *boltTx) Put(t Task) error {
+	bk := b.bucket()
+	v, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	// Keep the sequence ahead of explicitly written IDs (imports, undo).
+	if uint64(t.ID) > bk.Sequence() {
+		if err := bk.SetSequence(uint64(t.ID)); err != nil {
+			return err
+		}
+	}
+	return bk.Put(itob(uint64(t.ID)), v)
+}
+
+func (b *boltTx) Delete(id int) error {
+	bk := b.bucket()
+	key := itob(uint64(id))
+	if bk == nil || bk.Get(key) == nil {
+		return NotFound(id)
+	}
+	return bk.Delete(key)
+}
+
+func (b *boltTx) NextID() (int, error) {
+	seq, err := b.bucket().NextSequence()
+	return int(seq), err
+}
+
+// PushOp implements Tx. Entries are keyed by sequence number so the cursor
+// walks them oldest first.
+func (b *boltTx) PushOp(op Operation) error {
+	bk := b.tx.Bucket(journalBucket).Bucket(b.list)
+	v, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	seq, err := bk.NextSequence()
+	if err != nil {
+		return err
+	}
+	if err := bk.Put(itob(seq), v); err != nil {
+		return err
+	}
+	// Collect first: deleting while iterating makes the cursor skip keys.
+	var stale [][]byte
+	c := bk.Cursor()
+	for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k)+JournalLimit <= seq; k, _ = c.Next() {
+		stale = append(stale, k)
+	}
+	for _, k := range stale {
+		if err := bk.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PopOp implements Tx.
+func (b *boltTx) PopOp() (Operation, bool, error) {
+	bk := b.tx.Bucket(journalBucket).Bucket(b.list)
+	if bk == nil {
+		return Operation{}, false, nil
+	}
+	k, v := bk.Cursor().Last()
+	if k == nil {
+		return Operation{}, false, nil
+	}
+	var op Operation
+	if err := json.Unmarshal(v, &op); err != nil {
+		return Operation{}, false, corrupt("decode journal entry", err)
+	}
+	return op, true, bk.Delete(k)
+}
+
+// AppendHistory implements Tx. Events are keyed by sequence number, so the
+// cursor walks them oldest first.
+func (b *boltTx) AppendHistory(ev Event) error {
+	bk := b.tx.Bucket(historyBucket).Bucket(b.list)
+	v, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	seq, err := bk.NextSequence()
+	if err != nil {
+		return err
+	}
+	return bk.Put(itob(seq), v)
+}
+
+// History implements Tx.
+func (b *boltTx) History() ([]Event, error) {
+	bk := b.tx.Bucket(historyBucket).Bucket(b.list)
+	if bk == nil {
+		return nil, nil
+	}
+	var events []Event
+	err := bk.ForEach(func(_, v []byte) error {
+		var ev Event
+		if err := json.Unmarshal(v, &ev); err != nil {
+			return corrupt("decode history entry", err)
+		}
+		events = append(events, ev)
+		return nil
+	})
+	return events, err
+}
+
+// Trash implements Tx.
+func (b *boltTx) Trash() ([]Trashed, error) {
+	bk := b.tx.Bucket(trashBucket).Bucket(b.list)
+	if bk == nil {
+		return nil, nil
+	}
+	var list []Trashed
+	err := bk.ForEach(func(k, v []byte) error {
+		var e Trashed
+		if err := json.Unmarshal(v, &e); err != nil {
+			return corrupt(fmt.Sprintf("decode trashed task %d", binary.BigEndian.Uint64(k)), err)
+		}
+		list = append(list, e)
+		return nil
+	})
+	return list, err
+}
+
+// PutTrash implements Tx.
+func (b *boltTx) PutTrash(e Trashed) error {
+	v, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.tx.Bucket(trashBucket).Bucket(b.list).Put(itob(uint64(e.Task.ID)), v)
+}
+
+// DeleteTrash implements Tx.
+func (b *boltTx) DeleteTrash(id int) error {
+	bk := b.tx.Bucket(trashBucket).Bucket(b.list)
+	key := itob(uint64(id))
+	if bk == nil || bk.Get(key) == nil {
+		return NotFound(id)
+	}
+	return bk.Delete(key)
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,113 @@
+package tasks
+
+import (
+	"slices"
+	"time"
+)
+
+// Burndown returns how many tasks were open (neither done nor cancelled) at
+// each of times, given the list as it is now and its history. It works back
+// from the present, undoing one event at a time, so tasks older than the
+// history count as they are now. times must be in ascending order.
+func Burndown(list []Task, events []Event, times []time.Time) []int {
+	open := make(map[int]bool, len(list))
+	for _, t := range list {
+		open[t.ID] = !t.Closed()
+	}
+
+	deletedOpen := deletedOpen(events)
+	counts := make([]int, len(times))
+	i := len(events) - 1
+	for k := len(times) - 1; k >= 0; k-- {
+		for ; i >= 0 && events[i].Time.After(times[k]); i-- {
+			ev := events[i]
+			switch ev.Action {
+			case Created:
+				delete(open, ev.TaskID)
+			case Deleted:
+				open[ev.TaskID] = deletedOpen[i]
+			case Updated:
+				if c, ok := statusChange(ev); ok {
+					open[ev.TaskID] = !closedStatus(c.Old)
+				}
+			}
+		}
+		for _, o := range open {
+			if o {
+				counts[k]++
+			}
+		}
+	}
+	return counts
+}
+
+// Flow is how a stretch of history changed the number of open tasks: the
+// count at its end is the count at its start plus Added and Reopened minus
+// Closed and Deleted. Undoing a change counts as the opposite change.
+type Flow struct {
+	Added    int // created open
+	Reopened int // closed before, open after
+	Closed   int // done or cancelled
+	Deleted  int // deleted while open
+}
+
+// BurndownFlow sums up the events after from and up to to the way Burndown
+// counts them.
+func BurndownFlow(events []Event, from, to time.Time) Flow {
+	deletedOpen := deletedOpen(events)
+	var f Flow
+	for i, ev := range events {
+		if !ev.Time.After(from) || ev.Time.After(to) {
+			continue
+		}
+		c, ok := statusChange(ev)
+		switch {
+		case ev.Action == Deleted:
+			if deletedOpen[i] {
+				f.Deleted++
+			}
+		case !ok:
+		case ev.Action == Created:
+			if !closedStatus(c.New) {
+				f.Added++
+			}
+		case closedStatus(c.Old) && !closedStatus(c.New):
+			f.Reopened++
+		case !closedStatus(c.Old) && closedStatus(c.New):
+			f.Closed++
+		}
+	}
+	return f
+}
+
+// deletedOpen reports for each of events whether it deleted an open task.
+// A deleted event does not say what the task's status was, so it is found
+// from the events before: the last status the task was given.
+func deletedOpen(events []Event) []bool {
+	open := make([]bool, len(events))
+	last := map[int]string{}
+	for i, ev := range events {
+		if c, ok := statusChange(ev); ok {
+			last[ev.TaskID] = c.New
+		}
+		if ev.Action == Deleted {
+			open[i] = !closedStatus(last[ev.TaskID])
+		}
+	}
+	return open
+}
+
+// statusChange returns ev's change to its task's status, if it made one.
+func statusChange(ev Event) (FieldChange, bool) {
+	i := slices.IndexFunc(ev.Changes, func(c FieldChange) bool { return c.Field == "status" })
+	if i < 0 {
+		return FieldChange{}, false
+	}
+	return ev.Changes[i], true
+}
+
+// closedStatus reports whether a status named in the history closes a task.
+func closedStatus(name string) bool {
+	s, err := ParseStatus(name)
+	return err == nil && s.Closed()
+}
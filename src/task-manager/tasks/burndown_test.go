@@ -0,0 +1,54 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBurndownFlowAddsUp(t *testing.T) {
+	m := newTestManager()
+	old := mustAdd(t, m, Task{Description: "Before the chart"})
+
+	a := mustAdd(t, m, Task{Description: "Call mom"})
+	b := mustAdd(t, m, Task{Description: "Water plants"})
+	steps := []func() error{
+		func() (err error) { _, err = m.Complete(a.ID, false); return err },
+		func() (err error) { _, err = m.Undo(); return err }, // completion undone
+		func() (err error) { _, err = m.Complete(b.ID, false); return err },
+		func() (err error) { _, err = m.Reopen(b.ID); return err },
+		func() (err error) { _, err = m.Complete(old.ID, false); return err },
+		func() (err error) { _, err = m.Delete(a.ID); return err },
+		func() (err error) { _, err = m.Undo(); return err }, // deletion undone
+		func() (err error) { _, err = m.Undo(); return err }, // completion of old undone
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	var (
+		list   []Task
+		events []Event
+	)
+	err := m.Store().View(func(tx Tx) (err error) {
+		if list, err = tx.List(); err != nil {
+			return err
+		}
+		events, err = tx.History()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := events[0].Time, events[len(events)-1].Time // after adding old
+	counts := Burndown(list, events, []time.Time{from, to})
+	f := BurndownFlow(events, from, to)
+	if counts[0]+f.Added+f.Reopened-f.Closed-f.Deleted != counts[1] {
+		t.Fatalf("%d -> %d does not add up with %+v", counts[0], counts[1], f)
+	}
+	want := Flow{Added: 3, Reopened: 3, Closed: 3, Deleted: 1}
+	if counts[0] != 1 || counts[1] != 3 || f != want {
+		t.Fatalf("counts %v, flow %+v; want [1 3], %+v", counts, f, want)
+	}
+}
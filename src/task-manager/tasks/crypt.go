@@ -0,0 +1,124 @@
+package tasks
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// ErrWrongKey is returned when a key does not open an encrypted task file.
+var ErrWrongKey = errors.New("wrong passphrase")
+
+// ErrEncrypted is returned when an encrypted task file is opened without a
+// key.
+var ErrEncrypted = errors.New("task file is encrypted")
+
+// KeyParams describes how the key of an encrypted task file is derived from
+// its passphrase. It is stored in the clear at the top of the file.
+type KeyParams struct {
+	KDF  string `json:"kdf"` // always "scrypt"
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+
+	// Check lets a key be verified before it is used or cached.
+	Check []byte `json:"check"`
+}
+
+// scrypt cost for new files: about 100ms and 32 MiB on a laptop.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// NewKeyParams returns parameters with a fresh random salt. The check value
+// is filled in by SetCheck once the key is known.
+func NewKeyParams() (KeyParams, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return KeyParams{}, err
+	}
+	return KeyParams{KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: salt}, nil
+}
+
+// Key derives the 256-bit file key from passphrase.
+func (p KeyParams) Key(passphrase []byte) ([]byte, error) {
+	if p.KDF != "scrypt" {
+		return nil, i18n.Errorf("unsupported key derivation %q", p.KDF)
+	}
+	return scrypt.Key(passphrase, p.Salt, p.N, p.R, p.P, 32)
+}
+
+// Verify reports whether key is the key these parameters were created with.
+func (p KeyParams) Verify(key []byte) bool {
+	return hmac.Equal(p.Check, keyCheck(key))
+}
+
+// SetCheck records key as the file's key.
+func (p *KeyParams) SetCheck(key []byte) {
+	p.Check = keyCheck(key)
+}
+
+func keyCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("task-manager key check"))
+	return mac.Sum(nil)[:16]
+}
+
+// KeyFunc supplies the key for an encrypted task file. create is set when
+// the file is about to be encrypted for the first time, so the passphrase
+// should be confirmed; params then has no check value yet.
+type KeyFunc func(params KeyParams, create bool) ([]byte, error)
+
+// envelope is the on-disk form of an encrypted snapshot.
+type envelope struct {
+	Encrypted *KeyParams `json:"encrypted"`
+	Nonce     []byte     `json:"nonce"`
+	Data      []byte     `json:"data"`
+}
+
+func seal(params KeyParams, key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(envelope{
+		Encrypted: &params,
+		Nonce:     nonce,
+		Data:      aead.Seal(nil, nonce, plaintext, params.Salt),
+	}, "", "  ")
+}
+
+func unseal(env envelope, key []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Data, env.Encrypted.Salt)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"sort"
+	"time"
+)
+
+// Digest is the daily summary of a list: what is overdue, what is due on
+// the day, and what was completed the day before.
+type Digest struct {
+	Date               time.Time `json:"date"` // midnight starting the day
+	Overdue            []Task    `json:"overdue"`
+	DueToday           []Task    `json:"due_today"`
+	CompletedYesterday []Task    `json:"completed_yesterday"`
+}
+
+// NewDigest summarises list for the local day containing now. Open tasks
+// due before that day are overdue; snoozed tasks are left out of both.
+func NewDigest(list []Task, now time.Time) Digest {
+	y, m, d := now.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	end, yesterday := start.AddDate(0, 0, 1), start.AddDate(0, 0, -1)
+
+	dg := Digest{Date: start, Overdue: []Task{}, DueToday: []Task{}, CompletedYesterday: []Task{}}
+	for _, t := range list {
+		switch {
+		case t.Closed():
+			if t.Done() && t.CompletedAt != nil && !t.CompletedAt.Before(yesterday) && t.CompletedAt.Before(start) {
+				dg.CompletedYesterday = append(dg.CompletedYesterday, t)
+			}
+		case t.Due == nil || t.IsSnoozed(now):
+		case t.Due.Before(start):
+			dg.Overdue = append(dg.Overdue, t)
+		case t.Due.Before(end):
+			dg.DueToday = append(dg.DueToday, t)
+		}
+	}
+	byDue := func(list []Task) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Due.Before(*list[j].Due) })
+	}
+	byDue(dg.Overdue)
+	byDue(dg.DueToday)
+	sort.SliceStable(dg.CompletedYesterday, func(i, j int) bool {
+		return dg.CompletedYesterday[i].CompletedAt.Before(*dg.CompletedYesterday[j].CompletedAt)
+	})
+	return dg
+}
+
+// Empty reports whether the digest has nothing to say.
+func (d Digest) Empty() bool {
+	return len(d.Overdue) == 0 && len(d.DueToday) == 0 && len(d.CompletedYesterday) == 0
+}
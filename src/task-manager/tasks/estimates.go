@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"sort"
+	"time"
+)
+
+// EstimateStat compares the estimated and the tracked time of the completed
+// tasks in a project that have both.
+type EstimateStat struct {
+	Project   string        `json:"project"` // "" for tasks without one
+	Tasks     int           `json:"tasks"`
+	Estimated time.Duration `json:"estimated"`
+	Tracked   time.Duration `json:"tracked"`
+	// Accuracy is the mean, over the tasks, of the smaller of estimate and
+	// tracked time divided by the larger: 1 when every estimate was exact,
+	// 0.5 when each was off by a factor of two either way.
+	Accuracy float64 `json:"accuracy"`
+}
+
+// Ratio is the tracked time over the estimated time: above 1 means the work
+// took longer than planned.
+func (s EstimateStat) Ratio() float64 {
+	if s.Estimated == 0 {
+		return 0
+	}
+	return float64(s.Tracked) / float64(s.Estimated)
+}
+
+func (s *EstimateStat) add(t Task) {
+	lo, hi := t.Estimate, t.Spent
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	// Running mean, so Accuracy is always ready to read.
+	s.Tasks++
+	s.Accuracy += (float64(lo)/float64(hi) - s.Accuracy) / float64(s.Tasks)
+	s.Estimated += t.Estimate
+	s.Tracked += t.Spent
+}
+
+// Estimates compares estimates with tracked time per project, ordered by
+// project name, and over all of list. Only completed tasks with an estimate
+// and some tracked time count.
+func Estimates(list []Task) (perProject []EstimateStat, total EstimateStat) {
+	stats := map[string]*EstimateStat{}
+	for _, t := range list {
+		if !t.Done() || t.Estimate <= 0 || t.Spent <= 0 {
+			continue
+		}
+		s := stats[t.Project]
+		if s == nil {
+			s = &EstimateStat{Project: t.Project}
+			stats[t.Project] = s
+		}
+		s.add(t)
+		total.add(t)
+	}
+	for _, s := range stats {
+		perProject = append(perProject, *s)
+	}
+	sort.Slice(perProject, func(i, j int) bool { return perProject[i].Project < perProject[j].Project })
+	return perProject, total
+}
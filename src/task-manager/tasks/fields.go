@@ -0,0 +1,146 @@
+package tasks
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// MergeFields lists the fields sync merges independently, named as in Diff.
+// "conflicts" is the Conflicts list itself, so resolving a conflict on one
+// machine carries over to the others.
+var MergeFields = []string{
+	"description", "status", "due", "wait", "priority", "estimate", "tags",
+	"project", "context", "assignee", "notes", "attachments", "conflicts",
+}
+
+// Stamp is when, and in which revision of the task, a field last changed.
+type Stamp struct {
+	Time time.Time `json:"time"`
+	Rev  int       `json:"rev"`
+}
+
+// Conflict is a change that lost a field-level sync merge: both machines
+// changed Field, and the other change was later.
+type Conflict struct {
+	Field string    `json:"field"`
+	Value string    `json:"value"` // the discarded value, as Diff shows it
+	Time  time.Time `json:"time"`  // when it was set
+	Task  Task      `json:"task"`  // the discarded version, to take the value from
+}
+
+// SetField copies field, one of MergeFields, from src to t.
+func (t *Task) SetField(field string, src Task) {
+	switch field {
+	case "description":
+		t.Description = src.Description
+	case "status":
+		t.Status, t.CompletedAt, t.StartedAt = src.Status, src.CompletedAt, src.StartedAt
+		t.Spent = src.Spent
+	case "due":
+		t.Due = src.Due
+	case "wait":
+		t.Wait = src.Wait
+	case "priority":
+		t.Priority = src.Priority
+	case "estimate":
+		t.Estimate = src.Estimate
+	case "tags":
+		t.Tags = src.Tags
+	case "project":
+		t.Project = src.Project
+	case "context":
+		t.Context = src.Context
+	case "assignee":
+		t.Assignee = src.Assignee
+	case "notes":
+		t.Notes = src.Notes
+	case "attachments":
+		t.Attachments = src.Attachments
+	case "conflicts":
+		t.Conflicts = src.Conflicts
+	}
+}
+
+// FieldText is field of t as Diff shows it.
+func FieldText(t Task, field string) string {
+	for _, c := range Diff(nil, &t) {
+		if c.Field == field {
+			return c.New
+		}
+	}
+	return ""
+}
+
+// stamp records in after.Stamps the fields that differ from before, at
+// after's revision.
+func stamp(before, after *Task, now time.Time) {
+	var fields []string
+	for _, c := range Diff(before, after) {
+		switch c.Field {
+		case "note":
+			fields = append(fields, "notes")
+		default:
+			fields = append(fields, c.Field)
+		}
+	}
+	var prior []Conflict
+	if before != nil {
+		prior = before.Conflicts
+	}
+	if !slices.EqualFunc(prior, after.Conflicts, sameConflict) {
+		fields = append(fields, "conflicts")
+	}
+	if len(fields) == 0 {
+		return
+	}
+	// The map may be shared with the before-image; never write to it.
+	after.Stamps = maps.Clone(after.Stamps)
+	if after.Stamps == nil {
+		after.Stamps = map[string]Stamp{}
+	}
+	for _, f := range fields {
+		after.Stamps[f] = Stamp{Time: now, Rev: after.Rev}
+	}
+}
+
+func sameConflict(a, b Conflict) bool {
+	return a.Field == b.Field && a.Value == b.Value && a.Time.Equal(b.Time)
+}
+
+// Resolve settles the conflicts of task id on the given fields, or on all
+// of them when fields is empty. With take, each field gets the discarded
+// value (the newest one, if several were); otherwise the current value is
+// kept. The settled conflicts are removed either way.
+func Resolve(tx Tx, id int, fields []string, take bool) (Task, []Conflict, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, nil, err
+	}
+	var settled, open []Conflict
+	for _, c := range t.Conflicts {
+		if len(fields) == 0 || slices.Contains(fields, c.Field) {
+			settled = append(settled, c)
+		} else {
+			open = append(open, c)
+		}
+	}
+	if len(settled) == 0 {
+		return t, nil, i18n.Errorf("task %d has no conflicts to resolve", id)
+	}
+	if take {
+		newest := map[string]Conflict{}
+		for _, c := range settled {
+			if n, ok := newest[c.Field]; !ok || c.Time.After(n.Time) {
+				newest[c.Field] = c
+			}
+		}
+		for field, c := range newest {
+			t.SetField(field, c.Task)
+		}
+	}
+	t.Conflicts = open
+	return t, settled, tx.Put(t)
+}
@@ -0,0 +1,225 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// snapshotVersion is the on-disk format version written by FileStore.
+// Version 2 guarantees every task a UUID.
+const snapshotVersion = 2
+
+// snapshot is the JSON document FileStore reads and writes.
+type snapshot struct {
+	Version int         `json:"version"`
+	NextID  int         `json:"next_id"`
+	Tasks   []Task      `json:"tasks"`
+	Journal []Operation `json:"journal,omitempty"`
+	History []Event     `json:"history,omitempty"`
+	Trash   []Trashed   `json:"trash,omitempty"`
+}
+
+// FileStore keeps every task in a single JSON file. Each transaction reads
+// the whole file and, for updates, atomically replaces it, holding an
+// advisory lock on a .<name>.lock file beside it so that transactions of
+// several processes do not interleave. A FileStore
+// opened with OpenEncryptedFile encrypts the file with AES-GCM.
+type FileStore struct {
+	path string
+	mu   sync.Mutex // serialises transactions within this process; the lock file across processes
+
+	keyFunc KeyFunc    // nil for plain files
+	params  *KeyParams // of the encrypted file, once known
+	key     []byte
+}
+
+// OpenFile returns a store backed by the JSON file at path, creating the
+// parent directory if needed. A missing file is treated as an empty store.
+func OpenFile(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, i18n.Errorf("create data dir: %w", err)
+	}
+	return &FileStore{path: path}, nil
+}
+
+// OpenEncryptedFile is OpenFile for a file encrypted at rest. key is asked
+// for the file key on first use. An existing plain file is read as is and
+// encrypted on the next update.
+func OpenEncryptedFile(path string, key KeyFunc) (*FileStore, error) {
+	s, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s.keyFunc = key
+	return s, nil
+}
+
+// View implements Store.
+func (s *FileStore) View(fn func(Tx) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	snap, err := s.load()
+	if err != nil {
+		return err
+	}
+	return fn(newMemTx(snap))
+}
+
+// Update implements Store.
+func (s *FileStore) Update(fn func(Tx) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.update(fn)
+}
+
+// update is Update with the lock held.
+func (s *FileStore) update(fn func(Tx) error) error {
+	snap, err := s.load()
+	if err != nil {
+		return err
+	}
+	tx := newMemTx(snap)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return s.save(tx.snapshot())
+}
+
+// Close implements Store.
+func (s *FileStore) Close() error { return nil }
+
+// lock serialises a transaction with every other one on the file, in this
+// process and in others, until unlock is called.
+func (s *FileStore) lock() (unlock func(), err error) {
+	s.mu.Lock()
+	name := filepath.Join(filepath.Dir(s.path), "."+filepath.Base(s.path)+".lock")
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, i18n.Errorf("lock tasks: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		s.mu.Unlock()
+		return nil, i18n.Errorf("lock %s: %w", name, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+		s.mu.Unlock()
+	}, nil
+}
+
+func (s *FileStore) load() (snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshot{Version: snapshotVersion, NextID: 1}, nil
+	}
+	if err != nil {
+		return snapshot{}, i18n.Errorf("read tasks: %w", err)
+	}
+	if data, err = s.decrypt(data); err != nil {
+		return snapshot{}, fmt.Errorf("%s: %w", s.path, err)
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snapshot{}, corrupt("parse "+s.path, err)
+	}
+	if snap.Version > snapshotVersion {
+		return snapshot{}, i18n.Errorf("%s: format version %d is newer than this binary supports", s.path, snap.Version)
+	}
+	if snap.Version < 2 {
+		// Upgrade in place, so reads agree on the UUIDs they hand out.
+		assignUUIDs(snap.Tasks)
+		snap.Version = snapshotVersion
+		if err := s.save(snap); err != nil {
+			return snapshot{}, err
+		}
+	}
+	return snap, nil
+}
+
+// save writes to a temporary file and renames it over the original so a
+// crash mid-write never leaves a truncated task file behind.
+func (s *FileStore) save(snap snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if data, err = s.encrypt(data); err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tasks-*.json")
+	if err != nil {
+		return i18n.Errorf("write tasks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return i18n.Errorf("write tasks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return i18n.Errorf("write tasks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return i18n.Errorf("write tasks: %w", err)
+	}
+	return nil
+}
+
+// decrypt returns the snapshot JSON in data, which may be an encrypted
+// envelope.
+func (s *FileStore) decrypt(data []byte) ([]byte, error) {
+	var env envelope
+	if json.Unmarshal(data, &env) != nil || env.Encrypted == nil {
+		return data, nil // plain snapshot
+	}
+	if s.keyFunc == nil {
+		return nil, ErrEncrypted
+	}
+	if s.key == nil || s.params == nil || !bytes.Equal(s.params.Salt, env.Encrypted.Salt) {
+		key, err := s.keyFunc(*env.Encrypted, false)
+		if err != nil {
+			return nil, err
+		}
+		if !env.Encrypted.Verify(key) {
+			return nil, ErrWrongKey
+		}
+		s.params, s.key = env.Encrypted, key
+	}
+	return unseal(env, s.key)
+}
+
+// encrypt wraps snapshot JSON in an envelope when the store is encrypted,
+// choosing a passphrase the first time.
+func (s *FileStore) encrypt(data []byte) ([]byte, error) {
+	if s.keyFunc == nil {
+		return data, nil
+	}
+	if s.key == nil {
+		params, err := NewKeyParams()
+		if err != nil {
+			return nil, err
+		}
+		key, err := s.keyFunc(params, true)
+		if err != nil {
+			return nil, err
+		}
+		params.SetCheck(key)
+		s.params, s.key = &params, key
+	}
+	return seal(*s.params, s.key, data)
+}
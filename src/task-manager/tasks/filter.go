@@ -0,0 +1,79 @@
+package tasks
+
+import "time"
+
+// Filter selects tasks. A nil Filter matches everything.
+type Filter func(t Task) bool
+
+// Match reports whether t passes f.
+func (f Filter) Match(t Task) bool {
+	return f == nil || f(t)
+}
+
+// Select returns the tasks in list that pass f, preserving order.
+func Select(list []Task, f Filter) []Task {
+	if f == nil {
+		return list
+	}
+	var out []Task
+	for _, t := range list {
+		if f(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// And matches tasks that pass every non-nil filter.
+func And(filters ...Filter) Filter {
+	var active []Filter
+	for _, f := range filters {
+		if f != nil {
+			active = append(active, f)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	return func(t Task) bool {
+		for _, f := range active {
+			if !f(t) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches tasks that pass at least one filter; a nil filter matches
+// everything.
+func Or(filters ...Filter) Filter {
+	return func(t Task) bool {
+		for _, f := range filters {
+			if f.Match(t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches tasks that fail f.
+func Not(f Filter) Filter {
+	return func(t Task) bool { return !f.Match(t) }
+}
+
+// Actionable matches tasks that are not snoozed past now.
+func Actionable(now time.Time) Filter {
+	return func(t Task) bool { return !t.IsSnoozed(now) }
+}
+
+// DueBefore matches tasks due strictly before when.
+func DueBefore(when time.Time) Filter {
+	return func(t Task) bool { return t.Due != nil && t.Due.Before(when) }
+}
+
+// DueAfter matches tasks due strictly after when.
+func DueAfter(when time.Time) Filter {
+	return func(t Task) bool { return t.Due != nil && t.Due.After(when) }
+}
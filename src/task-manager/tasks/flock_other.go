@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package tasks
+
+import "os"
+
+// lockFile does nothing where flock is missing: transactions are only
+// serialised within the process.
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
@@ -0,0 +1,189 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrNotCommitted is returned by GitStore.Update when a change was saved
+// but git failed to commit it.
+var ErrNotCommitted = errors.New("change saved but not committed to git")
+
+// GitStore is a FileStore whose JSON file lives in a git work tree and is
+// committed after every update that changes it, so the repository holds
+// the full history of the list: `git log` and `git diff` show what changed
+// and when, and pushing to a remote backs it up. It needs the git binary.
+type GitStore struct {
+	*FileStore
+	dir string // the work tree
+}
+
+// OpenGit returns a store backed by the JSON file at path, making the
+// parent directory a git repository if it is not one yet.
+func OpenGit(path string) (*GitStore, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, errors.New("the git backend needs git on the PATH")
+	}
+	fs, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &GitStore{FileStore: fs, dir: filepath.Dir(path)}
+	if _, err := os.Stat(filepath.Join(s.dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if _, err := s.git("init", "--quiet"); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.ignoreLocks(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ignoreLocks keeps the lock files of the task files out of git status.
+func (s *GitStore) ignoreLocks() error {
+	const pattern = "/.*.lock"
+	exclude := filepath.Join(s.dir, ".git", "info", "exclude")
+	data, err := os.ReadFile(exclude)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if slices.Contains(strings.Split(string(data), "\n"), pattern) {
+		return nil
+	}
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(exclude), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(exclude, append(data, pattern+"\n"...), 0o600)
+}
+
+// Update implements Store, committing the file with a message naming the
+// operations the transaction journalled or undid. The file stays locked
+// until the commit is done, so the commits of several processes do not
+// collide. If the change was saved but could not be committed, the error
+// wraps ErrNotCommitted; the next commit picks the change up.
+func (s *GitStore) Update(fn func(Tx) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	var msg string
+	err = s.update(func(tx Tx) error {
+		otx := &opTx{Tx: tx}
+		if err := fn(otx); err != nil {
+			return err
+		}
+		msg = otx.message(filepath.Base(s.path))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.commit(msg); err != nil {
+		return fmt.Errorf("%s: %w: %v", s.path, ErrNotCommitted, err)
+	}
+	return nil
+}
+
+// commit records the file in the repository, if it changed.
+func (s *GitStore) commit(msg string) error {
+	name := filepath.Base(s.path)
+	if _, err := s.git("add", "--", name); err != nil {
+		return err
+	}
+	if _, err := s.git("diff", "--cached", "--quiet", "--", name); err == nil {
+		return nil // nothing to commit
+	}
+	args := []string{"commit", "--quiet", "-m", msg, "--", name}
+	if out, _ := s.git("config", "user.email"); out == "" {
+		// git refuses to commit without an identity.
+		args = append([]string{"-c", "user.name=task", "-c", "user.email=task@localhost"}, args...)
+	}
+	_, err := s.git(args...)
+	return err
+}
+
+// git runs git in the work tree and returns its trimmed output.
+func (s *GitStore) git(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Dir = s.dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if msg := strings.TrimSpace(stderr.String()); msg != "" && errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// opTx notes the operations journalled and undone in a transaction, for
+// the commit message.
+type opTx struct {
+	Tx
+	pushed []Operation
+	undone []Operation
+}
+
+func (tx *opTx) PushOp(op Operation) error {
+	tx.pushed = append(tx.pushed, op)
+	return tx.Tx.PushOp(op)
+}
+
+func (tx *opTx) PopOp() (Operation, bool, error) {
+	op, ok, err := tx.Tx.PopOp()
+	if ok && err == nil {
+		tx.undone = append(tx.undone, op)
+	}
+	return op, ok, err
+}
+
+// message describes the transaction one task per line, under a subject
+// naming the first, e.g. "complete 3: Buy milk".
+func (tx *opTx) message(file string) string {
+	var lines []string
+	describe := func(prefix string, op Operation) {
+		for _, c := range op.Changes {
+			desc := ""
+			if t, err := tx.Get(c.ID); err == nil {
+				desc = t.Description
+			} else if c.Before != nil {
+				desc = c.Before.Description
+			}
+			lines = append(lines, fmt.Sprintf("%s%s %d: %s", prefix, op.Name, c.ID, desc))
+		}
+	}
+	for _, op := range tx.undone {
+		describe("undo ", op)
+	}
+	for _, op := range tx.pushed {
+		describe("", op)
+	}
+	switch len(lines) {
+	case 0:
+		return "update " + file
+	case 1:
+		return lines[0]
+	}
+	return fmt.Sprintf("%s (and %d more)\n\n%s\n", lines[0], len(lines)-1, strings.Join(lines, "\n"))
+}
+
+// GitDir returns the work tree of the git backend in the data directory
+// dir.
+func GitDir(dir string) string {
+	return filepath.Join(dir, "git")
+}
@@ -0,0 +1,91 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitTest opens a GitStore in a fresh directory, isolated from the
+// user's git configuration.
+func gitTest(t *testing.T) (*GitStore, *Manager) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	s, err := OpenGit(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, NewManager(s)
+}
+
+func gitLog(t *testing.T, s *GitStore) []string {
+	t.Helper()
+	out, err := s.git("log", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(out, "\n")
+}
+
+func TestGitStoreCommitsEveryChange(t *testing.T) {
+	s, m := gitTest(t)
+	if _, err := m.Add(Task{Description: "Buy milk"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(Task{Description: "Call mom"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Complete(1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	// A transaction that changes nothing makes no commit.
+	if err := s.Update(func(Tx) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"undo complete 1: Buy milk", "complete 1: Buy milk", "add 2: Call mom", "add 1: Buy milk"}
+	if got := gitLog(t, s); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("git log = %q, want %q", got, want)
+	}
+	if out, err := s.git("status", "--porcelain"); err != nil || out != "" {
+		t.Fatalf("git status = %q, %v; want a clean tree", out, err)
+	}
+}
+
+func TestGitStoreReportsFailedCommits(t *testing.T) {
+	s, m := gitTest(t)
+	hook := filepath.Join(s.dir, ".git", "hooks", "pre-commit")
+	if err := os.MkdirAll(filepath.Dir(hook), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := m.Add(Task{Description: "Buy milk"})
+	if !errors.Is(err, ErrNotCommitted) {
+		t.Fatalf("Add = %v, want ErrNotCommitted", err)
+	}
+	if got, err := m.Get(1); err != nil || got.Description != "Buy milk" {
+		t.Fatalf("Get(1) = %+v, %v; the change should be saved", got, err)
+	}
+
+	os.Remove(hook)
+	if _, err := m.Add(Task{Description: "Call mom"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := s.git("show", "HEAD:tasks.json")
+	if err != nil || !strings.Contains(out, "Buy milk") || !strings.Contains(out, "Call mom") {
+		t.Fatalf("committed file lacks a task: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,214 @@
+package tasks
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is one entry of a list's change history: who did what to which
+// task, and how each field changed. Unlike the undo journal, the history is
+// append-only and never trimmed.
+type Event struct {
+	Time        time.Time     `json:"time"`
+	User        string        `json:"user,omitempty"`
+	Op          string        `json:"op"` // the command, e.g. "edit"
+	TaskID      int           `json:"task_id"`
+	Description string        `json:"description"` // as of the event
+	Action      string        `json:"action"`      // "created", "updated" or "deleted"
+	Changes     []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is the old and new value of one field, rendered as text.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Event actions.
+const (
+	Created = "created"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+// TaskHistory returns the events about task id, oldest first.
+func TaskHistory(events []Event, id int) []Event {
+	var out []Event
+	for _, ev := range events {
+		if ev.TaskID == id {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// Track runs fn in tx and adds the changes it makes to the history under
+// op, without journalling them for undo or touching revisions. Sync uses
+// it to apply tasks exactly as received.
+func Track(tx Tx, op Operation, fn func(Tx) error) error {
+	ttx := &trackTx{Tx: tx}
+	if err := fn(ttx); err != nil {
+		return err
+	}
+	return logChanges(tx, op, ttx.changes)
+}
+
+// logChanges appends an event for every change whose task really differs
+// afterwards.
+func logChanges(tx Tx, op Operation, changes []Change) error {
+	for _, c := range changes {
+		var after *Task
+		t, err := tx.Get(c.ID)
+		switch {
+		case err == nil:
+			after = &t
+		case !errors.Is(err, ErrNotFound):
+			return err
+		}
+		ev := Event{Time: op.Time, User: op.User, Op: op.Name, TaskID: c.ID, Changes: Diff(c.Before, after)}
+		switch {
+		case c.Before == nil && after == nil:
+			continue // created and deleted again
+		case c.Before == nil:
+			ev.Action, ev.Description = Created, after.Description
+		case after == nil:
+			ev.Action, ev.Description, ev.Changes = Deleted, c.Before.Description, nil
+		default:
+			if len(ev.Changes) == 0 {
+				continue
+			}
+			ev.Action, ev.Description = Updated, after.Description
+		}
+		if err := tx.AppendHistory(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Diff lists the user-visible fields that differ between before and after;
+// nil stands for a task that does not exist. Sync metadata is ignored.
+func Diff(before, after *Task) []FieldChange {
+	var b, a Task
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+	var changes []FieldChange
+	add := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add("description", b.Description, a.Description)
+	add("status", status(b, before != nil), status(a, after != nil))
+	add("due", timeText(b.Due), timeText(a.Due))
+	add("wait", timeText(b.Wait), timeText(a.Wait))
+	add("priority", b.Priority.String(), a.Priority.String())
+	add("estimate", durationText(b.Estimate), durationText(a.Estimate))
+	add("spent", durationText(b.Spent), durationText(a.Spent))
+	add("tags", strings.Join(b.Tags, ","), strings.Join(a.Tags, ","))
+	add("parent", idText(b.Parent), idText(a.Parent))
+	add("project", b.Project, a.Project)
+	add("context", b.Context, a.Context)
+	add("assignee", b.Assignee, a.Assignee)
+	if len(a.Notes) >= len(b.Notes) && slices.Equal(a.Notes[:len(b.Notes)], b.Notes) {
+		for _, n := range a.Notes[len(b.Notes):] {
+			add("note", "", n.Text)
+		}
+	} else {
+		add("notes", strconv.Itoa(len(b.Notes)), strconv.Itoa(len(a.Notes)))
+	}
+	add("attachments", attachmentText(b.Attachments), attachmentText(a.Attachments))
+	return changes
+}
+
+func durationText(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func attachmentText(list []Attachment) string {
+	refs := make([]string, len(list))
+	for i, a := range list {
+		refs[i] = a.Ref
+	}
+	return strings.Join(refs, ", ")
+}
+
+func status(t Task, exists bool) string {
+	if !exists {
+		return ""
+	}
+	return string(t.Status.orPending())
+}
+
+func timeText(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func idText(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+// captor records the first before-image of every task written through a
+// transaction wrapper.
+type captor struct {
+	seen    map[int]bool
+	changes []Change
+}
+
+func (c *captor) capture(tx Tx, id int) error {
+	if c.seen == nil {
+		c.seen = map[int]bool{}
+	}
+	if c.seen[id] {
+		return nil
+	}
+	c.seen[id] = true
+	t, err := tx.Get(id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.changes = append(c.changes, Change{ID: id})
+	case err != nil:
+		return err
+	default:
+		c.changes = append(c.changes, Change{ID: id, Before: &t})
+	}
+	return nil
+}
+
+// trackTx captures before-images for Track and otherwise passes writes
+// through unchanged.
+type trackTx struct {
+	Tx
+	captor
+}
+
+func (t *trackTx) Put(task Task) error {
+	if err := t.capture(t.Tx, task.ID); err != nil {
+		return err
+	}
+	return t.Tx.Put(task)
+}
+
+func (t *trackTx) Delete(id int) error {
+	if err := t.capture(t.Tx, id); err != nil {
+		return err
+	}
+	return t.Tx.Delete(id)
+}
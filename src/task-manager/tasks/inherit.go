@@ -0,0 +1,49 @@
+package tasks
+
+import "slices"
+
+// Inherited returns a copy of list in which tasks without a due date or a
+// priority of their own take those of their nearest ancestor in list that
+// has one. Sorting and urgency go by it, so subtasks rank with the work
+// they belong to; the tasks themselves, as stored and shown, keep their own
+// values.
+func Inherited(list []Task) []Task {
+	byID := make(map[int]Task, len(list))
+	for _, t := range list {
+		if t.ID != 0 { // 0 is no parent, and tasks not stored yet
+			byID[t.ID] = t
+		}
+	}
+	out := slices.Clone(list)
+	for i := range out {
+		t := &out[i]
+		// The depth bound guards against a parent cycle in damaged data.
+		p, ok := byID[t.Parent]
+		for depth := 0; ok && depth < len(list) && (t.Due == nil || t.Priority == PriorityNone); depth++ {
+			if t.Due == nil {
+				t.Due = p.Due
+			}
+			if t.Priority == PriorityNone {
+				t.Priority = p.Priority
+			}
+			p, ok = byID[p.Parent]
+		}
+	}
+	return out
+}
+
+// sortInherited stably sorts list by cmp applied to the inherited values
+// of its tasks.
+func sortInherited(list []Task, cmp func(a, b Task) int) {
+	eff := Inherited(list)
+	order := make([]int, len(list))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int { return cmp(eff[i], eff[j]) })
+	sorted := make([]Task, len(list))
+	for k, i := range order {
+		sorted[k] = list[i]
+	}
+	copy(list, sorted)
+}
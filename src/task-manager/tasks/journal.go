@@ -0,0 +1,181 @@
+package tasks
+
+import (
+	"errors"
+	"time"
+)
+
+// JournalLimit is the number of operations each store remembers for undo.
+const JournalLimit = 100
+
+// ErrNothingToUndo is returned by Undo when the journal is empty.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Change is the state of one task before an operation touched it. A nil
+// Before means the operation created the task.
+type Change struct {
+	ID     int   `json:"id"`
+	Before *Task `json:"before,omitempty"`
+}
+
+// Operation is a journal entry: one mutating command, who ran it, and the
+// before-image of every task and trash entry it changed.
+type Operation struct {
+	Name    string        `json:"name"`
+	Time    time.Time     `json:"time"`
+	User    string        `json:"user,omitempty"`
+	Changes []Change      `json:"changes"`
+	Trash   []TrashChange `json:"trash,omitempty"`
+}
+
+// Record runs fn in tx and, if it changed anything, appends op (with its
+// Changes filled in) to the journal so it can be reverted with Undo, and
+// the changes to the history. Every task fn writes gets a UUID if it has
+// none and its revision bumped.
+func Record(tx Tx, op Operation, fn func(Tx) error) error {
+	jtx := &journalTx{Tx: tx, now: op.Time}
+	if err := fn(jtx); err != nil {
+		return err
+	}
+	if len(jtx.changes) == 0 && len(jtx.trash) == 0 {
+		return nil
+	}
+	op.Changes, op.Trash = jtx.changes, jtx.trash
+	if err := tx.PushOp(op); err != nil {
+		return err
+	}
+	return logChanges(tx, op, op.Changes)
+}
+
+// Undo reverts the most recent journalled operation, restoring every task
+// it changed and removing every task it created, and logs that to the
+// history as an "undo" by user. Restored tasks get a new revision so that
+// sync propagates the undo.
+func Undo(tx Tx, now time.Time, user string) (Operation, error) {
+	op, ok, err := tx.PopOp()
+	if err != nil {
+		return Operation{}, err
+	}
+	if !ok {
+		return Operation{}, ErrNothingToUndo
+	}
+	ttx := &trackTx{Tx: tx}
+	if err := undo(ttx, op, now); err != nil {
+		return Operation{}, err
+	}
+	return op, logChanges(tx, Operation{Name: "undo", Time: now, User: user}, ttx.changes)
+}
+
+func undo(tx Tx, op Operation, now time.Time) error {
+	for i := len(op.Trash) - 1; i >= 0; i-- {
+		c := op.Trash[i]
+		if c.Before != nil {
+			if err := tx.PutTrash(*c.Before); err != nil {
+				return err
+			}
+		} else if err := tx.DeleteTrash(c.ID); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	for i := len(op.Changes) - 1; i >= 0; i-- {
+		c := op.Changes[i]
+		if c.Before == nil {
+			if err := tx.Delete(c.ID); err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+			continue
+		}
+		t := *c.Before
+		var cur *Task
+		if got, err := tx.Get(c.ID); err == nil {
+			cur = &got
+			t.Rev = max(t.Rev, got.Rev)
+		}
+		t.Rev++
+		t.UpdatedAt = &now
+		stamp(cur, &t, now)
+		if err := tx.Put(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// journalTx captures the first before-image of every task and trash entry
+// written through it and maintains the tasks' sync metadata, field stamps
+// included.
+type journalTx struct {
+	Tx
+	captor
+	now       time.Time
+	trashSeen map[int]bool
+	trash     []TrashChange
+}
+
+func (j *journalTx) Put(t Task) error {
+	if err := j.capture(j.Tx, t.ID); err != nil {
+		return err
+	}
+	var before *Task
+	cur, err := j.Tx.Get(t.ID)
+	switch {
+	case err == nil:
+		before = &cur
+		t.Rev = cur.Rev + 1
+		if t.UUID == "" {
+			t.UUID = cur.UUID
+		}
+	case errors.Is(err, ErrNotFound):
+		t.Rev++
+	default:
+		return err
+	}
+	if t.UUID == "" {
+		t.UUID = NewUUID()
+	}
+	now := j.now
+	t.UpdatedAt = &now
+	stamp(before, &t, now)
+	return j.Tx.Put(t)
+}
+
+func (j *journalTx) Delete(id int) error {
+	if err := j.capture(j.Tx, id); err != nil {
+		return err
+	}
+	return j.Tx.Delete(id)
+}
+
+func (j *journalTx) PutTrash(e Trashed) error {
+	if err := j.captureTrash(e.Task.ID); err != nil {
+		return err
+	}
+	return j.Tx.PutTrash(e)
+}
+
+func (j *journalTx) DeleteTrash(id int) error {
+	if err := j.captureTrash(id); err != nil {
+		return err
+	}
+	return j.Tx.DeleteTrash(id)
+}
+
+func (j *journalTx) captureTrash(id int) error {
+	if j.trashSeen[id] {
+		return nil
+	}
+	if j.trashSeen == nil {
+		j.trashSeen = map[int]bool{}
+	}
+	j.trashSeen[id] = true
+	e, err := trashEntry(j.Tx, id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		j.trash = append(j.trash, TrashChange{ID: id})
+	case err != nil:
+		return err
+	default:
+		j.trash = append(j.trash, TrashChange{ID: id, Before: &e})
+	}
+	return nil
+}
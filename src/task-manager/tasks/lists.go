@@ -0,0 +1,84 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"gopatterns/task-manager/i18n"
+)
+
+var listNameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidListName checks that name can be used as a list name: letters,
+// digits, '-' and '_', so it is safe in file names and URLs.
+func ValidListName(name string) error {
+	if !listNameRE.MatchString(name) {
+		return i18n.Errorf("invalid list name %q (use letters, digits, - and _)", name)
+	}
+	return nil
+}
+
+// FileListPath is the JSON file holding list in dir. The default list keeps
+// the historical name tasks.json.
+func FileListPath(dir, list string) string {
+	if list == "" || list == DefaultList {
+		return filepath.Join(dir, "tasks.json")
+	}
+	return filepath.Join(dir, "tasks-"+list+".json")
+}
+
+// FileLists returns the names of the lists stored as JSON files in dir.
+func FileLists(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "tasks*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, m := range matches {
+		base := strings.TrimSuffix(filepath.Base(m), ".json")
+		switch {
+		case base == "tasks":
+			names = append(names, DefaultList)
+		case strings.HasPrefix(base, "tasks-") && ValidListName(base[len("tasks-"):]) == nil:
+			names = append(names, base[len("tasks-"):])
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// BoltLists returns the names of the lists in the bolt database at path. A
+// missing database has no lists.
+func BoltLists(path string) ([]string, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second, ReadOnly: true})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, i18n.Errorf("open %s: database is locked by another task process", path)
+		}
+		return nil, i18n.Errorf("open %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var names []string
+	err = db.View(func(tx *bolt.Tx) error {
+		lists := tx.Bucket(listsBucket)
+		if lists == nil {
+			return nil
+		}
+		return lists.ForEachBucket(func(k []byte) error {
+			names = append(names, string(k))
+			return nil
+		})
+	})
+	return names, err
+}
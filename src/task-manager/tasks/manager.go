@@ -0,0 +1,232 @@
+package tasks
+
+import (
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// Manager is the task manager as a library, for Go programs that embed it
+// rather than run the task command: the command's operations over any
+// Store, each in its own transaction and journalled like the command's, so
+// History shows them and Undo or 'task undo' reverts them.
+//
+//	m := tasks.NewManager(tasks.NewMemStore())
+//	t, err := m.Add(tasks.Task{Description: "Write report", Priority: tasks.PriorityHigh})
+//	...
+//	open, err := m.Query("status=pending and priority>=medium")
+type Manager struct {
+	store Store
+
+	// Now stamps every change; it defaults to time.Now.
+	Now func() time.Time
+	// User is who the journal and history say made the changes.
+	User string
+}
+
+// NewManager returns a Manager over store, which it closes on Close.
+func NewManager(store Store) *Manager {
+	return &Manager{store: store, Now: time.Now}
+}
+
+// Store returns the store m works on, for the operations Manager lacks.
+// Wrap changes in Record so they are journalled like m's.
+func (m *Manager) Store() Store { return m.store }
+
+// Close closes the store.
+func (m *Manager) Close() error { return m.store.Close() }
+
+// Add stores t as a new task and returns it with its ID. CreatedAt
+// defaults to now, and tags and context are normalised as the command
+// line does.
+func (m *Manager) Add(t Task) (Task, error) {
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = m.Now()
+	}
+	if !t.Priority.Valid() {
+		return Task{}, i18n.Errorf("%w: priority %d", ErrInvalid, t.Priority)
+	}
+	t.Tags = NormalizeTags(t.Tags)
+	t.Context = NormalizeContext(t.Context)
+	t.Project = strings.TrimSpace(t.Project)
+	return m.change("add", func(tx Tx) (Task, error) {
+		return Insert(tx, t)
+	})
+}
+
+// Get returns task id or an ErrNotFound error.
+func (m *Manager) Get(id int) (t Task, err error) {
+	err = m.store.View(func(tx Tx) error {
+		t, err = tx.Get(id)
+		return err
+	})
+	return t, err
+}
+
+// List returns the tasks matching every filter, ordered by ID.
+func (m *Manager) List(filters ...Filter) ([]Task, error) {
+	var list []Task
+	err := m.store.View(func(tx Tx) (err error) {
+		list, err = tx.List()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Select(list, And(filters...)), nil
+}
+
+// Query is List with a filter expression in the syntax of ParseFilter.
+func (m *Manager) Query(expr string) ([]Task, error) {
+	f, err := ParseFilter(expr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.List(f)
+}
+
+// Edit applies fn to task id and stores the result, unless fn returns an
+// error. The ID cannot be changed, and the description, priority and
+// parent are checked as Add checks them.
+func (m *Manager) Edit(id int, fn func(*Task) error) (Task, error) {
+	return m.change("edit", func(tx Tx) (Task, error) {
+		t, err := tx.Get(id)
+		if err != nil {
+			return Task{}, err
+		}
+		if err := fn(&t); err != nil {
+			return Task{}, err
+		}
+		t.ID = id
+		if t.Description = strings.TrimSpace(t.Description); t.Description == "" {
+			return Task{}, i18n.Errorf("%w: empty description", ErrInvalid)
+		}
+		if !t.Priority.Valid() {
+			return Task{}, i18n.Errorf("%w: priority %d", ErrInvalid, t.Priority)
+		}
+		if err := CheckParent(tx, id, t.Parent); err != nil {
+			return Task{}, err
+		}
+		return t, tx.Put(t)
+	})
+}
+
+// Complete marks task id done; see the function Complete for force.
+func (m *Manager) Complete(id int, force bool) (Task, error) {
+	return m.change("complete", func(tx Tx) (Task, error) {
+		return Complete(tx, id, m.Now(), force)
+	})
+}
+
+// Start marks task id as in progress.
+func (m *Manager) Start(id int) (Task, error) {
+	return m.change("start", func(tx Tx) (Task, error) {
+		return Start(tx, id, m.Now())
+	})
+}
+
+// Stop puts task id back in the backlog, adding to the time spent on it.
+func (m *Manager) Stop(id int) (Task, error) {
+	return m.change("stop", func(tx Tx) (Task, error) {
+		return Stop(tx, id, m.Now())
+	})
+}
+
+// Reopen makes a done or cancelled task id pending again.
+func (m *Manager) Reopen(id int) (Task, error) {
+	return m.change("reopen", func(tx Tx) (Task, error) {
+		return Reopen(tx, id, m.Now())
+	})
+}
+
+// Assign sets the assignee of task id; "" unassigns it.
+func (m *Manager) Assign(id int, user string) (Task, error) {
+	return m.change("assign", func(tx Tx) (Task, error) {
+		return Assign(tx, id, user)
+	})
+}
+
+// Note adds a note to task id.
+func (m *Manager) Note(id int, text string) (Task, error) {
+	if text = strings.TrimSpace(text); text == "" {
+		return Task{}, i18n.Errorf("%w: empty note", ErrInvalid)
+	}
+	return m.change("note", func(tx Tx) (Task, error) {
+		t, err := tx.Get(id)
+		if err != nil {
+			return Task{}, err
+		}
+		t.Annotate(m.Now(), text)
+		return t, tx.Put(t)
+	})
+}
+
+// Delete moves task id to the trash, from which Restore brings it back.
+func (m *Manager) Delete(id int) (t Task, err error) {
+	err = m.update("delete", func(tx Tx) error {
+		t, err = Discard(tx, id, m.Now(), m.User)
+		return err
+	})
+	return t, err
+}
+
+// Restore brings task id back from the trash and returns it, with a new ID
+// if its old one has been taken since.
+func (m *Manager) Restore(id int) (Task, error) {
+	return m.change("restore", func(tx Tx) (Task, error) {
+		return Restore(tx, id)
+	})
+}
+
+// Trash returns the deleted tasks that Restore can bring back.
+func (m *Manager) Trash() (list []Trashed, err error) {
+	err = m.store.View(func(tx Tx) error {
+		list, err = tx.Trash()
+		return err
+	})
+	return list, err
+}
+
+// History returns the changes made to task id, oldest first, or to every
+// task if id is 0.
+func (m *Manager) History(id int) (events []Event, err error) {
+	err = m.store.View(func(tx Tx) error {
+		events, err = tx.History()
+		return err
+	})
+	if err != nil || id == 0 {
+		return events, err
+	}
+	return TaskHistory(events, id), nil
+}
+
+// Undo reverts the most recent operation, whoever made it, and returns
+// it; ErrNothingToUndo if there is none.
+func (m *Manager) Undo() (op Operation, err error) {
+	err = m.store.Update(func(tx Tx) error {
+		op, err = Undo(tx, m.Now(), m.User)
+		return err
+	})
+	return op, err
+}
+
+// change runs fn as the journalled operation op and returns the task fn
+// returns as stored, with the UUID and revision Record gave it.
+func (m *Manager) change(op string, fn func(Tx) (Task, error)) (t Task, err error) {
+	err = m.update(op, func(tx Tx) error {
+		if t, err = fn(tx); err != nil {
+			return err
+		}
+		t, err = tx.Get(t.ID)
+		return err
+	})
+	return t, err
+}
+
+// update runs fn as the journalled operation op.
+func (m *Manager) update(op string, fn func(Tx) error) error {
+	return m.store.Update(func(tx Tx) error {
+		return Record(tx, Operation{Name: op, Time: m.Now(), User: m.User}, fn)
+	})
+}
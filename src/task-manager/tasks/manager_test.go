@@ -0,0 +1,247 @@
+package tasks
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestManager returns a Manager over a MemStore whose clock advances
+// a minute on every change.
+func newTestManager() *Manager {
+	m := NewManager(NewMemStore())
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	m.Now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	m.User = "tester"
+	return m
+}
+
+func mustAdd(t *testing.T, m *Manager, task Task) Task {
+	t.Helper()
+	got, err := m.Add(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestManagerAdd(t *testing.T) {
+	m := newTestManager()
+	got := mustAdd(t, m, Task{Description: "  Buy milk ", Tags: []string{"work", "+home", " home"}, Context: " @errands", Project: " house "})
+	if got.ID != 1 || got.Description != "Buy milk" || got.Status != Pending {
+		t.Fatalf("Add = %+v", got)
+	}
+	if got.UUID == "" || got.Rev == 0 || got.CreatedAt.IsZero() {
+		t.Fatalf("Add did not stamp the task: %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "home" || got.Tags[1] != "work" || got.Context != "errands" || got.Project != "house" {
+		t.Fatalf("Add did not normalise the task: %+v", got)
+	}
+	stored, err := m.Get(got.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.UUID != got.UUID || stored.Description != got.Description {
+		t.Fatalf("Get = %+v, want %+v", stored, got)
+	}
+}
+
+func TestManagerErrors(t *testing.T) {
+	m := newTestManager()
+	parent := mustAdd(t, m, Task{Description: "Move house"})
+	mustAdd(t, m, Task{Description: "Pack books", Parent: parent.ID})
+
+	tests := []struct {
+		name string
+		fn   func() error
+		want error // nil for any error
+	}{
+		{"empty description", func() error { _, err := m.Add(Task{Description: " "}); return err }, ErrInvalid},
+		{"bad priority", func() error { _, err := m.Add(Task{Description: "x", Priority: 10}); return err }, ErrInvalid},
+		{"missing parent", func() error { _, err := m.Add(Task{Description: "x", Parent: 9}); return err }, ErrNotFound},
+		{"get missing", func() error { _, err := m.Get(9); return err }, ErrNotFound},
+		{"open subtasks", func() error { _, err := m.Complete(parent.ID, false); return err }, ErrOpenSubtasks},
+		{"reopen pending", func() error { _, err := m.Reopen(parent.ID); return err }, ErrTransition},
+		{"empty note", func() error { _, err := m.Note(parent.ID, " "); return err }, ErrInvalid},
+		{"edit to empty", func() error {
+			_, err := m.Edit(parent.ID, func(t *Task) error { t.Description = ""; return nil })
+			return err
+		}, ErrInvalid},
+		{"own parent", func() error {
+			_, err := m.Edit(parent.ID, func(t *Task) error { t.Parent = parent.ID; return nil })
+			return err
+		}, nil},
+	}
+	for _, tt := range tests {
+		if err := tt.fn(); err == nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if list, _ := m.List(); len(list) != 2 {
+		t.Fatalf("failed operations left %d tasks, want 2", len(list))
+	}
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	m := newTestManager()
+	task := mustAdd(t, m, Task{Description: "Write report"})
+
+	task, err := m.Start(task.ID)
+	if err != nil || task.Status != InProgress || task.StartedAt == nil {
+		t.Fatalf("Start = %+v, %v", task, err)
+	}
+	task, err = m.Stop(task.ID)
+	if err != nil || task.Status != Pending || task.Spent <= 0 || task.StartedAt != nil {
+		t.Fatalf("Stop = %+v, %v", task, err)
+	}
+	task, err = m.Complete(task.ID, false)
+	if err != nil || !task.Done() || task.CompletedAt == nil {
+		t.Fatalf("Complete = %+v, %v", task, err)
+	}
+	if _, err := m.Complete(task.ID, false); !errors.Is(err, ErrAlreadyCompleted) {
+		t.Fatalf("second Complete err = %v, want ErrAlreadyCompleted", err)
+	}
+	task, err = m.Reopen(task.ID)
+	if err != nil || task.Status != Pending || task.CompletedAt != nil {
+		t.Fatalf("Reopen = %+v, %v", task, err)
+	}
+	task, err = m.Assign(task.ID, "ana")
+	if err != nil || task.Assignee != "ana" {
+		t.Fatalf("Assign = %+v, %v", task, err)
+	}
+	task, err = m.Note(task.ID, "ask Bob for figures")
+	if err != nil || len(task.Notes) != 1 {
+		t.Fatalf("Note = %+v, %v", task, err)
+	}
+
+	events, err := m.History(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || events[0].User != "tester" {
+		t.Fatalf("History = %+v", events)
+	}
+}
+
+func TestManagerUndo(t *testing.T) {
+	m := newTestManager()
+	task := mustAdd(t, m, Task{Description: "Call mom"})
+	if _, err := m.Complete(task.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	op, err := m.Undo()
+	if err != nil || op.Name != "complete" {
+		t.Fatalf("Undo = %+v, %v", op, err)
+	}
+	if got, _ := m.Get(task.ID); got.Done() {
+		t.Fatal("Undo did not reopen the task")
+	}
+	if _, err := m.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get(task.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("undoing add: Get err = %v, want ErrNotFound", err)
+	}
+	if _, err := m.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Undo on an empty journal err = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestManagerDeleteRestore(t *testing.T) {
+	m := newTestManager()
+	task := mustAdd(t, m, Task{Description: "Water plants"})
+	if _, err := m.Delete(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get(task.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete err = %v, want ErrNotFound", err)
+	}
+	trash, err := m.Trash()
+	if err != nil || len(trash) != 1 || trash[0].User != "tester" {
+		t.Fatalf("Trash = %+v, %v", trash, err)
+	}
+	restored, err := m.Restore(task.ID)
+	if err != nil || restored.Description != "Water plants" || restored.UUID != task.UUID {
+		t.Fatalf("Restore = %+v, %v", restored, err)
+	}
+	if trash, _ := m.Trash(); len(trash) != 0 {
+		t.Fatalf("Trash after Restore = %+v", trash)
+	}
+}
+
+func TestManagerQuery(t *testing.T) {
+	m := newTestManager()
+	mustAdd(t, m, Task{Description: "Buy milk", Tags: []string{"home"}, Priority: PriorityHigh})
+	mustAdd(t, m, Task{Description: "Fix bike", Tags: []string{"home"}})
+	mustAdd(t, m, Task{Description: "Send invoice", Project: "work", Priority: PriorityMedium})
+	if _, err := m.Complete(2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want []int
+	}{
+		{"tag=home", []int{1, 2}},
+		{"status=pending and priority>=medium", []int{1, 3}},
+		{"not project=work", []int{1, 2}},
+		{"search=bike", []int{2}},
+	}
+	for _, tt := range tests {
+		list, err := m.Query(tt.expr)
+		if err != nil {
+			t.Fatalf("Query(%q): %v", tt.expr, err)
+		}
+		if got := ids(list); !slices.Equal(got, tt.want) {
+			t.Errorf("Query(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+	if _, err := m.Query("colour=red"); err == nil {
+		t.Error("Query with an unknown field succeeded")
+	}
+}
+
+func TestMemStoreRollsBackFailedUpdates(t *testing.T) {
+	s := NewMemStore()
+	m := NewManager(s)
+	task := mustAdd(t, m, Task{Description: "Buy milk"})
+
+	boom := errors.New("boom")
+	err := s.Update(func(tx Tx) error {
+		task.Description = "changed"
+		if err := tx.Put(task); err != nil {
+			return err
+		}
+		if _, err := tx.NextID(); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Update err = %v, want boom", err)
+	}
+	if got, _ := m.Get(task.ID); got.Description != "Buy milk" {
+		t.Fatalf("failed Update changed the task to %q", got.Description)
+	}
+	if next := mustAdd(t, m, Task{Description: "Call mom"}); next.ID != 2 {
+		t.Fatalf("failed Update used up an ID: next ID = %d, want 2", next.ID)
+	}
+}
+
+func TestMemStoreCopiesTasks(t *testing.T) {
+	m := NewManager(NewMemStore())
+	task := mustAdd(t, m, Task{Description: "Buy milk", Tags: []string{"home"}})
+	task.Tags[0] = "changed"
+	list, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	list[0].Tags = append(list[0].Tags[:0], "again")
+	if got, _ := m.Get(task.ID); got.Tags[0] != "home" {
+		t.Fatalf("stored tags = %v, want [home]", got.Tags)
+	}
+}
@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// MemStore keeps the tasks in memory only, for tests and for programs that
+// embed the task manager and keep nothing, or keep it their own way. Like
+// FileStore it runs each transaction on a copy of the data, encoded the
+// way FileStore writes it, so a failed Update changes nothing and the
+// tasks it hands out never share memory with the stored ones.
+type MemStore struct {
+	mu   sync.Mutex
+	data []byte // the encoded snapshot; nil while empty
+}
+
+// NewMemStore returns an empty in-memory store.
+func NewMemStore() *MemStore {
+	return &MemStore{}
+}
+
+// View implements Store.
+func (s *MemStore) View(fn func(Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.load()
+	if err != nil {
+		return err
+	}
+	return fn(newMemTx(snap))
+}
+
+// Update implements Store.
+func (s *MemStore) Update(fn func(Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.load()
+	if err != nil {
+		return err
+	}
+	tx := newMemTx(snap)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	data, err := json.Marshal(tx.snapshot())
+	if err != nil {
+		return err
+	}
+	s.data = data
+	return nil
+}
+
+// Close implements Store. The tasks stay in memory until s is dropped.
+func (s *MemStore) Close() error { return nil }
+
+func (s *MemStore) load() (snapshot, error) {
+	if s.data == nil {
+		return snapshot{Version: snapshotVersion, NextID: 1}, nil
+	}
+	var snap snapshot
+	if err := json.Unmarshal(s.data, &snap); err != nil {
+		return snapshot{}, corrupt("memory store", err)
+	}
+	return snap, nil
+}
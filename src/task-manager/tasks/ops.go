@@ -0,0 +1,160 @@
+package tasks
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// Errors reported by the operations below; front ends map them to their
+// own responses.
+var (
+	ErrInvalid          = errors.New("invalid task")
+	ErrAlreadyCompleted = errors.New("already completed")
+	ErrOpenSubtasks     = errors.New("open subtasks") // only for errors.Is; messages count them
+	ErrTransition       = errors.New("invalid status change")
+)
+
+// Insert stores t as a new task with a fresh ID, after checking that it has
+// a description and that its parent exists. It returns the stored task.
+func Insert(tx Tx, t Task) (Task, error) {
+	t.Description = strings.TrimSpace(t.Description)
+	if t.Description == "" {
+		return Task{}, i18n.Errorf("%w: empty description", ErrInvalid)
+	}
+	if t.Parent < 0 {
+		return Task{}, i18n.Errorf("%w: parent ID %d", ErrInvalid, t.Parent)
+	}
+	id, err := tx.NextID()
+	if err != nil {
+		return Task{}, err
+	}
+	t.ID = id
+	t.Status = t.Status.orPending()
+	if err := CheckParent(tx, id, t.Parent); err != nil {
+		return Task{}, err
+	}
+	return t, tx.Put(t)
+}
+
+// Complete marks task id done at now. It refuses tasks that are already
+// done and, unless force is set, tasks with open subtasks.
+func Complete(tx Tx, id int, now time.Time, force bool) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if t.Done() {
+		return Task{}, i18n.Errorf("task %d is %w", id, ErrAlreadyCompleted)
+	}
+	if !force {
+		list, err := tx.List()
+		if err != nil {
+			return Task{}, err
+		}
+		if open := OpenChildren(list, id); len(open) > 0 {
+			err := i18n.Pluralf(len(open), "task %d has %d open subtask", "task %d has %d open subtasks", id, len(open))
+			return Task{}, openSubtasksError{err}
+		}
+	}
+	if err := t.SetStatus(Done, now); err != nil {
+		return Task{}, err
+	}
+	return t, tx.Put(t)
+}
+
+// openSubtasksError is the error of Complete for a task with open
+// subtasks. Its message counts them, and it matches ErrOpenSubtasks.
+type openSubtasksError struct{ error }
+
+func (e openSubtasksError) Unwrap() error        { return e.error }
+func (e openSubtasksError) Is(target error) bool { return target == ErrOpenSubtasks }
+
+// Start marks task id as in progress. Starting a task in progress again
+// keeps its original start time.
+func Start(tx Tx, id int, now time.Time) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if t.Status == InProgress {
+		return t, nil
+	}
+	return Transition(tx, id, InProgress, now)
+}
+
+// Stop moves task id from in progress or waiting back to the backlog,
+// keeping the time it was in progress. Stopping a pending task does
+// nothing; closed tasks must be reopened instead.
+func Stop(tx Tx, id int, now time.Time) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	switch {
+	case t.Status.orPending() == Pending:
+		return t, nil
+	case t.Closed():
+		return Task{}, i18n.Errorf("%w: task %d is %s; reopen it instead", ErrTransition, id, i18n.Word(t.Status))
+	}
+	return Transition(tx, id, Pending, now)
+}
+
+// Reopen moves task id from done or cancelled back to the backlog.
+func Reopen(tx Tx, id int, now time.Time) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if !t.Closed() {
+		return Task{}, i18n.Errorf("%w: task %d is %s, not done or cancelled", ErrTransition, id, i18n.Word(t.Status.orPending()))
+	}
+	return Transition(tx, id, Pending, now)
+}
+
+// Transition moves task id to status to at now, if the state machine allows
+// it.
+func Transition(tx Tx, id int, to Status, now time.Time) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if err := t.SetStatus(to, now); err != nil {
+		return Task{}, err
+	}
+	return t, tx.Put(t)
+}
+
+// Snooze hides task id from the default list until until; nil wakes it.
+func Snooze(tx Tx, id int, until *time.Time) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if t.Closed() && until != nil {
+		return Task{}, i18n.Errorf("task %d is %w", id, ErrAlreadyCompleted)
+	}
+	t.Wait = until
+	return t, tx.Put(t)
+}
+
+// Assign sets the assignee of task id; "" unassigns it.
+func Assign(tx Tx, id int, user string) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	t.Assignee = strings.TrimSpace(user)
+	return t, tx.Put(t)
+}
+
+// Remove deletes task id and returns it as it was.
+func Remove(tx Tx, id int) (Task, error) {
+	t, err := tx.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	return t, tx.Delete(id)
+}
@@ -0,0 +1,72 @@
+package tasks
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// Priority ranks tasks; higher values are more important. The named levels
+// cover most needs, but any value from 0 to 9 is accepted.
+type Priority int
+
+const (
+	PriorityNone   Priority = 0
+	PriorityLow    Priority = 1
+	PriorityMedium Priority = 2
+	PriorityHigh   Priority = 3
+
+	maxPriority Priority = 9
+)
+
+// ParsePriority accepts low/medium/high (or L/M/H), "none", or a number 0-9.
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return PriorityNone, nil
+	case "l", "low":
+		return PriorityLow, nil
+	case "m", "medium", "med":
+		return PriorityMedium, nil
+	case "h", "high":
+		return PriorityHigh, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || Priority(n) > maxPriority {
+		return 0, i18n.Errorf("invalid priority %q (want low, medium, high or 0-%d)", s, maxPriority)
+	}
+	return Priority(n), nil
+}
+
+// Valid reports whether p is within the accepted 0-9 range.
+func (p Priority) Valid() bool {
+	return p >= PriorityNone && p <= maxPriority
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityNone:
+		return ""
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	default:
+		return strconv.Itoa(int(p))
+	}
+}
+
+// SortByPriority orders list by descending priority, subtasks without one
+// taking their parent's, then by ID.
+func SortByPriority(list []Task) {
+	sortInherited(list, func(a, b Task) int {
+		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"sort"
+	"strings"
+)
+
+// NormalizeContext trims a context name and its optional GTD-style "@"
+// prefix, so "@home" and "home" are the same context.
+func NormalizeContext(name string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+}
+
+// InProject matches tasks in project or one of its sub-projects, written
+// with dots: "website" matches "website" and "website.blog".
+func InProject(project string) Filter {
+	return func(t Task) bool {
+		return t.Project == project || strings.HasPrefix(t.Project, project+".")
+	}
+}
+
+// InContext matches tasks in the given context.
+func InContext(context string) Filter {
+	context = NormalizeContext(context)
+	return func(t Task) bool { return t.Context == context }
+}
+
+// AssignedTo matches tasks assigned to user; "" matches unassigned tasks.
+func AssignedTo(user string) Filter {
+	user = strings.TrimSpace(user)
+	return func(t Task) bool { return t.Assignee == user }
+}
+
+// Assignees returns the distinct assignees in list, sorted.
+func Assignees(list []Task) []string {
+	seen := map[string]bool{}
+	var users []string
+	for _, t := range list {
+		if t.Assignee != "" && !seen[t.Assignee] {
+			seen[t.Assignee] = true
+			users = append(users, t.Assignee)
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+// ProjectCount is the number of open and done tasks in a project;
+// cancelled tasks count as neither.
+type ProjectCount struct {
+	Project string `json:"project"`
+	Pending int    `json:"pending"`
+	Done    int    `json:"done"`
+}
+
+// CountProjects tallies tasks per project, ordered by project name. Tasks
+// without a project are not counted.
+func CountProjects(list []Task) []ProjectCount {
+	counts := map[string]*ProjectCount{}
+	for _, t := range list {
+		if t.Project == "" {
+			continue
+		}
+		c, ok := counts[t.Project]
+		if !ok {
+			c = &ProjectCount{Project: t.Project}
+			counts[t.Project] = c
+		}
+		switch {
+		case t.Done():
+			c.Done++
+		case !t.Closed():
+			c.Pending++
+		}
+	}
+	out := make([]ProjectCount, 0, len(counts))
+	for _, c := range counts {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Project < out[j].Project })
+	return out
+}
@@ -0,0 +1,318 @@
+package tasks
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// FilterFields lists the fields a filter expression can test.
+var FilterFields = []string{"project", "tag", "context", "assignee", "status", "priority", "search", "due", "created", "completed"}
+
+// A DateParser reads the value of a date field in a filter expression,
+// such as "2025-01-01" or "tomorrow".
+type DateParser func(string) (time.Time, error)
+
+// ParseFilter compiles a filter expression such as
+//
+//	status=pending and (tag=home or priority=high) and due<2025-01-01
+//
+// Terms compare a field with a value using = (or :) and !=, and priority
+// and the date fields due, created and completed also with <, <=, > and
+// >=; they combine with and, or and not, in that order of precedence, and
+// parentheses. Adjacent terms without an operator must all match. project
+// matches sub-projects too, assignee=none unassigned tasks, due=none tasks
+// without a due date, and search is the substring match of Search. Values
+// with spaces or colons go in double quotes.
+//
+// date reads the values of date fields; nil accepts only YYYY-MM-DD. A
+// date that is the last second of a day, as one without a time of day is,
+// compares with whole days, so due<=2025-01-01 includes tasks due at any
+// time that day.
+func ParseFilter(expr string, date DateParser) (Filter, error) {
+	toks, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, i18n.Errorf("filter: empty expression")
+	}
+	if date == nil {
+		date = parseDay
+	}
+	p := &filterParser{toks: toks, date: date}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, i18n.Errorf("filter: unexpected %q", p.toks[p.pos].text)
+	}
+	return f, nil
+}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	var toks []filterToken
+	rs := []rune(expr)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case (r == '!' || r == '<' || r == '>') && i+1 < len(rs) && rs[i+1] == '=':
+			toks = append(toks, filterToken{text: string(rs[i : i+2])})
+			i += 2
+		case strings.ContainsRune("()=:<>", r):
+			toks = append(toks, filterToken{text: string(r)})
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			if j == len(rs) {
+				return nil, i18n.Errorf("filter: unterminated quote")
+			}
+			toks = append(toks, filterToken{text: string(rs[i+1 : j]), quoted: true})
+			i = j + 1
+		default:
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !strings.ContainsRune(`()=:!<>"`, rs[j]) {
+				j++
+			}
+			if j == i {
+				return nil, i18n.Errorf("filter: unexpected %q", r)
+			}
+			toks = append(toks, filterToken{text: string(rs[i:j])})
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+type filterParser struct {
+	toks []filterToken
+	pos  int
+	date DateParser
+}
+
+// filterOps are the comparison operators a term can use.
+var filterOps = []string{"=", ":", "!=", "<", "<=", ">", ">="}
+
+// keyword reports whether the next token is the unquoted word kw, and
+// consumes it if so.
+func (p *filterParser) keyword(kw string) bool {
+	if p.pos < len(p.toks) && !p.toks[p.pos].quoted && strings.EqualFold(p.toks[p.pos].text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) or() (Filter, error) {
+	f, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		g, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		f = Or(f, g)
+	}
+	return f, nil
+}
+
+func (p *filterParser) and() (Filter, error) {
+	f, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.toks) {
+		if !p.keyword("and") {
+			// Implicit and, unless the group or expression ends here.
+			if t := p.toks[p.pos]; !t.quoted && (t.text == ")" || strings.EqualFold(t.text, "or")) {
+				break
+			}
+		}
+		g, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		f = And(f, g)
+	}
+	return f, nil
+}
+
+func (p *filterParser) not() (Filter, error) {
+	if p.keyword("not") {
+		f, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return Not(f), nil
+	}
+	if p.keyword("(") {
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, i18n.Errorf("filter: missing )")
+		}
+		return f, nil
+	}
+	return p.term()
+}
+
+func (p *filterParser) term() (Filter, error) {
+	if p.pos+3 > len(p.toks) {
+		if p.pos < len(p.toks) {
+			return nil, i18n.Errorf("filter: incomplete term at %q (want field=value)", p.toks[p.pos].text)
+		}
+		return nil, i18n.Errorf("filter: unexpected end (want field=value)")
+	}
+	field, op, val := p.toks[p.pos], p.toks[p.pos+1], p.toks[p.pos+2]
+	if field.quoted || op.quoted || !slices.Contains(filterOps, op.text) {
+		return nil, i18n.Errorf("filter: expected field=value at %q", field.text)
+	}
+	p.pos += 3
+	name := strings.ToLower(field.text)
+	switch name {
+	case "priority":
+		return priorityFilter(op.text, val.text)
+	case "due", "created", "completed":
+		return p.dateFilter(name, op.text, val.text)
+	}
+	if op.text != "=" && op.text != ":" && op.text != "!=" {
+		return nil, i18n.Errorf("filter: %s cannot be compared with %s", name, op.text)
+	}
+	f, err := fieldFilter(name, val.text)
+	if err != nil {
+		return nil, err
+	}
+	if op.text == "!=" {
+		f = Not(f)
+	}
+	return f, nil
+}
+
+func fieldFilter(field, val string) (Filter, error) {
+	switch field {
+	case "project":
+		return InProject(val), nil
+	case "tag", "tags":
+		tags := NormalizeTags([]string{val})
+		if len(tags) == 0 {
+			return nil, i18n.Errorf("filter: empty tag")
+		}
+		return WithTags(tags...), nil
+	case "context":
+		return InContext(val), nil
+	case "assignee":
+		if strings.EqualFold(val, "none") {
+			val = ""
+		}
+		return AssignedTo(val), nil
+	case "status":
+		s, err := ParseStatus(val)
+		if err != nil {
+			return nil, i18n.Errorf("filter: %w", err)
+		}
+		return func(t Task) bool { return t.Status.orPending() == s }, nil
+	case "search":
+		terms := strings.Fields(strings.ToLower(val))
+		return func(t Task) bool { return matchesAll(searchText(t), terms) }, nil
+	}
+	return nil, i18n.Errorf("filter: unknown field %q (want one of %s)", field, strings.Join(FilterFields, ", "))
+}
+
+// priorityFilter compares priorities by importance, so priority>=medium
+// matches medium and high.
+func priorityFilter(op, val string) (Filter, error) {
+	prio, err := ParsePriority(val)
+	if err != nil {
+		return nil, i18n.Errorf("filter: %w", err)
+	}
+	return func(t Task) bool { return compared(op, int(t.Priority)-int(prio)) }, nil
+}
+
+// dateFilter compares the date field with val. Tasks without the date
+// only match field=none, and field!= anything.
+func (p *filterParser) dateFilter(field, op, val string) (Filter, error) {
+	get := func(t Task) *time.Time {
+		switch field {
+		case "due":
+			return t.Due
+		case "completed":
+			return t.CompletedAt
+		}
+		return &t.CreatedAt
+	}
+	if strings.EqualFold(val, "none") {
+		switch op {
+		case "=", ":":
+			return func(t Task) bool { return get(t) == nil }, nil
+		case "!=":
+			return func(t Task) bool { return get(t) != nil }, nil
+		}
+		return nil, i18n.Errorf("filter: %s cannot be compared with %s none", field, op)
+	}
+	when, err := p.date(val)
+	if err != nil {
+		return nil, i18n.Errorf("filter: %s: %w", field, err)
+	}
+	wholeDay := when.Equal(lastSecond(when))
+	return func(t Task) bool {
+		d := get(t)
+		if d == nil {
+			return op == "!="
+		}
+		if wholeDay {
+			return compared(op, lastSecond(d.In(when.Location())).Compare(when))
+		}
+		return compared(op, d.Compare(when))
+	}, nil
+}
+
+// compared reports whether a comparison that came out as cmp (negative,
+// zero or positive) satisfies op.
+func compared(op string, cmp int) bool {
+	switch op {
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return cmp == 0
+}
+
+// lastSecond is the last second of t's day in its zone.
+func lastSecond(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
+
+// parseDay is the DateParser used when ParseFilter is given none.
+func parseDay(s string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, i18n.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+	}
+	return lastSecond(t), nil
+}
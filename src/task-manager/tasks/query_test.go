@@ -0,0 +1,139 @@
+package tasks
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 1, d, 15, 0, 0, 0, time.Local)
+		return &t
+	}
+	list := []Task{
+		{ID: 1, Description: "Buy milk", Tags: []string{"home"}, Priority: PriorityHigh, Due: day(1), Context: "errands"},
+		{ID: 2, Description: "Fix the bike", Tags: []string{"home"}, Status: Done, CompletedAt: day(3)},
+		{ID: 3, Description: "Send invoice", Project: "work.billing", Priority: PriorityMedium, Assignee: "ana", Due: day(10)},
+		{ID: 4, Description: "Plan offsite", Project: "work", Status: InProgress, Priority: PriorityLow},
+		{ID: 5, Description: "Read: Dune", Status: Cancelled},
+	}
+
+	tests := []struct {
+		expr string
+		want []int
+	}{
+		{"tag=home", []int{1, 2}},
+		{"tags:home", []int{1, 2}},
+		{"tag=+home", []int{1, 2}},
+		{"project=work", []int{3, 4}},
+		{"project=work.billing", []int{3}},
+		{"project!=work", []int{1, 2, 5}},
+		{"context=@errands", []int{1}},
+		{"assignee=ana", []int{3}},
+		{"assignee=none", []int{1, 2, 4, 5}},
+		{"status=pending", []int{1, 3}},
+		{"status=started", []int{4}},
+		{"status=completed", []int{2}},
+		{"status=canceled", []int{5}},
+		{"priority=high", []int{1}},
+		{"priority>=medium", []int{1, 3}},
+		{"priority<medium", []int{2, 4, 5}},
+		{"priority>1", []int{1, 3}},
+		{"search=bike", []int{2}},
+		{`search="read: dune"`, []int{5}},
+		{"due=none", []int{2, 4, 5}},
+		{"due!=none", []int{1, 3}},
+		{"due<=2025-01-01", []int{1}},
+		{"due<2025-01-01", nil},
+		{"due=2025-01-10", []int{3}},
+		{"due>2025-01-01", []int{3}},
+		{"due!=2025-01-01", []int{2, 3, 4, 5}},
+		{"completed>=2025-01-02", []int{2}},
+		{"created=none", nil},
+		{"tag=home status=pending", []int{1}},
+		{"tag=home and status=pending", []int{1}},
+		{"tag=home or project=work", []int{1, 2, 3, 4}},
+		{"not tag=home", []int{3, 4, 5}},
+		{"not not tag=home", []int{1, 2}},
+		{"status=pending and (tag=home or priority=medium)", []int{1, 3}},
+		{"status=pending and tag=home or project=work", []int{1, 3, 4}},
+		{"STATUS=Pending AND Tag=home", []int{1}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr, nil)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := ids(Select(list, f)); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseFilter(%q) matches %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"tag",
+		"tag=",
+		"tag=home and",
+		"(tag=home",
+		"tag=home)",
+		"colour=red",
+		"tag=+",
+		"status=someday",
+		"priority=urgent",
+		"due=tomorrow",
+		"due<none",
+		"project<work",
+		`search="unterminated`,
+		`"tag"=home`,
+		"tag!home",
+		"tag=home or or tag=work",
+	}
+	for _, expr := range tests {
+		if _, err := ParseFilter(expr, nil); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestParseFilterDateParser(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	date := func(s string) (time.Time, error) {
+		if s == "tomorrow" {
+			return lastSecond(now.AddDate(0, 0, 1)), nil
+		}
+		return parseDay(s)
+	}
+	due := now.AddDate(0, 0, 1)
+	list := []Task{{ID: 1, Due: &due}, {ID: 2, Due: &now}}
+
+	tests := []struct {
+		expr string
+		want []int
+	}{
+		{"due=tomorrow", []int{1}},
+		{"due<tomorrow", []int{2}},
+		{"due<=2025-01-02", []int{1, 2}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr, date)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", tt.expr, err)
+		}
+		if got := ids(Select(list, f)); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseFilter(%q) matches %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func ids(list []Task) []int {
+	out := make([]int, len(list))
+	for i, t := range list {
+		out[i] = t.ID
+	}
+	return out
+}
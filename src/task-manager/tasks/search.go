@@ -0,0 +1,257 @@
+package tasks
+
+import (
+	"strings"
+	"unicode"
+)
+
+// indexThreshold is the list size above which Search builds a trigram index
+// instead of scanning every task for every term.
+const indexThreshold = 500
+
+// searchText is the lower-cased text Search matches against.
+func searchText(t Task) string {
+	parts := append([]string{t.Description, t.Project, t.Context}, t.Tags...)
+	for _, n := range t.Notes {
+		parts = append(parts, n.Text)
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+// Search returns the tasks whose description, notes, tags, project or
+// context contain every whitespace-separated term of query, ignoring case.
+// Order is preserved.
+func Search(list []Task, query string) []Task {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	if len(list) > indexThreshold {
+		return NewIndex(list).Search(query)
+	}
+	var out []Task
+	for _, t := range list {
+		if matchesAll(searchText(t), terms) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func matchesAll(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// Index is a trigram index over task text. Any substring of three or more
+// characters shares all of its trigrams with the texts that contain it, so
+// intersecting posting lists yields a small candidate set that is then
+// verified with a plain substring check.
+type Index struct {
+	tasks []Task
+	texts []string
+	grams map[string][]int // trigram -> positions in tasks, ascending
+}
+
+// NewIndex indexes list.
+func NewIndex(list []Task) *Index {
+	idx := &Index{tasks: list, texts: make([]string, len(list)), grams: map[string][]int{}}
+	for i, t := range list {
+		text := searchText(t)
+		idx.texts[i] = text
+		seen := map[string]bool{}
+		for _, g := range trigrams(text) {
+			if !seen[g] {
+				seen[g] = true
+				idx.grams[g] = append(idx.grams[g], i)
+			}
+		}
+	}
+	return idx
+}
+
+// Search behaves like the package-level Search over the indexed list.
+func (idx *Index) Search(query string) []Task {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var candidates []int // nil = every task
+	for _, term := range terms {
+		for _, g := range trigrams(term) {
+			candidates = intersect(candidates, idx.grams[g])
+			if candidates != nil && len(candidates) == 0 {
+				return nil
+			}
+		}
+	}
+
+	var out []Task
+	check := func(i int) {
+		if matchesAll(idx.texts[i], terms) {
+			out = append(out, idx.tasks[i])
+		}
+	}
+	if candidates == nil {
+		// Only terms shorter than three characters: nothing to narrow on.
+		for i := range idx.tasks {
+			check(i)
+		}
+		return out
+	}
+	for _, i := range candidates {
+		check(i)
+	}
+	return out
+}
+
+func trigrams(s string) []string {
+	r := []rune(s)
+	if len(r) < 3 {
+		return nil
+	}
+	out := make([]string, 0, len(r)-2)
+	for i := 0; i+3 <= len(r); i++ {
+		out = append(out, string(r[i:i+3]))
+	}
+	return out
+}
+
+// intersect merges two ascending posting lists; a nil a means "everything".
+func intersect(a, b []int) []int {
+	if a == nil {
+		return append([]int{}, b...)
+	}
+	out := a[:0]
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
+
+// Fuzzy returns the tasks whose description best matches query, ignoring
+// case: an exact match beats descriptions containing every term of the
+// query, which beat descriptions holding the query's letters in order
+// ("grcy" matches "groceries"), the tighter the better. Only the
+// best-matching tasks are returned, in list order.
+func Fuzzy(list []Task, query string) []Task {
+	q := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if q == "" {
+		return nil
+	}
+	terms := strings.Fields(q)
+	letters := []rune(strings.ReplaceAll(q, " ", ""))
+
+	// Lower scores are better: 0 exact, 1 every term, then 2 plus the
+	// length of the shortest stretch holding the letters in order.
+	best, out := -1, []Task(nil)
+	for _, t := range list {
+		desc := strings.ToLower(t.Description)
+		score := -1
+		switch {
+		case strings.Join(strings.Fields(desc), " ") == q:
+			score = 0
+		case matchesAll(desc, terms):
+			score = 1
+		default:
+			if n := window([]rune(desc), letters); n > 0 {
+				score = 2 + n
+			}
+		}
+		switch {
+		case score < 0:
+		case best < 0 || score < best:
+			best, out = score, []Task{t}
+		case score == best:
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// window returns the length of the shortest stretch of s that contains the
+// runes of sub in order, or 0 if there is none.
+func window(s, sub []rune) int {
+	shortest := 0
+	for start := range s {
+		if s[start] != sub[0] {
+			continue
+		}
+		i, j := start, 0
+		for ; i < len(s) && j < len(sub); i++ {
+			if s[i] == sub[j] {
+				j++
+			}
+		}
+		if j < len(sub) {
+			break // no later start can match either
+		}
+		if n := i - start; shortest == 0 || n < shortest {
+			shortest = n
+		}
+	}
+	return shortest
+}
+
+// Similarity scores how alike two descriptions are, from 0 to 1: the Dice
+// coefficient of their trigram sets, ignoring case, punctuation and spacing,
+// so "Buy milk" and "buy milk!" score 1.
+func Similarity(a, b string) float64 {
+	ga, gb := gramSet(a), gramSet(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range ga {
+		if gb[g] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ga)+len(gb))
+}
+
+// Similar returns the task of list whose description is most like desc, if
+// its Similarity reaches min.
+func Similar(list []Task, desc string, min float64) (Task, bool) {
+	var (
+		best  Task
+		score float64
+	)
+	for _, t := range list {
+		if s := Similarity(desc, t.Description); s > score {
+			best, score = t, s
+		}
+	}
+	return best, score > 0 && score >= min
+}
+
+// gramSet returns the trigrams of s's words, lower-cased and padded with a
+// space on both sides so that short words still count.
+func gramSet(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+	set := map[string]bool{}
+	for _, g := range trigrams(" " + strings.Join(words, " ") + " ") {
+		set[g] = true
+	}
+	return set
+}
@@ -0,0 +1,120 @@
+package tasks
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// SortKey is one key of a sort order: a field name, optionally descending.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// sortFields are the field names ParseSort accepts.
+var sortFields = []string{
+	"assignee", "completed", "context", "created", "description",
+	"due", "id", "priority", "project", "status",
+}
+
+// SortFields returns the field names ParseSort accepts, sorted.
+func SortFields() []string {
+	return slices.Clone(sortFields)
+}
+
+// compareField compares a and b by field in ascending order. Tasks without
+// a value for the field (no due date, no project) sort after those with
+// one whatever the direction, so absent reports that the result must not be
+// reversed.
+func compareField(field string, a, b Task) (c int, absent bool) {
+	switch field {
+	case "id":
+		return cmp.Compare(a.ID, b.ID), false
+	case "description":
+		return cmp.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description)), false
+	case "priority":
+		return cmp.Compare(a.Priority, b.Priority), false
+	case "status":
+		return cmp.Compare(a.Status.Rank(), b.Status.Rank()), false
+	case "created":
+		return a.CreatedAt.Compare(b.CreatedAt), false
+	case "due":
+		return compareTime(a.Due, b.Due)
+	case "completed":
+		return compareTime(a.CompletedAt, b.CompletedAt)
+	case "project":
+		return compareString(a.Project, b.Project)
+	case "context":
+		return compareString(a.Context, b.Context)
+	case "assignee":
+		return compareString(a.Assignee, b.Assignee)
+	}
+	return 0, false
+}
+
+// ParseSort parses a comma-separated sort order such as
+// "due,priority,-created"; a leading "-" sorts that key descending.
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !slices.Contains(sortFields, key.Field) {
+			return nil, i18n.Errorf("unknown sort field %q (want %s)", key.Field, strings.Join(SortFields(), ", "))
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, i18n.Errorf("empty sort order %q", spec)
+	}
+	return keys, nil
+}
+
+// SortBy orders list by each key in turn, then by ID. Subtasks without a
+// due date or priority sort by their parent's (see Inherited).
+func SortBy(list []Task, keys []SortKey) {
+	sortInherited(list, func(a, b Task) int {
+		for _, key := range keys {
+			c, absent := compareField(key.Field, a, b)
+			if key.Desc && !absent {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func compareTime(a, b *time.Time) (int, bool) {
+	if a == nil || b == nil {
+		return compareBool(a == nil, b == nil), true
+	}
+	return a.Compare(*b), false
+}
+
+func compareString(a, b string) (int, bool) {
+	if a == "" || b == "" {
+		return compareBool(a == "", b == ""), true
+	}
+	return cmp.Compare(a, b), false
+}
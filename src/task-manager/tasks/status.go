@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// Status is the stage a task is in.
+type Status string
+
+// The statuses, in the order they are listed and sorted.
+const (
+	Pending    Status = "pending"     // in the backlog
+	InProgress Status = "in-progress" // being worked on; time is tracked
+	Waiting    Status = "waiting"     // blocked on someone or something else
+	Done       Status = "done"
+	Cancelled  Status = "cancelled" // closed without being done
+)
+
+// Statuses lists every status.
+var Statuses = []Status{Pending, InProgress, Waiting, Done, Cancelled}
+
+// transitions lists the statuses each status may move to. Closed tasks
+// must be reopened before anything else happens to them.
+var transitions = map[Status][]Status{
+	Pending:    {InProgress, Waiting, Done, Cancelled},
+	InProgress: {Pending, Waiting, Done, Cancelled},
+	Waiting:    {Pending, InProgress, Done, Cancelled},
+	Done:       {Pending},
+	Cancelled:  {Pending},
+}
+
+// ParseStatus parses a status name. It also accepts the names earlier
+// versions used, which may still be in the history: "started" and
+// "completed".
+func ParseStatus(s string) (Status, error) {
+	switch v := Status(strings.ToLower(strings.TrimSpace(s))); v {
+	case "started", "in_progress", "inprogress":
+		return InProgress, nil
+	case "completed":
+		return Done, nil
+	case "canceled":
+		return Cancelled, nil
+	default:
+		if slices.Contains(Statuses, v) {
+			return v, nil
+		}
+	}
+	return "", i18n.Errorf("unknown status %q (want %s)", s, statusNames())
+}
+
+func statusNames() string {
+	names := make([]string, len(Statuses))
+	for i, s := range Statuses {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Closed reports whether s ends a task's life: done or cancelled.
+func (s Status) Closed() bool { return s == Done || s == Cancelled }
+
+// Rank orders statuses as Statuses lists them.
+func (s Status) Rank() int { return slices.Index(Statuses, s.orPending()) }
+
+func (s Status) orPending() Status {
+	if s == "" {
+		return Pending
+	}
+	return s
+}
+
+// CanMove reports whether a task may go from status s to to.
+func (s Status) CanMove(to Status) bool {
+	return slices.Contains(transitions[s.orPending()], to)
+}
+
+// Closed reports whether t is done or cancelled.
+func (t Task) Closed() bool { return t.Status.Closed() }
+
+// Done reports whether t was completed.
+func (t Task) Done() bool { return t.Status == Done }
+
+// SetStatus moves t to status s at now, if the state machine allows it, and
+// keeps the timestamps in step: leaving InProgress adds the session to the
+// tracked time, and closing a task records when.
+func (t *Task) SetStatus(s Status, now time.Time) error {
+	if t.Status.orPending() == s {
+		return i18n.Errorf("%w: task %d is already %s", ErrTransition, t.ID, i18n.Word(s))
+	}
+	if !t.Status.CanMove(s) {
+		return i18n.Errorf("%w: task %d is %s and cannot become %s", ErrTransition, t.ID, i18n.Word(t.Status.orPending()), i18n.Word(s))
+	}
+	t.Move(s, now)
+	return nil
+}
+
+// Move is SetStatus without the check, for importers and the edit form,
+// which take a task's status from elsewhere as it is.
+func (t *Task) Move(s Status, now time.Time) {
+	if t.Status.orPending() == s {
+		return
+	}
+	if t.StartedAt != nil {
+		t.Spent = t.Tracked(now).Round(time.Second)
+		t.StartedAt = nil
+	}
+	t.CompletedAt = nil
+	switch s {
+	case InProgress:
+		t.StartedAt = &now
+	case Done, Cancelled:
+		t.CompletedAt = &now
+	}
+	t.Status = s
+}
+
+// taskJSON is Task without its JSON methods.
+type taskJSON Task
+
+// MarshalJSON writes t with a "completed" field next to "status", for hook
+// scripts and sync peers written against earlier versions.
+func (t Task) MarshalJSON() ([]byte, error) {
+	t.Status = t.Status.orPending()
+	return json.Marshal(struct {
+		taskJSON
+		Completed bool `json:"completed"`
+	}{taskJSON(t), t.Done()})
+}
+
+// UnmarshalJSON reads a task, working out the status of tasks written
+// before there was one from their "completed" flag and start time.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*taskJSON
+		Completed bool `json:"completed"`
+	}{taskJSON: (*taskJSON)(t)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch {
+	case t.Status != "":
+		if s, err := ParseStatus(string(t.Status)); err == nil {
+			t.Status = s
+		}
+	case v.Completed:
+		t.Status = Done
+	case t.StartedAt != nil:
+		t.Status = InProgress
+	default:
+		t.Status = Pending
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package tasks
+
+import (
+	"errors"
+	"sort"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// ErrCorrupt is returned when stored data cannot be decoded: a damaged
+// database, or a task file broken by a hand edit.
+var ErrCorrupt = errors.New("task data is corrupt")
+
+// corrupt reports that what could not be decoded because of err.
+func corrupt(what string, err error) error {
+	return i18n.Errorf("%s: %w: %v", what, ErrCorrupt, err)
+}
+
+// Tx is a view of the task data inside a single transaction.
+type Tx interface {
+	// Get returns the task with the given ID or an ErrNotFound error.
+	Get(id int) (Task, error)
+	// List returns every task ordered by ID.
+	List() ([]Task, error)
+	// Put inserts or replaces t.
+	Put(t Task) error
+	// Delete removes the task with the given ID or returns ErrNotFound.
+	Delete(id int) error
+	// NextID reserves and returns a fresh task ID.
+	NextID() (int, error)
+	// PushOp appends op to the undo journal, dropping the oldest entries
+	// beyond JournalLimit.
+	PushOp(op Operation) error
+	// PopOp removes and returns the newest journal entry; ok is false when
+	// the journal is empty.
+	PopOp() (op Operation, ok bool, err error)
+	// AppendHistory adds ev to the change history.
+	AppendHistory(ev Event) error
+	// History returns the change history, oldest first.
+	History() ([]Event, error)
+	// Trash returns the deleted tasks kept for restoring, by task ID.
+	Trash() ([]Trashed, error)
+	// PutTrash stores e in the trash, replacing any entry for the same
+	// task ID.
+	PutTrash(e Trashed) error
+	// DeleteTrash drops the trash entry for task id or returns ErrNotFound.
+	DeleteTrash(id int) error
+}
+
+// Store is implemented by every storage backend. Update runs fn in a
+// read-write transaction that is committed only if fn returns nil; View runs
+// fn in a read-only one.
+type Store interface {
+	View(fn func(Tx) error) error
+	Update(fn func(Tx) error) error
+	Close() error
+}
+
+// memTx is a Tx over an in-memory snapshot; file-based backends load the
+// snapshot, run the transaction against it and write it back on success.
+type memTx struct {
+	tasks   map[int]Task
+	nextID  int
+	journal []Operation
+	history []Event
+	trash   map[int]Trashed
+}
+
+func newMemTx(s snapshot) *memTx {
+	tx := &memTx{tasks: make(map[int]Task, len(s.Tasks)), nextID: s.NextID, journal: s.Journal, history: s.History, trash: map[int]Trashed{}}
+	for _, e := range s.Trash {
+		tx.trash[e.Task.ID] = e
+	}
+	for _, t := range s.Tasks {
+		tx.tasks[t.ID] = t
+		if t.ID >= tx.nextID {
+			tx.nextID = t.ID + 1
+		}
+	}
+	if tx.nextID < 1 {
+		tx.nextID = 1
+	}
+	return tx
+}
+
+func (tx *memTx) snapshot() snapshot {
+	list, _ := tx.List()
+	trash, _ := tx.Trash()
+	return snapshot{Version: snapshotVersion, NextID: tx.nextID, Tasks: list, Journal: tx.journal, History: tx.history, Trash: trash}
+}
+
+func (tx *memTx) Get(id int) (Task, error) {
+	t, ok := tx.tasks[id]
+	if !ok {
+		return Task{}, NotFound(id)
+	}
+	return t, nil
+}
+
+func (tx *memTx) List() ([]Task, error) {
+	list := make([]Task, 0, len(tx.tasks))
+	for _, t := range tx.tasks {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+func (tx *memTx) Put(t Task) error {
+	tx.tasks[t.ID] = t
+	if t.ID >= tx.nextID {
+		tx.nextID = t.ID + 1
+	}
+	return nil
+}
+
+func (tx *memTx) Delete(id int) error {
+	if _, ok := tx.tasks[id]; !ok {
+		return NotFound(id)
+	}
+	delete(tx.tasks, id)
+	return nil
+}
+
+func (tx *memTx) NextID() (int, error) {
+	id := tx.nextID
+	tx.nextID++
+	return id, nil
+}
+
+func (tx *memTx) PushOp(op Operation) error {
+	tx.journal = append(tx.journal, op)
+	if n := len(tx.journal) - JournalLimit; n > 0 {
+		tx.journal = tx.journal[n:]
+	}
+	return nil
+}
+
+func (tx *memTx) PopOp() (Operation, bool, error) {
+	if len(tx.journal) == 0 {
+		return Operation{}, false, nil
+	}
+	op := tx.journal[len(tx.journal)-1]
+	tx.journal = tx.journal[:len(tx.journal)-1]
+	return op, true, nil
+}
+
+func (tx *memTx) AppendHistory(ev Event) error {
+	tx.history = append(tx.history, ev)
+	return nil
+}
+
+func (tx *memTx) History() ([]Event, error) {
+	return tx.history, nil
+}
+
+func (tx *memTx) Trash() ([]Trashed, error) {
+	var list []Trashed
+	for _, e := range tx.trash {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Task.ID < list[j].Task.ID })
+	return list, nil
+}
+
+func (tx *memTx) PutTrash(e Trashed) error {
+	tx.trash[e.Task.ID] = e
+	return nil
+}
+
+func (tx *memTx) DeleteTrash(id int) error {
+	if _, ok := tx.trash[id]; !ok {
+		return NotFound(id)
+	}
+	delete(tx.trash, id)
+	return nil
+}
+
+// Copy writes every task in src into dst in a single transaction, keeping
+// the original IDs. It is used to move data between backends.
+func Copy(dst, src Store) (int, error) {
+	var list []Task
+	if err := src.View(func(tx Tx) error {
+		var err error
+		list, err = tx.List()
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	err := dst.Update(func(tx Tx) error {
+		for _, t := range list {
+			if err := tx.Put(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(list), err
+}
+
+// Notice is a history event from a committed transaction, together with
+// the task as that transaction left it (nil once deleted).
+type Notice struct {
+	Event
+	Task *Task
+}
+
+// Notify wraps s so that fn sees the history events of every Update that
+// commits, after it has committed. Transactions that log nothing are not
+// reported.
+func Notify(s Store, fn func([]Notice)) Store {
+	return &notifyStore{Store: s, fn: fn}
+}
+
+type notifyStore struct {
+	Store
+	fn func([]Notice)
+}
+
+func (s *notifyStore) Update(fn func(Tx) error) error {
+	var notices []Notice
+	err := s.Store.Update(func(tx Tx) error {
+		ntx := &noticeTx{Tx: tx}
+		if err := fn(ntx); err != nil {
+			return err
+		}
+		notices = make([]Notice, len(ntx.events))
+		for i, ev := range ntx.events {
+			notices[i].Event = ev
+			if t, err := tx.Get(ev.TaskID); err == nil {
+				notices[i].Task = &t
+			}
+		}
+		return nil
+	})
+	if err == nil && len(notices) > 0 {
+		s.fn(notices)
+	}
+	return err
+}
+
+// noticeTx collects the events appended to the history.
+type noticeTx struct {
+	Tx
+	events []Event
+}
+
+func (tx *noticeTx) AppendHistory(ev Event) error {
+	tx.events = append(tx.events, ev)
+	return tx.Tx.AppendHistory(ev)
+}
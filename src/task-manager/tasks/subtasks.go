@@ -0,0 +1,80 @@
+package tasks
+
+import "gopatterns/task-manager/i18n"
+
+// Node is a task placed in a hierarchy: Depth is 0 for top-level tasks.
+type Node struct {
+	Task
+	Depth int `json:"depth"`
+}
+
+// Children returns the direct subtasks of id, preserving order.
+func Children(list []Task, id int) []Task {
+	return Select(list, func(t Task) bool { return t.Parent == id })
+}
+
+// OpenChildren returns the subtasks of id, at any depth, that are neither
+// done nor cancelled.
+func OpenChildren(list []Task, id int) []Task {
+	var open []Task
+	for _, c := range Children(list, id) {
+		if !c.Closed() {
+			open = append(open, c)
+		}
+		open = append(open, OpenChildren(list, c.ID)...)
+	}
+	return open
+}
+
+// Tree flattens list into depth-first order, each subtask directly under its
+// parent. Siblings keep their relative order from list. Tasks whose parent
+// is not in list (filtered out or deleted) are shown at the top level.
+func Tree(list []Task) []Node {
+	present := make(map[int]bool, len(list))
+	for _, t := range list {
+		present[t.ID] = true
+	}
+	kids := map[int][]Task{}
+	var roots []Task
+	for _, t := range list {
+		if t.Parent != 0 && present[t.Parent] && t.Parent != t.ID {
+			kids[t.Parent] = append(kids[t.Parent], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	out := make([]Node, 0, len(list))
+	seen := make(map[int]bool, len(list))
+	var walk func(t Task, depth int)
+	walk = func(t Task, depth int) {
+		if seen[t.ID] {
+			return
+		}
+		seen[t.ID] = true
+		out = append(out, Node{Task: t, Depth: depth})
+		for _, c := range kids[t.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, t := range roots {
+		walk(t, 0)
+	}
+	return out
+}
+
+// CheckParent verifies that making parent the parent of id keeps the
+// hierarchy a tree: parent must exist and must not be id or a descendant.
+func CheckParent(tx Tx, id, parent int) error {
+	for p := parent; p != 0; {
+		if p == id {
+			return i18n.Errorf("task %d cannot be a subtask of itself or of its own subtasks", id)
+		}
+		t, err := tx.Get(p)
+		if err != nil {
+			return i18n.Errorf("parent: %w", err)
+		}
+		p = t.Parent
+	}
+	return nil
+}
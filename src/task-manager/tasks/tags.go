@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// NormalizeTags trims tags, drops empty ones and duplicates, and sorts the
+// rest so tag sets compare and render consistently.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// HasTag reports whether t carries tag.
+func (t Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// AddTags attaches tags to t.
+func (t *Task) AddTags(tags ...string) {
+	t.Tags = NormalizeTags(append(t.Tags, tags...))
+}
+
+// RemoveTags detaches tags from t.
+func (t *Task) RemoveTags(tags ...string) {
+	t.Tags = slices.DeleteFunc(t.Tags, func(tag string) bool { return slices.Contains(tags, tag) })
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+}
+
+// WithTags matches tasks carrying every one of tags.
+func WithTags(tags ...string) Filter {
+	return func(t Task) bool {
+		for _, tag := range tags {
+			if !t.HasTag(tag) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// TagCount is the number of tasks carrying a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Open  int    `json:"open"`
+	Total int    `json:"total"`
+}
+
+// CountTags tallies tag usage across list, ordered by tag name.
+func CountTags(list []Task) []TagCount {
+	counts := map[string]*TagCount{}
+	for _, t := range list {
+		for _, tag := range t.Tags {
+			c, ok := counts[tag]
+			if !ok {
+				c = &TagCount{Tag: tag}
+				counts[tag] = c
+			}
+			c.Total++
+			if !t.Closed() {
+				c.Open++
+			}
+		}
+	}
+	out := make([]TagCount, 0, len(counts))
+	for _, c := range counts {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tag < out[j].Tag })
+	return out
+}
@@ -0,0 +1,127 @@
+// Package tasks is the core of the task manager: the Task model, the
+// storage abstraction every backend implements, and Manager, which
+// programs embedding the task manager use instead of the task command.
+package tasks
+
+import (
+	"errors"
+	"time"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// Task is a single to-do item.
+type Task struct {
+	ID          int           `json:"id"`
+	Description string        `json:"description"`
+	Status      Status        `json:"status"`
+	CreatedAt   time.Time     `json:"created_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"` // when it was done or cancelled
+	StartedAt   *time.Time    `json:"started_at,omitempty"`   // while in progress
+	Due         *time.Time    `json:"due,omitempty"`
+	Wait        *time.Time    `json:"wait,omitempty"` // hidden from the list until then
+	Priority    Priority      `json:"priority,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Parent      int           `json:"parent,omitempty"`
+	Project     string        `json:"project,omitempty"`
+	Context     string        `json:"context,omitempty"`
+	Assignee    string        `json:"assignee,omitempty"`
+	Estimate    time.Duration `json:"estimate,omitempty"` // expected effort
+	Spent       time.Duration `json:"spent,omitempty"`    // tracked in earlier start/stop sessions
+	Notes       []Note        `json:"notes,omitempty"`
+	Attachments []Attachment  `json:"attachments,omitempty"`
+
+	// Sync metadata, maintained by Record: a stable identity across
+	// machines, a revision bumped on every change, and when that was.
+	UUID      string     `json:"uuid,omitempty"`
+	Rev       int        `json:"rev,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Stamps records, per field, the last change, so sync can merge
+	// tasks edited on two machines field by field.
+	Stamps map[string]Stamp `json:"stamps,omitempty"`
+	// Conflicts holds the values a sync merge discarded because both
+	// machines changed the field, until someone reviews them.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+}
+
+// Note is a timestamped annotation on a task.
+type Note struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// Attachment is a file or URL referenced from a task. Only the reference
+// is stored, never the file's contents.
+type Attachment struct {
+	Time time.Time `json:"time"`
+	Ref  string    `json:"ref"` // an absolute path or a URL
+}
+
+// ErrNotFound is returned when no task has the requested ID.
+var ErrNotFound = errors.New("task not found")
+
+// NotFound returns an ErrNotFound error naming id.
+func NotFound(id int) error {
+	return i18n.Errorf("task %d: %w", id, ErrNotFound)
+}
+
+// Start marks t as being worked on from now.
+func (t *Task) Start(now time.Time) {
+	t.Move(InProgress, now)
+}
+
+// Stop puts t back in the backlog, adding the time since it was started to
+// the time spent on it.
+func (t *Task) Stop(now time.Time) {
+	t.Move(Pending, now)
+}
+
+// Tracked is the time spent on t: its finished start/stop sessions, and the
+// running one up to now.
+func (t Task) Tracked(now time.Time) time.Duration {
+	d := t.Spent
+	if t.StartedAt != nil && now.After(*t.StartedAt) {
+		d += now.Sub(*t.StartedAt)
+	}
+	return d
+}
+
+// Complete marks t as done at now, ending its running session.
+func (t *Task) Complete(now time.Time) {
+	t.Move(Done, now)
+}
+
+// Reopen puts t back in the backlog if it is done or cancelled.
+func (t *Task) Reopen() {
+	if t.Closed() {
+		t.Status, t.CompletedAt = Pending, nil
+	}
+}
+
+// Annotate appends a note to t.
+func (t *Task) Annotate(now time.Time, text string) {
+	t.Notes = append(t.Notes, Note{Time: now, Text: text})
+}
+
+// Attach appends a reference to t's attachments, unless it is already
+// there, and reports whether it was added.
+func (t *Task) Attach(now time.Time, ref string) bool {
+	for _, a := range t.Attachments {
+		if a.Ref == ref {
+			return false
+		}
+	}
+	t.Attachments = append(t.Attachments, Attachment{Time: now, Ref: ref})
+	return true
+}
+
+// IsSnoozed reports whether t is open but snoozed until after now, and so
+// hidden from the list.
+func (t Task) IsSnoozed(now time.Time) bool {
+	return !t.Closed() && t.Wait != nil && now.Before(*t.Wait)
+}
+
+// IsOverdue reports whether t is still open past its due date.
+func (t Task) IsOverdue(now time.Time) bool {
+	return !t.Closed() && t.Due != nil && now.After(*t.Due)
+}
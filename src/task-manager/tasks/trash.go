@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"errors"
+	"time"
+)
+
+// TrashDays is how long deleted tasks stay restorable unless configured
+// otherwise.
+const TrashDays = 30
+
+// Trashed is a deleted task kept so it can be restored, with when and by
+// whom it was deleted.
+type Trashed struct {
+	Task      Task      `json:"task"`
+	DeletedAt time.Time `json:"deleted_at"`
+	User      string    `json:"user,omitempty"`
+}
+
+// TrashChange is the state of one trash entry before an operation touched
+// it. A nil Before means the operation created the entry.
+type TrashChange struct {
+	ID     int      `json:"id"`
+	Before *Trashed `json:"before,omitempty"`
+}
+
+// Discard deletes task id and keeps it in the trash, stamped with now and
+// user, so Restore can bring it back. Its subtasks are left in place.
+func Discard(tx Tx, id int, now time.Time, user string) (Task, error) {
+	t, err := Remove(tx, id)
+	if err != nil {
+		return Task{}, err
+	}
+	return t, tx.PutTrash(Trashed{Task: t, DeletedAt: now, User: user})
+}
+
+// Restore moves task id from the trash back into the list. It keeps its ID
+// unless another task has taken it, and loses its parent if that is gone;
+// either way the returned task says where it ended up.
+func Restore(tx Tx, id int) (Task, error) {
+	e, err := trashEntry(tx, id)
+	if err != nil {
+		return Task{}, err
+	}
+	t := e.Task
+	if _, err := tx.Get(t.ID); err == nil {
+		if t.ID, err = tx.NextID(); err != nil {
+			return Task{}, err
+		}
+	} else if !errors.Is(err, ErrNotFound) {
+		return Task{}, err
+	}
+	if t.Parent != 0 {
+		if _, err := tx.Get(t.Parent); errors.Is(err, ErrNotFound) {
+			t.Parent = 0
+		} else if err != nil {
+			return Task{}, err
+		}
+	}
+	if err := tx.DeleteTrash(id); err != nil {
+		return Task{}, err
+	}
+	return t, tx.Put(t)
+}
+
+// PurgeTrash permanently drops the trash entries deleted at or before
+// cutoff and returns them.
+func PurgeTrash(tx Tx, cutoff time.Time) ([]Trashed, error) {
+	list, err := tx.Trash()
+	if err != nil {
+		return nil, err
+	}
+	var purged []Trashed
+	for _, e := range list {
+		if e.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := tx.DeleteTrash(e.Task.ID); err != nil {
+			return nil, err
+		}
+		purged = append(purged, e)
+	}
+	return purged, nil
+}
+
+// trashEntry returns the trash entry for task id or an ErrNotFound error.
+func trashEntry(tx Tx, id int) (Trashed, error) {
+	list, err := tx.Trash()
+	if err != nil {
+		return Trashed{}, err
+	}
+	for _, e := range list {
+		if e.Task.ID == id {
+			return e, nil
+		}
+	}
+	return Trashed{}, NotFound(id)
+}
@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// Coefficients weight the terms of a task's urgency. Each term is a factor
+// between 0 and 1 (see Urgency), multiplied by its coefficient; Tag adds a
+// fixed amount for each listed tag the task carries.
+type Coefficients struct {
+	Due      float64            // due date proximity, full when a week overdue
+	Priority float64            // high priority; medium and low count less
+	Age      float64            // time since creation, full after a year
+	Tags     float64            // having tags at all
+	Notes    float64            // having notes
+	Project  float64            // belonging to a project
+	Started  float64            // being in progress
+	Blocking float64            // being an open subtask of an open task
+	Blocked  float64            // having open subtasks (usually negative)
+	Tag      map[string]float64 // per-tag bonus, e.g. "next": 15
+}
+
+// DefaultCoefficients are Taskwarrior's defaults, with subtasks standing in
+// for dependencies: a subtask blocks its parent.
+func DefaultCoefficients() Coefficients {
+	return Coefficients{
+		Due:      12,
+		Priority: 6,
+		Age:      2,
+		Tags:     1,
+		Notes:    1,
+		Project:  1,
+		Started:  4,
+		Blocking: 8,
+		Blocked:  -5,
+		Tag:      map[string]float64{"next": 15},
+	}
+}
+
+// Urgency scores every task in list at now; higher is more urgent. Done and
+// cancelled tasks score 0. Subtasks without a due date or priority are
+// scored with their parent's (see Inherited).
+func Urgency(list []Task, now time.Time, c Coefficients) map[int]float64 {
+	byID := make(map[int]Task, len(list))
+	for _, t := range list {
+		byID[t.ID] = t
+	}
+	scores := make(map[int]float64, len(list))
+	for _, t := range Inherited(list) {
+		if t.Closed() {
+			scores[t.ID] = 0
+			continue
+		}
+		u := c.Due*dueFactor(t, now) +
+			c.Priority*priorityFactor(t.Priority) +
+			c.Age*min(now.Sub(t.CreatedAt).Hours()/24/365, 1) +
+			c.Tags*countFactor(len(t.Tags)) +
+			c.Notes*countFactor(len(t.Notes))
+		if t.Project != "" {
+			u += c.Project
+		}
+		if t.StartedAt != nil {
+			u += c.Started
+		}
+		if p, ok := byID[t.Parent]; ok && !p.Closed() {
+			u += c.Blocking
+		}
+		if len(OpenChildren(list, t.ID)) > 0 {
+			u += c.Blocked
+		}
+		for _, tag := range t.Tags {
+			u += c.Tag[tag]
+		}
+		scores[t.ID] = math.Round(u*100) / 100
+	}
+	return scores
+}
+
+// SortByUrgency orders list by descending score, then by ID.
+func SortByUrgency(list []Task, scores map[int]float64) {
+	slices.SortStableFunc(list, func(a, b Task) int {
+		if c := cmp.Compare(scores[b.ID], scores[a.ID]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// dueFactor rises linearly from 0.2 two weeks before the due date to 1 a
+// week after it; tasks without a due date get 0.
+func dueFactor(t Task, now time.Time) float64 {
+	if t.Due == nil {
+		return 0
+	}
+	overdue := now.Sub(*t.Due).Hours() / 24
+	switch {
+	case overdue >= 7:
+		return 1
+	case overdue >= -14:
+		return (overdue+14)*0.8/21 + 0.2
+	default:
+		return 0.2
+	}
+}
+
+// priorityFactor maps high/medium/low to 1, 0.65 and 0.3; numeric
+// priorities above high scale on from there.
+func priorityFactor(p Priority) float64 {
+	switch p {
+	case PriorityNone:
+		return 0
+	case PriorityLow:
+		return 0.3
+	case PriorityMedium:
+		return 0.65
+	default:
+		return float64(p) / float64(PriorityHigh)
+	}
+}
+
+// countFactor rewards having one, two or more tags or notes.
+func countFactor(n int) float64 {
+	switch n {
+	case 0:
+		return 0
+	case 1:
+		return 0.8
+	case 2:
+		return 0.9
+	default:
+		return 1
+	}
+}
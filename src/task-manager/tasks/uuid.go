@@ -0,0 +1,83 @@
+package tasks
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopatterns/task-manager/i18n"
+)
+
+// NewUUID returns a random (version 4) UUID. Tasks carry one so they can be
+// matched across machines, where their short IDs differ.
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// MinUUIDPrefix is the shortest UUID prefix FindUUID accepts.
+const MinUUIDPrefix = 6
+
+// IsUUIDRef reports whether s could name a task by UUID: hex digits and
+// dashes, at least MinUUIDPrefix long. All-digit strings are short IDs and
+// ranges, unless they are as long as the first group of a UUID and have no
+// dash before its end, as in "12345678" or a whole UUID.
+func IsUUIDRef(s string) bool {
+	if len(s) < MinUUIDPrefix || len(s) > 36 {
+		return false
+	}
+	digits := true
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+			digits = false
+		case c == '-':
+		default:
+			return false
+		}
+	}
+	return !digits || len(s) >= 8 && !strings.Contains(s[:8], "-")
+}
+
+// FindUUID returns the task in list whose UUID is ref or starts with it,
+// ignoring case. A prefix that fits several tasks is an error.
+func FindUUID(list []Task, ref string) (Task, error) {
+	ref = strings.ToLower(ref)
+	var found []Task
+	for _, t := range list {
+		if t.UUID != "" && strings.HasPrefix(strings.ToLower(t.UUID), ref) {
+			found = append(found, t)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Task{}, i18n.Errorf("no task with UUID %s: %w", ref, ErrNotFound)
+	case 1:
+		return found[0], nil
+	}
+	ids := make([]string, len(found))
+	for i, t := range found {
+		ids[i] = strconv.Itoa(t.ID)
+	}
+	return Task{}, i18n.Errorf("UUID prefix %s is ambiguous: it matches tasks %s", ref, strings.Join(ids, ", "))
+}
+
+// assignUUIDs gives every task in list without a UUID a new one and
+// reports whether it changed any.
+func assignUUIDs(list []Task) bool {
+	changed := false
+	for i := range list {
+		if list[i].UUID == "" {
+			list[i].UUID = NewUUID()
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,202 @@
+package tasksync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"gopatterns/backoff"
+	"gopatterns/retry"
+	"gopatterns/task-manager/tasks"
+)
+
+// Stats summarises one sync from the client's point of view.
+type Stats struct {
+	Sent      int // local changes and deletions pushed
+	Received  int // tasks created or updated locally
+	Removed   int // tasks deleted locally
+	Conflicts int
+}
+
+// reconnect spaces out the attempts at a sync request. The jitter keeps
+// clients that lost the server together from all coming back at once.
+var reconnect = []retry.Option{
+	retry.RetryIf(retry.Transient),
+	retry.Attempts(4),
+	retry.WithBackoff(backoff.DecorrelatedJitter{Base: 250 * time.Millisecond, Max: 10 * time.Second}),
+}
+
+// Client talks to the sync endpoint of a `task serve` instance.
+type Client struct {
+	URL   string // server base URL, e.g. http://desktop:7080
+	Token string // bearer token; user:password in URL is sent as Basic auth
+	User  string // local user the received changes are logged under
+	HTTP  *http.Client
+	Retry []retry.Option // more options for retry.Do, e.g. Attempts
+}
+
+// Sync exchanges changes between the local list in tx and the server. base
+// is the state after the previous sync (nil the first time); the new base
+// is returned and should be saved once tx commits. The exchange is tried up
+// to four times, with jittered backoff, on network errors and 5xx statuses.
+func (c *Client) Sync(ctx context.Context, tx tasks.Tx, base map[string]int) (map[string]int, Stats, error) {
+	var stats Stats
+	local, err := withUUIDs(tx)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	req := Request{Base: base}
+	if req.Base == nil {
+		req.Base = map[string]int{}
+	}
+	present := map[string]bool{}
+	for _, rec := range toRecords(local) {
+		present[rec.UUID] = true
+		if rev, ok := req.Base[rec.UUID]; !ok || rev != rec.Rev {
+			req.Changed = append(req.Changed, rec)
+		}
+	}
+	for uuid := range req.Base {
+		if !present[uuid] {
+			req.Deleted = append(req.Deleted, uuid)
+		}
+	}
+	stats.Sent = len(req.Changed) + len(req.Deleted)
+
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return nil, stats, err
+	}
+	stats.Conflicts = resp.Conflicts
+
+	var applied Stats
+	op := tasks.Operation{Name: "sync", Time: time.Now(), User: c.User}
+	err = tasks.Track(tx, op, func(tx tasks.Tx) (err error) {
+		applied, err = apply(tx, local, resp.Tasks)
+		return err
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	stats.Received, stats.Removed = applied.Received, applied.Removed
+
+	next := make(map[string]int, len(resp.Tasks))
+	for _, rec := range resp.Tasks {
+		next[rec.UUID] = rec.Rev
+	}
+	return next, stats, nil
+}
+
+// apply makes the local list match remote, keeping local IDs for tasks
+// that already exist here.
+func apply(tx tasks.Tx, local []tasks.Task, remote []Record) (Stats, error) {
+	var stats Stats
+	byUUID := make(map[string]tasks.Task, len(local))
+	for _, t := range local {
+		byUUID[t.UUID] = t
+	}
+
+	seen := map[string]bool{}
+	for _, rec := range remote {
+		seen[rec.UUID] = true
+		t := rec.Task
+		cur, exists := byUUID[t.UUID]
+		if exists {
+			if cur.Rev == t.Rev {
+				continue
+			}
+			t.ID = cur.ID
+		} else {
+			id, err := tx.NextID()
+			if err != nil {
+				return stats, err
+			}
+			t.ID = id
+		}
+		t.Parent = cur.Parent
+		if err := tx.Put(t); err != nil {
+			return stats, err
+		}
+		byUUID[t.UUID] = t
+		stats.Received++
+	}
+
+	for _, t := range local {
+		if !seen[t.UUID] {
+			if err := tx.Delete(t.ID); err != nil {
+				return stats, err
+			}
+			delete(byUUID, t.UUID)
+			stats.Removed++
+		}
+	}
+
+	// Parent links, by UUID, now that every task has a local ID.
+	for _, rec := range remote {
+		t := byUUID[rec.UUID]
+		parent := 0
+		if p, ok := byUUID[rec.ParentUUID]; ok {
+			parent = p.ID
+		}
+		if t.Parent != parent {
+			t.Parent = parent
+			if err := tx.Put(t); err != nil {
+				return stats, err
+			}
+		}
+	}
+	return stats, nil
+}
+
+func (c *Client) post(ctx context.Context, req Request) (Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	u, err := url.Parse(strings.TrimSuffix(c.URL, "/") + Path)
+	if err != nil {
+		return Response{}, fmt.Errorf("sync: remote: %w", err)
+	}
+	user := u.User
+	u.User = nil
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	opts := append(slices.Clone(reconnect), c.Retry...)
+	return retry.DoValue(ctx, func(ctx context.Context) (Response, error) {
+		hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return Response{}, retry.Permanent(err)
+		}
+		hreq.Header.Set("Content-Type", "application/json")
+		if c.Token != "" {
+			hreq.Header.Set("Authorization", "Bearer "+c.Token)
+		} else if user != nil {
+			pass, _ := user.Password()
+			hreq.SetBasicAuth(user.Username(), pass)
+		}
+		hresp, err := hc.Do(hreq)
+		if err != nil {
+			return Response{}, err
+		}
+		defer hresp.Body.Close()
+		if hresp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(hresp.Body, 512))
+			return Response{}, fmt.Errorf("sync: %w", &retry.StatusError{Code: hresp.StatusCode, Status: hresp.Status, Body: strings.TrimSpace(string(msg))})
+		}
+		var resp Response
+		if err := json.NewDecoder(hresp.Body).Decode(&resp); err != nil {
+			return Response{}, fmt.Errorf("sync: decode response: %w", err)
+		}
+		return resp, nil
+	}, opts...)
+}
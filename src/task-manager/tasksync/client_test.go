@@ -0,0 +1,69 @@
+package tasksync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopatterns/backoff"
+	"gopatterns/retry"
+	"gopatterns/task-manager/tasks"
+)
+
+func TestClientRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int32 // requests answered with fail before the server syncs
+		fail      int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"first try", 0, 0, 1, false},
+		{"after a 503", 1, http.StatusServiceUnavailable, 2, false},
+		{"server down", 5, http.StatusBadGateway, 4, true},
+		{"unauthorized", 5, http.StatusUnauthorized, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tasks.NewMemStore()
+			var calls atomic.Int32
+			handler := Handler(server, nil, func() time.Time { return t0 })
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					http.Error(w, "try later", tt.fail)
+					return
+				}
+				handler.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			local := tasks.NewMemStore()
+			c := &Client{URL: srv.URL, Retry: []retry.Option{retry.WithBackoff(backoff.Constant{})}}
+			var stats Stats
+			err := local.Update(func(tx tasks.Tx) (err error) {
+				if err = tx.Put(tasks.Task{ID: 1, Description: "Buy milk", CreatedAt: t0}); err != nil {
+					return err
+				}
+				_, stats, err = c.Sync(context.Background(), tx, nil)
+				return err
+			})
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("%d requests, want %d", got, tt.wantCalls)
+			}
+			if tt.wantErr {
+				var status *retry.StatusError
+				if !errors.As(err, &status) || status.Code != tt.fail || status.Body != "try later" {
+					t.Fatalf("Sync = %v, want status %d with the server's message", err, tt.fail)
+				}
+				return
+			}
+			if err != nil || stats.Sent != 1 {
+				t.Fatalf("Sync = %+v, %v; want one task sent", stats, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package tasksync
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"gopatterns/task-manager/auth"
+	"gopatterns/task-manager/hooks"
+	"gopatterns/task-manager/tasks"
+)
+
+// Path is where Handler is mounted.
+const Path = "/v1/sync"
+
+// maxRequest bounds the size of a sync request body.
+const maxRequest = 32 << 20
+
+// Handler serves sync requests against store. Wrap it in auth.Middleware.
+// Callers limited to auth.ScopeRead may only sync without sending changes,
+// which fetches the list. The tasks a sync writes or deletes go through the
+// hook scripts of runner, which may be nil; a rejected change fails the
+// whole sync with 422 Unprocessable Entity.
+func Handler(store tasks.Store, runner *hooks.Runner, now func() time.Time) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequest)).Decode(&req); err != nil {
+			http.Error(w, "bad sync request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		id, _ := auth.FromContext(r.Context())
+		scope := auth.ScopeRead
+		if len(req.Changed) > 0 || len(req.Deleted) > 0 {
+			scope = auth.ScopeWrite
+		}
+		if !id.Can(scope) {
+			http.Error(w, "forbidden: this sync needs the "+scope+" scope", http.StatusForbidden)
+			return
+		}
+		var resp Response
+		runner := runner.With("TASK_COMMAND=sync", "TASK_USER="+id.Subject)
+		err := store.Update(func(tx tasks.Tx) (err error) {
+			resp, err = Merge(runner.Tx(r.Context(), tx), req, now(), id.Subject)
+			return err
+		})
+		var rejected *hooks.RejectedError
+		switch {
+		case errors.As(err, &rejected), errors.Is(err, tasks.ErrInvalid):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,73 @@
+package tasksync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopatterns/retry"
+	"gopatterns/task-manager/hooks"
+	"gopatterns/task-manager/tasks"
+)
+
+func TestHandlerRunsHooks(t *testing.T) {
+	dir := t.TempDir()
+	// on-add rejects secrets and files everything else under a new name.
+	script := "#!/bin/sh\n" +
+		"grep -q secret && exit 1\n" +
+		"echo '{\"description\": \"Filed by the server\"}'\n"
+	if err := os.WriteFile(filepath.Join(dir, hooks.Add), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	server := tasks.NewMemStore()
+	srv := httptest.NewServer(Handler(server, &hooks.Runner{Dir: dir}, func() time.Time { return t0 }))
+	defer srv.Close()
+
+	sync := func(description string) (tasks.Task, error) {
+		local := tasks.NewMemStore()
+		c := &Client{URL: srv.URL}
+		err := local.Update(func(tx tasks.Tx) (err error) {
+			if err = tx.Put(tasks.Task{ID: 1, Description: description, CreatedAt: t0}); err != nil {
+				return err
+			}
+			_, _, err = c.Sync(context.Background(), tx, nil)
+			return err
+		})
+		if err != nil {
+			return tasks.Task{}, err
+		}
+		var got tasks.Task
+		err = local.View(func(tx tasks.Tx) (err error) {
+			got, err = tx.Get(1)
+			return err
+		})
+		return got, err
+	}
+
+	got, err := sync("Buy milk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != "Filed by the server" {
+		t.Fatalf("synced task reads %q, want the hook's rewrite", got.Description)
+	}
+
+	_, err = sync("secret plan")
+	var status *retry.StatusError
+	if !errors.As(err, &status) || status.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("sync of a task the hook rejects = %v, want 422", err)
+	}
+	var list []tasks.Task
+	server.View(func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	})
+	if len(list) != 1 {
+		t.Fatalf("server has %d tasks, want only the accepted one", len(list))
+	}
+}
@@ -0,0 +1,278 @@
+// Package tasksync keeps task lists on several machines in step through a
+// `task serve` instance.
+//
+// Every task carries a UUID and a revision that Record bumps on each change.
+// A client remembers the revision of every task as of its last sync (its
+// base). To sync it sends the server the tasks whose revision moved since
+// then and the UUIDs of base tasks it no longer has; the server merges them
+// into its own list and answers with the merged list, which the client
+// adopts. When both sides changed a task, it is merged field by field: each
+// field takes the side that changed it, and a field changed on both takes
+// the later change and keeps the other as a conflict to review with `task
+// conflicts`. A change always beats a deletion.
+package tasksync
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+// Record is a task on the wire. Short IDs are local to each machine, so the
+// parent link travels as a UUID.
+type Record struct {
+	tasks.Task
+	ParentUUID string `json:"parent_uuid,omitempty"`
+}
+
+// Request is what a client sends to the server.
+type Request struct {
+	// Base maps the UUID of every task the client had after its last sync
+	// to its revision at that time.
+	Base map[string]int `json:"base"`
+	// Changed holds the tasks created or modified since the last sync.
+	Changed []Record `json:"changed"`
+	// Deleted lists the base UUIDs the client no longer has.
+	Deleted []string `json:"deleted"`
+}
+
+// Response is the merged list the server returns.
+type Response struct {
+	Tasks     []Record `json:"tasks"`
+	Conflicts int      `json:"conflicts"` // fields changed on both sides, left for review
+}
+
+// Merge applies req to the server's list in tx and returns the result. The
+// changes are logged to the history as a "sync" by user.
+func Merge(tx tasks.Tx, req Request, now time.Time, user string) (Response, error) {
+	var resp Response
+	err := tasks.Track(tx, tasks.Operation{Name: "sync", Time: now, User: user}, func(tx tasks.Tx) (err error) {
+		resp, err = merge(tx, req, now)
+		return err
+	})
+	return resp, err
+}
+
+func merge(tx tasks.Tx, req Request, now time.Time) (Response, error) {
+	list, err := withUUIDs(tx)
+	if err != nil {
+		return Response{}, err
+	}
+	byUUID := make(map[string]tasks.Task, len(list))
+	for _, t := range list {
+		byUUID[t.UUID] = t
+	}
+
+	var resp Response
+	parents := map[string]string{} // child UUID -> parent UUID, for new links
+	for _, rec := range req.Changed {
+		incoming := rec.Task
+		if incoming.UUID == "" {
+			continue
+		}
+		cur, exists := byUUID[incoming.UUID]
+		base, known := req.Base[incoming.UUID]
+		switch {
+		case !exists:
+			// New on the client, or deleted here while the client changed it:
+			// either way the client's version is kept.
+			id, err := tx.NextID()
+			if err != nil {
+				return Response{}, err
+			}
+			incoming.ID = id
+		case !known || cur.Rev == base:
+			// Unchanged here since the client last saw it.
+			incoming.ID = cur.ID
+		default:
+			// Changed on both sides.
+			merged, lost := mergeFields(cur, incoming, base, now)
+			resp.Conflicts += lost
+			merged.ID = cur.ID
+			incoming = merged
+		}
+		incoming.Parent = 0
+		if rec.ParentUUID != "" {
+			parents[incoming.UUID] = rec.ParentUUID
+		}
+		if err := tx.Put(incoming); err != nil {
+			return Response{}, err
+		}
+		// Read it back: a hook script may have rewritten it.
+		if incoming, err = tx.Get(incoming.ID); err != nil {
+			return Response{}, err
+		}
+		byUUID[incoming.UUID] = incoming
+	}
+
+	for _, uuid := range req.Deleted {
+		cur, exists := byUUID[uuid]
+		if !exists || cur.Rev != req.Base[uuid] {
+			continue // already gone, or changed here since: keep it
+		}
+		if err := tx.Delete(cur.ID); err != nil {
+			return Response{}, err
+		}
+		delete(byUUID, uuid)
+	}
+
+	// Resolve parent links now that every task has a local ID.
+	for child, parent := range parents {
+		t, ok := byUUID[child]
+		p, pok := byUUID[parent]
+		if !ok || !pok {
+			continue
+		}
+		t.Parent = p.ID
+		if err := tx.Put(t); err != nil {
+			return Response{}, err
+		}
+	}
+
+	merged, err := tx.List()
+	if err != nil {
+		return Response{}, err
+	}
+	resp.Tasks = toRecords(merged)
+	return resp, nil
+}
+
+// mergeFields combines the server's and the client's version of a task
+// that both changed since revision base, returning the result with a
+// revision above both, so every machine picks it up, and the number of
+// conflicts. Each field takes the version of the side that changed it. A
+// field changed on both takes the later change and keeps the other as a
+// tasks.Conflict; notes and conflicts are combined instead. Versions
+// without field stamps, written before tasks had them, are settled as a
+// whole by time.
+func mergeFields(server, client tasks.Task, base int, now time.Time) (tasks.Task, int) {
+	rev := max(server.Rev, client.Rev) + 1
+	if server.Stamps == nil || client.Stamps == nil {
+		winner := server
+		if later(client, server) {
+			winner = client
+		}
+		winner.Rev = rev
+		return winner, 1
+	}
+
+	merged := server
+	merged.Rev = rev
+	if later(client, server) {
+		merged.UpdatedAt = client.UpdatedAt
+	}
+	merged.Stamps = maps.Clone(server.Stamps)
+	var lost []tasks.Conflict
+	for _, f := range tasks.MergeFields {
+		ss, cs := server.Stamps[f], client.Stamps[f]
+		// Fields taken from the client are restamped with the merged
+		// revision, so they read as changed to every other machine.
+		clientNewer := cs.Time.After(ss.Time)
+		newer := tasks.Stamp{Time: ss.Time, Rev: rev}
+		if clientNewer {
+			newer.Time = cs.Time
+		}
+		switch {
+		case cs.Rev <= base:
+			// Unchanged on the client: the server's value stands.
+		case ss.Rev <= base:
+			merged.SetField(f, client)
+			merged.Stamps[f] = tasks.Stamp{Time: cs.Time, Rev: rev}
+		case f == "notes":
+			merged.Notes = mergeNotes(server.Notes, client.Notes)
+			merged.Stamps[f] = newer
+		case f == "conflicts":
+			merged.Conflicts = mergeConflicts(server.Conflicts, client.Conflicts)
+			merged.Stamps[f] = newer
+		case tasks.FieldText(server, f) == tasks.FieldText(client, f):
+			// The same change on both sides.
+		default:
+			winner, loser, at := server, client, cs.Time
+			if clientNewer {
+				winner, loser, at = client, server, ss.Time
+			}
+			merged.SetField(f, winner)
+			merged.Stamps[f] = newer
+			loser.Stamps, loser.Conflicts = nil, nil
+			lost = append(lost, tasks.Conflict{Field: f, Value: tasks.FieldText(loser, f), Time: at, Task: loser})
+		}
+	}
+	if len(lost) > 0 {
+		merged.Conflicts = append(slices.Clone(merged.Conflicts), lost...)
+		merged.Stamps["conflicts"] = tasks.Stamp{Time: now, Rev: rev}
+	}
+	return merged, len(lost)
+}
+
+// mergeNotes combines two versions of a task's notes in time order,
+// dropping duplicates.
+func mergeNotes(a, b []tasks.Note) []tasks.Note {
+	out := slices.Clone(a)
+	for _, n := range b {
+		if !slices.ContainsFunc(out, func(o tasks.Note) bool { return o.Text == n.Text && o.Time.Equal(n.Time) }) {
+			out = append(out, n)
+		}
+	}
+	slices.SortStableFunc(out, func(x, y tasks.Note) int { return x.Time.Compare(y.Time) })
+	return out
+}
+
+// mergeConflicts combines two versions of a task's unresolved conflicts.
+func mergeConflicts(a, b []tasks.Conflict) []tasks.Conflict {
+	out := slices.Clone(a)
+	for _, c := range b {
+		if !slices.ContainsFunc(out, func(o tasks.Conflict) bool {
+			return o.Field == c.Field && o.Value == c.Value && o.Time.Equal(c.Time)
+		}) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// later reports whether a was changed after b.
+func later(a, b tasks.Task) bool {
+	switch {
+	case a.UpdatedAt == nil:
+		return false
+	case b.UpdatedAt == nil:
+		return true
+	default:
+		return a.UpdatedAt.After(*b.UpdatedAt)
+	}
+}
+
+// withUUIDs gives every task in tx a UUID, for lists written before tasks
+// had them, and returns the list.
+func withUUIDs(tx tasks.Tx) ([]tasks.Task, error) {
+	list, err := tx.List()
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range list {
+		if t.UUID != "" {
+			continue
+		}
+		t.UUID = tasks.NewUUID()
+		if err := tx.Put(t); err != nil {
+			return nil, err
+		}
+		list[i] = t
+	}
+	return list, nil
+}
+
+func toRecords(list []tasks.Task) []Record {
+	uuids := make(map[int]string, len(list))
+	for _, t := range list {
+		uuids[t.ID] = t.UUID
+	}
+	recs := make([]Record, len(list))
+	for i, t := range list {
+		recs[i] = Record{Task: t, ParentUUID: uuids[t.Parent]}
+		recs[i].ID, recs[i].Parent = 0, 0
+	}
+	return recs
+}
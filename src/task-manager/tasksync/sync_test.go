@@ -0,0 +1,233 @@
+package tasksync
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+)
+
+var t0 = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func at(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+
+// baseTask is a task as both sides had it at revision 1.
+func baseTask() tasks.Task {
+	t := tasks.Task{
+		UUID:        "0b6f5c2e-0000-4000-8000-000000000001",
+		Description: "Buy milk",
+		Priority:    tasks.PriorityLow,
+		Tags:        []string{"home"},
+		CreatedAt:   t0,
+		Rev:         1,
+		Stamps:      map[string]tasks.Stamp{},
+	}
+	for _, f := range tasks.MergeFields {
+		t.Stamps[f] = tasks.Stamp{Time: t0, Rev: 1}
+	}
+	updated := t0
+	t.UpdatedAt = &updated
+	return t
+}
+
+// edited returns t changed by edit at minute min, as revision t.Rev+1
+// touching the given fields.
+func edited(t tasks.Task, min int, edit func(*tasks.Task), fields ...string) tasks.Task {
+	t.Stamps = maps.Clone(t.Stamps)
+	edit(&t)
+	t.Rev++
+	when := at(min)
+	t.UpdatedAt = &when
+	for _, f := range fields {
+		t.Stamps[f] = tasks.Stamp{Time: when, Rev: t.Rev}
+	}
+	return t
+}
+
+func TestMergeFields(t *testing.T) {
+	describe := func(s string) func(*tasks.Task) { return func(t *tasks.Task) { t.Description = s } }
+	prioritise := func(p tasks.Priority) func(*tasks.Task) { return func(t *tasks.Task) { t.Priority = p } }
+	note := func(min int, s string) func(*tasks.Task) {
+		return func(t *tasks.Task) { t.Notes = append(slices.Clone(t.Notes), tasks.Note{Time: at(min), Text: s}) }
+	}
+
+	tests := []struct {
+		name      string
+		server    tasks.Task
+		client    tasks.Task
+		desc      string
+		prio      tasks.Priority
+		notes     []string
+		conflicts []string // the discarded values
+	}{
+		{
+			name:   "different fields",
+			server: edited(baseTask(), 5, describe("Buy oat milk"), "description"),
+			client: edited(baseTask(), 3, prioritise(tasks.PriorityHigh), "priority"),
+			desc:   "Buy oat milk",
+			prio:   tasks.PriorityHigh,
+		},
+		{
+			name:   "only the client changed the field",
+			server: edited(baseTask(), 9, prioritise(tasks.PriorityMedium), "priority"),
+			client: edited(baseTask(), 3, describe("Buy soy milk"), "description"),
+			desc:   "Buy soy milk",
+			prio:   tasks.PriorityMedium,
+		},
+		{
+			name:      "same field, client later",
+			server:    edited(baseTask(), 3, describe("Buy oat milk"), "description"),
+			client:    edited(baseTask(), 5, describe("Buy soy milk"), "description"),
+			desc:      "Buy soy milk",
+			prio:      tasks.PriorityLow,
+			conflicts: []string{"Buy oat milk"},
+		},
+		{
+			name:      "same field, server later",
+			server:    edited(baseTask(), 5, describe("Buy oat milk"), "description"),
+			client:    edited(baseTask(), 3, describe("Buy soy milk"), "description"),
+			desc:      "Buy oat milk",
+			prio:      tasks.PriorityLow,
+			conflicts: []string{"Buy soy milk"},
+		},
+		{
+			name:   "same change on both sides",
+			server: edited(baseTask(), 5, describe("Buy oat milk"), "description"),
+			client: edited(baseTask(), 3, describe("Buy oat milk"), "description"),
+			desc:   "Buy oat milk",
+			prio:   tasks.PriorityLow,
+		},
+		{
+			name:   "notes are combined",
+			server: edited(baseTask(), 5, note(5, "ask Ana"), "notes"),
+			client: edited(baseTask(), 3, note(3, "the big carton"), "notes"),
+			desc:   "Buy milk",
+			prio:   tasks.PriorityLow,
+			notes:  []string{"the big carton", "ask Ana"},
+		},
+	}
+	for _, tt := range tests {
+		merged, lost := mergeFields(tt.server, tt.client, 1, at(10))
+		if merged.Description != tt.desc || merged.Priority != tt.prio {
+			t.Errorf("%s: merged %q priority %v, want %q priority %v", tt.name, merged.Description, merged.Priority, tt.desc, tt.prio)
+		}
+		if merged.Rev != 3 {
+			t.Errorf("%s: merged revision %d, want 3", tt.name, merged.Rev)
+		}
+		var notes []string
+		for _, n := range merged.Notes {
+			notes = append(notes, n.Text)
+		}
+		if !slices.Equal(notes, tt.notes) {
+			t.Errorf("%s: notes %q, want %q", tt.name, notes, tt.notes)
+		}
+		var values []string
+		for _, c := range merged.Conflicts {
+			values = append(values, c.Value)
+		}
+		if lost != len(tt.conflicts) || !slices.Equal(values, tt.conflicts) {
+			t.Errorf("%s: %d conflicts %q, want %q", tt.name, lost, values, tt.conflicts)
+		}
+		// Fields taken from the client read as changed to other machines.
+		for _, f := range []string{"description", "priority"} {
+			if tt.client.Stamps[f].Rev > 1 && tt.server.Stamps[f].Rev == 1 && merged.Stamps[f].Rev != merged.Rev {
+				t.Errorf("%s: %s stamped with revision %d, want %d", tt.name, f, merged.Stamps[f].Rev, merged.Rev)
+			}
+		}
+	}
+}
+
+func TestMergeFieldsWithoutStamps(t *testing.T) {
+	server := edited(baseTask(), 5, func(t *tasks.Task) { t.Description = "Buy oat milk" })
+	client := edited(baseTask(), 3, func(t *tasks.Task) { t.Priority = tasks.PriorityHigh })
+	server.Stamps = nil
+
+	merged, lost := mergeFields(server, client, 1, at(10))
+	if merged.Description != "Buy oat milk" || merged.Priority != tasks.PriorityLow || lost != 1 {
+		t.Fatalf("merged %q priority %v with %d conflicts, want the later version whole", merged.Description, merged.Priority, lost)
+	}
+	if merged.Rev != 3 {
+		t.Fatalf("merged revision %d, want 3", merged.Rev)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	s := tasks.NewMemStore()
+	kept := baseTask()
+	gone := baseTask()
+	gone.UUID, gone.Description = "0b6f5c2e-0000-4000-8000-000000000002", "Call mom"
+	changedHere := baseTask()
+	changedHere.UUID, changedHere.Description = "0b6f5c2e-0000-4000-8000-000000000003", "Fix bike"
+	err := s.Update(func(tx tasks.Tx) error {
+		for i, t := range []tasks.Task{kept, gone, changedHere} {
+			t.ID = i + 1
+			if err := tx.Put(t); err != nil {
+				return err
+			}
+		}
+		t := changedHere
+		t.ID, t.Rev, t.Description = 3, 2, "Fix the bike"
+		return tx.Put(t)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child := edited(baseTask(), 4, func(t *tasks.Task) {
+		t.UUID, t.Description, t.Rev = "0b6f5c2e-0000-4000-8000-000000000004", "Buy cereal", 0
+	}, tasks.MergeFields...)
+	req := Request{
+		Base: map[string]int{kept.UUID: 1, gone.UUID: 1, changedHere.UUID: 1},
+		Changed: []Record{
+			{Task: edited(kept, 5, func(t *tasks.Task) { t.Description = "Buy oat milk" }, "description")},
+			{Task: child, ParentUUID: kept.UUID},
+		},
+		Deleted: []string{gone.UUID, changedHere.UUID},
+	}
+	var resp Response
+	err = s.Update(func(tx tasks.Tx) (err error) {
+		resp, err = Merge(tx, req, at(10), "ana")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byUUID := map[string]Record{}
+	for _, r := range resp.Tasks {
+		byUUID[r.UUID] = r
+		if r.ID != 0 || r.Parent != 0 {
+			t.Errorf("record %s carries local IDs %d/%d", r.UUID, r.ID, r.Parent)
+		}
+	}
+	if len(resp.Tasks) != 3 || resp.Conflicts != 0 {
+		t.Fatalf("merged %d tasks with %d conflicts, want 3 and 0: %+v", len(resp.Tasks), resp.Conflicts, resp.Tasks)
+	}
+	if got := byUUID[kept.UUID].Description; got != "Buy oat milk" {
+		t.Errorf("changed task reads %q, want the client's change", got)
+	}
+	if _, ok := byUUID[gone.UUID]; ok {
+		t.Error("task deleted by the client and unchanged here was kept")
+	}
+	if got := byUUID[changedHere.UUID].Description; got != "Fix the bike" {
+		t.Errorf("task deleted by the client but changed here reads %q, want it kept", got)
+	}
+	if got := byUUID[child.UUID].ParentUUID; got != kept.UUID {
+		t.Errorf("new subtask's parent is %q, want %q", got, kept.UUID)
+	}
+
+	var list []tasks.Task
+	if err := s.View(func(tx tasks.Tx) (err error) {
+		list, err = tx.List()
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range list {
+		if task.UUID == child.UUID && (task.ID != 4 || task.Parent != 1) {
+			t.Errorf("new subtask stored as %d under %d, want 4 under 1", task.ID, task.Parent)
+		}
+	}
+}
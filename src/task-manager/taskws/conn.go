@@ -0,0 +1,252 @@
+package taskws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Frame opcodes (RFC 6455 section 5.2).
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// Close status codes (RFC 6455 section 7.4.1).
+const (
+	closeNormal     = 1000
+	closeGoingAway  = 1001
+	closeProtocol   = 1002
+	closeTooBig     = 1009
+	closeOverloaded = 1013 // "try again later": the client fell behind
+)
+
+const (
+	// acceptGUID is appended to the client's key to prove the server speaks
+	// WebSocket.
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// maxFrame bounds what a client may send; it has nothing to say
+	// beyond pings and closes.
+	maxFrame = 4 << 10
+	// writeTimeout bounds every write, so a stuck client cannot pin a
+	// goroutine.
+	writeTimeout = 10 * time.Second
+)
+
+// errClosed is returned by readLoop once the client has closed the
+// connection with a close frame.
+var errClosed = errors.New("taskws: connection closed by client")
+
+// conn is the server side of an upgraded WebSocket connection.
+type conn struct {
+	rwc net.Conn
+	br  *bufio.Reader
+
+	mu     sync.Mutex // serialises writes
+	closed bool       // a close frame was sent
+}
+
+// upgrade completes the opening handshake for r, or answers it with an
+// error status. Handshakes from web pages on another origin are refused, so
+// a site the user visits cannot read their tasks from a local server.
+func upgrade(w http.ResponseWriter, r *http.Request) (*conn, error) {
+	fail := func(status int, msg string) (*conn, error) {
+		http.Error(w, msg, status)
+		return nil, fmt.Errorf("taskws: %s", msg)
+	}
+	switch {
+	case r.Method != http.MethodGet:
+		w.Header().Set("Allow", "GET")
+		return fail(http.StatusMethodNotAllowed, "method not allowed")
+	case !headerHas(r.Header, "Connection", "upgrade") || !headerHas(r.Header, "Upgrade", "websocket"):
+		w.Header().Set("Upgrade", "websocket")
+		return fail(http.StatusUpgradeRequired, "expected a WebSocket handshake")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return fail(http.StatusUpgradeRequired, "unsupported WebSocket version")
+	case !sameOrigin(r):
+		return fail(http.StatusForbidden, "cross-origin WebSocket requests are not allowed")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if raw, err := base64.StdEncoding.DecodeString(key); err != nil || len(raw) != 16 {
+		return fail(http.StatusBadRequest, "bad Sec-WebSocket-Key")
+	}
+
+	rwc, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return fail(http.StatusInternalServerError, "cannot take over the connection")
+	}
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+	rwc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := io.WriteString(rwc, resp); err != nil {
+		rwc.Close()
+		return nil, err
+	}
+	return &conn{rwc: rwc, br: brw.Reader}, nil
+}
+
+// headerHas reports whether the comma-separated header name lists token,
+// ignoring case.
+func headerHas(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin reports whether r comes from a page served by this host, or
+// from a client that is no browser and sends no Origin.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// writeFrame sends one unfragmented frame. Nothing is sent after a close
+// frame.
+func (c *conn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	if op == opClose {
+		c.closed = true
+	}
+	hdr := make([]byte, 2, 10)
+	hdr[0] = 0x80 | op // FIN
+	switch n := len(payload); {
+	case n < 126:
+		hdr[1] = byte(n)
+	case n <= 0xffff:
+		hdr[1] = 126
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(n))
+	default:
+		hdr[1] = 127
+		hdr = binary.BigEndian.AppendUint64(hdr, uint64(n))
+	}
+	c.rwc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := (&net.Buffers{hdr, payload}).WriteTo(c.rwc)
+	return err
+}
+
+// close sends a close frame with code and reason, unless one was sent
+// already, and drops the connection.
+func (c *conn) close(code uint16, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	err := c.writeFrame(opClose, append(payload, reason...))
+	if errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+	return errors.Join(err, c.rwc.Close())
+}
+
+// readFrame reads the next frame from the client, whose frames are always
+// masked. Data frames are returned unassembled: the server ignores them.
+func (c *conn) readFrame() (op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(c.br, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	op = hdr[0] & 0x0f
+	if hdr[1]&0x80 == 0 {
+		return 0, nil, protocolError("unmasked client frame")
+	}
+	n := uint64(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= opClose && (n > 125 || hdr[0]&0x80 == 0) {
+		return 0, nil, protocolError("bad control frame")
+	}
+	if n > maxFrame {
+		return 0, nil, errTooBig
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return op, payload, nil
+}
+
+// readLoop answers pings until the client closes the connection or breaks
+// the protocol, and returns why it stopped.
+func (c *conn) readLoop() error {
+	for {
+		op, payload, err := c.readFrame()
+		var perr protocolError
+		switch {
+		case errors.As(err, &perr):
+			c.close(closeProtocol, string(perr))
+			return err
+		case errors.Is(err, errTooBig):
+			c.close(closeTooBig, "frame too big")
+			return err
+		case err != nil:
+			return err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return err
+			}
+		case opClose:
+			c.close(closeNormal, "")
+			return errClosed
+		case opPong, opText, opBinary, opContinuation:
+		default:
+			c.close(closeProtocol, "unknown opcode")
+			return protocolError("unknown opcode")
+		}
+	}
+}
+
+// protocolError is a violation of RFC 6455 by the client.
+type protocolError string
+
+func (e protocolError) Error() string { return "taskws: " + string(e) }
+
+var errTooBig = errors.New("taskws: client frame too big")
@@ -0,0 +1,215 @@
+// Package taskws pushes the changes made to a task list to WebSocket
+// clients as they happen, so web and terminal front ends can live-update
+// without polling. The WebSocket protocol (RFC 6455) is implemented here
+// rather than with a library; the server only ever sends, so it needs no
+// more of it than text messages, pings and closing handshakes.
+package taskws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopatterns/task-manager/tasks"
+	"gopatterns/task-manager/webhook"
+)
+
+// Path is where Hub.Handler is mounted.
+const Path = "/ws"
+
+const (
+	// queueSize is how many messages may wait for a client before it is
+	// disconnected for falling behind.
+	queueSize = 64
+	// pingInterval keeps idle connections from being dropped by proxies.
+	pingInterval = 30 * time.Second
+)
+
+// Hub fans the changes to one task list out to the connected clients. Each
+// change is sent as a text message holding the webhook.Payload a webhook
+// would receive, with the task as it is afterwards.
+type Hub struct {
+	List string // reported in every message
+
+	mu      sync.Mutex
+	clients map[*client]bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// client is one connection and the messages queued for it.
+type client struct {
+	conn   *conn
+	events []string // event names to send, or nil for all
+	send   chan []byte
+	stop   chan uint16 // carries the close code when the hub drops it
+}
+
+// message is one encoded payload and the event it reports.
+type message struct {
+	event string
+	data  []byte
+}
+
+// NewHub returns a hub for the task list named list.
+func NewHub(list string) *Hub {
+	return &Hub{List: list, clients: map[*client]bool{}}
+}
+
+// Publish queues notices for every client that wants them. It never blocks,
+// so it can be passed to tasks.Notify: a client whose queue is full is
+// disconnected instead, and can reconnect and refetch.
+func (h *Hub) Publish(notices []tasks.Notice) {
+	msgs := make([]message, 0, len(notices))
+	for _, n := range notices {
+		p := webhook.NewPayload(h.List, n)
+		data, err := json.Marshal(p)
+		if err != nil {
+			continue
+		}
+		msgs = append(msgs, message{p.Event, data})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+clients:
+	for c := range h.clients {
+		for _, m := range msgs {
+			if c.events != nil && !slices.Contains(c.events, m.event) {
+				continue
+			}
+			select {
+			case c.send <- m.data:
+			default:
+				h.drop(c, closeOverloaded)
+				continue clients
+			}
+		}
+	}
+}
+
+// Handler upgrades requests to WebSocket connections that receive every
+// change published from then on, or with ?events=completed,deleted only
+// those events (see package webhook for their names). Wrap it in
+// auth.Middleware; read-only tokens suffice.
+func (h *Hub) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []string
+		if q := r.URL.Query().Get("events"); q != "" {
+			for _, e := range strings.Split(q, ",") {
+				e = strings.TrimSpace(e)
+				if !validEvent(e) {
+					http.Error(w, "unknown event "+e, http.StatusBadRequest)
+					return
+				}
+				events = append(events, e)
+			}
+		}
+		conn, err := upgrade(w, r)
+		if err != nil {
+			return
+		}
+		c := &client{conn: conn, events: events, send: make(chan []byte, queueSize), stop: make(chan uint16, 1)}
+		if !h.add(c) {
+			conn.close(closeGoingAway, "server shutting down")
+			return
+		}
+		defer h.wg.Done()
+		defer h.remove(c)
+		h.serve(c)
+	})
+}
+
+// serve writes c's messages until it goes away or the hub drops it.
+func (h *Hub) serve(c *client) {
+	read := make(chan error, 1)
+	go func() { read <- c.conn.readLoop() }()
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		var err error
+		select {
+		case data := <-c.send:
+			err = c.conn.writeFrame(opText, data)
+		case <-ping.C:
+			err = c.conn.writeFrame(opPing, nil)
+		case code := <-c.stop:
+			reason := "server shutting down"
+			if code == closeOverloaded {
+				reason = "too slow, reconnect"
+			}
+			c.conn.close(code, reason)
+			<-read
+			return
+		case <-read:
+			c.conn.rwc.Close()
+			return
+		}
+		if err != nil {
+			c.conn.rwc.Close()
+			<-read
+			return
+		}
+	}
+}
+
+func (h *Hub) add(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.clients[c] = true
+	h.wg.Add(1)
+	return true
+}
+
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+}
+
+// drop tells c to close with code and forgets it. h.mu must be held.
+func (h *Hub) drop(c *client, code uint16) {
+	delete(h.clients, c)
+	select {
+	case c.stop <- code:
+	default:
+	}
+}
+
+// Close disconnects every client with a going-away status and refuses new
+// ones, waiting until they are gone or ctx ends. It suits
+// lifecycle.Register next to http.Server.Shutdown, which leaves WebSocket
+// connections alone.
+func (h *Hub) Close(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for c := range h.clients {
+		h.drop(c, closeGoingAway)
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// validEvent reports whether e names a change event; digests are only
+// posted to webhooks.
+func validEvent(e string) bool {
+	return e != webhook.Digest && slices.Contains(webhook.Events, e)
+}
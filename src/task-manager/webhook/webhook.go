@@ -0,0 +1,167 @@
+// Package webhook posts task lifecycle events (a task created, completed,
+// deleted...) as JSON to configured URLs, to wire the task manager into
+// chat tools and home automation.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"gopatterns/retry"
+	"gopatterns/task-manager/tasks"
+)
+
+// Events a hook can subscribe to: task lifecycle events, and the daily
+// digest posted by `task digest -post`.
+const (
+	Created   = "created"
+	Updated   = "updated" // any other change
+	Completed = "completed"
+	Deleted   = "deleted"
+	Digest    = "digest"
+)
+
+// Events lists every event name, for validating configuration.
+var Events = []string{Created, Updated, Completed, Deleted, Digest}
+
+// DefaultEvents are sent to hooks that do not choose their own.
+var DefaultEvents = []string{Created, Completed, Deleted}
+
+// Hook is a URL and the events it receives; no Events means DefaultEvents.
+type Hook struct {
+	URL    string
+	Events []string
+}
+
+// Wants reports whether h subscribes to event.
+func (h Hook) Wants(event string) bool {
+	if len(h.Events) == 0 {
+		return slices.Contains(DefaultEvents, event)
+	}
+	return slices.Contains(h.Events, event)
+}
+
+// Payload is the JSON body POSTed for each event.
+type Payload struct {
+	Event   string              `json:"event"`
+	Text    string              `json:"text"` // shown as the message by Slack and Mattermost
+	List    string              `json:"list"`
+	Time    time.Time           `json:"time"`
+	User    string              `json:"user,omitempty"`
+	Op      string              `json:"op"` // the command or API call that made the change
+	TaskID  int                 `json:"task_id,omitempty"`
+	Task    *tasks.Task         `json:"task,omitempty"` // absent once deleted
+	Changes []tasks.FieldChange `json:"changes,omitempty"`
+	Digest  *tasks.Digest       `json:"digest,omitempty"` // digest events only
+}
+
+// Event classifies a history event as a lifecycle event.
+func Event(ev tasks.Event) string {
+	switch ev.Action {
+	case tasks.Created:
+		return Created
+	case tasks.Deleted:
+		return Deleted
+	}
+	for _, c := range ev.Changes {
+		if c.Field == "status" && (c.New == string(tasks.Done) || c.New == "completed") {
+			return Completed
+		}
+	}
+	return Updated
+}
+
+// NewPayload describes notice n from list.
+func NewPayload(list string, n tasks.Notice) Payload {
+	event := Event(n.Event)
+	who := n.User
+	if who == "" {
+		who = "someone"
+	}
+	return Payload{
+		Event:   event,
+		Text:    fmt.Sprintf("%s %s task %d: %s", who, event, n.TaskID, n.Description),
+		List:    list,
+		Time:    n.Time,
+		User:    n.User,
+		Op:      n.Op,
+		TaskID:  n.TaskID,
+		Task:    n.Task,
+		Changes: n.Changes,
+	}
+}
+
+// Sender delivers notices from one task list to its hooks.
+type Sender struct {
+	List  string
+	Hooks []Hook
+	HTTP  *http.Client   // nil means a client with a 5s timeout
+	Retry []retry.Option // more options for retry.Do, e.g. Attempts
+}
+
+// Send POSTs every notice to each hook that wants it, in order. A delivery
+// that fails with a network error or a 5xx status is retried as retry.Do
+// does; the errors of those that still fail are joined.
+func (s *Sender) Send(ctx context.Context, notices []tasks.Notice) error {
+	var errs []error
+	for _, n := range notices {
+		errs = append(errs, s.deliver(ctx, NewPayload(s.List, n)))
+	}
+	return errors.Join(errs...)
+}
+
+// SendDigest POSTs d, with text as the message, to each hook subscribed to
+// the digest event.
+func (s *Sender) SendDigest(ctx context.Context, d tasks.Digest, text string, now time.Time) error {
+	return s.deliver(ctx, Payload{Event: Digest, Text: text, List: s.List, Time: now, Op: "digest", Digest: &d})
+}
+
+// deliver POSTs p to each hook that wants it.
+func (s *Sender) deliver(ctx context.Context, p Payload) error {
+	var errs []error
+	for _, h := range s.Hooks {
+		if !h.Wants(p.Event) {
+			continue
+		}
+		if err := s.post(ctx, h.URL, p); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", h.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sender) post(ctx context.Context, url string, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	hc := s.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 5 * time.Second}
+	}
+	opts := append([]retry.Option{retry.RetryIf(retry.Transient)}, s.Retry...)
+	return retry.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode/100 != 2 {
+			return &retry.StatusError{Code: resp.StatusCode, Status: resp.Status}
+		}
+		return nil
+	}, opts...)
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopatterns/backoff"
+	"gopatterns/retry"
+	"gopatterns/task-manager/tasks"
+)
+
+func TestSendRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int // answered in turn, the last one from then on
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"delivered", []int{http.StatusNoContent}, 1, false},
+		{"after a 503", []int{http.StatusServiceUnavailable, http.StatusOK}, 2, false},
+		{"5xx every time", []int{http.StatusBadGateway}, 3, true},
+		{"4xx not retried", []int{http.StatusNotFound}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1))
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses))-1])
+			}))
+			defer srv.Close()
+
+			s := &Sender{
+				List:  "home",
+				Hooks: []Hook{{URL: srv.URL}},
+				Retry: []retry.Option{retry.WithBackoff(backoff.Constant{})},
+			}
+			n := tasks.Notice{Event: tasks.Event{Time: time.Now(), Op: "add", TaskID: 1, Description: "Buy milk", Action: tasks.Created}}
+			err := s.Send(context.Background(), []tasks.Notice{n})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send = %v, want error %v", err, tt.wantErr)
+			}
+			var status *retry.StatusError
+			if tt.wantErr && !errors.As(err, &status) {
+				t.Fatalf("Send = %v, want a retry.StatusError", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("%d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSendRetriesRefusedConnections(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	var attempts int
+	s := &Sender{
+		Hooks: []Hook{{URL: url}},
+		Retry: []retry.Option{
+			retry.WithBackoff(backoff.Constant{}),
+			retry.OnRetry(func(int, error, time.Duration) { attempts++ }),
+		},
+	}
+	n := tasks.Notice{Event: tasks.Event{Op: "add", TaskID: 1, Action: tasks.Created}}
+	if err := s.Send(context.Background(), []tasks.Notice{n}); err == nil {
+		t.Fatal("Send to a closed server succeeded")
+	}
+	if attempts != 2 {
+		t.Fatalf("%d retries, want 2", attempts)
+	}
+}
@@ -0,0 +1,206 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchHandler processes a batch of jobs of type J into one value of type R
+// per job, in the same order. An error fails every job of the batch.
+type BatchHandler[J, R any] func(ctx context.Context, jobs []J) ([]R, error)
+
+// Batching sets how NewBatch groups jobs.
+type Batching struct {
+	// Size is the most jobs in a batch; at least 1.
+	Size int
+	// Wait is how long a batch may wait for more jobs after its first.
+	// At 0, a batch is made of the jobs already queued.
+	Wait time.Duration
+}
+
+// NewBatch is New for a handler that takes jobs in batches, such as bulk
+// inserts, made of up to b.Size queued jobs, the most urgent first, or of
+// those that arrive within b.Wait. A worker waits for a whole batch before
+// taking the next one, and each job gets its own Result.
+//
+// A batch is one attempt for WithRetry, one call for WithBreaker and one
+// span for WithTracing; options that look at a job, WithBreaker and
+// WithJobTimeout, look at the first of the batch. WithMiddleware passes
+// each job through the middleware on its own, concurrently, and the jobs
+// that come through it are handled in one call; an error returned for any
+// job fails the batch.
+func NewBatch[J, R any](workers int, b Batching, handler BatchHandler[J, R], opts ...Option[J, R]) *Pool[J, R] {
+	cfg := newConfig(opts)
+	b.Size = max(b.Size, 1)
+	b.Wait = max(b.Wait, 0)
+	return start(workers, b, chainBatch(handler, cfg.middleware...), cfg)
+}
+
+// chainBatch returns handler with mw around each job of a batch, as
+// described for NewBatch. A job whose middleware calls next again once the
+// batch has been handled is handled again on its own.
+func chainBatch[J, R any](handler BatchHandler[J, R], mw ...Middleware[J, R]) BatchHandler[J, R] {
+	if len(mw) == 0 {
+		return handler
+	}
+	return func(ctx context.Context, jobs []J) ([]R, error) {
+		c := &batchCall[J, R]{
+			jobs:    make([]*J, len(jobs)),
+			waiting: len(jobs),
+			ready:   make(chan struct{}),
+			done:    make(chan struct{}),
+		}
+		values := make([]R, len(jobs))
+		errs := make([]error, len(jobs))
+		var wg sync.WaitGroup
+		for i, job := range jobs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var reached atomic.Bool
+				defer func() {
+					if r := recover(); r != nil {
+						errs[i] = fmt.Errorf("workerpool: job panicked: %v", r)
+					}
+					if !reached.Swap(true) {
+						c.arrive(i, nil) // turned back by the middleware
+					}
+				}()
+				values[i], errs[i] = Chain(func(ctx context.Context, job J) (R, error) {
+					if reached.Swap(true) {
+						return handleOne(ctx, handler, job)
+					}
+					c.arrive(i, &job)
+					<-c.done
+					if c.err != nil {
+						var zero R
+						return zero, c.err
+					}
+					return c.values[i], nil
+				}, mw...)(ctx, job)
+			}()
+		}
+
+		<-c.ready
+		c.handle(ctx, handler)
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+}
+
+// batchCall gathers the jobs of a batch that come through the middleware
+// for one call to the handler.
+type batchCall[J, R any] struct {
+	mu      sync.Mutex
+	jobs    []*J          // by position in the batch, nil if not through
+	waiting int           // jobs neither through the middleware nor turned back
+	ready   chan struct{} // closed once none is waiting
+	done    chan struct{} // closed once values and err are set
+	values  []R           // by position in the batch
+	err     error
+}
+
+// arrive records that the job at position i of the batch came through the
+// middleware as job, or was turned back if job is nil.
+func (c *batchCall[J, R]) arrive(i int, job *J) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.jobs[i] = job
+	if c.waiting--; c.waiting == 0 {
+		close(c.ready)
+	}
+}
+
+// handle calls handler with the jobs that came through, if any, in the
+// order of the batch.
+func (c *batchCall[J, R]) handle(ctx context.Context, handler BatchHandler[J, R]) {
+	defer close(c.done)
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("workerpool: job panicked: %v", r)
+		}
+	}()
+	var (
+		jobs []J
+		at   []int
+	)
+	for i, job := range c.jobs {
+		if job != nil {
+			jobs = append(jobs, *job)
+			at = append(at, i)
+		}
+	}
+	if len(jobs) == 0 {
+		return
+	}
+	values, err := handler(ctx, jobs)
+	if err == nil && len(values) != len(jobs) {
+		err = fmt.Errorf("workerpool: handler returned %d values for %d jobs", len(values), len(jobs))
+	}
+	if c.err = err; err != nil {
+		return
+	}
+	c.values = make([]R, len(c.jobs))
+	for k, i := range at {
+		c.values[i] = values[k]
+	}
+}
+
+// handleOne calls handler with job alone.
+func handleOne[J, R any](ctx context.Context, handler BatchHandler[J, R], job J) (R, error) {
+	var zero R
+	values, err := handler(ctx, []J{job})
+	if err != nil {
+		return zero, err
+	}
+	if len(values) != 1 {
+		return zero, fmt.Errorf("workerpool: handler returned %d values for 1 job", len(values))
+	}
+	return values[0], nil
+}
+
+// gather adds up to p.batch.Size-1 jobs to first as described for
+// Batching. open is false if it found the queue closed.
+func (p *Pool[J, R]) gather(first queued[J]) (batch []queued[J], open bool) {
+	batch = []queued[J]{first}
+	if p.batch.Size == 1 {
+		return batch, true
+	}
+	var wait <-chan time.Time // nil, never ready, with no Wait
+	if p.batch.Wait > 0 {
+		timer := time.NewTimer(p.batch.Wait)
+		defer timer.Stop()
+		wait = timer.C
+	}
+	for len(batch) < p.batch.Size {
+		var (
+			q  queued[J]
+			ok bool
+		)
+		select {
+		case q, ok = <-p.out:
+		default: // none queued: wait for one, if the batch may
+			if wait == nil {
+				return batch, true
+			}
+			select {
+			case q, ok = <-p.out:
+			case <-wait:
+				return batch, true
+			}
+		}
+		if !ok {
+			return batch, false
+		}
+		batch = append(batch, q)
+	}
+	return batch, true
+}
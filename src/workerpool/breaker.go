@@ -0,0 +1,55 @@
+package workerpool
+
+import (
+	"context"
+
+	"gopatterns/breaker"
+	"gopatterns/retry"
+)
+
+// WithBreaker runs jobs through circuit breakers, one per key(job), such as
+// the job's type or the host it calls. While a circuit is open its jobs
+// fail at once with breaker.ErrOpen, without a retry, instead of tying up
+// workers on a dependency that is down; they become dead letters, to be
+// redriven once it is back. Every attempt counts towards the circuit, and
+// any error as a failure.
+func WithBreaker[J, R any](key func(J) string, s breaker.Settings) Option[J, R] {
+	return func(c *config[J, R]) {
+		c.breakerKey = key
+		c.breakers = breaker.NewGroup(s)
+	}
+}
+
+// Breakers returns the circuits of WithBreaker, or nil.
+func (p *Pool[J, R]) Breakers() *breaker.Group {
+	return p.breakers
+}
+
+// call runs jobs through the handler, in the circuit of the first and with
+// retries if configured.
+func (p *Pool[J, R]) call(ctx context.Context, jobs []J) ([]R, error) {
+	run := p.run
+	if _, ok := ctx.Deadline(); ok {
+		run = p.runBounded
+	}
+	attempt := func(ctx context.Context) ([]R, error) { return run(ctx, jobs) }
+	if p.breakers != nil {
+		b := p.breakers.Get(p.breakerKey(jobs[0]))
+		attempt = func(ctx context.Context) ([]R, error) {
+			done, err := b.Allow()
+			if err != nil {
+				if p.retry != nil {
+					err = retry.Permanent(err) // no use retrying now
+				}
+				return nil, err
+			}
+			values, err := run(ctx, jobs)
+			done(err == nil)
+			return values, err
+		}
+	}
+	if p.retry == nil {
+		return attempt(ctx)
+	}
+	return retry.DoValue(ctx, attempt, traceRetries(ctx, p.retry)...)
+}
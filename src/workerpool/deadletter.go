@@ -0,0 +1,88 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gopatterns/retry"
+)
+
+// DeadLetter is a job that failed for good: its handler returned an error
+// on every attempt, or one that retry.Permanent marked as not worth
+// retrying.
+type DeadLetter[J any] struct {
+	Job      J
+	Priority int
+	Errors   []error   // one per attempt, oldest first
+	Failed   time.Time // when the last attempt failed
+}
+
+// Err returns the error of the last attempt.
+func (d DeadLetter[J]) Err() error { return d.Errors[len(d.Errors)-1] }
+
+// WithRetry runs a failing handler again as retry.DoValue does with opts:
+// 3 attempts with exponential backoff unless they say otherwise. Only the
+// outcome of the last attempt is sent on Results, and the worker stays
+// busy with the job until then.
+func WithRetry[J, R any](opts ...retry.Option) Option[J, R] {
+	return func(c *config[J, R]) { c.retry = append(c.retry, opts...) }
+}
+
+// WithDeadLetters keeps up to n jobs that failed for good, dropping the
+// oldest beyond that, for DeadLetters and Redrive. n below 1 keeps none,
+// which is the default.
+func WithDeadLetters[J, R any](n int) Option[J, R] {
+	return func(c *config[J, R]) { c.deadLetters = max(n, 0) }
+}
+
+// DeadLetters returns the jobs that failed for good, oldest first.
+func (p *Pool[J, R]) DeadLetters() []DeadLetter[J] {
+	p.deadMu.Lock()
+	defer p.deadMu.Unlock()
+	return slices.Clone(p.dead)
+}
+
+// Redrive submits the dead letters again at their priority, oldest first,
+// taking them out of the dead letters, and returns how many it submitted.
+// If Submit fails, the ones not yet submitted are kept and the error is
+// returned.
+func (p *Pool[J, R]) Redrive(ctx context.Context) (int, error) {
+	p.deadMu.Lock()
+	list := p.dead
+	p.dead = nil
+	p.deadMu.Unlock()
+	for i, d := range list {
+		if err := p.SubmitPriority(ctx, d.Job, d.Priority); err != nil {
+			p.deadMu.Lock()
+			p.dead = p.keepDead(append(list[i:], p.dead...))
+			p.deadMu.Unlock()
+			return i, err
+		}
+	}
+	return len(list), nil
+}
+
+// bury records q, whose handler failed with err, as a dead letter.
+func (p *Pool[J, R]) bury(q queued[J], err error) {
+	if p.deadLimit == 0 {
+		return
+	}
+	d := DeadLetter[J]{Job: q.job, Priority: q.priority, Errors: []error{err}, Failed: time.Now()}
+	var re *retry.Error
+	if errors.As(err, &re) {
+		d.Errors = slices.Clone(re.Errors)
+	}
+	p.deadMu.Lock()
+	defer p.deadMu.Unlock()
+	p.dead = p.keepDead(append(p.dead, d))
+}
+
+// keepDead trims list to the newest p.deadLimit entries.
+func (p *Pool[J, R]) keepDead(list []DeadLetter[J]) []DeadLetter[J] {
+	if n := len(list) - p.deadLimit; n > 0 {
+		return slices.Delete(list, 0, n)
+	}
+	return list
+}
@@ -0,0 +1,171 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Graph is a set of jobs, some of which may only run once others have
+// succeeded. The zero value is an empty graph.
+type Graph[J any] struct {
+	jobs []J
+	deps [][]int
+}
+
+// Add adds job to the graph, to run once the jobs with the given IDs have
+// succeeded, and returns its ID. A job can only depend on jobs added
+// before it, so a graph has no cycles; Add panics on an ID it has not
+// returned.
+func (g *Graph[J]) Add(job J, deps ...int) int {
+	for _, d := range deps {
+		if d < 0 || d >= len(g.jobs) {
+			panic(fmt.Sprintf("workerpool: Graph.Add: no job %d", d))
+		}
+	}
+	g.jobs = append(g.jobs, job)
+	g.deps = append(g.deps, slices.Clone(deps))
+	return len(g.jobs) - 1
+}
+
+// Len returns the number of jobs in the graph.
+func (g *Graph[J]) Len() int { return len(g.jobs) }
+
+// DependencyError is the error of a job of a Graph that did not run
+// because a job it depends on failed, with the error of that job.
+type DependencyError struct {
+	Err error
+}
+
+func (e *DependencyError) Error() string {
+	return "workerpool: dependency failed: " + e.Err.Error()
+}
+
+func (e *DependencyError) Unwrap() error { return e.Err }
+
+// SubmitGraph queues the jobs of g at PriorityNormal, each once the jobs it
+// depends on have succeeded, so independent ones run concurrently. The
+// jobs that depend on one that failed, directly or not, fail with a
+// DependencyError without running. Every job gets a Result.
+//
+// SubmitGraph waits, as Submit does, to queue the jobs that depend on
+// none, and returns the first error doing so; the others are queued in
+// the background. Jobs not yet queued when Shutdown is called never run:
+// they are dead letters, failed with ErrClosed, and so are the jobs that
+// depend on them, with a DependencyError, if WithDeadLetters keeps any.
+func (p *Pool[J, R]) SubmitGraph(ctx context.Context, g *Graph[J]) error {
+	run := &graphRun[J, R]{
+		p:        p,
+		ctx:      context.WithoutCancel(ctx), // for its values, such as the trace
+		jobs:     slices.Clone(g.jobs),
+		waiting:  make([]int, len(g.jobs)),
+		children: make([][]int, len(g.jobs)),
+	}
+	var roots []int
+	for id, deps := range g.deps {
+		run.waiting[id] = len(deps)
+		for _, d := range deps {
+			run.children[d] = append(run.children[d], id)
+		}
+		if len(deps) == 0 {
+			roots = append(roots, id)
+		}
+	}
+	for _, id := range roots {
+		if err := p.submit(ctx, run.queued(id, nil)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// graphRun tracks the jobs of a Graph submitted to a pool.
+type graphRun[J, R any] struct {
+	p        *Pool[J, R]
+	ctx      context.Context
+	jobs     []J
+	children [][]int // the jobs that depend on each
+
+	mu      sync.Mutex
+	waiting []int // dependencies of each yet to succeed, -1 once one failed
+}
+
+// queued returns job id, ready to be queued, failing with failed if that
+// is not nil.
+func (r *graphRun[J, R]) queued(id int, failed error) queued[J] {
+	return queued[J]{
+		job:      r.jobs[id],
+		priority: PriorityNormal,
+		delivery: &Delivery{Done: func(err error) { r.done(id, err) }},
+		failed:   failed,
+	}
+}
+
+// done queues the jobs that depended on id and are now ready to run, or
+// to fail if id failed.
+func (r *graphRun[J, R]) done(id int, err error) {
+	var ready []int
+	r.mu.Lock()
+	for _, c := range r.children[id] {
+		if r.waiting[c] < 0 {
+			continue // failed already, with another dependency
+		}
+		if err != nil {
+			r.waiting[c] = -1
+			ready = append(ready, c)
+		} else if r.waiting[c]--; r.waiting[c] == 0 {
+			ready = append(ready, c)
+		}
+	}
+	r.mu.Unlock()
+	if len(ready) == 0 {
+		return
+	}
+	var failed error
+	if err != nil {
+		failed = &DependencyError{Err: err}
+	}
+	go func() { // not on the worker, which may be needed to make room
+		for _, c := range ready {
+			if err := r.p.submit(r.ctx, r.queued(c, failed)); err != nil {
+				r.lost(c, err)
+			}
+		}
+	}()
+}
+
+// lost dead-letters job id, which could not be queued because of err, and
+// the jobs that depend on it, directly or not, which now never run.
+func (r *graphRun[J, R]) lost(id int, err error) {
+	r.p.bury(r.queued(id, nil), err)
+	dep := &DependencyError{Err: err}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := slices.Clone(r.children[id])
+	for len(next) > 0 {
+		c := next[len(next)-1]
+		next = next[:len(next)-1]
+		if r.waiting[c] < 0 {
+			continue // queued to fail already, or lost with another dependency
+		}
+		r.waiting[c] = -1
+		r.p.bury(r.queued(c, nil), dep)
+		next = append(next, r.children[c]...)
+	}
+}
+
+// skip sends the results of the jobs of batch that fail without running,
+// and returns the others.
+func (p *Pool[J, R]) skip(batch []queued[J]) []queued[J] {
+	return slices.DeleteFunc(batch, func(q queued[J]) bool {
+		if q.failed == nil {
+			return false
+		}
+		p.forget(q)
+		q.delivery.Done(q.failed)
+		p.metrics.failed.Add(1)
+		p.results <- Result[J, R]{Job: q.job, Err: q.failed}
+		return true
+	})
+}
@@ -0,0 +1,164 @@
+// Package kafkasource feeds a workerpool pool from a Kafka consumer group,
+// committing each partition's offset only as far as the jobs that have
+// succeeded, in order, so a restart redelivers every job that had not:
+// at-least-once delivery.
+//
+// It works with any Kafka client through Reader. With segmentio/kafka-go,
+// for instance, a *kafka.Reader that has a GroupID needs only its message
+// type converted:
+//
+//	type reader struct{ r *kafka.Reader }
+//
+//	func (r reader) FetchMessage(ctx context.Context) (kafkasource.Message, error) {
+//		m, err := r.r.FetchMessage(ctx)
+//		return kafkasource.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset, Key: m.Key, Value: m.Value}, err
+//	}
+//
+//	func (r reader) CommitMessages(ctx context.Context, msgs ...kafkasource.Message) error {
+//		km := make([]kafka.Message, len(msgs))
+//		for i, m := range msgs {
+//			km[i] = kafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset}
+//		}
+//		return r.r.CommitMessages(ctx, km...)
+//	}
+package kafkasource
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"gopatterns/workerpool"
+)
+
+// Message is a record fetched from Kafka.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// Reader is a consumer group member that fetches messages without
+// committing them.
+type Reader interface {
+	// FetchMessage waits for the next message until ctx is done.
+	FetchMessage(ctx context.Context) (Message, error)
+	// CommitMessages commits the offsets of msgs: the group resumes after
+	// them.
+	CommitMessages(ctx context.Context, msgs ...Message) error
+}
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithCommitFailed commits past jobs that failed for good, as well as
+// those that succeeded, for pools that deal with failures themselves, with
+// WithDeadLetters for instance. By default a failure holds its partition's
+// offset back, so it and every later job of the partition are redelivered
+// after a restart.
+func WithCommitFailed() Option {
+	return func(s *Source) { s.commitFailed = true }
+}
+
+// WithOnError calls fn with the errors of CommitMessages, which are
+// otherwise dropped: a commit that failed is made good by the next.
+func WithOnError(fn func(error)) Option {
+	return func(s *Source) { s.onError = fn }
+}
+
+// Source is a workerpool.Source for the messages of r, delivering their
+// Value.
+type Source struct {
+	r            Reader
+	commitFailed bool
+	onError      func(error)
+
+	mu         sync.Mutex // held while committing, to commit in order
+	partitions map[partition]*inFlight
+}
+
+var _ workerpool.Source = (*Source)(nil)
+
+type partition struct {
+	topic string
+	id    int
+}
+
+// inFlight is a partition's messages delivered and not yet committed, in
+// offset order.
+type inFlight struct {
+	msgs []Message
+	done []bool
+	held bool // by a failure, without WithCommitFailed: nothing is tracked
+}
+
+// New returns a Source fetching from r.
+func New(r Reader, opts ...Option) *Source {
+	s := &Source{r: r, partitions: map[partition]*inFlight{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Receive fetches the next message.
+func (s *Source) Receive(ctx context.Context) (workerpool.Delivery, error) {
+	m, err := s.r.FetchMessage(ctx)
+	if err != nil {
+		return workerpool.Delivery{}, err
+	}
+	key := partition{m.Topic, m.Partition}
+	s.mu.Lock()
+	p, ok := s.partitions[key]
+	if !ok {
+		p = &inFlight{}
+		s.partitions[key] = p
+	}
+	if !p.held { // else nothing will be committed: no need to track it
+		p.msgs = append(p.msgs, Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset})
+		p.done = append(p.done, false)
+	}
+	s.mu.Unlock()
+	return workerpool.Delivery{Data: m.Value, Done: func(err error) { s.done(key, m.Offset, err) }}, nil
+}
+
+// done records the outcome of the message at offset, and commits the
+// partition up to the last of the messages done in a row from its first.
+func (s *Source) done(key partition, offset int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.partitions[key]
+	if p.held {
+		return
+	}
+	if err != nil && !s.commitFailed {
+		*p = inFlight{held: true}
+		return
+	}
+	i, ok := slices.BinarySearchFunc(p.msgs, offset, func(m Message, offset int64) int {
+		return cmp.Compare(m.Offset, offset)
+	})
+	if !ok {
+		return
+	}
+	p.done[i] = true
+	n := 0
+	for n < len(p.done) && p.done[n] {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	last := p.msgs[n-1]
+	p.msgs, p.done = p.msgs[n:], p.done[n:]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.r.CommitMessages(ctx, last); err != nil && s.onError != nil {
+		s.onError(err)
+	}
+}
@@ -0,0 +1,107 @@
+package kafkasource
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"gopatterns/workerpool"
+)
+
+// fakeReader hands out msgs, then waits for ctx.
+type fakeReader struct {
+	msgs chan Message
+
+	mu      sync.Mutex
+	commits []int64
+}
+
+func newFakeReader(values ...string) *fakeReader {
+	r := &fakeReader{msgs: make(chan Message, len(values))}
+	for i, v := range values {
+		r.msgs <- Message{Topic: "jobs", Offset: int64(i), Value: []byte(v)}
+	}
+	return r
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (Message, error) {
+	select {
+	case m := <-r.msgs:
+		return m, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range msgs {
+		r.commits = append(r.commits, m.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) committed() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.commits)
+}
+
+func TestCommitsInOrderAfterSuccess(t *testing.T) {
+	r := newFakeReader("0", "1", "2")
+	s := New(r)
+	ctx := context.Background()
+	var ds []func(error)
+	for range 3 {
+		d, err := s.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds = append(ds, d.Done)
+	}
+	ds[1](nil)
+	if c := r.committed(); len(c) != 0 {
+		t.Fatalf("committed %v past an unfinished job", c)
+	}
+	ds[0](nil)
+	ds[2](nil)
+	if c := r.committed(); !slices.Equal(c, []int64{1, 2}) {
+		t.Fatalf("committed %v, want [1 2]", c)
+	}
+}
+
+func TestFailureHoldsOffset(t *testing.T) {
+	for _, commitFailed := range []bool{false, true} {
+		r := newFakeReader("1", "0", "3")
+		var opts []Option
+		if commitFailed {
+			opts = append(opts, WithCommitFailed())
+		}
+		s := New(r, opts...)
+		p := workerpool.New(1, func(_ context.Context, n int) (int, error) {
+			if n == 0 {
+				return 0, errors.New("zero")
+			}
+			return n, nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		go p.Consume(ctx, s, func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+		for range 3 {
+			<-p.Results()
+		}
+		cancel()
+		p.Shutdown(context.Background())
+
+		want := []int64{0}
+		if commitFailed {
+			want = []int64{0, 1, 2}
+		}
+		if c := r.committed(); !slices.Equal(c, want) {
+			t.Errorf("WithCommitFailed %v: committed %v, want %v", commitFailed, c, want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package workerpool
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the histogram of job
+// processing times: the Prometheus client's defaults.
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metrics counts what a pool does. Every pool keeps them; they cost a few
+// atomic adds per job.
+type metrics struct {
+	submitted atomic.Uint64
+	completed atomic.Uint64 // finished without an error
+	failed    atomic.Uint64 // finished with one, retries included
+	retried   atomic.Uint64 // attempts after the first
+	busy      atomic.Int64  // workers running a job
+
+	mu      sync.Mutex
+	buckets []uint64 // per latencyBuckets, not cumulative, then +Inf
+	sum     time.Duration
+	recent  []time.Duration // the latest recentJobs durations, for Stats
+	next    int             // where the next one goes in recent
+}
+
+// observe records a job that took d.
+func (m *metrics) observe(d time.Duration) {
+	i, _ := slices.BinarySearch(latencyBuckets, d.Seconds())
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.buckets == nil {
+		m.buckets = make([]uint64, len(latencyBuckets)+1)
+	}
+	m.buckets[i]++
+	m.sum += d
+	if len(m.recent) < recentJobs {
+		m.recent = append(m.recent, d)
+	} else {
+		m.recent[m.next] = d
+		m.next = (m.next + 1) % recentJobs
+	}
+}
+
+// Metered is a pool whose metrics a Collector can report. Every *Pool is
+// one.
+type Metered interface {
+	sample() sample
+}
+
+// sample is a pool's metrics at one moment.
+type sample struct {
+	Stats
+	buckets []uint64 // cumulative, then the total
+	sum     float64
+}
+
+func (p *Pool[J, R]) sample() sample {
+	s := sample{Stats: p.Stats(), buckets: make([]uint64, len(latencyBuckets)+1)}
+	m := &p.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n uint64
+	for i := range s.buckets {
+		if m.buckets != nil {
+			n += m.buckets[i]
+		}
+		s.buckets[i] = n
+	}
+	s.sum = m.sum.Seconds()
+	return s
+}
+
+// Collector reports the metrics of pools in the Prometheus text format,
+// each labelled with its name, for a scraper to collect from ServeHTTP.
+// It is safe for concurrent use.
+type Collector struct {
+	mu    sync.Mutex
+	pools map[string]Metered
+}
+
+// NewCollector returns a Collector reporting no pools yet.
+func NewCollector() *Collector {
+	return &Collector{pools: map[string]Metered{}}
+}
+
+// Register reports p as pool="name", replacing any pool of that name.
+func (c *Collector) Register(name string, p Metered) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pools[name] = p
+}
+
+// Unregister stops reporting the pool of that name.
+func (c *Collector) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pools, name)
+}
+
+// ServeHTTP writes the metrics, for a /metrics endpoint.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics of every pool to w:
+//
+//	workerpool_jobs_submitted_total    counter
+//	workerpool_jobs_completed_total    counter, jobs that succeeded
+//	workerpool_jobs_failed_total       counter, jobs that failed for good
+//	workerpool_jobs_retried_total      counter, attempts after the first
+//	workerpool_queue_depth             gauge, jobs waiting for a worker
+//	workerpool_workers                 gauge, workers running
+//	workerpool_workers_busy            gauge, workers running a job
+//	workerpool_job_duration_seconds    histogram, including retries
+//
+// Utilization is workers_busy over workers.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.pools))
+	for name := range c.pools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	samples := make([]sample, len(names))
+	for i, name := range names {
+		samples[i] = c.pools[name].sample()
+	}
+	c.mu.Unlock()
+
+	cw := &countWriter{w: w}
+	b := bufio.NewWriter(cw)
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = `pool="` + labelEscaper.Replace(name) + `"`
+	}
+	family := func(name, kind, help string, value func(s sample) string) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for i, s := range samples {
+			fmt.Fprintf(b, "%s{%s} %s\n", name, labels[i], value(s))
+		}
+	}
+	count := func(n uint64) string { return strconv.FormatUint(n, 10) }
+	gauge := func(n int) string { return strconv.Itoa(n) }
+
+	family("workerpool_jobs_submitted_total", "counter", "Jobs submitted.",
+		func(s sample) string { return count(s.Submitted) })
+	family("workerpool_jobs_completed_total", "counter", "Jobs that succeeded.",
+		func(s sample) string { return count(s.Completed) })
+	family("workerpool_jobs_failed_total", "counter", "Jobs that failed for good.",
+		func(s sample) string { return count(s.Failed) })
+	family("workerpool_jobs_retried_total", "counter", "Job attempts after the first.",
+		func(s sample) string { return count(s.Retried) })
+	family("workerpool_queue_depth", "gauge", "Jobs waiting for a worker.",
+		func(s sample) string { return gauge(s.Queued) })
+	family("workerpool_workers", "gauge", "Workers running.",
+		func(s sample) string { return gauge(s.Workers) })
+	family("workerpool_workers_busy", "gauge", "Workers running a job.",
+		func(s sample) string { return gauge(s.Busy) })
+
+	const hist = "workerpool_job_duration_seconds"
+	fmt.Fprintf(b, "# HELP %s Time spent processing a job, retries included.\n# TYPE %s histogram\n", hist, hist)
+	for i, s := range samples {
+		for j, le := range latencyBuckets {
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", hist, labels[i], strconv.FormatFloat(le, 'g', -1, 64), s.buckets[j])
+		}
+		total := s.buckets[len(latencyBuckets)]
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", hist, labels[i], total)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", hist, labels[i], strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", hist, labels[i], total)
+	}
+	err := b.Flush()
+	return cw.n, err
+}
+
+// labelEscaper escapes a label value for the text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// countWriter counts the bytes written through it, for WriteTo.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
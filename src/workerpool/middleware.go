@@ -0,0 +1,22 @@
+package workerpool
+
+// Middleware wraps a handler with behaviour of its own, such as logging or
+// authorization, before or after calling next.
+type Middleware[J, R any] func(next Handler[J, R]) Handler[J, R]
+
+// Chain returns handler wrapped in mw, the first outermost: it is called
+// first, and calls the second, and so on down to handler.
+func Chain[J, R any](handler Handler[J, R], mw ...Middleware[J, R]) Handler[J, R] {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler
+}
+
+// WithMiddleware wraps the handler of New in mw, as Chain does, within the
+// middleware of any earlier WithMiddleware. The middleware runs on every
+// attempt at a job, within its timeout and span. NewBatch runs it around
+// each job of a batch.
+func WithMiddleware[J, R any](mw ...Middleware[J, R]) Option[J, R] {
+	return func(c *config[J, R]) { c.middleware = append(c.middleware, mw...) }
+}
@@ -0,0 +1,130 @@
+// Package natssource feeds a workerpool pool from a NATS JetStream
+// consumer, acknowledging each message once its job has succeeded and
+// negatively acknowledging it, for redelivery, once it has failed for good.
+// It can publish each job's value to a reply subject.
+//
+// It works with any NATS client through Fetcher, Msg and Publisher. With
+// nats.go, a jetstream.Msg is a Msg as it is, and a jetstream.Consumer
+// and a *nats.Conn need a line each:
+//
+//	type fetcher struct{ c jetstream.Consumer }
+//
+//	func (f fetcher) Next(ctx context.Context) (natssource.Msg, error) {
+//		return f.c.Next(jetstream.FetchContext(ctx))
+//	}
+//
+//	type publisher struct{ nc *nats.Conn }
+//
+//	func (p publisher) Publish(_ context.Context, subject string, data []byte) error {
+//		return p.nc.Publish(subject, data)
+//	}
+package natssource
+
+import (
+	"context"
+	"encoding/json"
+
+	"gopatterns/workerpool"
+)
+
+// Msg is a message from a JetStream consumer with explicit acks.
+type Msg interface {
+	Data() []byte
+	Subject() string
+	Ack() error
+	Nak() error
+}
+
+// Fetcher fetches messages from a JetStream consumer.
+type Fetcher interface {
+	// Next waits for the next message until ctx is done.
+	Next(ctx context.Context) (Msg, error)
+}
+
+// Publisher publishes core NATS messages.
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithReplies publishes the value of each job that succeeds to
+// subject(msg), unless that is "", using pub. A value that is a []byte is
+// sent as it is, and any other as JSON.
+func WithReplies(pub Publisher, subject func(msg Msg) string) Option {
+	return func(s *Source) {
+		s.pub = pub
+		s.subject = subject
+	}
+}
+
+// WithOnError calls fn with the errors of acks and replies, which are
+// otherwise dropped: the server redelivers a message that was not acked.
+func WithOnError(fn func(error)) Option {
+	return func(s *Source) { s.onError = fn }
+}
+
+// Source is a workerpool.Source for the messages of a JetStream consumer,
+// delivering their data.
+type Source struct {
+	f       Fetcher
+	pub     Publisher
+	subject func(Msg) string
+	onError func(error)
+}
+
+var _ workerpool.Source = (*Source)(nil)
+
+// New returns a Source fetching from f.
+func New(f Fetcher, opts ...Option) *Source {
+	s := &Source{f: f}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Receive fetches the next message.
+func (s *Source) Receive(ctx context.Context) (workerpool.Delivery, error) {
+	msg, err := s.f.Next(ctx)
+	if err != nil {
+		return workerpool.Delivery{}, err
+	}
+	d := workerpool.Delivery{
+		Data: msg.Data(),
+		Done: func(err error) {
+			if err != nil {
+				s.report(msg.Nak())
+			} else {
+				s.report(msg.Ack())
+			}
+		},
+	}
+	if s.pub != nil {
+		d.Reply = func(value any) { s.report(s.reply(msg, value)) }
+	}
+	return d, nil
+}
+
+// reply publishes value in reply to msg.
+func (s *Source) reply(msg Msg, value any) error {
+	subject := s.subject(msg)
+	if subject == "" {
+		return nil
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+	return s.pub.Publish(context.Background(), subject, data)
+}
+
+func (s *Source) report(err error) {
+	if err != nil && s.onError != nil {
+		s.onError(err)
+	}
+}
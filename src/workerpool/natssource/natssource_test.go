@@ -0,0 +1,89 @@
+package natssource
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"gopatterns/workerpool"
+)
+
+type fakeMsg struct {
+	data    string
+	mu      *sync.Mutex
+	outcome map[string]string
+}
+
+func (m fakeMsg) Data() []byte    { return []byte(m.data) }
+func (m fakeMsg) Subject() string { return "jobs." + m.data }
+func (m fakeMsg) Ack() error      { return m.set("ack") }
+func (m fakeMsg) Nak() error      { return m.set("nak") }
+
+func (m fakeMsg) set(s string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcome[m.data] = s
+	return nil
+}
+
+type fakeFetcher chan Msg
+
+func (f fakeFetcher) Next(ctx context.Context) (Msg, error) {
+	select {
+	case m := <-f:
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type publisherFunc func(subject string, data []byte)
+
+func (f publisherFunc) Publish(_ context.Context, subject string, data []byte) error {
+	f(subject, data)
+	return nil
+}
+
+func TestAckNakAndReply(t *testing.T) {
+	var mu sync.Mutex
+	outcome := map[string]string{}
+	replies := map[string]string{}
+	f := make(fakeFetcher, 3)
+	for _, data := range []string{"x", "1", "2"} {
+		f <- fakeMsg{data: data, mu: &mu, outcome: outcome}
+	}
+	s := New(f, WithReplies(publisherFunc(func(subject string, data []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		replies[subject] = string(data)
+	}), func(m Msg) string { return "results." + m.Subject() }))
+
+	p := workerpool.New(1, func(_ context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, errors.New("two")
+		}
+		return n * 10, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	consumed := make(chan error, 1)
+	go func() {
+		consumed <- p.Consume(ctx, s, func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+	}()
+	for range 2 { // "x" does not decode, and never runs
+		<-p.Results()
+	}
+	cancel()
+	<-consumed
+	p.Shutdown(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if outcome["1"] != "ack" || outcome["2"] != "nak" || outcome["x"] != "nak" {
+		t.Errorf("outcomes %v", outcome)
+	}
+	if len(replies) != 1 || replies["results.jobs.1"] != "10" {
+		t.Errorf("replies %v", replies)
+	}
+}
@@ -0,0 +1,135 @@
+package workerpool
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var jobsBucket = []byte("jobs")
+
+// DiskQueue keeps the jobs of a pool in a bbolt database from the moment
+// they are submitted until they have run, so the jobs a crash or a
+// Shutdown that gave up left behind run when the next pool starts. Jobs
+// are stored as JSON: J must survive encoding/json.
+//
+// A job may run twice, if the process stops between running it and
+// forgetting it, and failed jobs are forgotten like the others: handlers
+// should be idempotent, and WithDeadLetters keeps the failures.
+type DiskQueue[J any] struct {
+	db      *bolt.DB
+	pending []queued[J] // stored when opened, for the first pool
+}
+
+// storedJob is the database value of a job.
+type storedJob[J any] struct {
+	Job       J         `json:"job"`
+	Priority  int       `json:"priority"`
+	Submitted time.Time `json:"submitted"`
+}
+
+// OpenQueue opens, creating it if needed, the queue database at path.
+func OpenQueue[J any](path string) (*DiskQueue[J], error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, fmt.Errorf("open %s: queue is locked by another process", path)
+		}
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	d := &DiskQueue[J]{db: db}
+	if d.pending, err = d.stored(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// Close closes the database, once the pool using it has shut down.
+func (d *DiskQueue[J]) Close() error {
+	return d.db.Close()
+}
+
+// Len returns the number of jobs stored: queued, running, or left behind.
+func (d *DiskQueue[J]) Len() int {
+	n := 0
+	d.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(jobsBucket).Stats().KeyN
+		return nil
+	})
+	return n
+}
+
+// WithPersistence keeps the pool's jobs in d, as described for DiskQueue,
+// starting with those stored there when d was opened. Submit fails if it
+// cannot store the job. d is for one pool at a time.
+func WithPersistence[J, R any](d *DiskQueue[J]) Option[J, R] {
+	return func(c *config[J, R]) { c.persist = d }
+}
+
+// add stores q, returning its ID.
+func (d *DiskQueue[J]) add(q queued[J]) (uint64, error) {
+	v, err := json.Marshal(storedJob[J]{Job: q.job, Priority: q.priority, Submitted: q.at})
+	if err != nil {
+		return 0, fmt.Errorf("workerpool: store job: %w", err)
+	}
+	var id uint64
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobsBucket)
+		id, _ = b.NextSequence()
+		return b.Put(itob(id), v)
+	})
+	return id, err
+}
+
+// remove forgets the job with the given ID.
+func (d *DiskQueue[J]) remove(id uint64) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete(itob(id))
+	})
+}
+
+// stored returns the jobs stored, in the order they were added.
+func (d *DiskQueue[J]) stored() ([]queued[J], error) {
+	var list []queued[J]
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(k, v []byte) error {
+			var s storedJob[J]
+			if err := json.Unmarshal(v, &s); err != nil {
+				return fmt.Errorf("workerpool: stored job %d: %w", binary.BigEndian.Uint64(k), err)
+			}
+			list = append(list, queued[J]{
+				job:      s.Job,
+				priority: s.Priority,
+				at:       s.Submitted,
+				stored:   binary.BigEndian.Uint64(k),
+			})
+			return nil
+		})
+	})
+	return list, err
+}
+
+// forget removes q from the DiskQueue, if it is stored there.
+func (p *Pool[J, R]) forget(q queued[J]) {
+	if q.stored != 0 {
+		p.persist.remove(q.stored)
+	}
+}
+
+func itob(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
@@ -0,0 +1,130 @@
+package workerpool
+
+import (
+	"container/heap"
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Priority levels for SubmitPriority. Any int will do: higher priorities
+// run first, and jobs of equal priority in the order they were submitted.
+const (
+	PriorityLow    = -10
+	PriorityNormal = 0
+	PriorityHigh   = 10
+)
+
+// queued is a job waiting for a worker.
+type queued[J any] struct {
+	job      J
+	priority int
+	seq      uint64            // submission order within a priority
+	at       time.Time         // when it was submitted
+	span     trace.SpanContext // of the submitter, with WithTracing
+	stored   uint64            // its ID in the DiskQueue, with WithPersistence
+	delivery *Delivery         // from Consume or SubmitGraph
+	failed   error             // with no need to run, from SubmitGraph
+}
+
+// jobHeap orders queued jobs by descending priority, then submission.
+type jobHeap[J any] []queued[J]
+
+func (h jobHeap[J]) Len() int { return len(h) }
+func (h jobHeap[J]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h jobHeap[J]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *jobHeap[J]) Push(x any)   { *h = append(*h, x.(queued[J])) }
+func (h *jobHeap[J]) Pop() any {
+	old := *h
+	q := old[len(old)-1]
+	old[len(old)-1] = queued[J]{} // let the job be collected
+	*h = old[:len(old)-1]
+	return q
+}
+
+// SubmitPriority is Submit for a job that overtakes the queued jobs of
+// lower priority. Jobs already running are not interrupted.
+func (p *Pool[J, R]) SubmitPriority(ctx context.Context, job J, priority int) error {
+	return p.submit(ctx, queued[J]{job: job, priority: priority})
+}
+
+// submit queues q, as SubmitPriority.
+func (p *Pool[J, R]) submit(ctx context.Context, q queued[J]) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	select {
+	case <-p.quit:
+		return ErrClosed
+	default:
+	}
+	q.at = time.Now()
+	if p.tracer != nil {
+		q.span = trace.SpanContextFromContext(ctx)
+	}
+	if p.persist != nil {
+		id, err := p.persist.add(q)
+		if err != nil {
+			return err
+		}
+		q.stored = id
+	}
+	err := ErrClosed
+	select {
+	case p.in <- q:
+		p.metrics.submitted.Add(1)
+		return nil
+	case <-p.quit:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	p.forget(q)
+	return err
+}
+
+// dispatch holds the queue, starting with h: it takes submitted jobs while
+// there is room and offers the most urgent to the workers, until Shutdown
+// closes p.in and the queue is empty, and then closes p.out.
+func (p *Pool[J, R]) dispatch(size int, h jobHeap[J]) {
+	var (
+		seq  uint64
+		open = true
+	)
+	for i := range h {
+		seq++
+		h[i].seq = seq
+	}
+	heap.Init(&h)
+	for open || h.Len() > 0 {
+		var (
+			in   <-chan queued[J] // nil while full or closed
+			out  chan<- queued[J] // nil while empty
+			next queued[J]
+		)
+		if open && h.Len() < size {
+			in = p.in
+		}
+		if h.Len() > 0 {
+			out, next = p.out, h[0]
+		}
+		select {
+		case q, ok := <-in:
+			if !ok {
+				open = false
+				continue
+			}
+			seq++
+			q.seq = seq
+			heap.Push(&h, q)
+		case out <- next:
+			heap.Pop(&h)
+		}
+		p.depth.Store(int64(h.Len()))
+	}
+	close(p.out)
+}
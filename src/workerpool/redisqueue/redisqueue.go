@@ -0,0 +1,184 @@
+// Package redisqueue is a job queue in Redis (6.2 or later) for workerpool
+// pools in several processes to share. Producers push jobs onto a list;
+// each consumer moves the job it takes onto a processing list of its own,
+// and removes it from there once it has run, so the jobs of a consumer
+// that died can be recovered.
+package redisqueue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopatterns/workerpool"
+)
+
+// Option configures a Queue.
+type Option func(*config)
+
+type config struct {
+	password string
+	db       int
+	consumer string
+	idle     int
+}
+
+// WithPassword authenticates to the server.
+func WithPassword(password string) Option {
+	return func(c *config) { c.password = password }
+}
+
+// WithDB selects the database by number; 0 by default.
+func WithDB(db int) Option {
+	return func(c *config) { c.db = db }
+}
+
+// WithConsumer names the consumer, and so its processing list. It must be
+// the same across restarts for Recover to find the jobs it left, and
+// unique among the consumers running. The default is the host name and
+// process ID, which is not the same across restarts.
+func WithConsumer(name string) Option {
+	return func(c *config) { c.consumer = name }
+}
+
+// WithIdleConns sets how many idle connections are kept for reuse; 2 by
+// default. Each Receive in progress holds one more.
+func WithIdleConns(n int) Option {
+	return func(c *config) { c.idle = max(n, 0) }
+}
+
+// Queue is a job queue in the Redis list key. It is a workerpool.Source,
+// and safe for concurrent use.
+type Queue struct {
+	addr       string
+	cfg        config
+	key        string
+	processing string
+	failed     string
+	conns      chan *conn // idle
+}
+
+var _ workerpool.Source = (*Queue)(nil)
+
+// New returns the queue in the list key on the server at addr. It
+// connects on first use.
+func New(addr, key string, opts ...Option) *Queue {
+	cfg := config{idle: 2}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.consumer == "" {
+		host, _ := os.Hostname()
+		cfg.consumer = host + ":" + strconv.Itoa(os.Getpid())
+	}
+	return &Queue{
+		addr:       addr,
+		cfg:        cfg,
+		key:        key,
+		processing: key + ":processing:" + cfg.consumer,
+		failed:     key + ":failed",
+		conns:      make(chan *conn, cfg.idle),
+	}
+}
+
+// Push adds a job to the queue.
+func (q *Queue) Push(ctx context.Context, data []byte) error {
+	_, err := q.do(ctx, "LPUSH", q.key, string(data))
+	return err
+}
+
+// Receive waits for a job and moves it to the consumer's processing list.
+// Its Done removes it from there, moving it to the list key+":failed" if
+// it failed. A job whose Done could not reach the server stays in the
+// processing list, for Recover, as may one the server moved just as ctx
+// interrupted Receive.
+func (q *Queue) Receive(ctx context.Context) (workerpool.Delivery, error) {
+	reply, err := q.do(ctx, "BLMOVE", q.key, q.processing, "RIGHT", "LEFT", "0")
+	if err != nil {
+		return workerpool.Delivery{}, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return workerpool.Delivery{}, fmt.Errorf("redisqueue: BLMOVE replied %T", reply)
+	}
+	return workerpool.Delivery{Data: data, Done: func(err error) { q.done(data, err) }}, nil
+}
+
+func (q *Queue) done(data []byte, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err != nil {
+		if _, err := q.do(ctx, "LPUSH", q.failed, string(data)); err != nil {
+			return
+		}
+	}
+	q.do(ctx, "LREM", q.processing, "1", string(data))
+}
+
+// Recover moves the jobs left in the consumer's processing list, by a
+// previous run that died or gave up on them, back to the front of the
+// queue, and returns how many. Call it before consuming.
+func (q *Queue) Recover(ctx context.Context) (int, error) {
+	n := 0
+	for {
+		reply, err := q.do(ctx, "LMOVE", q.processing, q.key, "LEFT", "RIGHT")
+		if err != nil || reply == nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (q *Queue) Len(ctx context.Context) (int, error) {
+	reply, err := q.do(ctx, "LLEN", q.key)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := reply.(int64)
+	return int(n), nil
+}
+
+// Close closes the idle connections. The Queue may still be used; it
+// reconnects.
+func (q *Queue) Close() error {
+	for {
+		select {
+		case c := <-q.conns:
+			c.nc.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs a command on an idle connection, or a new one, interrupting it
+// if ctx is done first.
+func (q *Queue) do(ctx context.Context, args ...string) (any, error) {
+	var c *conn
+	select {
+	case c = <-q.conns:
+	default:
+		var err error
+		if c, err = dial(ctx, q.addr, &q.cfg); err != nil {
+			return nil, err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { c.nc.SetDeadline(time.Unix(1, 0)) })
+	reply, err := c.do(args...)
+	if !stop() || broken(err) { // past its deadline, or out of step
+		c.nc.Close()
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return reply, err
+	}
+	select {
+	case q.conns <- c:
+	default:
+		c.nc.Close()
+	}
+	return reply, err
+}
@@ -0,0 +1,211 @@
+package redisqueue
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"gopatterns/workerpool"
+)
+
+// fakeRedis serves the list commands Queue uses.
+type fakeRedis struct {
+	mu    sync.Mutex
+	lists map[string][][]byte
+}
+
+func startFake(t *testing.T) (*fakeRedis, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	f := &fakeRedis{lists: map[string][][]byte{}}
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(nc)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(nc net.Conn) {
+	defer nc.Close()
+	c := &conn{nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+	for {
+		req, err := c.read()
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, a := range req.([]any) {
+			args = append(args, string(a.([]byte)))
+		}
+		f.reply(c, f.run(args))
+		if c.w.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) reply(c *conn, v any) {
+	switch v := v.(type) {
+	case nil:
+		c.w.WriteString("$-1\r\n")
+	case int:
+		fmt.Fprintf(c.w, ":%d\r\n", v)
+	case []byte:
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(v), v)
+	case error:
+		fmt.Fprintf(c.w, "-%s\r\n", v)
+	}
+}
+
+func (f *fakeRedis) run(args []string) any {
+	switch args[0] {
+	case "BLMOVE":
+		for {
+			if v := f.run([]string{"LMOVE", args[1], args[2], args[3], args[4]}); v != nil {
+				return v
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch args[0] {
+	case "LPUSH":
+		f.lists[args[1]] = append([][]byte{[]byte(args[2])}, f.lists[args[1]]...)
+		return len(f.lists[args[1]])
+	case "LMOVE":
+		src := f.lists[args[1]]
+		if len(src) == 0 {
+			return nil
+		}
+		var v []byte
+		if args[3] == "RIGHT" {
+			v, f.lists[args[1]] = src[len(src)-1], src[:len(src)-1]
+		} else {
+			v, f.lists[args[1]] = src[0], src[1:]
+		}
+		if args[4] == "LEFT" {
+			f.lists[args[2]] = append([][]byte{v}, f.lists[args[2]]...)
+		} else {
+			f.lists[args[2]] = append(f.lists[args[2]], v)
+		}
+		return v
+	case "LREM":
+		list := f.lists[args[1]]
+		if i := slices.IndexFunc(list, func(v []byte) bool { return string(v) == args[3] }); i >= 0 {
+			f.lists[args[1]] = slices.Delete(list, i, i+1)
+			return 1
+		}
+		return 0
+	case "LLEN":
+		return len(f.lists[args[1]])
+	}
+	return errors.New("ERR unknown command " + args[0])
+}
+
+func (f *fakeRedis) len(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.lists[key])
+}
+
+func TestQueueFeedsPool(t *testing.T) {
+	f, addr := startFake(t)
+	q := New(addr, "jobs", WithConsumer("a"))
+	defer q.Close()
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		if err := q.Push(ctx, []byte(strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := workerpool.New(2, func(_ context.Context, n int) (int, error) {
+		if n == 3 {
+			return 0, errors.New("three")
+		}
+		return n, nil
+	})
+	ctx, cancel := context.WithCancel(ctx)
+	consumed := make(chan error, 1)
+	atoi := func(b []byte) (int, error) { return strconv.Atoi(string(b)) }
+	go func() { consumed <- p.Consume(ctx, q, atoi) }()
+	var got []int
+	for r := range p.Results() {
+		if r.Err == nil {
+			got = append(got, r.Value)
+		}
+		if len(got) == 4 {
+			break
+		}
+	}
+	cancel()
+	if err := <-consumed; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Consume: %v", err)
+	}
+	p.Shutdown(context.Background())
+	for range p.Results() {
+	}
+
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 4, 5}) {
+		t.Fatalf("got %v", got)
+	}
+	if n, err := q.Len(context.Background()); n != 0 || err != nil {
+		t.Fatalf("Len: %d, %v", n, err)
+	}
+	if n := f.len("jobs:processing:a"); n != 0 {
+		t.Fatalf("%d jobs still processing", n)
+	}
+	if n := f.len("jobs:failed"); n != 1 {
+		t.Fatalf("%d jobs failed, want 1", n)
+	}
+}
+
+func TestRecoverRequeuesAbandonedJobs(t *testing.T) {
+	f, addr := startFake(t)
+	ctx := context.Background()
+	q := New(addr, "jobs", WithConsumer("a"))
+	q.Push(ctx, []byte("1"))
+	q.Push(ctx, []byte("2"))
+	if _, err := q.Receive(ctx); err != nil { // and dies before Done
+		t.Fatal(err)
+	}
+	q.Close()
+
+	q = New(addr, "jobs", WithConsumer("a"))
+	defer q.Close()
+	if n, err := q.Recover(ctx); n != 1 || err != nil {
+		t.Fatalf("Recover: %d, %v", n, err)
+	}
+	d, err := q.Receive(ctx)
+	if err != nil || string(d.Data) != "1" {
+		t.Fatalf("Receive after Recover: %q, %v", d.Data, err)
+	}
+	d.Done(nil)
+	if n := f.len("jobs:processing:a"); n != 0 {
+		t.Fatalf("%d jobs still processing", n)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	q.Receive(ctx) // takes "2"
+	if _, err := q.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Receive on an empty queue: %v", err)
+	}
+}
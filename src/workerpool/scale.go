@@ -0,0 +1,84 @@
+package workerpool
+
+import "time"
+
+// Autoscale bounds and tunes the number of workers of a pool that follows
+// its load. Workers are added when jobs keep waiting, and removed one at a
+// time as they find nothing to do. The two are deliberately asymmetric, so
+// a pool that has just grown for a burst does not shrink at the first lull
+// and grow again.
+type Autoscale struct {
+	Min, Max int // bounds on the number of workers; Min is at least 1
+
+	// Interval is how often the queue is checked; 100ms by default. Jobs
+	// waiting at two checks in a row add workers.
+	Interval time.Duration
+	// MaxWait adds workers at the first check after a job has waited this
+	// long in the queue to start; 10 Intervals by default.
+	MaxWait time.Duration
+	// IdleTimeout is how long a worker above Min waits for a job before it
+	// exits; 10s by default.
+	IdleTimeout time.Duration
+}
+
+// WithAutoscale lets the pool grow and shrink between a.Min and a.Max
+// workers as described for Autoscale.
+func WithAutoscale[J, R any](a Autoscale) Option[J, R] {
+	return func(c *config[J, R]) { c.autoscale = &a }
+}
+
+func (a Autoscale) withDefaults() Autoscale {
+	a.Min = max(a.Min, 1)
+	a.Max = max(a.Max, a.Min)
+	if a.Interval <= 0 {
+		a.Interval = 100 * time.Millisecond
+	}
+	if a.MaxWait <= 0 {
+		a.MaxWait = 10 * a.Interval
+	}
+	if a.IdleTimeout <= 0 {
+		a.IdleTimeout = 10 * time.Second
+	}
+	return a
+}
+
+// autoscale adds workers while jobs back up, until the pool is done.
+func (p *Pool[J, R]) autoscale() {
+	tick := time.NewTicker(p.scale.Interval)
+	defer tick.Stop()
+	backlog := false // jobs were waiting at the previous check
+	for {
+		select {
+		case <-tick.C:
+		case <-p.done:
+			return
+		}
+		depth := int(p.depth.Load())
+		p.workersMu.Lock()
+		slow := p.lastWait >= p.scale.MaxWait
+		if depth > 0 && (backlog || slow) && p.workers > 0 {
+			// Enough workers for the backlog, within bounds. A pool that
+			// has finished (no workers) stays finished.
+			p.spawn(min(depth, p.scale.Max-p.workers))
+			p.lastWait = 0
+			backlog = false
+		} else {
+			backlog = depth > 0
+			if !backlog {
+				p.lastWait = 0 // only waits behind the current backlog count
+			}
+		}
+		p.workersMu.Unlock()
+	}
+}
+
+// retire lets an idle worker exit if the pool is above its minimum.
+func (p *Pool[J, R]) retire() bool {
+	p.workersMu.Lock()
+	defer p.workersMu.Unlock()
+	if p.workers <= p.scale.Min {
+		return false
+	}
+	p.workers--
+	return true
+}
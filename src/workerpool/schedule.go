@@ -0,0 +1,104 @@
+package workerpool
+
+import (
+	"container/heap"
+	"time"
+)
+
+// later is a job waiting for its time to be queued.
+type later[J any] struct {
+	job J
+	at  time.Time
+	seq uint64 // scheduling order, among jobs due at the same time
+}
+
+// laterHeap orders scheduled jobs by time, then scheduling order.
+type laterHeap[J any] []later[J]
+
+func (h laterHeap[J]) Len() int { return len(h) }
+func (h laterHeap[J]) Less(i, j int) bool {
+	if !h[i].at.Equal(h[j].at) {
+		return h[i].at.Before(h[j].at)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h laterHeap[J]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *laterHeap[J]) Push(x any)   { *h = append(*h, x.(later[J])) }
+func (h *laterHeap[J]) Pop() any {
+	old := *h
+	l := old[len(old)-1]
+	old[len(old)-1] = later[J]{}
+	*h = old[:len(old)-1]
+	return l
+}
+
+// SubmitAt queues job at PriorityNormal at t, or at once if t has passed.
+// It does not wait for room in the queue, and returns ErrClosed once
+// Shutdown has been called. Jobs not yet due when Shutdown is called never
+// run: they are dead letters, failed with ErrClosed, if WithDeadLetters
+// keeps any.
+func (p *Pool[J, R]) SubmitAt(t time.Time, job J) error {
+	p.scheduled.Add(1)
+	select {
+	case p.later <- later[J]{job: job, at: t}:
+		return nil
+	case <-p.quit:
+		p.scheduled.Add(-1)
+		return ErrClosed
+	}
+}
+
+// SubmitAfter is SubmitAt for d from now.
+func (p *Pool[J, R]) SubmitAfter(d time.Duration, job J) error {
+	return p.SubmitAt(time.Now().Add(d), job)
+}
+
+// schedule holds the jobs of SubmitAt until they are due, and then queues
+// them, until Shutdown.
+func (p *Pool[J, R]) schedule() {
+	defer p.scheduling.Done()
+	var (
+		h     laterHeap[J]
+		seq   uint64
+		timer = time.NewTimer(0)
+	)
+	defer timer.Stop()
+	for {
+		var due <-chan time.Time // nil, never ready, with nothing scheduled
+		if h.Len() > 0 {
+			timer.Reset(time.Until(h[0].at))
+			due = timer.C
+		}
+		select {
+		case l := <-p.later:
+			seq++
+			l.seq = seq
+			heap.Push(&h, l)
+		case <-due:
+			var jobs []J
+			for h.Len() > 0 && !h[0].at.After(time.Now()) {
+				jobs = append(jobs, heap.Pop(&h).(later[J]).job)
+			}
+			p.scheduled.Add(-int64(len(jobs)))
+			p.scheduling.Add(1)
+			go p.queueDue(jobs) // without holding up SubmitAt
+		case <-p.quit:
+			p.scheduled.Add(-int64(h.Len()))
+			for _, l := range h {
+				p.bury(queued[J]{job: l.job, priority: PriorityNormal}, ErrClosed)
+			}
+			return
+		}
+	}
+}
+
+// queueDue queues jobs whose time has come, in order.
+func (p *Pool[J, R]) queueDue(jobs []J) {
+	defer p.scheduling.Done()
+	for _, job := range jobs {
+		q := queued[J]{job: job, priority: PriorityNormal}
+		if err := p.submit(p.ctx, q); err != nil {
+			p.bury(q, err)
+		}
+	}
+}
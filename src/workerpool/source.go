@@ -0,0 +1,59 @@
+package workerpool
+
+import "context"
+
+// Delivery is a job received from a Source, still encoded.
+type Delivery struct {
+	Data []byte
+	// Done is called once with the job's outcome after it has run: nil if
+	// it succeeded, or the error it failed with for good. It is not
+	// called for a job a Shutdown that gave up left behind, which the
+	// Source should deliver again.
+	Done func(err error)
+	// Reply, if set, is called with the job's value before Done if it
+	// succeeded, for a Source that sends results back.
+	Reply func(value any)
+}
+
+// Source delivers jobs from outside the pool, such as a queue that pools
+// in several processes consume. Implementations live in subpackages, such
+// as workerpool/redisqueue.
+type Source interface {
+	// Receive waits for the next job until ctx is done.
+	Receive(ctx context.Context) (Delivery, error)
+}
+
+// Consume submits the jobs src delivers, decoded with decode, at
+// PriorityNormal until ctx is done, Receive fails or the pool is shut
+// down, and returns why. A job decode fails on is done with its error
+// without running.
+func (p *Pool[J, R]) Consume(ctx context.Context, src Source, decode func([]byte) (J, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for {
+		d, err := src.Receive(ctx)
+		if err != nil {
+			select {
+			case <-p.quit:
+				return ErrClosed
+			default:
+				return err
+			}
+		}
+		job, err := decode(d.Data)
+		if err != nil {
+			d.Done(err)
+			continue
+		}
+		if err := p.submit(ctx, queued[J]{job: job, priority: PriorityNormal, delivery: &d}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package workerpool
+
+import (
+	"expvar"
+	"slices"
+	"time"
+)
+
+// recentJobs is how many of the latest jobs the latencies of Stats are
+// taken from.
+const recentJobs = 1024
+
+// Stats is a snapshot of what a pool is doing and has done.
+type Stats struct {
+	Workers   int    // running
+	Busy      int    // running a job
+	Queued    int    // waiting for a worker
+	Scheduled int    // waiting for their time, from SubmitAt
+	Submitted uint64 // since New
+	Completed uint64 // succeeded since New
+	Failed    uint64 // failed for good since New
+	Retried   uint64 // attempts after the first since New
+
+	// The median and 99th percentile of the time spent processing the
+	// latest jobs, retries included; zero before the first job.
+	P50, P99 time.Duration
+}
+
+// Stats returns the pool's counts and latencies as of now.
+func (p *Pool[J, R]) Stats() Stats {
+	m := &p.metrics
+	s := Stats{
+		Workers:   p.Workers(),
+		Busy:      int(m.busy.Load()),
+		Queued:    int(p.depth.Load()),
+		Scheduled: int(p.scheduled.Load()),
+		Submitted: m.submitted.Load(),
+		Completed: m.completed.Load(),
+		Failed:    m.failed.Load(),
+		Retried:   m.retried.Load(),
+	}
+	m.mu.Lock()
+	recent := slices.Clone(m.recent)
+	m.mu.Unlock()
+	if len(recent) > 0 {
+		slices.Sort(recent)
+		s.P50 = recent[(len(recent)-1)*50/100]
+		s.P99 = recent[(len(recent)-1)*99/100]
+	}
+	return s
+}
+
+// Publish makes the pool's Stats an expvar variable called name, served
+// as JSON on /debug/vars with the rest. Like expvar.Publish, it panics if
+// the name is taken.
+func (p *Pool[J, R]) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return p.Stats() }))
+}
@@ -0,0 +1,55 @@
+package workerpool
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout gives every job d to finish, retries included, unless
+// WithJobTimeout says otherwise. Past it, the handler's context is done and
+// the job fails with context.DeadlineExceeded; a handler that ignores its
+// context is left to finish in the background while the worker moves on.
+// d of 0, the default, is no timeout.
+func WithTimeout[J, R any](d time.Duration) Option[J, R] {
+	return func(c *config[J, R]) { c.timeout = max(d, 0) }
+}
+
+// WithJobTimeout gives each job the timeout timeout(job) returns, as
+// WithTimeout does for all of them; 0 falls back to WithTimeout's.
+func WithJobTimeout[J, R any](timeout func(J) time.Duration) Option[J, R] {
+	return func(c *config[J, R]) { c.jobTimeout = timeout }
+}
+
+// withTimeout returns ctx bounded by the timeout of job, if it has one.
+func (p *Pool[J, R]) withTimeout(ctx context.Context, job J) (context.Context, context.CancelFunc) {
+	d := p.timeout
+	if p.jobTimeout != nil {
+		if t := p.jobTimeout(job); t > 0 {
+			d = t
+		}
+	}
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// runBounded is run for a ctx with a deadline: it returns when the deadline
+// passes, even if the handler has not.
+func (p *Pool[J, R]) runBounded(ctx context.Context, jobs []J) ([]R, error) {
+	type outcome struct {
+		values []R
+		err    error
+	}
+	ch := make(chan outcome, 1) // the handler may finish after nobody waits
+	go func() {
+		values, err := p.run(ctx, jobs)
+		ch <- outcome{values, err}
+	}()
+	select {
+	case o := <-ch:
+		return o.values, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
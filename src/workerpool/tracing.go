@@ -0,0 +1,72 @@
+package workerpool
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gopatterns/retry"
+)
+
+// WithTracing runs every job in a span from tp, or from the global
+// provider if tp is nil. The span is a child of the span, if any, in the
+// context the job was submitted with, and is in the context passed to the
+// handler. Each retry is an event on it, and a job that fails for good
+// sets its error status.
+func WithTracing[J, R any](tp trace.TracerProvider) Option[J, R] {
+	return func(c *config[J, R]) {
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		c.tracer = tp.Tracer("gopatterns/workerpool")
+	}
+}
+
+// startSpan starts the span of batch, a child of its first job's
+// submitter, or returns ctx alone without tracing.
+func (p *Pool[J, R]) startSpan(ctx context.Context, batch []queued[J]) (context.Context, trace.Span) {
+	if p.tracer == nil {
+		return ctx, nil
+	}
+	q := batch[0]
+	attrs := []attribute.KeyValue{
+		attribute.Int("workerpool.priority", q.priority),
+		attribute.Int64("workerpool.queue_wait_ms", time.Since(q.at).Milliseconds()),
+	}
+	if p.batch.Size > 1 {
+		attrs = append(attrs, attribute.Int("workerpool.batch_size", len(batch)))
+	}
+	return p.tracer.Start(trace.ContextWithSpanContext(ctx, q.span), "workerpool.job",
+		trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span with the outcome of its job.
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceRetries adds an event to the span in ctx for every retry.
+func traceRetries(ctx context.Context, opts []retry.Option) []retry.Option {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], retry.OnRetry(func(attempt int, err error, delay time.Duration) {
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("workerpool.attempt", attempt),
+			attribute.String("error", err.Error()),
+			attribute.Int64("workerpool.delay_ms", delay.Milliseconds()),
+		))
+	}))
+}
@@ -0,0 +1,356 @@
+// Package workerpool runs jobs on a set of goroutines fed from a bounded
+// queue and hands their results back on a channel. The set is fixed unless
+// WithAutoscale lets it follow the load.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"gopatterns/breaker"
+	"gopatterns/retry"
+)
+
+// ErrClosed is returned by Submit once Shutdown has been called.
+var ErrClosed = errors.New("workerpool: pool is shut down")
+
+// Result is the outcome of one job: the handler's value, or its error.
+type Result[J, R any] struct {
+	Job   J
+	Value R
+	Err   error
+}
+
+// Handler processes a job of type J into a value of type R. ctx is
+// cancelled when Shutdown gives up waiting.
+type Handler[J, R any] func(ctx context.Context, job J) (R, error)
+
+// Option configures a Pool of jobs of type J and results of type R. An
+// option made for a pool of other types does not compile, and Go cannot
+// infer the types from the call, so they are spelled out:
+//
+//	p := workerpool.New(4, handle, workerpool.WithQueueSize[Job, string](100))
+type Option[J, R any] func(*config[J, R])
+
+type config[J, R any] struct {
+	queue       int // -1 until set
+	autoscale   *Autoscale
+	retry       []retry.Option
+	deadLetters int
+	breakerKey  func(J) string
+	breakers    *breaker.Group
+	tracer      trace.Tracer
+	timeout     time.Duration
+	jobTimeout  func(J) time.Duration
+	persist     *DiskQueue[J]
+	middleware  []Middleware[J, R]
+}
+
+// WithQueueSize sets how many submitted jobs may wait for a worker (at
+// least one), and how many results may wait to be received. Values below 0
+// are ignored. The default is the number of workers, or the most
+// WithAutoscale allows.
+func WithQueueSize[J, R any](n int) Option[J, R] {
+	return func(c *config[J, R]) {
+		if n >= 0 {
+			c.queue = n
+		}
+	}
+}
+
+// Pool is a set of workers sharing a queue of jobs of type J, whose
+// handler turns each into a result of type R.
+type Pool[J, R any] struct {
+	handler BatchHandler[J, R]
+	batch   Batching       // Size 1 but for NewBatch
+	in      chan queued[J] // to the dispatcher
+	out     chan queued[J] // from the dispatcher to the workers
+	depth   atomic.Int64   // jobs queued in the dispatcher
+	results chan Result[J, R]
+	scale   Autoscale      // Min == Max for a fixed pool
+	retry   []retry.Option // nil: one attempt per job
+
+	later      chan later[J]  // to the scheduler
+	scheduled  atomic.Int64   // jobs it holds, or is being sent
+	scheduling sync.WaitGroup // the scheduler and the jobs it queues
+
+	breakers   *breaker.Group // nil without WithBreaker
+	breakerKey func(J) string
+	tracer     trace.Tracer // nil without WithTracing
+	timeout    time.Duration
+	jobTimeout func(J) time.Duration
+	persist    *DiskQueue[J] // nil without WithPersistence
+
+	ctx    context.Context // cancelled when Shutdown times out
+	cancel context.CancelFunc
+
+	// mu is held for reading by every Submit in progress, so Shutdown can
+	// close in once they have all given up.
+	mu       sync.RWMutex
+	quit     chan struct{} // closed by Shutdown
+	shutdown sync.Once
+	done     chan struct{} // closed once every worker has exited
+
+	workersMu sync.Mutex
+	workers   int           // running
+	lastWait  time.Duration // queue wait of the latest job started
+
+	deadMu    sync.Mutex
+	dead      []DeadLetter[J]
+	deadLimit int
+
+	metrics metrics
+}
+
+// New starts workers goroutines (at least one) that pass each submitted job
+// to handler. With WithAutoscale, workers is only the starting number,
+// kept within its bounds.
+func New[J, R any](workers int, handler Handler[J, R], opts ...Option[J, R]) *Pool[J, R] {
+	cfg := newConfig(opts)
+	handler = Chain(handler, cfg.middleware...)
+	return start(workers, Batching{Size: 1}, func(ctx context.Context, jobs []J) ([]R, error) {
+		v, err := handler(ctx, jobs[0])
+		return []R{v}, err
+	}, cfg)
+}
+
+func newConfig[J, R any](opts []Option[J, R]) config[J, R] {
+	cfg := config[J, R]{queue: -1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// start is New for batches of b.
+func start[J, R any](workers int, b Batching, handler BatchHandler[J, R], cfg config[J, R]) *Pool[J, R] {
+	workers = max(workers, 1)
+	scale := Autoscale{Min: workers, Max: workers}
+	if cfg.autoscale != nil {
+		scale = cfg.autoscale.withDefaults()
+		workers = min(max(workers, scale.Min), scale.Max)
+	}
+	if cfg.queue < 0 {
+		cfg.queue = scale.Max
+	}
+	p := &Pool[J, R]{
+		handler: handler,
+		batch:   b,
+		in:      make(chan queued[J]),
+		out:     make(chan queued[J]),
+		later:   make(chan later[J]),
+		results: make(chan Result[J, R], cfg.queue),
+		scale:   scale,
+		retry:   cfg.retry,
+		tracer:  cfg.tracer,
+		timeout: cfg.timeout,
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+
+		breakers:   cfg.breakers,
+		breakerKey: cfg.breakerKey,
+		jobTimeout: cfg.jobTimeout,
+		persist:    cfg.persist,
+		deadLimit:  cfg.deadLetters,
+	}
+	var stored jobHeap[J]
+	if p.persist != nil {
+		stored, p.persist.pending = p.persist.pending, nil
+	}
+	if p.retry != nil {
+		p.retry = append(slices.Clip(p.retry), retry.OnRetry(func(int, error, time.Duration) {
+			p.metrics.retried.Add(1)
+		}))
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	go p.dispatch(max(cfg.queue, 1), stored)
+	p.scheduling.Add(1)
+	go p.schedule()
+	p.workersMu.Lock()
+	p.spawn(workers)
+	p.workersMu.Unlock()
+	if scale.Max > scale.Min {
+		go p.autoscale()
+	}
+	return p
+}
+
+// Submit queues job at PriorityNormal, waiting for room in the queue
+// until ctx is done. It returns ErrClosed once Shutdown has been called.
+func (p *Pool[J, R]) Submit(ctx context.Context, job J) error {
+	return p.SubmitPriority(ctx, job, PriorityNormal)
+}
+
+// Results returns the channel every job's result is sent on, in the order
+// the jobs finish. It is closed once the pool has shut down. Receive from
+// it until then: workers wait for room on it before taking the next job.
+func (p *Pool[J, R]) Results() <-chan Result[J, R] {
+	return p.results
+}
+
+// Workers returns the number of workers running.
+func (p *Pool[J, R]) Workers() int {
+	p.workersMu.Lock()
+	defer p.workersMu.Unlock()
+	return p.workers
+}
+
+// Shutdown stops accepting jobs and waits for the queued and running ones
+// to finish. If ctx is done first, it cancels the context of the running
+// handlers, fails the jobs still queued with context.Canceled without
+// running them, and returns ctx.Err(); the pool then winds down in the
+// background.
+func (p *Pool[J, R]) Shutdown(ctx context.Context) error {
+	p.shutdown.Do(func() {
+		close(p.quit)
+		p.scheduling.Wait()
+		p.mu.Lock()
+		close(p.in)
+		p.mu.Unlock()
+	})
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// spawn starts n more workers. p.workersMu must be held.
+func (p *Pool[J, R]) spawn(n int) {
+	p.workers += n
+	for range n {
+		go p.work()
+	}
+}
+
+// work runs jobs until the queue is closed and empty, or until the worker
+// has been idle long enough to retire.
+func (p *Pool[J, R]) work() {
+	var (
+		timer *time.Timer
+		idle  <-chan time.Time // nil, never ready, in a fixed pool
+	)
+	if p.scale.Max > p.scale.Min {
+		timer = time.NewTimer(p.scale.IdleTimeout)
+		defer timer.Stop()
+		idle = timer.C
+	}
+	for {
+		select {
+		case q, ok := <-p.out:
+			if !ok {
+				p.exit()
+				return
+			}
+			batch, open := p.gather(q)
+			p.process(batch)
+			if !open {
+				p.exit()
+				return
+			}
+		case <-idle:
+			if p.retire() {
+				return
+			}
+		}
+		if timer != nil {
+			timer.Reset(p.scale.IdleTimeout)
+		}
+	}
+}
+
+// process runs a batch of jobs and sends their results.
+func (p *Pool[J, R]) process(batch []queued[J]) {
+	if batch = p.skip(batch); len(batch) == 0 {
+		return
+	}
+	p.workersMu.Lock()
+	p.lastWait = time.Since(batch[0].at)
+	p.workersMu.Unlock()
+
+	var (
+		values []R
+		err    = p.ctx.Err()
+	)
+	if err == nil {
+		jobs := make([]J, len(batch))
+		for i, q := range batch {
+			jobs[i] = q.job
+		}
+		p.metrics.busy.Add(1)
+		start := time.Now()
+		ctx, span := p.startSpan(p.ctx, batch)
+		ctx, cancel := p.withTimeout(ctx, jobs[0])
+		values, err = p.call(ctx, jobs)
+		cancel()
+		endSpan(span, err)
+		took := time.Since(start)
+		for range batch {
+			p.metrics.observe(took)
+		}
+		p.metrics.busy.Add(-1)
+		for i, q := range batch {
+			if err != nil {
+				p.bury(q, err)
+			}
+			if p.ctx.Err() == nil { // or left for the next pool to run
+				p.forget(q)
+				if d := q.delivery; d != nil {
+					if err == nil && d.Reply != nil {
+						d.Reply(values[i])
+					}
+					d.Done(err)
+				}
+			}
+		}
+	}
+	for i, q := range batch {
+		r := Result[J, R]{Job: q.job, Err: err}
+		if i < len(values) {
+			r.Value = values[i]
+		}
+		if r.Err != nil {
+			p.metrics.failed.Add(1)
+		} else {
+			p.metrics.completed.Add(1)
+		}
+		p.results <- r
+	}
+}
+
+// exit accounts for a worker leaving a closed queue; the last one out
+// finishes the shutdown.
+func (p *Pool[J, R]) exit() {
+	p.workersMu.Lock()
+	defer p.workersMu.Unlock()
+	if p.workers--; p.workers == 0 {
+		p.cancel()
+		close(p.results)
+		close(p.done)
+	}
+}
+
+// run calls the handler, turning a panic into the jobs' error so one bad
+// job does not take the process down.
+func (p *Pool[J, R]) run(ctx context.Context, jobs []J) (values []R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("workerpool: job panicked: %v", r)
+		}
+	}()
+	values, err = p.handler(ctx, jobs)
+	if err == nil && len(values) != len(jobs) {
+		err = fmt.Errorf("workerpool: handler returned %d values for %d jobs", len(values), len(jobs))
+	}
+	return values, err
+}
@@ -0,0 +1,723 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"gopatterns/backoff"
+	"gopatterns/breaker"
+	"gopatterns/retry"
+)
+
+func TestPoolProcessesEveryJob(t *testing.T) {
+	p := New(3, func(_ context.Context, n int) (int, error) {
+		return n * 2, nil
+	})
+	go func() {
+		for i := 1; i <= 20; i++ {
+			if err := p.Submit(context.Background(), i); err != nil {
+				t.Error(err)
+			}
+		}
+		p.Shutdown(context.Background())
+	}()
+
+	seen := map[int]bool{}
+	for r := range p.Results() {
+		if r.Err != nil {
+			t.Fatalf("job %d: %v", r.Job, r.Err)
+		}
+		if r.Value != r.Job*2 {
+			t.Fatalf("job %d: got %d, want %d", r.Job, r.Value, r.Job*2)
+		}
+		seen[r.Job] = true
+	}
+	if len(seen) != 20 {
+		t.Fatalf("got %d results, want 20", len(seen))
+	}
+}
+
+func TestSubmitAfterShutdown(t *testing.T) {
+	p := New(1, func(context.Context, int) (int, error) { return 0, nil })
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Submit after Shutdown: got %v, want ErrClosed", err)
+	}
+	if _, ok := <-p.Results(); ok {
+		t.Fatal("Results still open after Shutdown")
+	}
+}
+
+func TestShutdownTimeoutCancelsHandlers(t *testing.T) {
+	started := make(chan struct{})
+	p := New(1, func(ctx context.Context, job int) (struct{}, error) {
+		if job == 1 {
+			close(started)
+		}
+		<-ctx.Done()
+		return struct{}{}, ctx.Err()
+	}, WithQueueSize[int, struct{}](5))
+	for i := 1; i <= 3; i++ {
+		if err := p.Submit(context.Background(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown: got %v, want DeadlineExceeded", err)
+	}
+	n := 0
+	for r := range p.Results() {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Fatalf("job %d: got %v, want context.Canceled", r.Job, r.Err)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Fatalf("got %d results, want 3", n)
+	}
+}
+
+func TestPanicFailsJob(t *testing.T) {
+	p := New(1, func(context.Context, string) (string, error) { panic("boom") })
+	if err := p.Submit(context.Background(), "x"); err != nil {
+		t.Fatal(err)
+	}
+	r := <-p.Results()
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "panicked: boom") {
+		t.Fatalf("got %v, want a panic error", r.Err)
+	}
+	p.Shutdown(context.Background())
+}
+
+func TestAutoscaleGrowsAndShrinks(t *testing.T) {
+	release := make(chan struct{})
+	p := New(1, func(ctx context.Context, _ int) (int, error) {
+		<-release
+		return 0, nil
+	}, WithAutoscale[int, int](Autoscale{Min: 1, Max: 4, Interval: 5 * time.Millisecond, IdleTimeout: 50 * time.Millisecond}),
+		WithQueueSize[int, int](20))
+	go func() {
+		for range p.Results() {
+		}
+	}()
+	for i := range 10 {
+		if err := p.Submit(context.Background(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool { return p.Workers() == 4 })
+	close(release)
+	waitFor(t, func() bool { return p.Workers() == 1 })
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitFor polls cond for up to a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatal("condition not met within a second")
+}
+
+func TestPriorityOvertakesQueue(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	p := New(1, func(_ context.Context, job string) (string, error) {
+		if job == "first" {
+			close(started)
+			<-release
+		}
+		return job, nil
+	}, WithQueueSize[string, string](10))
+	ctx := context.Background()
+	if err := p.Submit(ctx, "first"); err != nil {
+		t.Fatal(err)
+	}
+	<-started // queue the rest behind it
+	for _, s := range []struct {
+		job  string
+		prio int
+	}{{"low", PriorityLow}, {"normal 1", PriorityNormal}, {"high", PriorityHigh}, {"normal 2", PriorityNormal}} {
+		if err := p.SubmitPriority(ctx, s.job, s.prio); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(release)
+	go p.Shutdown(ctx)
+
+	var got []string
+	for r := range p.Results() {
+		got = append(got, r.Value)
+	}
+	want := []string{"first", "high", "normal 1", "normal 2", "low"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+}
+
+func TestFailedJobsAreDeadLettered(t *testing.T) {
+	var healed atomic.Bool
+	calls := map[string]int{}
+	p := New(1, func(_ context.Context, job string) (string, error) {
+		calls[job]++
+		if job == "flaky" && calls[job] == 1 || job == "broken" && !healed.Load() {
+			return "", errors.New(job + " failed")
+		}
+		return job, nil
+	}, WithRetry[string, string](retry.Attempts(3), retry.WithBackoff(backoff.Constant{})), WithDeadLetters[string, string](10))
+	ctx := context.Background()
+	for _, job := range []string{"flaky", "broken"} {
+		if err := p.Submit(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+		if r := <-p.Results(); (r.Err != nil) != (job == "broken") {
+			t.Fatalf("%s: got error %v", job, r.Err)
+		}
+	}
+
+	dead := p.DeadLetters()
+	if len(dead) != 1 || dead[0].Job != "broken" || len(dead[0].Errors) != 3 {
+		t.Fatalf("got dead letters %+v, want broken with 3 errors", dead)
+	}
+	healed.Store(true)
+	if n, err := p.Redrive(ctx); n != 1 || err != nil {
+		t.Fatalf("Redrive: got %d, %v", n, err)
+	}
+	if r := <-p.Results(); r.Err != nil || r.Value != "broken" {
+		t.Fatalf("redriven job: got %+v", r)
+	}
+	if dead := p.DeadLetters(); len(dead) != 0 {
+		t.Fatalf("dead letters left after Redrive: %+v", dead)
+	}
+	p.Shutdown(ctx)
+}
+
+func TestBreakerFastFailsOpenCircuit(t *testing.T) {
+	clock := backoff.NewFakeClock(time.Now())
+	var calls atomic.Int32
+	p := New(1, func(_ context.Context, host string) (string, error) {
+		calls.Add(1)
+		if host == "down" {
+			return "", errors.New("connection refused")
+		}
+		return host, nil
+	}, WithBreaker[string, string](func(host string) string { return host }, breaker.Settings{Failures: 2, Cooldown: time.Minute, Clock: clock}))
+	ctx := context.Background()
+	run := func(host string) error {
+		if err := p.Submit(ctx, host); err != nil {
+			t.Fatal(err)
+		}
+		return (<-p.Results()).Err
+	}
+
+	run("down")
+	run("down")
+	if err := run("down"); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("third call: got %v, want ErrOpen", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("handler called %d times, want 2", n)
+	}
+	if err := run("up"); err != nil {
+		t.Fatalf("other key: %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if err := run("down"); errors.Is(err, breaker.ErrOpen) {
+		t.Fatal("no trial call after the cooldown")
+	}
+	if s := p.Breakers().Get("down").State(); s != breaker.Open {
+		t.Fatalf("after a failed trial: got %v, want open", s)
+	}
+	p.Shutdown(ctx)
+}
+
+func TestCollectorWritesPrometheusText(t *testing.T) {
+	p := New(2, func(_ context.Context, n int) (int, error) {
+		if n%2 == 0 {
+			return 0, errors.New("even")
+		}
+		return n, nil
+	}, WithRetry[int, int](retry.Attempts(2), retry.WithBackoff(backoff.Constant{})))
+	c := NewCollector()
+	c.Register(`batch "nightly"`, p)
+	go func() {
+		for i := 1; i <= 4; i++ {
+			p.Submit(context.Background(), i)
+		}
+		p.Shutdown(context.Background())
+	}()
+	for range p.Results() {
+	}
+
+	var b strings.Builder
+	if _, err := c.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE workerpool_jobs_submitted_total counter",
+		`workerpool_jobs_submitted_total{pool="batch \"nightly\""} 4`,
+		`workerpool_jobs_completed_total{pool="batch \"nightly\""} 2`,
+		`workerpool_jobs_failed_total{pool="batch \"nightly\""} 2`,
+		`workerpool_jobs_retried_total{pool="batch \"nightly\""} 2`,
+		`workerpool_workers_busy{pool="batch \"nightly\""} 0`,
+		"# TYPE workerpool_job_duration_seconds histogram",
+		`workerpool_job_duration_seconds_bucket{pool="batch \"nightly\"",le="+Inf"} 4`,
+		`workerpool_job_duration_seconds_count{pool="batch \"nightly\""} 4`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, b.String())
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	p := New(2, func(_ context.Context, n int) (int, error) {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		if n == 10 {
+			return 0, errors.New("ten")
+		}
+		return n, nil
+	})
+	if s := p.Stats(); s.Workers != 2 || s.P50 != 0 {
+		t.Fatalf("before any job: %+v", s)
+	}
+	go func() {
+		for i := 1; i <= 10; i++ {
+			p.Submit(context.Background(), i)
+		}
+		p.Shutdown(context.Background())
+	}()
+	for range p.Results() {
+	}
+
+	s := p.Stats()
+	if s.Submitted != 10 || s.Completed != 9 || s.Failed != 1 || s.Busy != 0 || s.Queued != 0 {
+		t.Fatalf("after the jobs: %+v", s)
+	}
+	if s.P50 < 5*time.Millisecond || s.P99 < 9*time.Millisecond || s.P50 > s.P99 {
+		t.Fatalf("latencies: p50 %v, p99 %v", s.P50, s.P99)
+	}
+}
+
+func TestTracingSpansJobs(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	p := New(1, func(ctx context.Context, n int) (int, error) {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			t.Error("no span in the handler's context")
+		}
+		if n == 2 {
+			return 0, errors.New("two")
+		}
+		return n, nil
+	}, WithTracing[int, int](tp), WithRetry[int, int](retry.Attempts(3), retry.WithBackoff(backoff.Constant{})))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "submit")
+	p.Submit(ctx, 1)
+	p.Submit(ctx, 2)
+	parent.End()
+	go p.Shutdown(context.Background())
+	for range p.Results() {
+	}
+
+	var jobs []sdktrace.ReadOnlySpan
+	for _, s := range rec.Ended() {
+		if s.Name() == "workerpool.job" {
+			jobs = append(jobs, s)
+		}
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d job spans, want 2", len(jobs))
+	}
+	for _, s := range jobs {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %v is not a child of the submitter's", s.SpanContext().SpanID())
+		}
+	}
+	ok, failed := jobs[0], jobs[1]
+	if ok.Status().Code == codes.Error {
+		ok, failed = failed, ok
+	}
+	if ok.Status().Code == codes.Error || len(ok.Events()) != 0 {
+		t.Errorf("successful job: status %v, events %v", ok.Status(), ok.Events())
+	}
+	retries := 0
+	for _, e := range failed.Events() {
+		if e.Name == "retry" {
+			retries++
+		}
+	}
+	if failed.Status().Code != codes.Error || retries != 2 {
+		t.Errorf("failed job: status %v, %d retry events, want 2", failed.Status(), retries)
+	}
+}
+
+func TestTimeoutFreesWorker(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	p := New(1, func(_ context.Context, d time.Duration) (time.Duration, error) {
+		if d < 0 {
+			<-release // ignores its context
+			return d, nil
+		}
+		time.Sleep(d)
+		return d, nil
+	}, WithTimeout[time.Duration, time.Duration](20*time.Millisecond), WithJobTimeout[time.Duration, time.Duration](func(d time.Duration) time.Duration {
+		if d > 0 {
+			return 2 * d
+		}
+		return 0
+	}))
+	go func() {
+		for _, d := range []time.Duration{-1, 50 * time.Millisecond, 0} {
+			p.Submit(context.Background(), d)
+		}
+		p.Shutdown(context.Background())
+	}()
+
+	got := map[time.Duration]error{}
+	for r := range p.Results() {
+		got[r.Job] = r.Err
+	}
+	if err := got[-1]; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("stuck job: got %v, want DeadlineExceeded", err)
+	}
+	if err := got[50*time.Millisecond]; err != nil {
+		t.Errorf("job with its own longer timeout: %v", err)
+	}
+	if err := got[0]; err != nil {
+		t.Errorf("quick job: %v", err)
+	}
+}
+
+func TestBatchGroupsJobs(t *testing.T) {
+	var sizes []int
+	p := NewBatch(1, Batching{Size: 3, Wait: time.Second}, func(_ context.Context, jobs []int) ([]int, error) {
+		sizes = append(sizes, len(jobs))
+		if slices.Contains(jobs, 13) {
+			return nil, errors.New("unlucky")
+		}
+		values := make([]int, len(jobs))
+		for i, n := range jobs {
+			values[i] = n * 2
+		}
+		return values, nil
+	}, WithQueueSize[int, int](10))
+	for _, n := range []int{1, 2, 3, 4, 13} {
+		if err := p.Submit(context.Background(), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	go p.Shutdown(context.Background())
+
+	for r := range p.Results() {
+		switch {
+		case r.Job >= 4:
+			if r.Err == nil {
+				t.Errorf("job %d in the unlucky batch: no error", r.Job)
+			}
+		case r.Err != nil || r.Value != r.Job*2:
+			t.Errorf("job %d: got %d, %v", r.Job, r.Value, r.Err)
+		}
+	}
+	// The last batch is cut short by Shutdown rather than the Wait.
+	if !slices.Equal(sizes, []int{3, 2}) {
+		t.Fatalf("batch sizes %v, want [3 2]", sizes)
+	}
+}
+
+func TestBatchRunsMiddlewarePerJob(t *testing.T) {
+	var batches [][]int
+	p := NewBatch(1, Batching{Size: 3, Wait: time.Second}, func(_ context.Context, jobs []int) ([]int, error) {
+		batches = append(batches, slices.Clone(jobs))
+		values := make([]int, len(jobs))
+		for i, n := range jobs {
+			values[i] = n * 2
+		}
+		return values, nil
+	}, WithQueueSize[int, int](10), WithMiddleware(func(next Handler[int, int]) Handler[int, int] {
+		return func(ctx context.Context, n int) (int, error) {
+			switch n {
+			case 2:
+				return -1, nil // answered without the handler
+			case 13:
+				return 0, errors.New("unlucky")
+			}
+			return next(ctx, n+100)
+		}
+	}))
+	for _, n := range []int{1, 2, 3, 4, 13} {
+		if err := p.Submit(context.Background(), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	go p.Shutdown(context.Background())
+
+	want := map[int]int{1: 202, 2: -1, 3: 206}
+	for r := range p.Results() {
+		switch {
+		case r.Job >= 4:
+			if r.Err == nil {
+				t.Errorf("job %d in the unlucky batch: no error", r.Job)
+			}
+		case r.Err != nil || r.Value != want[r.Job]:
+			t.Errorf("job %d: got %d, %v", r.Job, r.Value, r.Err)
+		}
+	}
+	if len(batches) != 2 || !slices.Equal(batches[0], []int{101, 103}) || !slices.Equal(batches[1], []int{104}) {
+		t.Fatalf("handler got batches %v, want [[101 103] [104]]", batches)
+	}
+}
+
+func TestPersistenceReplaysLeftoverJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+	d, err := OpenQueue[string](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	p := New(1, func(ctx context.Context, job string) (string, error) {
+		if job == "a" {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return job, nil
+	}, WithPersistence[string, string](d), WithQueueSize[string, string](5))
+	for _, job := range []string{"a", "b", "c"} {
+		if err := p.Submit(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	go p.Shutdown(ctx) // gives up at once, as if the process died
+	for range p.Results() {
+	}
+	if n := d.Len(); n != 3 {
+		t.Fatalf("after an interrupted run: %d jobs stored, want 3", n)
+	}
+	d.Close()
+
+	if d, err = OpenQueue[string](path); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	p = New(1, func(_ context.Context, job string) (string, error) {
+		return job, nil
+	}, WithPersistence[string, string](d))
+	go p.Shutdown(context.Background())
+	var got []string
+	for r := range p.Results() {
+		got = append(got, r.Value)
+	}
+	if !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("replayed %v, want [a b c]", got)
+	}
+	if n := d.Len(); n != 0 {
+		t.Fatalf("after a full run: %d jobs stored, want 0", n)
+	}
+}
+
+func TestSubmitAtRunsJobsWhenDue(t *testing.T) {
+	p := New(1, func(_ context.Context, job string) (time.Time, error) {
+		return time.Now(), nil
+	}, WithDeadLetters[string, time.Time](5))
+	start := time.Now()
+	if err := p.SubmitAfter(40*time.Millisecond, "late"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SubmitAt(start.Add(20*time.Millisecond), "early"); err != nil {
+		t.Fatal(err)
+	}
+	p.SubmitAfter(time.Hour, "never")
+	if s := p.Stats().Scheduled; s != 3 {
+		t.Fatalf("Stats().Scheduled = %d, want 3", s)
+	}
+
+	var order []string
+	for r := range p.Results() {
+		if r.Value.Sub(start) < 20*time.Millisecond {
+			t.Errorf("%s ran after %v", r.Job, r.Value.Sub(start))
+		}
+		if order = append(order, r.Job); len(order) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(order, []string{"early", "late"}) {
+		t.Fatalf("ran %v, want [early late]", order)
+	}
+	p.Shutdown(context.Background())
+	for range p.Results() {
+	}
+	dead := p.DeadLetters()
+	if len(dead) != 1 || dead[0].Job != "never" || !errors.Is(dead[0].Err(), ErrClosed) {
+		t.Fatalf("dead letters %+v, want never, failed with ErrClosed", dead)
+	}
+	if err := p.SubmitAfter(0, "closed"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("SubmitAfter after Shutdown: %v", err)
+	}
+}
+
+func TestGraphRunsJobsAfterTheirDependencies(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		done = map[string]bool{}
+	)
+	p := New(3, func(_ context.Context, job string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, dep := range strings.Split(job, "<")[1:] {
+			if !done[dep] {
+				t.Errorf("%s ran before %s", job, dep)
+			}
+		}
+		name, _, _ := strings.Cut(job, "<")
+		if name == "bad" {
+			return "", errors.New("bad")
+		}
+		done[name] = true
+		return name, nil
+	})
+
+	// a and b run first, c after both, d after c; bad fails, and so do
+	// e, which depends on it, and f, which depends on e.
+	var g Graph[string]
+	a := g.Add("a")
+	b := g.Add("b")
+	c := g.Add("c<a<b", a, b)
+	g.Add("d<c", c)
+	bad := g.Add("bad")
+	e := g.Add("e", bad, a)
+	g.Add("f", e)
+	if err := p.SubmitGraph(context.Background(), &g); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]error{}
+	for r := range p.Results() {
+		got[r.Job] = r.Err
+		if len(got) == g.Len() {
+			break
+		}
+	}
+	go p.Shutdown(context.Background())
+	for range p.Results() {
+	}
+	for _, job := range []string{"a", "b", "c<a<b", "d<c"} {
+		if err := got[job]; err != nil {
+			t.Errorf("%s: %v", job, err)
+		}
+	}
+	var de *DependencyError
+	for _, job := range []string{"e", "f"} {
+		if err := got[job]; !errors.As(err, &de) || de.Err == nil {
+			t.Errorf("%s: got %v, want a DependencyError", job, err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if done["e"] || done["f"] {
+		t.Error("a job whose dependency failed ran")
+	}
+}
+
+func TestGraphDeadLettersJobsLeftAtShutdown(t *testing.T) {
+	release := make(chan struct{})
+	p := New(1, func(_ context.Context, job string) (string, error) {
+		if job == "a" {
+			<-release
+		}
+		return job, nil
+	}, WithDeadLetters[string, string](10))
+
+	// b and c wait for a, which is still running when Shutdown is called.
+	var g Graph[string]
+	a := g.Add("a")
+	b := g.Add("b", a)
+	g.Add("c", b)
+	if err := p.SubmitGraph(context.Background(), &g); err != nil {
+		t.Fatal(err)
+	}
+	go p.Shutdown(context.Background())
+	for p.Submit(context.Background(), "x") != ErrClosed {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	for range p.Results() {
+	}
+
+	var dead []DeadLetter[string]
+	for deadline := time.Now().Add(time.Second); len(dead) < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		dead = p.DeadLetters()
+	}
+	if len(dead) != 2 || dead[0].Job != "b" || dead[1].Job != "c" {
+		t.Fatalf("dead letters %+v, want b and c", dead)
+	}
+	var de *DependencyError
+	if !errors.Is(dead[0].Err(), ErrClosed) || !errors.As(dead[1].Err(), &de) || !errors.Is(de, ErrClosed) {
+		t.Fatalf("dead letters failed with %v and %v", dead[0].Err(), dead[1].Err())
+	}
+}
+
+func TestMiddlewareWrapsHandler(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	record := func(name string) Middleware[int, int] {
+		return func(next Handler[int, int]) Handler[int, int] {
+			return func(ctx context.Context, n int) (int, error) {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				return next(ctx, n)
+			}
+		}
+	}
+	double := func(next Handler[int, int]) Handler[int, int] {
+		return func(ctx context.Context, n int) (int, error) {
+			v, err := next(ctx, n)
+			return v * 2, err
+		}
+	}
+	p := New(1, func(_ context.Context, n int) (int, error) {
+		return n + 1, nil
+	}, WithMiddleware(record("outer"), double), WithMiddleware(record("inner")))
+	p.Submit(context.Background(), 1)
+	go p.Shutdown(context.Background())
+	r := <-p.Results()
+	for range p.Results() {
+	}
+	if r.Value != 4 {
+		t.Fatalf("got %d, want (1+1)*2", r.Value)
+	}
+	if !slices.Equal(calls, []string{"outer", "inner"}) {
+		t.Fatalf("middleware called in order %v", calls)
+	}
+}